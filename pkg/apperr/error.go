@@ -10,6 +10,13 @@ import (
 const (
 	ExitCodeOK    int = 0
 	ExitCodeError int = iota
+	// ExitCodeDestroy is returned when the plan would destroy resources.
+	// This is distinct from the exit codes of terraform plan -detailed-exitcode.
+	ExitCodeDestroy int = 3
+	// ExitCodeCriticalFinding is returned when the security scan result contains critical findings
+	ExitCodeCriticalFinding int = 4
+	// ExitCodePolicyViolation is returned when any policy fails
+	ExitCodePolicyViolation int = 5
 )
 
 // ErrorFormatter is the interface for format
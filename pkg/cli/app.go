@@ -24,9 +24,27 @@ func New(flags *LDFlags) *cli.App {
 		&cli.StringFlag{Name: "repo", Usage: "GitHub Repository name"},
 		&cli.StringFlag{Name: "sha", Usage: "commit SHA (revision)"},
 		&cli.StringFlag{Name: "build-url", Usage: "build url"},
+		&cli.StringFlag{Name: "branch", Usage: "the head branch. This is used to find the pull request if neither pr nor sha is set"},
 		&cli.StringFlag{Name: "log-level", Usage: "log level"},
 		&cli.IntFlag{Name: "pr", Usage: "pull request number"},
+		&cli.IntFlag{Name: "target-pr", Usage: "the pull request number where the result is posted. The pull request isn't detected automatically"},
 		&cli.StringFlag{Name: "config", Usage: "config path"},
+		&cli.StringFlag{Name: "cost-estimate", Usage: "the file path of the cost estimate by infracost. If the value is '-', the cost estimate is read from the standard input"},
+		&cli.StringFlag{Name: "tflint", Usage: "the file path of the output of tflint --format json. If the value is '-', the result is read from the standard input"},
+		&cli.StringFlag{Name: "security-scan", Usage: "the file path of the output of trivy config --format json or tfsec --format json. If the value is '-', the result is read from the standard input"},
+		&cli.StringFlag{Name: "checkov", Usage: "the file path of the output of checkov --output json. If the value is '-', the result is read from the standard input"},
+		&cli.StringSliceFlag{Name: "chdir-glob", Usage: "glob patterns of directories. The plan command runs the command in each directory and posts a single comment"},
+		&cli.IntFlag{Name: "parallelism", Usage: "the maximum number of directories where the command runs concurrently. This is used with chdir-glob (default: 1)"},
+		&cli.BoolFlag{Name: "only-when-failed", Usage: "post the plan comment only if the plan fails, destroys resources, or can't be parsed. Labels are updated anyway"},
+		&cli.BoolFlag{Name: "drift-issue", Usage: "create, update, and close a GitHub issue which tracks the drift of the target instead of posting the plan comment"},
+		&cli.BoolFlag{Name: "dry-run", Usage: "render the comment and output it without posting it to GitHub"},
+		&cli.StringFlag{Name: "dry-run-output", Usage: "the file path where the comment is written in the dry run mode. By default, the comment is written to the standard output"},
+		&cli.StringFlag{Name: "comment-output", Aliases: []string{"output"}, Usage: "the file path where the posted comment is also written. This is useful to reuse the comment in following steps"},
+		&cli.StringFlag{Name: "result-format", Usage: "the format of the machine-readable result such as the changed resources, the exit code, and the posted comment. Only 'json' is supported"},
+		&cli.StringFlag{Name: "result-output", Usage: "the file path where the result is written. By default, the result is written to the standard output"},
+		&cli.StringFlag{Name: "terraform-output-file", Usage: "the file path of the output of terraform command. If this is set, the command isn't run and the file is read instead"},
+		&cli.IntFlag{Name: "exit-code", Usage: "the exit code of terraform command. This is used with terraform-output-file (default: 0)"},
+		&cli.StringFlag{Name: "plan-file", Usage: "the saved plan file or the output of terraform show -json. For the plan file, 'terraform show -json' is run instead of the command"},
 		&cli.StringSliceFlag{Name: "var", Usage: "template variables. The format of value is '<name>:<value>'"},
 	}
 	app.Commands = []*cli.Command{
@@ -40,6 +58,31 @@ func New(flags *LDFlags) *cli.App {
 			Usage:  "Run terraform apply and post a comment to GitHub commit or pull request",
 			Action: cmdApply,
 		},
+		{
+			Name:   "destroy",
+			Usage:  "Run terraform destroy and post a comment to GitHub commit or pull request",
+			Action: cmdDestroy,
+		},
+		{
+			Name:   "test",
+			Usage:  "Run terraform test and post a comment to GitHub commit or pull request",
+			Action: cmdTest,
+		},
+		{
+			Name:   "validate",
+			Usage:  "Run terraform validate -json and post a comment to GitHub commit or pull request",
+			Action: cmdValidate,
+		},
+		{
+			Name:   "fmt",
+			Usage:  "Run terraform fmt -check -diff and post a comment to GitHub commit or pull request",
+			Action: cmdFmt,
+		},
+		{
+			Name:   "validate-template",
+			Usage:  "Render the templates of plan and apply with sample data to validate them without running terraform and calling GitHub API",
+			Action: cmdValidateTemplate,
+		},
 		{
 			Name:  "version",
 			Usage: "Show version",
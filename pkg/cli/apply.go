@@ -24,10 +24,24 @@ func cmdApply(ctx *cli.Context) error {
 		return err
 	}
 
+	var parser terraform.Parser = terraform.NewApplyParser()
+	tpl := cfg.Terraform.Apply.Template
+	switch cfg.Terraform.OutputFormat {
+	case "json":
+		parser = terraform.NewJSONParser(terraform.CommandApply)
+	case "cdktf":
+		parser = terraform.NewCDKTFParser(terraform.CommandApply)
+	case "pulumi":
+		parser = terraform.NewPulumiParser(terraform.CommandApply)
+		if tpl == "" {
+			tpl = terraform.DefaultPulumiUpTemplate
+		}
+	}
+
 	t := &controller.Controller{
 		Config:             cfg,
-		Parser:             terraform.NewApplyParser(),
-		Template:           terraform.NewApplyTemplate(cfg.Terraform.Apply.Template),
+		Parser:             parser,
+		Template:           terraform.NewApplyTemplate(tpl),
 		ParseErrorTemplate: terraform.NewApplyParseErrorTemplate(cfg.Terraform.Apply.WhenParseError.Template),
 	}
 
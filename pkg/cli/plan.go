@@ -1,6 +1,13 @@
 package cli
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"unicode"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/config"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/controller"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
 	"github.com/urfave/cli/v2"
@@ -23,16 +30,122 @@ func cmdPlan(ctx *cli.Context) error {
 		return err
 	}
 
+	var parser terraform.Parser
+	tpl := cfg.Terraform.Plan.Template
+	switch {
+	case cfg.Terraform.OutputFormat == "json" || cfg.PlanFile != "":
+		p := terraform.NewJSONParser(terraform.CommandPlan)
+		p.IgnoredResources = cfg.Terraform.Plan.IgnoredResources
+		parser = p
+	case cfg.Terraform.OutputFormat == "cdktf":
+		p := terraform.NewCDKTFParser(terraform.CommandPlan)
+		p.Plan.IgnoredResources = cfg.Terraform.Plan.IgnoredResources
+		parser = p
+	case cfg.Terraform.OutputFormat == "pulumi":
+		parser = terraform.NewPulumiParser(terraform.CommandPlan)
+		if tpl == "" {
+			tpl = terraform.DefaultPulumiPreviewTemplate
+		}
+	case len(cfg.Terraform.Plan.MultiDir.Targets) != 0:
+		// The output of each directory is prefixed with the directory and parsed like terragrunt run-all plan
+		p := terraform.NewTerragruntParser()
+		p.Plan.IgnoredResources = cfg.Terraform.Plan.IgnoredResources
+		parser = p
+		if tpl == "" {
+			tpl = terraform.DefaultMultiDirPlanTemplate
+		}
+	case cfg.Terraform.Plan.TerragruntRunAll:
+		p := terraform.NewTerragruntParser()
+		p.Plan.IgnoredResources = cfg.Terraform.Plan.IgnoredResources
+		parser = p
+		if tpl == "" {
+			tpl = terraform.DefaultTerragruntPlanTemplate
+		}
+	default:
+		p := terraform.NewPlanParser()
+		p.IgnoredResources = cfg.Terraform.Plan.IgnoredResources
+		parser = p
+		if tpl == "" && isRefreshOnly(ctx.Args().Slice()) {
+			tpl = terraform.DefaultRefreshOnlyPlanTemplate
+		}
+	}
+
 	t := &controller.Controller{
 		Config:             cfg,
-		Parser:             terraform.NewPlanParser(),
-		Template:           terraform.NewPlanTemplate(cfg.Terraform.Plan.Template),
+		Parser:             parser,
+		Template:           terraform.NewPlanTemplate(tpl),
 		ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(cfg.Terraform.Plan.WhenParseError.Template),
+		EvaluatePolicy:     true,
+		MultiDir:           true,
 	}
 	args := ctx.Args()
-
-	return t.Run(ctx.Context, controller.Command{
+	command := controller.Command{
 		Cmd:  args.First(),
 		Args: args.Tail(),
-	})
+	}
+	if cfg.PlanFile != "" {
+		c, err := planFileCommand(&t.Config, command)
+		if err != nil {
+			return err
+		}
+		command = c
+	}
+
+	return t.Run(ctx.Context, command)
+}
+
+// planFileCommand returns the command to convert the saved plan file to JSON.
+// If the file is already JSON, the file is read as the output of the command instead of running the command.
+// The command defaults to terraform, and another binary such as tofu can be given as the command
+func planFileCommand(cfg *config.Config, command controller.Command) (controller.Command, error) {
+	isJSON, err := isJSONFile(cfg.PlanFile)
+	if err != nil {
+		return command, fmt.Errorf("read the plan file: %w", err)
+	}
+	if isJSON {
+		cfg.TerraformOutputFile = cfg.PlanFile
+		return command, nil
+	}
+	bin := command.Cmd
+	if bin == "" {
+		bin = "terraform"
+	}
+	return controller.Command{
+		Cmd:  bin,
+		Args: []string{"show", "-json", "-no-color", cfg.PlanFile},
+	}, nil
+}
+
+// isRefreshOnly returns true if the command is terraform plan -refresh-only
+func isRefreshOnly(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "-refresh-only", "--refresh-only", "-refresh-only=true", "--refresh-only=true":
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONFile returns true if the first non-space character of the file is "{".
+// A saved plan file is a zip archive, so it never starts with "{"
+func isJSONFile(p string) (bool, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	for {
+		c, _, err := r.ReadRune()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if !unicode.IsSpace(c) {
+			return c == '{', nil
+		}
+	}
 }
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/controller"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+	"github.com/urfave/cli/v2"
+)
+
+func cmdValidateTemplate(ctx *cli.Context) error {
+	logLevel := ctx.String("log-level")
+	setLogLevel(logLevel)
+
+	cfg, err := newConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if logLevel == "" {
+		logLevel = cfg.Log.Level
+		setLogLevel(logLevel)
+	}
+
+	if err := parseOpts(ctx, &cfg); err != nil {
+		return err
+	}
+
+	for _, ctrl := range []struct {
+		command string
+		ctrl    *controller.Controller
+	}{
+		{
+			command: "plan",
+			ctrl: &controller.Controller{
+				Config:             cfg,
+				Template:           terraform.NewPlanTemplate(cfg.Terraform.Plan.Template),
+				ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(cfg.Terraform.Plan.WhenParseError.Template),
+			},
+		},
+		{
+			command: "apply",
+			ctrl: &controller.Controller{
+				Config:             cfg,
+				Template:           terraform.NewApplyTemplate(cfg.Terraform.Apply.Template),
+				ParseErrorTemplate: terraform.NewApplyParseErrorTemplate(cfg.Terraform.Apply.WhenParseError.Template),
+			},
+		},
+		{
+			command: "destroy",
+			ctrl: &controller.Controller{
+				Config:             cfg,
+				Template:           terraform.NewDestroyTemplate(cfg.Terraform.Destroy.Template),
+				ParseErrorTemplate: terraform.NewDestroyParseErrorTemplate(cfg.Terraform.Destroy.WhenParseError.Template),
+			},
+		},
+		{
+			command: "test",
+			ctrl: &controller.Controller{
+				Config:             cfg,
+				Template:           terraform.NewTestTemplate(cfg.Terraform.Test.Template),
+				ParseErrorTemplate: terraform.NewTestParseErrorTemplate(cfg.Terraform.Test.WhenParseError.Template),
+			},
+		},
+		{
+			command: "validate",
+			ctrl: &controller.Controller{
+				Config:             cfg,
+				Template:           terraform.NewValidateTemplate(cfg.Terraform.Validate.Template),
+				ParseErrorTemplate: terraform.NewValidateParseErrorTemplate(cfg.Terraform.Validate.WhenParseError.Template),
+			},
+		},
+		{
+			command: "fmt",
+			ctrl: &controller.Controller{
+				Config:   cfg,
+				Template: terraform.NewFmtTemplate(cfg.Terraform.Fmt.Template),
+			},
+		},
+	} {
+		if err := ctrl.ctrl.ValidateTemplates(); err != nil {
+			return fmt.Errorf("%s: %w", ctrl.command, err)
+		}
+	}
+	return nil
+}
@@ -36,10 +36,73 @@ func parseOpts(ctx *cli.Context, cfg *config.Config) error {
 		cfg.CI.PRNumber = pr
 	}
 
+	if targetPR := ctx.Int("target-pr"); targetPR != 0 {
+		cfg.TargetPRNumber = targetPR
+	}
+
+	if branch := ctx.String("branch"); branch != "" {
+		cfg.CI.Branch = branch
+	}
+
 	if buildURL := ctx.String("build-url"); buildURL != "" {
 		cfg.CI.Link = buildURL
 	}
 
+	if costEstimate := ctx.String("cost-estimate"); costEstimate != "" {
+		cfg.CostEstimate = costEstimate
+	}
+
+	if tflint := ctx.String("tflint"); tflint != "" {
+		cfg.TFLint = tflint
+	}
+
+	if securityScan := ctx.String("security-scan"); securityScan != "" {
+		cfg.SecurityScan = securityScan
+	}
+
+	if checkov := ctx.String("checkov"); checkov != "" {
+		cfg.Checkov = checkov
+	}
+
+	if chdirGlobs := ctx.StringSlice("chdir-glob"); len(chdirGlobs) != 0 {
+		cfg.Terraform.Plan.MultiDir.Targets = chdirGlobs
+	}
+
+	if parallelism := ctx.Int("parallelism"); parallelism != 0 {
+		cfg.Terraform.Plan.MultiDir.Parallelism = parallelism
+	}
+
+	if ctx.Bool("only-when-failed") {
+		cfg.Terraform.Plan.OnlyWhenFailed.Enabled = true
+	}
+
+	if ctx.Bool("drift-issue") {
+		cfg.Terraform.Plan.DriftIssue.Enabled = true
+	}
+
+	cfg.DryRun = ctx.Bool("dry-run")
+	if dryRunOutput := ctx.String("dry-run-output"); dryRunOutput != "" {
+		cfg.DryRunOutput = dryRunOutput
+	}
+	if commentOutput := ctx.String("comment-output"); commentOutput != "" {
+		cfg.CommentOutput = commentOutput
+	}
+	if resultFormat := ctx.String("result-format"); resultFormat != "" {
+		cfg.ResultFormat = resultFormat
+	}
+	if resultOutput := ctx.String("result-output"); resultOutput != "" {
+		cfg.ResultOutput = resultOutput
+	}
+
+	if outputFile := ctx.String("terraform-output-file"); outputFile != "" {
+		cfg.TerraformOutputFile = outputFile
+	}
+	cfg.ExitCode = ctx.Int("exit-code")
+
+	if planFile := ctx.String("plan-file"); planFile != "" {
+		cfg.PlanFile = planFile
+	}
+
 	vars := ctx.StringSlice("var")
 	vm := make(map[string]string, len(vars))
 	if err := parseVarOpts(vars, vm); err != nil {
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// baseConfigCacheTTL is the duration while the cached base config is used without fetching it
+	baseConfigCacheTTL = 10 * time.Minute
+	// baseConfigTimeout is the timeout to fetch the base config
+	baseConfigTimeout = 10 * time.Second
+)
+
+// baseConfigLoader reads the base config which is set by `extends`.
+// The base config is either a URL or a file path. A relative file path is relative to the directory of the config file
+type baseConfigLoader struct {
+	client   *http.Client
+	cacheDir string
+	now      func() time.Time
+}
+
+func newBaseConfigLoader() *baseConfigLoader {
+	loader := &baseConfigLoader{
+		client: &http.Client{Timeout: baseConfigTimeout},
+		now:    time.Now,
+	}
+	// If the cache directory isn't found, the base config isn't cached
+	if dir, err := os.UserCacheDir(); err == nil {
+		loader.cacheDir = filepath.Join(dir, "tfcmt", "base_config")
+	}
+	return loader
+}
+
+func isURL(src string) bool {
+	return strings.HasPrefix(src, "https://") || isInsecureURL(src)
+}
+
+// isInsecureURL returns true if the base config is fetched with http.
+// The base config can change templates and labels, so it must not be tampered with
+func isInsecureURL(src string) bool {
+	return strings.HasPrefix(src, "http://")
+}
+
+// read returns the content of the base config
+func (loader *baseConfigLoader) read(ctx context.Context, src, dir string) ([]byte, error) {
+	if !isURL(src) {
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(dir, src)
+		}
+		return ioutil.ReadFile(src)
+	}
+	cachePath := loader.cachePath(src)
+	if cachePath != "" {
+		if stat, err := os.Stat(cachePath); err == nil && loader.now().Sub(stat.ModTime()) < baseConfigCacheTTL {
+			return ioutil.ReadFile(cachePath)
+		}
+	}
+	b, err := loader.fetch(ctx, src)
+	if err != nil {
+		if cachePath == "" {
+			return nil, err
+		}
+		// Use the stale cache if it exists
+		cache, cacheErr := ioutil.ReadFile(cachePath)
+		if cacheErr != nil {
+			return nil, err
+		}
+		logrus.WithFields(logrus.Fields{
+			"program":     "tfcmt",
+			"base_config": src,
+		}).WithError(err).Warn("fetch the base config. The cached base config is used")
+		return cache, nil
+	}
+	if cachePath != "" {
+		if err := loader.writeCache(cachePath, b); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program":     "tfcmt",
+				"base_config": src,
+			}).WithError(err).Warn("cache the base config")
+		}
+	}
+	return b, nil
+}
+
+func (loader *baseConfigLoader) cachePath(u string) string {
+	if loader.cacheDir == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(u))
+	return filepath.Join(loader.cacheDir, hex.EncodeToString(hash[:])+".yaml")
+}
+
+func (loader *baseConfigLoader) writeCache(p string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { //nolint:gomnd
+		return err
+	}
+	return ioutil.WriteFile(p, b, 0o644) //nolint:gosec,gomnd
+}
+
+func (loader *baseConfigLoader) fetch(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create a request to fetch the base config: %w", err)
+	}
+	resp, err := loader.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("status code %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const baseConfig = `
+tag: platform
+terraform:
+  plan:
+    template: base plan template
+    when_destroy:
+      label: destroy
+templates:
+  foo: base foo
+  bar: base bar
+`
+
+const localConfig = `
+extends: %s
+terraform:
+  plan:
+    when_destroy:
+      label: local-destroy
+templates:
+  bar: local bar
+`
+
+func writeFile(t *testing.T, p, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(p, []byte(content), 0o644); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+}
+
+func TestLoadFileExtends(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	exp := Config{
+		Tag: "platform",
+		Terraform: Terraform{
+			Plan: Plan{
+				Template: "base plan template",
+				WhenDestroy: WhenDestroy{
+					Label: "local-destroy",
+				},
+			},
+		},
+		Templates: map[string]string{
+			"foo": "base foo",
+			"bar": "local bar",
+		},
+	}
+	testCases := []struct {
+		name       string
+		status     int
+		cache      string
+		cacheAge   time.Duration
+		local      bool
+		exp        Config
+		fetchCount int32
+	}{
+		{
+			name:       "remote",
+			status:     http.StatusOK,
+			exp:        exp,
+			fetchCount: 1,
+		},
+		{
+			name:  "file",
+			local: true,
+			exp:   exp,
+		},
+		{
+			name:     "fresh cache",
+			status:   http.StatusOK,
+			cache:    baseConfig,
+			cacheAge: time.Minute,
+			exp:      exp,
+		},
+		{
+			name:       "stale cache is used if the base config can't be fetched",
+			status:     http.StatusInternalServerError,
+			cache:      baseConfig,
+			cacheAge:   time.Hour,
+			exp:        exp,
+			fetchCount: 1,
+		},
+		{
+			name:   "fall back to the local config",
+			status: http.StatusInternalServerError,
+			exp: Config{
+				Terraform: Terraform{
+					Plan: Plan{
+						WhenDestroy: WhenDestroy{
+							Label: "local-destroy",
+						},
+					},
+				},
+				Templates: map[string]string{
+					"bar": "local bar",
+				},
+			},
+			fetchCount: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			var fetchCount int32
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&fetchCount, 1)
+				w.WriteHeader(testCase.status)
+				if testCase.status == http.StatusOK {
+					_, _ = w.Write([]byte(baseConfig))
+				}
+			}))
+			defer server.Close()
+			dir := t.TempDir()
+			now := time.Now()
+			loader := &baseConfigLoader{
+				client:   server.Client(),
+				cacheDir: filepath.Join(dir, "cache"),
+				now: func() time.Time {
+					return now.Add(testCase.cacheAge)
+				},
+			}
+			src := server.URL + "/tfcmt.yaml"
+			if testCase.local {
+				writeFile(t, filepath.Join(dir, "base.yaml"), baseConfig)
+				src = "base.yaml"
+			}
+			if testCase.cache != "" {
+				if err := loader.writeCache(loader.cachePath(src), []byte(testCase.cache)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p := filepath.Join(dir, "tfcmt.yaml")
+			writeFile(t, p, fmt.Sprintf(localConfig, src))
+			var cfg Config
+			if err := cfg.loadFile(p, loader); err != nil {
+				t.Fatal(err)
+			}
+			exp := testCase.exp
+			exp.Extends = src
+			if diff := cmp.Diff(exp, cfg); diff != "" {
+				t.Error(diff)
+			}
+			if n := atomic.LoadInt32(&fetchCount); n != testCase.fetchCount {
+				t.Errorf("the base config is fetched %d times, wanted %d", n, testCase.fetchCount)
+			}
+		})
+	}
+}
+
+func TestLoadFileExtendsError(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(baseConfig))
+	}))
+	// subtests run in parallel after this function returns
+	t.Cleanup(server.Close)
+	testCases := []struct {
+		name   string
+		config string
+		base   string
+		ok     bool
+	}{
+		{
+			name:   "http isn't allowed by default",
+			config: fmt.Sprintf(localConfig, server.URL+"/tfcmt.yaml"),
+		},
+		{
+			name:   "http is allowed explicitly",
+			config: "extends_allow_http: true\n" + fmt.Sprintf(localConfig, server.URL+"/tfcmt.yaml"),
+			ok:     true,
+		},
+		{
+			name:   "the base config is invalid",
+			config: fmt.Sprintf(localConfig, "base.yaml"),
+			base:   "templates: [",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			loader := &baseConfigLoader{
+				client: server.Client(),
+				now:    time.Now,
+			}
+			if testCase.base != "" {
+				writeFile(t, filepath.Join(dir, "base.yaml"), testCase.base)
+			}
+			p := filepath.Join(dir, "tfcmt.yaml")
+			writeFile(t, p, testCase.config)
+			var cfg Config
+			err := cfg.loadFile(p, loader)
+			if testCase.ok {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cfg.Templates["foo"] != "base foo" {
+					t.Errorf("the base config should be loaded: %+v", cfg.Templates)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("error should be returned")
+			}
+		})
+	}
+}
@@ -1,26 +1,266 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/go-findconfig/findconfig"
 	"gopkg.in/yaml.v2"
 )
 
 // Config is for tfcmt config structure
 type Config struct {
-	CI               CI `yaml:"-"`
-	Terraform        Terraform
-	Vars             map[string]string `yaml:"-"`
-	EmbeddedVarNames []string          `yaml:"embedded_var_names"`
-	Templates        map[string]string
-	Log              Log
-	GHEBaseURL       string     `yaml:"ghe_base_url"`
-	GitHubToken      string     `yaml:"-"`
-	Complement       Complement `yaml:"ci"`
+	CI                  CI `yaml:"-"`
+	Terraform           Terraform
+	Vars                map[string]string `yaml:"-"`
+	EmbeddedVarNames    []string          `yaml:"embedded_var_names"`
+	Tag                 string
+	Timeout             string
+	Extends             string
+	ExtendsAllowHTTP    bool     `yaml:"extends_allow_http"`
+	TargetPRNumber      int      `yaml:"target_pr_number"`
+	IdempotencyKey      string   `yaml:"idempotency_key"`
+	TemplateEnvPrefixes []string `yaml:"template_env_prefixes"`
+	Templates           map[string]string
+	Log                 Log
+	GHEBaseURL          string     `yaml:"ghe_base_url"`
+	GHEUploadURL        string     `yaml:"ghe_upload_url"`
+	GitHubToken         string     `yaml:"-"`
+	GitHubApp           GitHubApp  `yaml:"github_app"`
+	GitLab              GitLab     `yaml:"gitlab"`
+	Gitea               Gitea      `yaml:"gitea"`
+	Complement          Complement `yaml:"ci"`
+	CostEstimate        string     `yaml:"cost_estimate"`
+	TFLint              string     `yaml:"tflint"`
+	SecurityScan        string     `yaml:"security_scan"`
+	Checkov             string     `yaml:"checkov"`
+	OldComment          OldComment `yaml:"old_comment"`
+	CommentCleanup      string     `yaml:"comment_cleanup"`
+	CheckRun            CheckRun   `yaml:"check_run"`
+	Metrics             Metrics
+	Gist                Gist
+	Truncate            Truncate
+	Storage             Storage
+	Deployment          Deployment
+	CommitStatus        CommitStatus `yaml:"commit_status"`
+	StepSummary         StepSummary  `yaml:"step_summary"`
+	AzureDevOps         AzureDevOps  `yaml:"azure_devops"`
+	CodeCommit          CodeCommit   `yaml:"codecommit"`
+	Notifier            string
+	Notifiers           []NotifierConfig
+	Slack               Slack
+	Teams               Teams
+	Discord             Discord
+	Webhook             Webhook
+	Datadog             Datadog
+	DryRun              bool   `yaml:"-"`
+	DryRunOutput        string `yaml:"-"`
+	CommentOutput       string `yaml:"-"`
+	ResultFormat        string `yaml:"-"`
+	ResultOutput        string `yaml:"-"`
+	TerraformOutputFile string `yaml:"-"`
+	PlanFile            string `yaml:"-"`
+	ExitCode            int    `yaml:"-"`
+}
+
+// OldComment is a configuration how to handle old comments of the same command and target
+type OldComment struct {
+	Action     string
+	Classifier string
+	Match      []string
+}
+
+// OldCommentAction returns how to handle old comments.
+// comment_cleanup is a shorthand of old_comment.action
+func (cfg *Config) OldCommentAction() string {
+	if cfg.OldComment.Action != "" {
+		return cfg.OldComment.Action
+	}
+	return cfg.CommentCleanup
+}
+
+// CheckRun is a configuration to create a GitHub check run with the result
+type CheckRun struct {
+	Enabled     bool
+	Name        string
+	SkipComment bool `yaml:"skip_comment"`
+}
+
+// CommitStatus is a configuration to set a GitHub commit status with the result
+type CommitStatus struct {
+	Enabled bool
+	Context string
+}
+
+// StepSummary is a configuration to append the result to the job summary of GitHub Actions
+type StepSummary struct {
+	Enabled bool
+}
+
+// Deployment is a configuration to create a GitHub deployment on terraform apply
+type Deployment struct {
+	Enabled     bool
+	Environment string
+}
+
+// GitHubApp is a configuration to authenticate as a GitHub App installation instead of a personal access token
+type GitHubApp struct {
+	AppID          int64  `yaml:"app_id"`
+	InstallationID int64  `yaml:"installation_id"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+	BaseURL        string `yaml:"base_url"`
+}
+
+// GitLab is a configuration of GitLab. The token is read from the environment variable GITLAB_TOKEN
+type GitLab struct {
+	BaseURL string `yaml:"base_url"`
+}
+
+// Gitea is a configuration of Gitea and Forgejo. The token is read from the environment variable GITEA_TOKEN
+type Gitea struct {
+	BaseURL string `yaml:"base_url"`
+}
+
+// AzureDevOps is a configuration of Azure DevOps.
+// The token is read from the environment variable SYSTEM_ACCESSTOKEN or AZURE_DEVOPS_EXT_PAT
+type AzureDevOps struct {
+	OrgURL string `yaml:"org_url"`
+}
+
+// CodeCommit is a configuration of AWS CodeCommit.
+// The credential is read from the environment variables or the container credential endpoint of CodeBuild
+type CodeCommit struct {
+	Region string
+}
+
+// NotifierConfig is a configuration of a notifier in notifiers.
+// The result is notified with all notifiers in order
+type NotifierConfig struct {
+	// Type is the name of the notifier such as "github" and "slack"
+	Type string
+	// When is a list of conditions to notify the result. If this is empty, all results are notified
+	When []string
+	// OnlyApplyFailure is a shorthand of `when: [apply_failure]`
+	OnlyApplyFailure bool `yaml:"only_apply_failure"`
+}
+
+// Triggers returns the conditions to notify the result
+func (n *NotifierConfig) Triggers() []string {
+	if n.OnlyApplyFailure {
+		return append([]string{"apply_failure"}, n.When...)
+	}
+	return n.When
+}
+
+// Slack is a configuration to send the result to Slack.
+// The webhook url and the bot token are read from the environment variables SLACK_WEBHOOK_URL and SLACK_BOT_TOKEN
+type Slack struct {
+	// Enabled sends the result to Slack in addition to the notifier
+	Enabled       bool
+	Channel       string
+	PlanTemplate  string `yaml:"plan_template"`
+	ApplyTemplate string `yaml:"apply_template"`
+	// When is a list of conditions to send the result. If this is empty, all results are sent
+	When []string
+}
+
+// Teams is a configuration to send the result to a Microsoft Teams channel.
+// The incoming webhook url is read from the environment variable TEAMS_WEBHOOK_URL
+type Teams struct {
+	// Enabled sends the result to Microsoft Teams in addition to the notifier
+	Enabled       bool
+	PlanTemplate  string `yaml:"plan_template"`
+	ApplyTemplate string `yaml:"apply_template"`
+	// When is a list of conditions to send the result. If this is empty, all results are sent
+	When []string
+}
+
+// Discord is a configuration to send the result to a Discord channel.
+// The webhook url is read from the environment variable DISCORD_WEBHOOK_URL
+type Discord struct {
+	// Enabled sends the result to Discord in addition to the notifier
+	Enabled       bool
+	PlanTemplate  string `yaml:"plan_template"`
+	ApplyTemplate string `yaml:"apply_template"`
+	// When is a list of conditions to send the result. If this is empty, apply failures and plans with destroyed resources are sent
+	When []string
+}
+
+// Datadog is a configuration to send the result to Datadog as an event.
+// The API key is read from the environment variable DD_API_KEY
+type Datadog struct {
+	// Enabled sends the result to Datadog in addition to the notifier
+	Enabled bool
+	// Site is the site of Datadog such as "datadoghq.eu". If this is empty, the environment variable DD_SITE or "datadoghq.com" is used
+	Site          string
+	PlanTemplate  string `yaml:"plan_template"`
+	ApplyTemplate string `yaml:"apply_template"`
+	// Tags are added to events in addition to the tags of template variables like "target:foo"
+	Tags []string
+	// When is a list of conditions to send the result. If this is empty, applies and apply failures are sent
+	When []string
+}
+
+// Webhook is a configuration to send the result to a HTTP endpoint as JSON.
+// The url and the secret to sign requests can also be set with the environment variables TFCMT_WEBHOOK_URL and TFCMT_WEBHOOK_SECRET
+type Webhook struct {
+	// Enabled sends the result to the webhook in addition to the notifier
+	Enabled bool
+	URL     string
+	// Headers are added to requests. Environment variables in the values are expanded
+	Headers map[string]string
+	// When is a list of conditions to send the result. If this is empty, all results are sent
+	When []string
+}
+
+// Metrics is a configuration to send metrics of notifications to StatsD, the OpenTelemetry collector, or the Prometheus Pushgateway
+type Metrics struct {
+	Sink     string
+	Endpoint string
+	Prefix   string
+}
+
+// Gist is a configuration to upload the result to a Gist if the comment is too large
+type Gist struct {
+	Enabled   bool
+	Threshold int
+	Public    bool
+	// PlanTemplate is the template of the compact comment of plan. The default is terraform.DefaultPlanGistTemplate
+	PlanTemplate string `yaml:"plan_template"`
+	// ApplyTemplate is the template of the compact comment of the other commands. The default is terraform.DefaultApplyGistTemplate
+	ApplyTemplate string `yaml:"apply_template"`
+}
+
+// Truncate is a configuration to shrink the comment if it is too large
+type Truncate struct {
+	Strategy     string
+	DropSections []string `yaml:"drop_sections"`
+	MaxLength    int      `yaml:"max_length"`
+}
+
+// Storage is a configuration to upload the whole output to S3 or GCS
+type Storage struct {
+	// Type is either "s3" or "gcs". If this is empty, the output isn't uploaded
+	Type   string
+	Bucket string
+	// Prefix is the template of the prefix of object keys. The default is "tfcmt"
+	Prefix string
+	// Command is the command to upload objects. The default is "aws" for S3 and "gcloud" for GCS
+	Command string
+	// PlanJSON is the file path of the plan JSON which is uploaded with the output. This is optional
+	PlanJSON string `yaml:"plan_json"`
+	// Presign makes the URL a presigned URL instead of the URL of the console
+	Presign bool
+	// ExpiresIn is the number of seconds until the presigned URL expires. The default is 3600
+	ExpiresIn int `yaml:"expires_in"`
 }
 
 type CI struct {
@@ -30,6 +270,7 @@ type CI struct {
 	SHA      string
 	Link     string
 	PRNumber int
+	Branch   string
 }
 
 type Log struct {
@@ -41,18 +282,109 @@ type Log struct {
 type Terraform struct {
 	Plan         Plan
 	Apply        Apply
+	Test         Test
+	Validate     Validate
+	Fmt          Fmt
+	Destroy      Destroy
 	UseRawOutput bool `yaml:"use_raw_output"`
+	// DisableOutputNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableOutputNormalization bool `yaml:"disable_output_normalization"`
+	// OutputFormat is the format of the output of terraform command, either "text", "json", "cdktf", or "pulumi".
+	// "json" is the output of terraform plan -json and terraform apply -json, and "cdktf" is the output of cdktf diff and cdktf deploy.
+	// "pulumi" is the output of pulumi preview and pulumi up with or without --json
+	OutputFormat string `yaml:"output_format"`
+	// Product is either "terraform" or "opentofu". If this is empty, the product is detected from the command name and the output
+	Product string
 }
 
+const (
+	ProductTerraform = "terraform"
+	ProductOpenTofu  = "opentofu"
+)
+
 // Plan is a terraform plan config
 type Plan struct {
-	Template            string
-	WhenAddOrUpdateOnly WhenAddOrUpdateOnly `yaml:"when_add_or_update_only"`
-	WhenDestroy         WhenDestroy         `yaml:"when_destroy"`
-	WhenNoChanges       WhenNoChanges       `yaml:"when_no_changes"`
-	WhenPlanError       WhenPlanError       `yaml:"when_plan_error"`
-	WhenParseError      WhenParseError      `yaml:"when_parse_error"`
-	DisableLabel        bool                `yaml:"disable_label"`
+	Template             string
+	WhenAddOrUpdateOnly  WhenAddOrUpdateOnly `yaml:"when_add_or_update_only"`
+	WhenDestroy          WhenDestroy         `yaml:"when_destroy"`
+	WhenNoChanges        WhenNoChanges       `yaml:"when_no_changes"`
+	WhenPlanError        WhenPlanError       `yaml:"when_plan_error"`
+	WhenReplace          WhenReplace         `yaml:"when_replace"`
+	WhenImportOnly       WhenImportOnly      `yaml:"when_import_only"`
+	WhenDriftDetected    WhenDriftDetected   `yaml:"when_drift_detected"`
+	WhenCheckFailed      WhenCheckFailed     `yaml:"when_check_failed"`
+	WhenCriticalFinding  WhenCriticalFinding `yaml:"when_critical_finding"`
+	WhenParseError       WhenParseError      `yaml:"when_parse_error"`
+	DisableLabel         bool                `yaml:"disable_label"`
+	LabelPrefix          string              `yaml:"label_prefix"`
+	PreservedLabels      []string            `yaml:"preserved_labels"`
+	SkipDuplicateComment bool                `yaml:"skip_duplicate_comment"`
+	IgnoredResources     []string            `yaml:"ignored_resources"`
+	MaxResources         int                 `yaml:"max_resources"`
+	WhenPRClosed         string              `yaml:"when_pr_closed"`
+	SourceMap            string              `yaml:"source_map"`
+	SummaryPosition      string              `yaml:"summary_position"`
+	OnlyWhenFailed       OnlyWhenFailed      `yaml:"only_when_failed"`
+	InlineComments       InlineComments      `yaml:"inline_comments"`
+	DriftIssue           DriftIssue          `yaml:"drift_issue"`
+	Review               Review
+	Policy               Policy
+	// TerragruntRunAll is true if the output is of terragrunt run-all plan. The output is parsed per module
+	TerragruntRunAll bool `yaml:"terragrunt_run_all"`
+	// MultiDir runs the command in multiple directories and posts a single comment
+	MultiDir MultiDir `yaml:"multi_dir"`
+}
+
+// MultiDir is a configuration to run the command in each directory matching Targets.
+// The results are aggregated as the results of modules of terragrunt run-all plan
+type MultiDir struct {
+	// Targets are glob patterns of directories such as "envs/*"
+	Targets []string
+	// Parallelism is the maximum number of directories where the command runs concurrently. The default is 1
+	Parallelism int
+}
+
+// Policy is a configuration to evaluate Rego policies against the plan JSON with conftest
+type Policy struct {
+	Enabled bool
+	// Command is the command of conftest. The default is "conftest"
+	Command string
+	// Paths are the directories of policies. The default is "policy"
+	Paths []string
+	// PlanJSON is the file path of the plan JSON to evaluate policies. This is optional
+	PlanJSON string `yaml:"plan_json"`
+	// Fail makes tfcmt exit with a non-zero exit code if any policy fails
+	Fail  bool
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// OnlyWhenFailed is a configuration to post the plan result only if the result matches any of the triggers.
+// The default triggers are "plan_error", "destroy", and "parse_error"
+type OnlyWhenFailed struct {
+	Enabled  bool
+	Triggers []string
+}
+
+// InlineComments is a configuration to post review comments on the resource blocks changed by the plan.
+// The resource blocks are found by the source map
+type InlineComments struct {
+	Enabled bool
+}
+
+// DriftIssue is a configuration to manage a GitHub issue per target which tracks the drift.
+// This is used when the plan is run on a schedule against the default branch
+type DriftIssue struct {
+	Enabled bool
+	Title   string
+	Labels  []string
+}
+
+// Review is a configuration to post the plan result as a pull request review
+type Review struct {
+	Enabled          bool
+	Event            string
+	EventWhenDestroy string `yaml:"event_when_destroy"`
 }
 
 // WhenAddOrUpdateOnly is a configuration to notify the plan result contains new or updated in place resources
@@ -63,8 +395,15 @@ type WhenAddOrUpdateOnly struct {
 
 // WhenDestroy is a configuration to notify the plan result contains destroy operation
 type WhenDestroy struct {
-	Label string
-	Color string `yaml:"label_color"`
+	Label         string
+	Color         string `yaml:"label_color"`
+	Fail          bool
+	FailThreshold int `yaml:"fail_threshold"`
+}
+
+// WhenCriticalFinding is a configuration when the security scan result contains critical findings
+type WhenCriticalFinding struct {
+	Fail bool
 }
 
 // WhenNoChanges is a configuration to add a label when the plan result contains no change
@@ -77,6 +416,32 @@ type WhenNoChanges struct {
 type WhenPlanError struct {
 	Label string
 	Color string `yaml:"label_color"`
+	// CategoryLabels maps error categories to labels. The key is "state_lock", "backend", "provider", "config", or "unknown"
+	CategoryLabels map[string]string `yaml:"category_labels"`
+}
+
+// WhenReplace is a configuration to add a label when the plan result contains replaced resources
+type WhenReplace struct {
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// WhenImportOnly is a configuration to add a label when the plan result only imports resources
+type WhenImportOnly struct {
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// WhenDriftDetected is a configuration to add a label when terraform plan -refresh-only detects the drift
+type WhenDriftDetected struct {
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// WhenCheckFailed is a configuration to add a label when any check block assertion, precondition, or postcondition fails
+type WhenCheckFailed struct {
+	Label string
+	Color string `yaml:"label_color"`
 }
 
 // WhenParseError is a configuration to notify the plan result returns an error
@@ -86,35 +451,479 @@ type WhenParseError struct {
 
 // Apply is a terraform apply config
 type Apply struct {
+	Template             string
+	WhenParseError       WhenParseError   `yaml:"when_parse_error"`
+	WhenSuccess          WhenApplySuccess `yaml:"when_success"`
+	WhenFailure          WhenApplyFailure `yaml:"when_failure"`
+	SkipDuplicateComment bool             `yaml:"skip_duplicate_comment"`
+}
+
+// Destroy is a terraform destroy config. Labels replace the labels of the plan result as well as Apply
+type Destroy struct {
+	Template       string
+	WhenParseError WhenParseError     `yaml:"when_parse_error"`
+	WhenSuccess    WhenDestroySuccess `yaml:"when_success"`
+	WhenFailure    WhenDestroyFailure `yaml:"when_failure"`
+}
+
+// WhenDestroySuccess is a configuration to replace the label of the plan result when terraform destroy succeeds
+type WhenDestroySuccess struct {
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// WhenDestroyFailure is a configuration to replace the label of the plan result when terraform destroy fails
+type WhenDestroyFailure struct {
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// Test is a terraform test config
+type Test struct {
 	Template       string
 	WhenParseError WhenParseError `yaml:"when_parse_error"`
 }
 
-// LoadFile binds the config file to Config structure
+// Validate is a terraform validate config
+type Validate struct {
+	Template       string
+	WhenParseError WhenParseError      `yaml:"when_parse_error"`
+	WhenFailure    WhenValidateFailure `yaml:"when_failure"`
+	// Annotations outputs diagnostics as workflow commands of GitHub Actions so that they are shown as annotations of the pull request
+	Annotations bool
+}
+
+// WhenValidateFailure is a configuration to add a label when terraform validate fails. The label is removed when terraform validate succeeds
+type WhenValidateFailure struct {
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// Fmt is a terraform fmt config
+type Fmt struct {
+	Template string
+	// Suggestion posts suggested changes of terraform fmt -diff as review comments
+	Suggestion FmtSuggestion
+}
+
+// FmtSuggestion is a configuration to post suggested changes of terraform fmt
+type FmtSuggestion struct {
+	Enabled bool
+	// Dir is the directory where terraform fmt is run from the repository root
+	Dir string
+}
+
+// WhenApplySuccess is a configuration to replace the label of the plan result when terraform apply succeeds
+type WhenApplySuccess struct {
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// WhenApplyFailure is a configuration to replace the label of the plan result when terraform apply fails
+type WhenApplyFailure struct {
+	Label string
+	Color string `yaml:"label_color"`
+}
+
+// LoadFile binds the config file to Config structure.
+// If the config file has `extends`, the base config is loaded first and the config file overrides it
 func (cfg *Config) LoadFile(path string) error {
+	return cfg.loadFile(path, newBaseConfigLoader())
+}
+
+func (cfg *Config) loadFile(path string, loader *baseConfigLoader) error {
 	if _, err := os.Stat(path); err != nil {
 		return fmt.Errorf("%s: no config file", path)
 	}
 	raw, _ := ioutil.ReadFile(path)
+	base := struct {
+		Extends          string
+		ExtendsAllowHTTP bool `yaml:"extends_allow_http"`
+	}{}
+	if err := yaml.Unmarshal(raw, &base); err == nil && base.Extends != "" {
+		if isInsecureURL(base.Extends) && !base.ExtendsAllowHTTP {
+			return fmt.Errorf("the base config must be fetched with https. To fetch it with http, set extends_allow_http to true: %s", base.Extends)
+		}
+		if err := cfg.loadBaseConfig(loader, base.Extends, filepath.Dir(path)); err != nil {
+			return err
+		}
+	}
 	return yaml.Unmarshal(raw, cfg)
 }
 
+// loadBaseConfig loads the base config.
+// If it fails to read the base config, the error is logged and only the config file and the default configuration are used.
+// If it fails to parse the base config, the error is returned because the base config may have been applied partially.
+// `extends` of the base config is ignored
+func (cfg *Config) loadBaseConfig(loader *baseConfigLoader, src, dir string) error {
+	b, err := loader.read(context.Background(), src, dir)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program":     "tfcmt",
+			"base_config": src,
+		}).WithError(err).Warn("read the base config")
+		return nil
+	}
+	if err := yaml.Unmarshal(b, cfg); err != nil {
+		return fmt.Errorf("parse the base config %s: %w", src, err)
+	}
+	return nil
+}
+
 // Validate validates config file
 func (cfg *Config) Validate() error {
-	if cfg.CI.Owner == "" {
-		return errors.New("repository owner is missing")
+	// The dry run mode doesn't post the comment, so the repository and the pull request aren't needed.
+	// This allows rendering the comment on a local machine.
+	// Chat services, the webhook, and Datadog don't need them either
+	needsRepo := !cfg.DryRun && cfg.usesRepository()
+	if needsRepo {
+		if cfg.CI.Owner == "" {
+			return errors.New("repository owner is missing")
+		}
+
+		if cfg.CI.Repo == "" {
+			return errors.New("repository name is missing")
+		}
+	}
+
+	if cfg.TargetPRNumber < 0 {
+		return errors.New("target_pr_number must not be negative")
+	}
+
+	if needsRepo && cfg.CI.SHA == "" && cfg.CI.PRNumber <= 0 && cfg.CI.Branch == "" && cfg.TargetPRNumber == 0 {
+		return errors.New("pull request number, SHA (revision), or branch is needed")
+	}
+
+	if err := validateStdinInputs(cfg); err != nil {
+		return err
+	}
+
+	for _, event := range []string{cfg.Terraform.Plan.Review.Event, cfg.Terraform.Plan.Review.EventWhenDestroy} {
+		switch event {
+		case "", "COMMENT", "REQUEST_CHANGES":
+		default:
+			return errors.New(`the review event must be either "COMMENT" or "REQUEST_CHANGES": ` + event)
+		}
+	}
+
+	switch cfg.OldComment.Action {
+	case "", "keep", "minimize", "delete":
+	default:
+		return errors.New(`old_comment.action must be either "keep", "minimize", or "delete": ` + cfg.OldComment.Action)
+	}
+
+	switch cfg.ResultFormat {
+	case "", "json":
+	default:
+		return errors.New(`--result-format must be "json": ` + cfg.ResultFormat)
+	}
+
+	switch cfg.CommentCleanup {
+	case "", "keep", "minimize", "delete":
+	default:
+		return errors.New(`comment_cleanup must be either "keep", "minimize", or "delete": ` + cfg.CommentCleanup)
+	}
+
+	if cfg.CommentCleanup != "" && cfg.OldComment.Action != "" && cfg.CommentCleanup != cfg.OldComment.Action {
+		return errors.New("comment_cleanup and old_comment.action must not be different")
+	}
+
+	switch cfg.OldComment.Classifier {
+	case "", "OUTDATED", "RESOLVED", "DUPLICATE", "OFF_TOPIC":
+	default:
+		return errors.New(`old_comment.classifier must be either "OUTDATED", "RESOLVED", "DUPLICATE", or "OFF_TOPIC": ` + cfg.OldComment.Classifier)
+	}
+
+	for _, match := range cfg.OldComment.Match {
+		switch match {
+		case "same_command", "same_target", "older_sha":
+		default:
+			return errors.New(`old_comment.match must be "same_command", "same_target", or "older_sha": ` + match)
+		}
+	}
+
+	switch cfg.Terraform.Plan.WhenPRClosed {
+	case "", "post", "skip", "merge_commit":
+	default:
+		return errors.New(`terraform.plan.when_pr_closed must be either "post", "skip", or "merge_commit": ` + cfg.Terraform.Plan.WhenPRClosed)
+	}
+
+	for category := range cfg.Terraform.Plan.WhenPlanError.CategoryLabels {
+		switch category {
+		case "state_lock", "backend", "provider", "config", "unknown":
+		default:
+			return errors.New(`keys of terraform.plan.when_plan_error.category_labels must be "state_lock", "backend", "provider", "config", or "unknown": ` + category)
+		}
+	}
+
+	switch cfg.Terraform.Plan.SummaryPosition {
+	case "", "top", "bottom":
+	default:
+		return errors.New(`terraform.plan.summary_position must be either "top" or "bottom": ` + cfg.Terraform.Plan.SummaryPosition)
+	}
+
+	for _, trigger := range cfg.Terraform.Plan.OnlyWhenFailed.Triggers {
+		switch trigger {
+		case "plan_error", "parse_error", "destroy", "replace", "add_or_update", "no_changes", "import":
+		default:
+			return errors.New(`terraform.plan.only_when_failed.triggers must be "plan_error", "parse_error", "destroy", "replace", "add_or_update", "no_changes", or "import": ` + trigger)
+		}
+	}
+
+	switch cfg.Terraform.Product {
+	case "", ProductTerraform, ProductOpenTofu:
+	default:
+		return errors.New(`terraform.product must be either "terraform" or "opentofu": ` + cfg.Terraform.Product)
+	}
+
+	switch cfg.Terraform.OutputFormat {
+	case "", "text", "json", "cdktf", "pulumi":
+	default:
+		return errors.New(`terraform.output_format must be "text", "json", "cdktf", or "pulumi": ` + cfg.Terraform.OutputFormat)
+	}
+
+	if cfg.Terraform.Plan.TerragruntRunAll && cfg.Terraform.OutputFormat != "" && cfg.Terraform.OutputFormat != "text" {
+		return errors.New(`terraform.plan.terragrunt_run_all can't be used with terraform.output_format "` + cfg.Terraform.OutputFormat + `"`)
+	}
+
+	if len(cfg.Terraform.Plan.MultiDir.Targets) != 0 {
+		if cfg.Terraform.Plan.TerragruntRunAll {
+			return errors.New("terraform.plan.multi_dir can't be used with terraform.plan.terragrunt_run_all")
+		}
+		if cfg.Terraform.OutputFormat != "" && cfg.Terraform.OutputFormat != "text" {
+			return errors.New(`terraform.plan.multi_dir can't be used with terraform.output_format "` + cfg.Terraform.OutputFormat + `"`)
+		}
+		for _, target := range cfg.Terraform.Plan.MultiDir.Targets {
+			if _, err := filepath.Match(target, ""); err != nil {
+				return fmt.Errorf("terraform.plan.multi_dir.targets is invalid: %w", err)
+			}
+		}
+	}
+
+	if cfg.Terraform.Plan.MultiDir.Parallelism < 0 {
+		return errors.New("terraform.plan.multi_dir.parallelism must not be negative")
+	}
+
+	if cfg.Terraform.Plan.MaxResources < 0 {
+		return errors.New("terraform.plan.max_resources must not be negative")
+	}
+
+	if cfg.Gist.Threshold < 0 {
+		return errors.New("gist.threshold must not be negative")
+	}
+
+	if err := cfg.Truncate.validate(); err != nil {
+		return fmt.Errorf("truncate is invalid: %w", err)
+	}
+
+	if err := cfg.Storage.validate(); err != nil {
+		return fmt.Errorf("storage is invalid: %w", err)
+	}
+
+	if cfg.CommitStatus.Context != "" {
+		if _, err := template.New("context").Parse(cfg.CommitStatus.Context); err != nil {
+			return fmt.Errorf("commit_status.context is invalid: %w", err)
+		}
+	}
+
+	if cfg.Deployment.Environment != "" {
+		if _, err := template.New("environment").Parse(cfg.Deployment.Environment); err != nil {
+			return fmt.Errorf("deployment.environment is invalid: %w", err)
+		}
 	}
 
-	if cfg.CI.Repo == "" {
-		return errors.New("repository name is missing")
+	if cfg.Terraform.Plan.DriftIssue.Title != "" {
+		if _, err := template.New("title").Parse(cfg.Terraform.Plan.DriftIssue.Title); err != nil {
+			return fmt.Errorf("terraform.plan.drift_issue.title is invalid: %w", err)
+		}
 	}
 
-	if cfg.CI.SHA == "" && cfg.CI.PRNumber <= 0 {
-		return errors.New("pull request number or SHA (revision) is needed")
+	if cfg.Timeout != "" {
+		if _, err := time.ParseDuration(cfg.Timeout); err != nil {
+			return fmt.Errorf("timeout is invalid: %w", err)
+		}
+	}
+
+	if cfg.Notifier != "" {
+		if err := validateNotifier(cfg.Notifier); err != nil {
+			return err
+		}
+	}
+
+	if err := validateNotifiers(cfg.Notifier, cfg.Notifiers); err != nil {
+		return err
+	}
+
+	for _, trigger := range cfg.Slack.When {
+		if err := validateTrigger(trigger); err != nil {
+			return fmt.Errorf("slack.when is invalid: %w", err)
+		}
+	}
+
+	for _, trigger := range cfg.Teams.When {
+		if err := validateTrigger(trigger); err != nil {
+			return fmt.Errorf("teams.when is invalid: %w", err)
+		}
+	}
+
+	for _, trigger := range cfg.Discord.When {
+		if err := validateTrigger(trigger); err != nil {
+			return fmt.Errorf("discord.when is invalid: %w", err)
+		}
+	}
+
+	for _, trigger := range cfg.Webhook.When {
+		if err := validateTrigger(trigger); err != nil {
+			return fmt.Errorf("webhook.when is invalid: %w", err)
+		}
+	}
+
+	for _, trigger := range cfg.Datadog.When {
+		if err := validateTrigger(trigger); err != nil {
+			return fmt.Errorf("datadog.when is invalid: %w", err)
+		}
+	}
+
+	switch cfg.Metrics.Sink {
+	case "", "statsd", "otlp", "pushgateway":
+	default:
+		return errors.New(`metrics.sink must be "statsd", "otlp", or "pushgateway": ` + cfg.Metrics.Sink)
+	}
+	return nil
+}
+
+// validateStdinInputs validates that at most one input is read from the standard input.
+// The standard input can be read only once, so the other inputs would be empty
+func validateStdinInputs(cfg *Config) error {
+	var fields []string
+	for _, input := range []struct {
+		field string
+		value string
+	}{
+		{field: "cost_estimate", value: cfg.CostEstimate},
+		{field: "tflint", value: cfg.TFLint},
+		{field: "security_scan", value: cfg.SecurityScan},
+		{field: "checkov", value: cfg.Checkov},
+	} {
+		if input.value == "-" {
+			fields = append(fields, input.field)
+		}
+	}
+	if len(fields) > 1 {
+		return errors.New("only one input can be read from the standard input: " + strings.Join(fields, ", "))
 	}
 	return nil
 }
 
+// validateNotifier validates the name of the notifier
+func validateNotifier(name string) error {
+	switch name {
+	case "github", "gitlab", "gitea", "bitbucket", "azure-devops", "codecommit", "slack", "teams", "discord", "webhook", "datadog":
+		return nil
+	default:
+		return errors.New(`notifier must be "github", "gitlab", "gitea", "bitbucket", "azure-devops", "codecommit", "slack", "teams", "discord", "webhook", or "datadog": ` + name)
+	}
+}
+
+// usesRepository returns true if the result is posted to a repository hosting service such as GitHub.
+// If neither notifier nor notifiers is set, the notifier is decided by the CI platform and it's always a repository hosting service
+func (cfg *Config) usesRepository() bool {
+	if len(cfg.Notifiers) != 0 {
+		for _, ntf := range cfg.Notifiers {
+			if isRepositoryNotifier(ntf.Type) {
+				return true
+			}
+		}
+		return false
+	}
+	return cfg.Notifier == "" || isRepositoryNotifier(cfg.Notifier)
+}
+
+// isRepositoryNotifier returns true if the notifier posts the result to a repository
+func isRepositoryNotifier(name string) bool {
+	switch name {
+	case "github", "gitlab", "gitea", "bitbucket", "azure-devops", "codecommit":
+		return true
+	default:
+		return false
+	}
+}
+
+// validateNotifiers validates notifiers. Each notifier can be used only once
+func validateNotifiers(notifier string, notifiers []NotifierConfig) error {
+	if len(notifiers) == 0 {
+		return nil
+	}
+	if notifier != "" {
+		return errors.New("notifier and notifiers can't be used at the same time")
+	}
+	types := make(map[string]struct{}, len(notifiers))
+	for i, ntf := range notifiers {
+		if err := validateNotifier(ntf.Type); err != nil {
+			return fmt.Errorf("notifiers[%d].type is invalid: %w", i, err)
+		}
+		if _, ok := types[ntf.Type]; ok {
+			return fmt.Errorf("notifiers[%d].type is duplicated: %s", i, ntf.Type)
+		}
+		types[ntf.Type] = struct{}{}
+		for _, trigger := range ntf.When {
+			if err := validateTrigger(trigger); err != nil {
+				return fmt.Errorf("notifiers[%d].when is invalid: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTrigger validates the condition to notify the result
+func (truncate *Truncate) validate() error {
+	switch truncate.Strategy {
+	case "", "head", "tail", "head_tail":
+	default:
+		return errors.New(`strategy must be "head", "tail", or "head_tail": ` + truncate.Strategy)
+	}
+	for _, section := range truncate.DropSections {
+		switch section {
+		case "ChangedResult", "ChangeOutsideTerraform", "Warning", "Result", "Stdout", "Stderr", "CombinedOutput":
+		default:
+			return errors.New(`drop_sections must be "ChangedResult", "ChangeOutsideTerraform", "Warning", "Result", "Stdout", "Stderr", or "CombinedOutput": ` + section)
+		}
+	}
+	if truncate.MaxLength < 0 {
+		return errors.New("max_length must not be negative")
+	}
+	return nil
+}
+
+func (storage *Storage) validate() error {
+	switch storage.Type {
+	case "":
+		return nil
+	case "s3", "gcs":
+	default:
+		return errors.New(`type must be either "s3" or "gcs": ` + storage.Type)
+	}
+	if storage.Bucket == "" {
+		return errors.New("bucket is required")
+	}
+	if _, err := template.New("prefix").Parse(storage.Prefix); err != nil {
+		return fmt.Errorf("prefix is invalid: %w", err)
+	}
+	if storage.ExpiresIn < 0 {
+		return errors.New("expires_in must not be negative")
+	}
+	return nil
+}
+
+func validateTrigger(trigger string) error {
+	switch trigger {
+	case "plan_error", "parse_error", "destroy", "replace", "add_or_update", "no_changes", "import", "apply_success", "apply_failure":
+		return nil
+	default:
+		return errors.New(`the condition must be "plan_error", "parse_error", "destroy", "replace", "add_or_update", "no_changes", "import", "apply_success", or "apply_failure": ` + trigger)
+	}
+}
+
 // Find returns config path
 func (cfg *Config) Find(file string) (string, error) {
 	if file != "" {
@@ -222,3 +222,661 @@ func TestFind(t *testing.T) { //nolint:paralleltest
 		defer removeDummy(testCase.file)
 	}
 }
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	validCI := CI{
+		Owner:    "suzuki-shunsuke",
+		Repo:     "tfcmt",
+		PRNumber: 1,
+	}
+	testCases := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{
+		{
+			name: "normal",
+			cfg: Config{
+				CI: validCI,
+			},
+			ok: true,
+		},
+		{
+			name: "owner is missing",
+			cfg: Config{
+				CI: CI{
+					Repo:     "tfcmt",
+					PRNumber: 1,
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "chat notifier without repository",
+			cfg: Config{
+				Notifier: "slack",
+			},
+			ok: true,
+		},
+		{
+			name: "chat notifiers without repository",
+			cfg: Config{
+				Notifiers: []NotifierConfig{
+					{Type: "webhook"},
+					{Type: "datadog"},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "repository notifier in notifiers without repository",
+			cfg: Config{
+				Notifiers: []NotifierConfig{
+					{Type: "slack"},
+					{Type: "gitlab"},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "only branch",
+			cfg: Config{
+				CI: CI{
+					Owner:  "suzuki-shunsuke",
+					Repo:   "tfcmt",
+					Branch: "feature",
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "only target pull request number",
+			cfg: Config{
+				CI: CI{
+					Owner: "suzuki-shunsuke",
+					Repo:  "tfcmt",
+				},
+				TargetPRNumber: 10,
+			},
+			ok: true,
+		},
+		{
+			name: "neither pull request number, sha, nor branch",
+			cfg: Config{
+				CI: CI{
+					Owner: "suzuki-shunsuke",
+					Repo:  "tfcmt",
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "the repository and the pull request aren't needed in the dry run mode",
+			cfg: Config{
+				DryRun: true,
+			},
+			ok: true,
+		},
+		{
+			name: "result format is invalid",
+			cfg: Config{
+				CI:           validCI,
+				ResultFormat: "yaml",
+			},
+			ok: false,
+		},
+		{
+			name: "old_comment.action is minimize",
+			cfg: Config{
+				CI: validCI,
+				OldComment: OldComment{
+					Action: "minimize",
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "max_resources is negative",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						MaxResources: -1,
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "invalid category of category_labels",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						WhenPlanError: WhenPlanError{
+							CategoryLabels: map[string]string{
+								"lock": "state-lock",
+							},
+						},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "invalid summary_position",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						SummaryPosition: "middle",
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "invalid output_format",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					OutputFormat: "yaml",
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "terragrunt_run_all with output_format json",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					OutputFormat: "json",
+					Plan: Plan{
+						TerragruntRunAll: true,
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "output_format cdktf",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					OutputFormat: "cdktf",
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "output_format pulumi",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					OutputFormat: "pulumi",
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "product",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Product: "opentofu",
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "invalid product",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Product: "tofu",
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "when_pr_closed",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						WhenPRClosed: "merge_commit",
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "invalid when_pr_closed",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						WhenPRClosed: "comment",
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "invalid only_when_failed trigger",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						OnlyWhenFailed: OnlyWhenFailed{
+							Enabled:  true,
+							Triggers: []string{"destroy", "warning"},
+						},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "terraform.plan.multi_dir",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						MultiDir: MultiDir{Targets: []string{"envs/*"}, Parallelism: 2},
+					},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "terraform.plan.multi_dir with terragrunt_run_all",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						TerragruntRunAll: true,
+						MultiDir:         MultiDir{Targets: []string{"envs/*"}},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "terraform.plan.multi_dir.targets is invalid",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						MultiDir: MultiDir{Targets: []string{"envs/["}},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "gist.threshold is negative",
+			cfg: Config{
+				CI:   validCI,
+				Gist: Gist{Threshold: -1},
+			},
+			ok: false,
+		},
+		{
+			name: "truncate",
+			cfg: Config{
+				CI:       validCI,
+				Truncate: Truncate{Strategy: "head_tail", DropSections: []string{"ChangeOutsideTerraform"}},
+			},
+			ok: true,
+		},
+		{
+			name: "storage",
+			cfg: Config{
+				CI:      validCI,
+				Storage: Storage{Type: "s3", Bucket: "tfcmt-artifacts", Prefix: "{{.Vars.target}}"},
+			},
+			ok: true,
+		},
+		{
+			name: "storage.type is invalid",
+			cfg: Config{
+				CI:      validCI,
+				Storage: Storage{Type: "azure", Bucket: "tfcmt-artifacts"},
+			},
+			ok: false,
+		},
+		{
+			name: "storage.bucket is required",
+			cfg: Config{
+				CI:      validCI,
+				Storage: Storage{Type: "gcs"},
+			},
+			ok: false,
+		},
+		{
+			name: "truncate.strategy is invalid",
+			cfg: Config{
+				CI:       validCI,
+				Truncate: Truncate{Strategy: "middle"},
+			},
+			ok: false,
+		},
+		{
+			name: "truncate.drop_sections is invalid",
+			cfg: Config{
+				CI:       validCI,
+				Truncate: Truncate{DropSections: []string{"Link"}},
+			},
+			ok: false,
+		},
+		{
+			name: "commit_status.context",
+			cfg: Config{
+				CI:           validCI,
+				CommitStatus: CommitStatus{Enabled: true, Context: "terraform/{{.Command}}/{{.Target}}"},
+			},
+			ok: true,
+		},
+		{
+			name: "commit_status.context is invalid",
+			cfg: Config{
+				CI:           validCI,
+				CommitStatus: CommitStatus{Enabled: true, Context: "terraform/{{.Command"},
+			},
+			ok: false,
+		},
+		{
+			name: "terraform.plan.drift_issue.title is invalid",
+			cfg: Config{
+				CI: validCI,
+				Terraform: Terraform{
+					Plan: Plan{
+						DriftIssue: DriftIssue{Enabled: true, Title: "Drift ({{.Target)"},
+					},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "comment_cleanup",
+			cfg: Config{
+				CI:             validCI,
+				CommentCleanup: "delete",
+			},
+			ok: true,
+		},
+		{
+			name: "comment_cleanup is invalid",
+			cfg: Config{
+				CI:             validCI,
+				CommentCleanup: "hide",
+			},
+			ok: false,
+		},
+		{
+			name: "comment_cleanup conflicts with old_comment.action",
+			cfg: Config{
+				CI:             validCI,
+				CommentCleanup: "delete",
+				OldComment:     OldComment{Action: "minimize"},
+			},
+			ok: false,
+		},
+		{
+			name: "old_comment.match",
+			cfg: Config{
+				CI:         validCI,
+				OldComment: OldComment{Action: "minimize", Classifier: "RESOLVED", Match: []string{"same_command", "same_target", "older_sha"}},
+			},
+			ok: true,
+		},
+		{
+			name: "old_comment.match is invalid",
+			cfg: Config{
+				CI:         validCI,
+				OldComment: OldComment{Action: "minimize", Match: []string{"same_branch"}},
+			},
+			ok: false,
+		},
+		{
+			name: "old_comment.classifier is invalid",
+			cfg: Config{
+				CI:         validCI,
+				OldComment: OldComment{Action: "minimize", Classifier: "outdated"},
+			},
+			ok: false,
+		},
+		{
+			name: "timeout",
+			cfg: Config{
+				CI:      validCI,
+				Timeout: "90s",
+			},
+			ok: true,
+		},
+		{
+			name: "timeout is invalid",
+			cfg: Config{
+				CI:      validCI,
+				Timeout: "5",
+			},
+			ok: false,
+		},
+		{
+			name: "old_comment.action is invalid",
+			cfg: Config{
+				CI: validCI,
+				OldComment: OldComment{
+					Action: "hide",
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "notifier is gitlab",
+			cfg: Config{
+				CI:       validCI,
+				Notifier: "gitlab",
+			},
+			ok: true,
+		},
+		{
+			name: "notifier is gitea",
+			cfg: Config{
+				CI:       validCI,
+				Notifier: "gitea",
+			},
+			ok: true,
+		},
+		{
+			name: "notifier is azure-devops",
+			cfg: Config{
+				CI:       validCI,
+				Notifier: "azure-devops",
+			},
+			ok: true,
+		},
+		{
+			name: "notifier is codecommit",
+			cfg: Config{
+				CI:       validCI,
+				Notifier: "codecommit",
+			},
+			ok: true,
+		},
+		{
+			name: "notifier is invalid",
+			cfg: Config{
+				CI:       validCI,
+				Notifier: "unknown",
+			},
+			ok: false,
+		},
+		{
+			name: "slack.when",
+			cfg: Config{
+				CI: validCI,
+				Slack: Slack{
+					When: []string{"apply_failure", "destroy"},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "slack.when is invalid",
+			cfg: Config{
+				CI: validCI,
+				Slack: Slack{
+					When: []string{"apply_error"},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "teams.when is invalid",
+			cfg: Config{
+				CI: validCI,
+				Teams: Teams{
+					When: []string{"destroyed"},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "discord.when is invalid",
+			cfg: Config{
+				CI: validCI,
+				Discord: Discord{
+					When: []string{"apply_error"},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "datadog.when is invalid",
+			cfg: Config{
+				CI: validCI,
+				Datadog: Datadog{
+					When: []string{"applied"},
+				},
+			},
+			ok: false,
+		},
+		{
+			name: "notifiers",
+			cfg: Config{
+				CI: validCI,
+				Notifiers: []NotifierConfig{
+					{Type: "github"},
+					{Type: "slack", OnlyApplyFailure: true},
+					{Type: "webhook", When: []string{"destroy"}},
+				},
+			},
+			ok: true,
+		},
+		{
+			name: "notifiers with notifier",
+			cfg: Config{
+				CI:        validCI,
+				Notifier:  "github",
+				Notifiers: []NotifierConfig{{Type: "slack"}},
+			},
+			ok: false,
+		},
+		{
+			name: "notifiers are duplicated",
+			cfg: Config{
+				CI:        validCI,
+				Notifiers: []NotifierConfig{{Type: "slack"}, {Type: "slack"}},
+			},
+			ok: false,
+		},
+		{
+			name: "notifiers[].type is invalid",
+			cfg: Config{
+				CI:        validCI,
+				Notifiers: []NotifierConfig{{Type: "email"}},
+			},
+			ok: false,
+		},
+		{
+			name: "notifiers[].when is invalid",
+			cfg: Config{
+				CI:        validCI,
+				Notifiers: []NotifierConfig{{Type: "slack", When: []string{"failure"}}},
+			},
+			ok: false,
+		},
+		{
+			name: "an input is read from the standard input",
+			cfg: Config{
+				CI:           validCI,
+				CostEstimate: "-",
+				TFLint:       "tflint.json",
+			},
+			ok: true,
+		},
+		{
+			name: "multiple inputs are read from the standard input",
+			cfg: Config{
+				CI:           validCI,
+				CostEstimate: "-",
+				Checkov:      "-",
+			},
+			ok: false,
+		},
+		{
+			name: "webhook.when is invalid",
+			cfg: Config{
+				CI: validCI,
+				Webhook: Webhook{
+					When: []string{"plan_failure"},
+				},
+			},
+			ok: false,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if err := testCase.cfg.Validate(); (err == nil) != testCase.ok {
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigOldCommentAction(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		cfg  Config
+		exp  string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name: "comment_cleanup",
+			cfg:  Config{CommentCleanup: "delete"},
+			exp:  "delete",
+		},
+		{
+			name: "old_comment.action",
+			cfg:  Config{OldComment: OldComment{Action: "minimize"}},
+			exp:  "minimize",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if action := testCase.cfg.OldCommentAction(); action != testCase.exp {
+				t.Errorf("got %q, wanted %q", action, testCase.exp)
+			}
+		})
+	}
+}
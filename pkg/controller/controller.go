@@ -6,16 +6,34 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/mattn/go-colorable"
+	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/config"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/metrics"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/azuredevops"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/bitbucket"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/codecommit"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/datadog"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/discord"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/gitea"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/gitlab"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/slack"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/teams"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier/webhook"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/platform"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
 )
@@ -25,6 +43,10 @@ type Controller struct {
 	Parser             terraform.Parser
 	Template           *terraform.Template
 	ParseErrorTemplate *terraform.Template
+	// EvaluatePolicy evaluates policies against the plan JSON if terraform.plan.policy is enabled. This is set by the plan command
+	EvaluatePolicy bool
+	// MultiDir runs the command in each directory of terraform.plan.multi_dir.targets. This is set by the plan command
+	MultiDir bool
 }
 
 type Command struct {
@@ -50,6 +72,41 @@ func (ctrl *Controller) Run(ctx context.Context, command Command) error {
 	if ntf == nil {
 		return errors.New("no notifier specified at all")
 	}
+	ntf, err = ctrl.wrapNotifier(ntf)
+	if err != nil {
+		return err
+	}
+
+	if ctrl.Config.TerraformOutputFile != "" {
+		// the command has already been run and its output is read from the file
+		artifactURL, planJSONURL := ctrl.uploadArtifacts(ctx, ctrl.Config.TerraformOutputFile)
+		return apperr.NewExitError(ntf.Notify(ctx, notifier.ParamExec{
+			CostEstimate:       ctrl.readCostEstimate(),
+			LintResult:         ctrl.readLintResult(),
+			SecurityScan:       ctrl.readSecurityScan(),
+			Checkov:            ctrl.readCheckov(),
+			PolicyResult:       ctrl.evaluateOutputFilePolicy(ctx),
+			ArtifactURL:        artifactURL,
+			PlanJSONURL:        planJSONURL,
+			CombinedOutputFile: ctrl.Config.TerraformOutputFile,
+			CIName:             ctrl.Config.CI.Name,
+			ExitCode:           ctrl.Config.ExitCode,
+			Product:            ctrl.product(""),
+		}))
+	}
+
+	if starter, ok := ntf.(notifier.Starter); ok {
+		// A failure to notify the start doesn't prevent the command from running
+		if err := starter.Start(ctx); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+			}).WithError(err).Error("notify the start of the command")
+		}
+	}
+
+	if ctrl.MultiDir && len(ctrl.Config.Terraform.Plan.MultiDir.Targets) != 0 {
+		return ctrl.runDirs(ctx, ntf, command)
+	}
 
 	cmd := exec.CommandContext(ctx, command.Cmd, command.Args...) //nolint:gosec
 	stdout := &bytes.Buffer{}
@@ -57,21 +114,357 @@ func (ctrl *Controller) Run(ctx context.Context, command Command) error {
 	combinedOutput := &bytes.Buffer{}
 	uncolorizedStdout := colorable.NewNonColorable(stdout)
 	uncolorizedStderr := colorable.NewNonColorable(stderr)
-	uncolorizedCombinedOutput := colorable.NewNonColorable(combinedOutput)
+	// The output is streamed to the terminal as it is produced while it is captured.
+	// stdout and stderr are copied in separate goroutines, so writes to the combined output are serialized
+	uncolorizedCombinedOutput := &syncWriter{w: colorable.NewNonColorable(combinedOutput)}
 	cmd.Stdout = io.MultiWriter(os.Stdout, uncolorizedStdout, uncolorizedCombinedOutput)
 	cmd.Stderr = io.MultiWriter(os.Stderr, uncolorizedStderr, uncolorizedCombinedOutput)
+	start := time.Now()
 	_ = cmd.Run()
+	duration := time.Since(start)
 
+	artifactURL, planJSONURL := ctrl.uploadOutput(ctx, combinedOutput.String())
 	return apperr.NewExitError(ntf.Notify(ctx, notifier.ParamExec{
+		CostEstimate:   ctrl.readCostEstimate(),
+		LintResult:     ctrl.readLintResult(),
+		SecurityScan:   ctrl.readSecurityScan(),
+		Checkov:        ctrl.readCheckov(),
+		PolicyResult:   ctrl.evaluateCommandPolicy(ctx, command, stdout.String()),
+		ArtifactURL:    artifactURL,
+		PlanJSONURL:    planJSONURL,
 		Stdout:         stdout.String(),
 		Stderr:         stderr.String(),
 		CombinedOutput: combinedOutput.String(),
 		Cmd:            cmd,
 		CIName:         ctrl.Config.CI.Name,
 		ExitCode:       cmd.ProcessState.ExitCode(),
+		Product:        ctrl.product(command.Cmd),
+		Duration:       duration,
 	}))
 }
 
+// syncWriter is an io.Writer which can be written concurrently
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// product returns the product which runs the command.
+// terraform.product takes precedence over the command name. If neither tells the product, it is detected from the output
+func (ctrl *Controller) product(cmd string) string {
+	if ctrl.Config.Terraform.OutputFormat == "pulumi" {
+		return terraform.ProductPulumi
+	}
+	switch ctrl.Config.Terraform.Product {
+	case config.ProductOpenTofu:
+		return terraform.ProductOpenTofu
+	case config.ProductTerraform:
+		return terraform.ProductTerraform
+	}
+	if filepath.Base(cmd) == "tofu" {
+		return terraform.ProductOpenTofu
+	}
+	return ""
+}
+
+// ValidateTemplates renders the template and the template for parse errors with sample data without running the command and calling GitHub API
+func (ctrl *Controller) ValidateTemplates() error {
+	for _, a := range []struct {
+		name     string
+		template *terraform.Template
+	}{
+		{name: "template", template: ctrl.Template},
+		{name: "template for parse errors", template: ctrl.ParseErrorTemplate},
+	} {
+		if a.template == nil {
+			continue
+		}
+		a.template.UseRawOutput = ctrl.Config.Terraform.UseRawOutput
+		a.template.Templates = ctrl.Config.Templates
+		if err := a.template.Validate(); err != nil {
+			return fmt.Errorf("the %s is invalid: %w", a.name, err)
+		}
+	}
+	return nil
+}
+
+// readCostEstimate reads the cost estimate. If it fails to read the cost estimate, the cost estimate is ignored
+func (ctrl *Controller) readCostEstimate() string {
+	return readOptionalInput(ctrl.Config.CostEstimate, "cost_estimate", "read a cost estimate")
+}
+
+// readLintResult reads the result of tflint. If it fails to read the result, the result is ignored
+func (ctrl *Controller) readLintResult() string {
+	return readOptionalInput(ctrl.Config.TFLint, "tflint", "read a result of tflint")
+}
+
+// readSecurityScan reads the result of a security scanner. If it fails to read the result, the result is ignored
+func (ctrl *Controller) readSecurityScan() string {
+	return readOptionalInput(ctrl.Config.SecurityScan, "security_scan", "read a result of a security scan")
+}
+
+// readCheckov reads the report of Checkov. If it fails to read the report, the report is ignored
+func (ctrl *Controller) readCheckov() string {
+	return readOptionalInput(ctrl.Config.Checkov, "checkov", "read a report of Checkov")
+}
+
+// isPlanJSONOutput returns true if the output of the command is the plan JSON
+func (ctrl *Controller) isPlanJSONOutput() bool {
+	return ctrl.Config.Terraform.OutputFormat == "json" || ctrl.Config.PlanFile != ""
+}
+
+// policyEnabled returns true if policies are evaluated
+func (ctrl *Controller) policyEnabled() bool {
+	return ctrl.EvaluatePolicy && ctrl.Config.Terraform.Plan.Policy.Enabled
+}
+
+// evaluateOutputFilePolicy evaluates policies against the plan JSON when the output is read from the file.
+// The output file is evaluated only if it is the plan JSON
+func (ctrl *Controller) evaluateOutputFilePolicy(ctx context.Context) string {
+	if !ctrl.policyEnabled() {
+		return ""
+	}
+	if p := ctrl.Config.Terraform.Plan.Policy.PlanJSON; p != "" {
+		return ctrl.evaluatePolicyFile(ctx, p)
+	}
+	if ctrl.isPlanJSONOutput() {
+		return ctrl.evaluatePolicyFile(ctx, ctrl.Config.TerraformOutputFile)
+	}
+	warnNoPlanJSON()
+	return ""
+}
+
+// evaluateCommandPolicy evaluates policies against the plan JSON when the command is run by tfcmt.
+// The plan JSON is read from terraform.plan.policy.plan_json, the standard output if it is the plan JSON,
+// or the output of `terraform show -json` on the plan file given by the -out option.
+// If the plan JSON isn't available, policies aren't evaluated
+func (ctrl *Controller) evaluateCommandPolicy(ctx context.Context, command Command, stdout string) string {
+	if !ctrl.policyEnabled() {
+		return ""
+	}
+	if p := ctrl.Config.Terraform.Plan.Policy.PlanJSON; p != "" {
+		return ctrl.evaluatePolicyFile(ctx, p)
+	}
+	if ctrl.isPlanJSONOutput() {
+		return ctrl.evaluatePolicy(ctx, stdout)
+	}
+	planFile := getPlanOutFile(command.Args)
+	if planFile == "" {
+		warnNoPlanJSON()
+		return ""
+	}
+	cmd := exec.CommandContext(ctx, command.Cmd, "show", "-json", "-no-color", planFile) //nolint:gosec
+	out := &bytes.Buffer{}
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program":   "tfcmt",
+			"plan_file": planFile,
+		}).WithError(err).Warn("convert the plan file to JSON to evaluate policies")
+		return ""
+	}
+	return ctrl.evaluatePolicy(ctx, out.String())
+}
+
+// getPlanOutFile returns the plan file given by the -out option of terraform plan
+func getPlanOutFile(args []string) string {
+	for i, arg := range args {
+		for _, opt := range []string{"-out", "--out"} {
+			if arg == opt && i+1 < len(args) {
+				return args[i+1]
+			}
+			if v := strings.TrimPrefix(arg, opt+"="); v != arg {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+func warnNoPlanJSON() {
+	logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	}).Warn("policies aren't evaluated because the plan JSON isn't available. Please give the plan JSON by --plan-file, terraform.output_format: json, terraform.plan.policy.plan_json, or the -out option of terraform plan")
+}
+
+// evaluatePolicyFile evaluates policies against the plan JSON file
+func (ctrl *Controller) evaluatePolicyFile(ctx context.Context, p string) string {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+			"file":    p,
+		}).WithError(err).Warn("read the plan JSON to evaluate policies")
+		return ""
+	}
+	return ctrl.evaluatePolicy(ctx, string(b))
+}
+
+// evaluatePolicy evaluates policies against the plan JSON with conftest and returns the output of conftest test --output json.
+// If policies aren't enabled or it fails to run conftest, an empty string is returned
+func (ctrl *Controller) evaluatePolicy(ctx context.Context, planJSON string) string {
+	policy := ctrl.Config.Terraform.Plan.Policy
+	if !ctrl.policyEnabled() {
+		return ""
+	}
+	bin := policy.Command
+	if bin == "" {
+		bin = "conftest"
+	}
+	paths := policy.Paths
+	if len(paths) == 0 {
+		paths = []string{"policy"}
+	}
+	args := []string{"test", "--no-color", "--output", "json", "--parser", "json"}
+	for _, p := range paths {
+		args = append(args, "--policy", p)
+	}
+	cmd := exec.CommandContext(ctx, bin, append(args, "-")...) //nolint:gosec
+	cmd.Stdin = strings.NewReader(planJSON)
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	// conftest exits with a non-zero exit code if any policy fails, so the error is ignored if the result is output
+	if err := cmd.Run(); err != nil && stdout.Len() == 0 {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+			"command": bin,
+		}).WithError(err).Warn("evaluate policies with conftest")
+		return ""
+	}
+	return stdout.String()
+}
+
+// readOptionalInput reads the file p. If p is "-", the standard input is read.
+// If p is empty or it fails to read the file, an empty string is returned
+func readOptionalInput(p, field, msg string) string {
+	if p == "" {
+		return ""
+	}
+	var (
+		b   []byte
+		err error
+	)
+	if p == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(p)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+			field:     p,
+		}).WithError(err).Warn(msg)
+		return ""
+	}
+	return string(b)
+}
+
+// readSourceMap reads the source map. If it fails to read the source map, resources aren't linked to their source locations
+func (ctrl *Controller) readSourceMap() terraform.SourceMap {
+	p := ctrl.Config.Terraform.Plan.SourceMap
+	if p == "" {
+		return nil
+	}
+	logE := logrus.WithFields(logrus.Fields{
+		"program":    "tfcmt",
+		"source_map": p,
+	})
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		logE.WithError(err).Warn("read a source map")
+		return nil
+	}
+	m, err := terraform.ParseSourceMap(b)
+	if err != nil {
+		logE.WithError(err).Warn("read a source map")
+		return nil
+	}
+	return m
+}
+
+// getPostTriggers returns the triggers to post the plan comment.
+// If terraform.plan.only_when_failed isn't enabled, nil is returned and the comment is always posted
+func (ctrl *Controller) getPostTriggers() []string {
+	onlyWhenFailed := ctrl.Config.Terraform.Plan.OnlyWhenFailed
+	if !onlyWhenFailed.Enabled {
+		return nil
+	}
+	if len(onlyWhenFailed.Triggers) == 0 {
+		return []string{github.PostTriggerPlanError, github.PostTriggerDestroy, github.PostTriggerParseError}
+	}
+	return onlyWhenFailed.Triggers
+}
+
+// getIdempotencyKey returns the idempotency key.
+// If it isn't configured, the environment variable TFCMT_IDEMPOTENCY_KEY is used.
+func (ctrl *Controller) getIdempotencyKey() string {
+	if ctrl.Config.IdempotencyKey != "" {
+		return ctrl.Config.IdempotencyKey
+	}
+	return os.Getenv("TFCMT_IDEMPOTENCY_KEY")
+}
+
+// getGitHubApp returns the configuration of the GitHub App.
+// The app id and the installation id are read from the configuration file or the environment variables GITHUB_APP_ID and GITHUB_APP_INSTALLATION_ID.
+// The private key is read from the file or the environment variable GITHUB_APP_PRIVATE_KEY.
+func (ctrl *Controller) getGitHubApp() (github.App, error) {
+	cfg := ctrl.Config.GitHubApp
+	if cfg.AppID == 0 {
+		id, err := getInt64Env("GITHUB_APP_ID")
+		if err != nil {
+			return github.App{}, err
+		}
+		if id == 0 {
+			return github.App{}, nil
+		}
+		cfg.AppID = id
+	}
+	if cfg.InstallationID == 0 {
+		id, err := getInt64Env("GITHUB_APP_INSTALLATION_ID")
+		if err != nil {
+			return github.App{}, err
+		}
+		cfg.InstallationID = id
+	}
+	key := os.Getenv("GITHUB_APP_PRIVATE_KEY")
+	if cfg.PrivateKeyPath != "" {
+		b, err := ioutil.ReadFile(cfg.PrivateKeyPath)
+		if err != nil {
+			return github.App{}, fmt.Errorf("read the private key of the GitHub App: %w", err)
+		}
+		key = string(b)
+	}
+	if key == "" {
+		return github.App{}, errors.New("the private key of the GitHub App is missing")
+	}
+	return github.App{
+		ID:             cfg.AppID,
+		InstallationID: cfg.InstallationID,
+		PrivateKey:     key,
+		BaseURL:        cfg.BaseURL,
+	}, nil
+}
+
+func getInt64Env(name string) (int64, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse the environment variable %s as an integer: %w", name, err)
+	}
+	return i, nil
+}
+
 func (ctrl *Controller) renderTemplate(tpl string) (string, error) {
 	tmpl, err := template.New("_").Funcs(sprig.TxtFuncMap()).Parse(tpl)
 	if err != nil {
@@ -92,6 +485,25 @@ func (ctrl *Controller) renderGitHubLabels() (github.ResultLabels, error) { //no
 		DestroyLabelColor:     ctrl.Config.Terraform.Plan.WhenDestroy.Color,
 		NoChangesLabelColor:   ctrl.Config.Terraform.Plan.WhenNoChanges.Color,
 		PlanErrorLabelColor:   ctrl.Config.Terraform.Plan.WhenPlanError.Color,
+		ReplaceLabelColor:     ctrl.Config.Terraform.Plan.WhenReplace.Color,
+		ImportLabelColor:      ctrl.Config.Terraform.Plan.WhenImportOnly.Color,
+		DriftLabelColor:       ctrl.Config.Terraform.Plan.WhenDriftDetected.Color,
+		CheckFailedLabelColor: ctrl.Config.Terraform.Plan.WhenCheckFailed.Color,
+		AppliedLabelColor:     ctrl.Config.Terraform.Apply.WhenSuccess.Color,
+		ApplyFailedLabelColor: ctrl.Config.Terraform.Apply.WhenFailure.Color,
+		Prefix:                ctrl.Config.Terraform.Plan.LabelPrefix,
+		Preserved:             ctrl.Config.Terraform.Plan.PreservedLabels,
+		// the labels of terraform destroy
+		DestroyedLabelColor:     ctrl.Config.Terraform.Destroy.WhenSuccess.Color,
+		DestroyFailedLabelColor: ctrl.Config.Terraform.Destroy.WhenFailure.Color,
+		// the label of terraform validate
+		ValidateFailedLabelColor: ctrl.Config.Terraform.Validate.WhenFailure.Color,
+		// the label of policies
+		PolicyViolationLabelColor: ctrl.Config.Terraform.Plan.Policy.Color,
+	}
+	if labels.Prefix == "" && ctrl.Config.Tag != "" && ctrl.Config.Tag != "tfcmt" {
+		// labels of configurations with different tags don't conflict
+		labels.Prefix = ctrl.Config.Tag + "/"
 	}
 
 	target, ok := ctrl.Config.Vars["target"]
@@ -108,6 +520,36 @@ func (ctrl *Controller) renderGitHubLabels() (github.ResultLabels, error) { //no
 	if labels.NoChangesLabelColor == "" {
 		labels.NoChangesLabelColor = "0e8a16" // green
 	}
+	if labels.ReplaceLabelColor == "" {
+		labels.ReplaceLabelColor = "fbca04" // yellow
+	}
+	if labels.ImportLabelColor == "" {
+		labels.ImportLabelColor = "c5def5" // light blue
+	}
+	if labels.DriftLabelColor == "" {
+		labels.DriftLabelColor = "e99695" // pink
+	}
+	if labels.CheckFailedLabelColor == "" {
+		labels.CheckFailedLabelColor = "d93f0b" // orange
+	}
+	if labels.AppliedLabelColor == "" {
+		labels.AppliedLabelColor = "0e8a16" // green
+	}
+	if labels.ApplyFailedLabelColor == "" {
+		labels.ApplyFailedLabelColor = "d93f0b" // red
+	}
+	if labels.DestroyedLabelColor == "" {
+		labels.DestroyedLabelColor = "0e8a16" // green
+	}
+	if labels.DestroyFailedLabelColor == "" {
+		labels.DestroyFailedLabelColor = "d93f0b" // red
+	}
+	if labels.ValidateFailedLabelColor == "" {
+		labels.ValidateFailedLabelColor = "d93f0b" // red
+	}
+	if labels.PolicyViolationLabelColor == "" {
+		labels.PolicyViolationLabelColor = "b60205" // dark red
+	}
 
 	if ctrl.Config.Terraform.Plan.WhenAddOrUpdateOnly.Label == "" {
 		if target == "" {
@@ -151,15 +593,144 @@ func (ctrl *Controller) renderGitHubLabels() (github.ResultLabels, error) { //no
 		labels.NoChangesLabel = nochangesLabel
 	}
 
+	if ctrl.Config.Terraform.Plan.WhenImportOnly.Label == "" {
+		if target == "" {
+			labels.ImportLabel = "import"
+		} else {
+			labels.ImportLabel = target + "/import"
+		}
+	} else {
+		importLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Plan.WhenImportOnly.Label)
+		if err != nil {
+			return labels, err
+		}
+		labels.ImportLabel = importLabel
+	}
+
+	if ctrl.Config.Terraform.Plan.WhenDriftDetected.Label == "" {
+		if target == "" {
+			labels.DriftLabel = "drift-detected"
+		} else {
+			labels.DriftLabel = target + "/drift-detected"
+		}
+	} else {
+		driftLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Plan.WhenDriftDetected.Label)
+		if err != nil {
+			return labels, err
+		}
+		labels.DriftLabel = driftLabel
+	}
+
 	planErrorLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Plan.WhenPlanError.Label)
 	if err != nil {
 		return labels, err
 	}
 	labels.PlanErrorLabel = planErrorLabel
 
+	if categoryLabels := ctrl.Config.Terraform.Plan.WhenPlanError.CategoryLabels; len(categoryLabels) != 0 {
+		labels.PlanErrorCategoryLabels = make(map[string]string, len(categoryLabels))
+		for category, label := range categoryLabels {
+			l, err := ctrl.renderTemplate(label)
+			if err != nil {
+				return labels, err
+			}
+			labels.PlanErrorCategoryLabels[category] = l
+		}
+	}
+
+	replaceLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Plan.WhenReplace.Label)
+	if err != nil {
+		return labels, err
+	}
+	labels.ReplaceLabel = replaceLabel
+
+	checkFailedLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Plan.WhenCheckFailed.Label)
+	if err != nil {
+		return labels, err
+	}
+	labels.CheckFailedLabel = checkFailedLabel
+
+	appliedLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Apply.WhenSuccess.Label)
+	if err != nil {
+		return labels, err
+	}
+	labels.AppliedLabel = appliedLabel
+
+	applyFailedLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Apply.WhenFailure.Label)
+	if err != nil {
+		return labels, err
+	}
+	labels.ApplyFailedLabel = applyFailedLabel
+
+	destroyedLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Destroy.WhenSuccess.Label)
+	if err != nil {
+		return labels, err
+	}
+	labels.DestroyedLabel = destroyedLabel
+
+	destroyFailedLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Destroy.WhenFailure.Label)
+	if err != nil {
+		return labels, err
+	}
+	labels.DestroyFailedLabel = destroyFailedLabel
+
+	if ctrl.Config.Terraform.Validate.WhenFailure.Label == "" {
+		if target == "" {
+			labels.ValidateFailedLabel = "validate-failed"
+		} else {
+			labels.ValidateFailedLabel = target + "/validate-failed"
+		}
+	} else {
+		validateFailedLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Validate.WhenFailure.Label)
+		if err != nil {
+			return labels, err
+		}
+		labels.ValidateFailedLabel = validateFailedLabel
+	}
+
+	if ctrl.Config.Terraform.Plan.Policy.Label == "" {
+		if target == "" {
+			labels.PolicyViolationLabel = "policy-violation"
+		} else {
+			labels.PolicyViolationLabel = target + "/policy-violation"
+		}
+	} else {
+		policyViolationLabel, err := ctrl.renderTemplate(ctrl.Config.Terraform.Plan.Policy.Label)
+		if err != nil {
+			return labels, err
+		}
+		labels.PolicyViolationLabel = policyViolationLabel
+	}
+
 	return labels, nil
 }
 
+// notifierName returns the service to post the result.
+// If the notifier isn't configured, it is decided by the CI platform. The default notifier is GitHub
+func (ctrl *Controller) notifierName() string {
+	if ctrl.Config.Notifier != "" {
+		return ctrl.Config.Notifier
+	}
+	switch ctrl.Config.CI.Name {
+	case "gitlab-ci":
+		return "gitlab"
+	case "bitbucket-pipelines":
+		return "bitbucket"
+	case "azure-pipelines":
+		return "azure-devops"
+	case "codebuild":
+		// CodeBuild also supports GitHub, so CodeCommit is used only if the source is a CodeCommit repository
+		if codecommit.IsRepoURL(os.Getenv(codecommit.EnvSourceRepoURL)) {
+			return "codecommit"
+		}
+	}
+	// Gitea Actions is compatible with GitHub Actions, so the notifier is decided by the environment variable
+	if os.Getenv("GITEA_ACTIONS") == "true" {
+		return "gitea"
+	}
+	return "github"
+}
+
 func (ctrl *Controller) getNotifier(ctx context.Context) (notifier.Notifier, error) {
 	labels := github.ResultLabels{}
 	if !ctrl.Config.Terraform.Plan.DisableLabel {
@@ -169,27 +740,434 @@ func (ctrl *Controller) getNotifier(ctx context.Context) (notifier.Notifier, err
 		}
 		labels = a
 	}
-	client, err := github.NewClient(ctx, github.Config{
-		Token:   ctrl.Config.GitHubToken,
-		BaseURL: ctrl.Config.GHEBaseURL,
-		Owner:   ctrl.Config.CI.Owner,
-		Repo:    ctrl.Config.CI.Repo,
+	if len(ctrl.Config.Notifiers) != 0 {
+		return ctrl.getNotifiers(ctx, labels)
+	}
+	name := ctrl.notifierName()
+	ntf, err := ctrl.newNotifier(ctx, name, labels)
+	if err != nil {
+		return nil, err
+	}
+	// Chat services, the webhook, and Datadog are notified in addition to the notifier
+	ntfs := notifier.Multi{ntf}
+	for _, chat := range []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "slack", enabled: ctrl.Config.Slack.Enabled},
+		{name: "teams", enabled: ctrl.Config.Teams.Enabled},
+		{name: "discord", enabled: ctrl.Config.Discord.Enabled},
+		{name: "webhook", enabled: ctrl.Config.Webhook.Enabled},
+		{name: "datadog", enabled: ctrl.Config.Datadog.Enabled},
+	} {
+		if !chat.enabled || chat.name == name {
+			continue
+		}
+		n, err := ctrl.newNotifier(ctx, chat.name, labels)
+		if err != nil {
+			return nil, err
+		}
+		ntfs = append(ntfs, n)
+	}
+	if len(ntfs) == 1 {
+		return ntf, nil
+	}
+	return ntfs, nil
+}
+
+// wrapNotifier wraps the notifier to handle what is common to all notifiers,
+// such as the timeout, metrics, and the machine-readable result
+func (ctrl *Controller) wrapNotifier(ntf notifier.Notifier) (notifier.Notifier, error) {
+	var timeout time.Duration
+	if ctrl.Config.Timeout != "" {
+		// the timeout has already been validated
+		timeout, _ = time.ParseDuration(ctrl.Config.Timeout)
+	}
+	ntf = &notifier.Deadline{
+		Notifier: ntf,
+		Timeout:  timeout,
+	}
+	sink, err := metrics.New(metrics.Config{
+		Sink:     ctrl.Config.Metrics.Sink,
+		Endpoint: ctrl.Config.Metrics.Endpoint,
+		Prefix:   ctrl.Config.Metrics.Prefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sink != nil && !ctrl.Config.DryRun {
+		ntf = &notifier.MetricsRecorder{
+			Notifier:             ntf,
+			Sink:                 sink,
+			Parser:               ctrl.Parser,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			Repo:                 ctrl.Config.CI.Owner + "/" + ctrl.Config.CI.Repo,
+			Target:               ctrl.Config.Vars["target"],
+		}
+	}
+	if ctrl.Config.ResultFormat != "" {
+		ntf = &notifier.ReportWriter{
+			Notifier:             ntf,
+			Parser:               ctrl.Parser,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			Output:               ctrl.Config.ResultOutput,
+		}
+	}
+	return ntf, nil
+}
+
+// getNotifiers returns the notifier which notifies the result with all notifiers in notifiers.
+// Notifiers with conditions notify only the results which match the conditions
+func (ctrl *Controller) getNotifiers(ctx context.Context, labels github.ResultLabels) (notifier.Notifier, error) {
+	ntfs := make(notifier.Multi, 0, len(ctrl.Config.Notifiers))
+	for _, cfg := range ctrl.Config.Notifiers {
+		ntf, err := ctrl.newNotifier(ctx, cfg.Type, labels)
+		if err != nil {
+			return nil, fmt.Errorf("initialize the notifier %s: %w", cfg.Type, err)
+		}
+		if triggers := cfg.Triggers(); len(triggers) != 0 {
+			ntf = &notifier.Conditional{
+				Notifier:             ntf,
+				Parser:               ctrl.Parser,
+				Triggers:             triggers,
+				DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			}
+		}
+		ntfs = append(ntfs, ntf)
+	}
+	if len(ntfs) == 1 {
+		return ntfs[0], nil
+	}
+	return ntfs, nil
+}
+
+// newNotifier returns the notifier of the service
+func (ctrl *Controller) newNotifier(ctx context.Context, name string, labels github.ResultLabels) (notifier.Notifier, error) {
+	switch name {
+	case "slack":
+		var planTemplate, applyTemplate *terraform.Template
+		if tpl := ctrl.Config.Slack.PlanTemplate; tpl != "" {
+			planTemplate = terraform.NewPlanTemplate(tpl)
+		}
+		if tpl := ctrl.Config.Slack.ApplyTemplate; tpl != "" {
+			applyTemplate = terraform.NewApplyTemplate(tpl)
+		}
+		return slack.NewNotifier(slack.Config{
+			Channel:              ctrl.Config.Slack.Channel,
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			PlanTemplate:         planTemplate,
+			ApplyTemplate:        applyTemplate,
+			ResultLabels:         labels,
+			Vars:                 ctrl.Config.Vars,
+			Templates:            ctrl.Config.Templates,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			Triggers:             ctrl.Config.Slack.When,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+		})
+	case "teams":
+		var planTemplate, applyTemplate *terraform.Template
+		if tpl := ctrl.Config.Teams.PlanTemplate; tpl != "" {
+			planTemplate = terraform.NewPlanTemplate(tpl)
+		}
+		if tpl := ctrl.Config.Teams.ApplyTemplate; tpl != "" {
+			applyTemplate = terraform.NewApplyTemplate(tpl)
+		}
+		return teams.NewNotifier(teams.Config{
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			PlanTemplate:         planTemplate,
+			ApplyTemplate:        applyTemplate,
+			ResultLabels:         labels,
+			Vars:                 ctrl.Config.Vars,
+			Templates:            ctrl.Config.Templates,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			Triggers:             ctrl.Config.Teams.When,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+		})
+	case "azure-devops":
+		return azuredevops.NewNotifier(azuredevops.Config{
+			OrgURL:  ctrl.Config.AzureDevOps.OrgURL,
+			Project: ctrl.Config.CI.Owner,
+			Repo:    ctrl.Config.CI.Repo,
+			PR: azuredevops.PullRequestInfo{
+				Revision: ctrl.Config.CI.SHA,
+				Number:   ctrl.Config.CI.PRNumber,
+			},
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			Template:             ctrl.Template,
+			ParseErrorTemplate:   ctrl.ParseErrorTemplate,
+			Vars:                 ctrl.Config.Vars,
+			Templates:            ctrl.Config.Templates,
+			UseRawOutput:         ctrl.Config.Terraform.UseRawOutput,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			MaxResources:         ctrl.Config.Terraform.Plan.MaxResources,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+			Output:               ctrl.Config.CommentOutput,
+		})
+	case "codecommit":
+		return codecommit.NewNotifier(codecommit.Config{
+			Region: ctrl.Config.CodeCommit.Region,
+			Repo:   ctrl.Config.CI.Repo,
+			PR: codecommit.PullRequestInfo{
+				Revision: ctrl.Config.CI.SHA,
+				Number:   ctrl.Config.CI.PRNumber,
+			},
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			Template:             ctrl.Template,
+			ParseErrorTemplate:   ctrl.ParseErrorTemplate,
+			Vars:                 ctrl.Config.Vars,
+			Templates:            ctrl.Config.Templates,
+			UseRawOutput:         ctrl.Config.Terraform.UseRawOutput,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			MaxResources:         ctrl.Config.Terraform.Plan.MaxResources,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+			Output:               ctrl.Config.CommentOutput,
+		})
+	case "bitbucket":
+		return bitbucket.NewNotifier(bitbucket.Config{
+			Owner: ctrl.Config.CI.Owner,
+			Repo:  ctrl.Config.CI.Repo,
+			PR: bitbucket.PullRequestInfo{
+				Revision: ctrl.Config.CI.SHA,
+				Number:   ctrl.Config.CI.PRNumber,
+			},
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			Template:             ctrl.Template,
+			ParseErrorTemplate:   ctrl.ParseErrorTemplate,
+			Vars:                 ctrl.Config.Vars,
+			Templates:            ctrl.Config.Templates,
+			UseRawOutput:         ctrl.Config.Terraform.UseRawOutput,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			MaxResources:         ctrl.Config.Terraform.Plan.MaxResources,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+			Output:               ctrl.Config.CommentOutput,
+		})
+	case "discord":
+		var planTemplate, applyTemplate *terraform.Template
+		if tpl := ctrl.Config.Discord.PlanTemplate; tpl != "" {
+			planTemplate = terraform.NewPlanTemplate(tpl)
+		}
+		if tpl := ctrl.Config.Discord.ApplyTemplate; tpl != "" {
+			applyTemplate = terraform.NewApplyTemplate(tpl)
+		}
+		return discord.NewNotifier(discord.Config{
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			PlanTemplate:         planTemplate,
+			ApplyTemplate:        applyTemplate,
+			ResultLabels:         labels,
+			Vars:                 ctrl.Config.Vars,
+			Templates:            ctrl.Config.Templates,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			Triggers:             ctrl.Config.Discord.When,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+		})
+	case "datadog":
+		var planTemplate, applyTemplate *terraform.Template
+		if tpl := ctrl.Config.Datadog.PlanTemplate; tpl != "" {
+			planTemplate = terraform.NewPlanTemplate(tpl)
+		}
+		if tpl := ctrl.Config.Datadog.ApplyTemplate; tpl != "" {
+			applyTemplate = terraform.NewApplyTemplate(tpl)
+		}
+		return datadog.NewNotifier(datadog.Config{
+			Site:                 ctrl.Config.Datadog.Site,
+			Repo:                 ctrl.Config.CI.Owner + "/" + ctrl.Config.CI.Repo,
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			PlanTemplate:         planTemplate,
+			ApplyTemplate:        applyTemplate,
+			ResultLabels:         labels,
+			Vars:                 ctrl.Config.Vars,
+			Templates:            ctrl.Config.Templates,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			Tags:                 ctrl.Config.Datadog.Tags,
+			Triggers:             ctrl.Config.Datadog.When,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+		})
+	case "webhook":
+		return webhook.NewNotifier(webhook.Config{
+			URL:     ctrl.Config.Webhook.URL,
+			Headers: ctrl.Config.Webhook.Headers,
+			CI: webhook.CI{
+				Name:     ctrl.Config.CI.Name,
+				Owner:    ctrl.Config.CI.Owner,
+				Repo:     ctrl.Config.CI.Repo,
+				SHA:      ctrl.Config.CI.SHA,
+				Branch:   ctrl.Config.CI.Branch,
+				PRNumber: ctrl.Config.CI.PRNumber,
+				Link:     ctrl.Config.CI.Link,
+			},
+			Parser:               ctrl.Parser,
+			Template:             ctrl.Template,
+			ParseErrorTemplate:   ctrl.ParseErrorTemplate,
+			ResultLabels:         labels,
+			Vars:                 ctrl.Config.Vars,
+			Templates:            ctrl.Config.Templates,
+			UseRawOutput:         ctrl.Config.Terraform.UseRawOutput,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			MaxResources:         ctrl.Config.Terraform.Plan.MaxResources,
+			Tag:                  ctrl.Config.Tag,
+			Triggers:             ctrl.Config.Webhook.When,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+			Output:               ctrl.Config.CommentOutput,
+		})
+	case "gitea":
+		return gitea.NewNotifier(gitea.Config{
+			BaseURL: ctrl.Config.Gitea.BaseURL,
+			Owner:   ctrl.Config.CI.Owner,
+			Repo:    ctrl.Config.CI.Repo,
+			PR: gitea.PullRequestInfo{
+				Revision: ctrl.Config.CI.SHA,
+				Number:   ctrl.Config.CI.PRNumber,
+			},
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			Template:             ctrl.Template,
+			ParseErrorTemplate:   ctrl.ParseErrorTemplate,
+			Vars:                 ctrl.Config.Vars,
+			EmbeddedVarNames:     ctrl.Config.EmbeddedVarNames,
+			Templates:            ctrl.Config.Templates,
+			UseRawOutput:         ctrl.Config.Terraform.UseRawOutput,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			MaxResources:         ctrl.Config.Terraform.Plan.MaxResources,
+			Tag:                  ctrl.Config.Tag,
+			OldComment: gitea.OldComment{
+				Action:     ctrl.Config.OldCommentAction(),
+				Classifier: ctrl.Config.OldComment.Classifier,
+			},
+			DryRun:       ctrl.Config.DryRun,
+			DryRunOutput: ctrl.Config.DryRunOutput,
+			Output:       ctrl.Config.CommentOutput,
+		})
+	case "gitlab":
+		return gitlab.NewNotifier(gitlab.Config{
+			BaseURL: ctrl.Config.GitLab.BaseURL,
+			Owner:   ctrl.Config.CI.Owner,
+			Repo:    ctrl.Config.CI.Repo,
+			MR: gitlab.MergeRequestInfo{
+				Revision: ctrl.Config.CI.SHA,
+				Number:   ctrl.Config.CI.PRNumber,
+			},
+			CI:                   ctrl.Config.CI.Link,
+			Parser:               ctrl.Parser,
+			Template:             ctrl.Template,
+			ParseErrorTemplate:   ctrl.ParseErrorTemplate,
+			ResultLabels:         labels,
+			Vars:                 ctrl.Config.Vars,
+			EmbeddedVarNames:     ctrl.Config.EmbeddedVarNames,
+			Templates:            ctrl.Config.Templates,
+			UseRawOutput:         ctrl.Config.Terraform.UseRawOutput,
+			DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+			MaxResources:         ctrl.Config.Terraform.Plan.MaxResources,
+			Tag:                  ctrl.Config.Tag,
+			DryRun:               ctrl.Config.DryRun,
+			DryRunOutput:         ctrl.Config.DryRunOutput,
+			Output:               ctrl.Config.CommentOutput,
+		})
+	}
+	app, err := ctrl.getGitHubApp()
+	if err != nil {
+		return nil, err
+	}
+	return github.NewNotifier(ctx, github.Config{
+		Token:     ctrl.Config.GitHubToken,
+		App:       app,
+		BaseURL:   ctrl.Config.GHEBaseURL,
+		UploadURL: ctrl.Config.GHEUploadURL,
+		Owner:     ctrl.Config.CI.Owner,
+		Repo:      ctrl.Config.CI.Repo,
 		PR: github.PullRequest{
 			Revision: ctrl.Config.CI.SHA,
 			Number:   ctrl.Config.CI.PRNumber,
+			Branch:   ctrl.Config.CI.Branch,
+		},
+		TargetPRNumber:       ctrl.Config.TargetPRNumber,
+		IdempotencyKey:       ctrl.getIdempotencyKey(),
+		CI:                   ctrl.Config.CI.Link,
+		Parser:               ctrl.Parser,
+		UseRawOutput:         ctrl.Config.Terraform.UseRawOutput,
+		DisableNormalization: ctrl.Config.Terraform.DisableOutputNormalization,
+		Template:             ctrl.Template,
+		ParseErrorTemplate:   ctrl.ParseErrorTemplate,
+		ResultLabels:         labels,
+		Vars:                 ctrl.Config.Vars,
+		EmbeddedVarNames:     ctrl.Config.EmbeddedVarNames,
+		TemplateEnvPrefixes:  ctrl.Config.TemplateEnvPrefixes,
+		Tag:                  ctrl.Config.Tag,
+		Templates:            ctrl.Config.Templates,
+		MaxResources:         ctrl.Config.Terraform.Plan.MaxResources,
+		SourceMap:            ctrl.readSourceMap(),
+		InlineComments:       ctrl.Config.Terraform.Plan.InlineComments.Enabled,
+		SkipDuplicateComment: ctrl.Config.Terraform.Plan.SkipDuplicateComment,
+		SkipDuplicateApply:   ctrl.Config.Terraform.Apply.SkipDuplicateComment,
+		FailOnDestroy:        ctrl.Config.Terraform.Plan.WhenDestroy.Fail,
+		DestroyThreshold:     ctrl.Config.Terraform.Plan.WhenDestroy.FailThreshold,
+		FailOnCritical:       ctrl.Config.Terraform.Plan.WhenCriticalFinding.Fail,
+		ClosedPRAction:       ctrl.Config.Terraform.Plan.WhenPRClosed,
+		PostTriggers:         ctrl.getPostTriggers(),
+		SummaryPosition:      ctrl.Config.Terraform.Plan.SummaryPosition,
+		Gist: github.Gist{
+			Enabled:       ctrl.Config.Gist.Enabled,
+			Threshold:     ctrl.Config.Gist.Threshold,
+			Public:        ctrl.Config.Gist.Public,
+			PlanTemplate:  ctrl.Config.Gist.PlanTemplate,
+			ApplyTemplate: ctrl.Config.Gist.ApplyTemplate,
+		},
+		Truncate: github.Truncate{
+			Strategy:     ctrl.Config.Truncate.Strategy,
+			DropSections: ctrl.Config.Truncate.DropSections,
+			MaxLength:    ctrl.Config.Truncate.MaxLength,
+		},
+		FailOnPolicyViolation: ctrl.Config.Terraform.Plan.Policy.Fail,
+		OldComment: github.OldComment{
+			Action:     ctrl.Config.OldCommentAction(),
+			Classifier: ctrl.Config.OldComment.Classifier,
+			Match:      ctrl.Config.OldComment.Match,
+		},
+		Review: github.Review{
+			Enabled:          ctrl.Config.Terraform.Plan.Review.Enabled,
+			Event:            ctrl.Config.Terraform.Plan.Review.Event,
+			EventWhenDestroy: ctrl.Config.Terraform.Plan.Review.EventWhenDestroy,
+		},
+		CheckRun: github.CheckRun{
+			Enabled:     ctrl.Config.CheckRun.Enabled,
+			Name:        ctrl.Config.CheckRun.Name,
+			SkipComment: ctrl.Config.CheckRun.SkipComment,
 		},
-		CI:                 ctrl.Config.CI.Link,
-		Parser:             ctrl.Parser,
-		UseRawOutput:       ctrl.Config.Terraform.UseRawOutput,
-		Template:           ctrl.Template,
-		ParseErrorTemplate: ctrl.ParseErrorTemplate,
-		ResultLabels:       labels,
-		Vars:               ctrl.Config.Vars,
-		EmbeddedVarNames:   ctrl.Config.EmbeddedVarNames,
-		Templates:          ctrl.Config.Templates,
+		CommitStatus: github.CommitStatus{
+			Enabled: ctrl.Config.CommitStatus.Enabled,
+			Context: ctrl.Config.CommitStatus.Context,
+		},
+		Deployment: github.Deployment{
+			Enabled:     ctrl.Config.Deployment.Enabled,
+			Environment: ctrl.Config.Deployment.Environment,
+		},
+		DriftIssue: github.DriftIssue{
+			Enabled: ctrl.Config.Terraform.Plan.DriftIssue.Enabled,
+			Title:   ctrl.Config.Terraform.Plan.DriftIssue.Title,
+			Labels:  ctrl.Config.Terraform.Plan.DriftIssue.Labels,
+		},
+		FmtSuggestion: github.FmtSuggestion{
+			Enabled: ctrl.Config.Terraform.Fmt.Suggestion.Enabled,
+			Dir:     ctrl.Config.Terraform.Fmt.Suggestion.Dir,
+		},
+		StepSummary:  ctrl.Config.StepSummary.Enabled,
+		Annotations:  ctrl.Config.Terraform.Validate.Annotations,
+		DryRun:       ctrl.Config.DryRun,
+		DryRunOutput: ctrl.Config.DryRunOutput,
+		Output:       ctrl.Config.CommentOutput,
 	})
-	if err != nil {
-		return nil, err
-	}
-	return client.Notify, nil
 }
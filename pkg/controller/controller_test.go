@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/config"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestSyncWriter(t *testing.T) {
+	t.Parallel()
+	const n = 1000
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	combinedOutput := &bytes.Buffer{}
+	combined := &syncWriter{w: combinedOutput}
+	// stdout and stderr are copied to the combined output in separate goroutines like exec.Cmd
+	streams := map[string]io.Writer{
+		"stdout": io.MultiWriter(stdout, combined),
+		"stderr": io.MultiWriter(stderr, combined),
+	}
+	var wg sync.WaitGroup
+	for name, w := range streams {
+		name, w := name, w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if _, err := io.WriteString(w, name+"\n"); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if diff := cmp.Diff(strings.Repeat("stdout\n", n), stdout.String()); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(strings.Repeat("stderr\n", n), stderr.String()); diff != "" {
+		t.Error(diff)
+	}
+	// the order of lines depends on the scheduling, but each write must be kept intact
+	lines := strings.Split(strings.TrimSuffix(combinedOutput.String(), "\n"), "\n")
+	sort.Strings(lines)
+	exp := make([]string, 0, 2*n)
+	for i := 0; i < n; i++ {
+		exp = append(exp, "stderr")
+	}
+	for i := 0; i < n; i++ {
+		exp = append(exp, "stdout")
+	}
+	if diff := cmp.Diff(exp, lines); diff != "" {
+		t.Error(diff)
+	}
+}
+
+// writeScript writes an executable shell script to the directory
+func writeScript(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(p, []byte("#!/bin/sh\n"+content), 0o755); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestController_evaluateCommandPolicy(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	// terraform show -json outputs the plan JSON deleting a resource
+	tf := writeScript(t, dir, "terraform", `if [ "$1" = show ]; then
+  echo '{"resource_changes":[{"address":"null_resource.foo","change":{"actions":["delete"]}}]}'
+fi
+`)
+	// conftest fails if the plan JSON given by the standard input deletes a resource
+	conftest := writeScript(t, dir, "conftest", `input=$(cat)
+case "$input" in
+  "{"*'"delete"'*)
+    echo '[{"filename":"","namespace":"main","successes":0,"failures":[{"msg":"null_resource.foo must not be deleted"}]}]'
+    exit 1;;
+  "{"*)
+    echo '[{"filename":"","namespace":"main","successes":1}]';;
+  *)
+    echo "the input isn't JSON" >&2
+    exit 1;;
+esac
+`)
+	data := []struct {
+		title string
+		args  []string
+		exp   []terraform.PolicyViolation
+	}{
+		{
+			title: "the plan file given by -out is converted to JSON",
+			args:  []string{"plan", "-out=tfplan"},
+			exp: []terraform.PolicyViolation{
+				{Namespace: "main", Severity: terraform.PolicySeverityFailure, Message: "null_resource.foo must not be deleted"},
+			},
+		},
+		{
+			title: "the plan file is given as a separate argument",
+			args:  []string{"plan", "-out", "tfplan"},
+			exp: []terraform.PolicyViolation{
+				{Namespace: "main", Severity: terraform.PolicySeverityFailure, Message: "null_resource.foo must not be deleted"},
+			},
+		},
+		{
+			title: "policies aren't evaluated without the plan JSON",
+			args:  []string{"plan"},
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			ctrl := &Controller{
+				EvaluatePolicy: true,
+			}
+			ctrl.Config.Terraform.Plan.Policy = config.Policy{
+				Enabled: true,
+				Command: conftest,
+			}
+			// the standard output of terraform plan isn't JSON
+			out := ctrl.evaluateCommandPolicy(context.Background(), Command{Cmd: tf, Args: d.args}, "Plan: 0 to add, 0 to change, 1 to destroy.\n")
+			if d.exp == nil {
+				if out != "" {
+					t.Fatalf("policies must not be evaluated: %s", out)
+				}
+				return
+			}
+			result, err := terraform.ParsePolicyResult([]byte(out))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(d.exp, result.Violations()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestObjectKeyPrefix(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	data := []struct {
+		title  string
+		prefix string
+		target string
+		exp    string
+	}{
+		{
+			title:  "without target",
+			prefix: "tfcmt",
+			exp:    "tfcmt/20210102T030405Z-0123abcd",
+		},
+		{
+			title:  "with target",
+			prefix: "tfcmt",
+			target: "aws/dev",
+			exp:    "tfcmt/aws/dev/20210102T030405Z-0123abcd",
+		},
+	}
+	for _, d := range data {
+		d := d
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			if s := objectKeyPrefix(d.prefix, d.target, now, "0123abcd"); s != d.exp {
+				t.Errorf("wanted %s, got %s", d.exp, s)
+			}
+		})
+	}
+}
+
+func TestRandomSuffix(t *testing.T) {
+	t.Parallel()
+	a, err := randomSuffix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := randomSuffix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 8 || a == b {
+		t.Errorf("the suffix must be 8 random hexadecimal characters: %s, %s", a, b)
+	}
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-colorable"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// dirOutput is the output of the command run in a directory
+type dirOutput struct {
+	stdout         string
+	stderr         string
+	combinedOutput string
+	exitCode       int
+}
+
+// runDirs runs the command in each directory matching terraform.plan.multi_dir.targets and posts a single comment.
+// The output of each directory is prefixed with the directory like "[envs/dev] ", which is parsed in the same way as terragrunt run-all plan
+func (ctrl *Controller) runDirs(ctx context.Context, ntf notifier.Notifier, command Command) error {
+	dirs, err := expandDirs(ctrl.Config.Terraform.Plan.MultiDir.Targets)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return errors.New("no directory matches terraform.plan.multi_dir.targets")
+	}
+	parallelism := ctrl.Config.Terraform.Plan.MultiDir.Parallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+
+	outputs := make([]dirOutput, len(dirs))
+	// the output of each directory is streamed to the terminal line by line with the prefix
+	terminal := &sync.Mutex{}
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i, dir := range dirs {
+		i, dir := i, dir
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			outputs[i] = runInDir(ctx, command, dir, terminal)
+		}()
+	}
+	wg.Wait()
+	duration := time.Since(start)
+
+	stdout := &strings.Builder{}
+	stderr := &strings.Builder{}
+	combinedOutput := &strings.Builder{}
+	exitCode := 0
+	for i, dir := range dirs {
+		prefix := "[" + dir + "] "
+		stdout.WriteString(prefixLines(prefix, outputs[i].stdout))
+		stderr.WriteString(prefixLines(prefix, outputs[i].stderr))
+		combinedOutput.WriteString(prefixLines(prefix, outputs[i].combinedOutput))
+		// an error takes precedence over the exit code 2 of -detailed-exitcode
+		if code := outputs[i].exitCode; code != 0 && (exitCode == 0 || exitCode == 2) {
+			exitCode = code
+		}
+	}
+
+	artifactURL, planJSONURL := ctrl.uploadOutput(ctx, combinedOutput.String())
+	return apperr.NewExitError(ntf.Notify(ctx, notifier.ParamExec{
+		CostEstimate:   ctrl.readCostEstimate(),
+		LintResult:     ctrl.readLintResult(),
+		SecurityScan:   ctrl.readSecurityScan(),
+		Checkov:        ctrl.readCheckov(),
+		ArtifactURL:    artifactURL,
+		PlanJSONURL:    planJSONURL,
+		Stdout:         stdout.String(),
+		Stderr:         stderr.String(),
+		CombinedOutput: combinedOutput.String(),
+		CIName:         ctrl.Config.CI.Name,
+		ExitCode:       exitCode,
+		Product:        ctrl.product(command.Cmd),
+		Duration:       duration,
+	}))
+}
+
+// expandDirs returns directories matching the glob patterns. Duplicates are removed and directories are sorted
+func expandDirs(patterns []string) ([]string, error) {
+	found := map[string]struct{}{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("expand the glob pattern %s: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if fi, err := os.Stat(match); err != nil || !fi.IsDir() {
+				continue
+			}
+			found[filepath.ToSlash(filepath.Clean(match))] = struct{}{}
+		}
+	}
+	dirs := make([]string, 0, len(found))
+	for dir := range found {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// runInDir runs the command in the directory and captures the output
+func runInDir(ctx context.Context, command Command, dir string, terminal *sync.Mutex) dirOutput {
+	cmd := exec.CommandContext(ctx, command.Cmd, command.Args...) //nolint:gosec
+	cmd.Dir = dir
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	combinedOutput := &bytes.Buffer{}
+	uncolorizedCombinedOutput := &syncWriter{w: colorable.NewNonColorable(combinedOutput)}
+	prefix := "[" + dir + "] "
+	terminalStdout := &prefixWriter{mu: terminal, w: os.Stdout, prefix: prefix}
+	terminalStderr := &prefixWriter{mu: terminal, w: os.Stderr, prefix: prefix}
+	cmd.Stdout = io.MultiWriter(terminalStdout, colorable.NewNonColorable(stdout), uncolorizedCombinedOutput)
+	cmd.Stderr = io.MultiWriter(terminalStderr, colorable.NewNonColorable(stderr), uncolorizedCombinedOutput)
+	_ = cmd.Run()
+	terminalStdout.flush()
+	terminalStderr.flush()
+	return dirOutput{
+		stdout:         stdout.String(),
+		stderr:         stderr.String(),
+		combinedOutput: combinedOutput.String(),
+		exitCode:       cmd.ProcessState.ExitCode(),
+	}
+}
+
+// prefixLines prefixes each line of the output
+func prefixLines(prefix, output string) string {
+	if output == "" {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// prefixWriter writes each line with the prefix. Lines of multiple writers sharing mu aren't mixed
+type prefixWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i == -1 {
+			return len(p), nil
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// flush writes the last line which doesn't end with a line break
+func (w *prefixWriter) flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+	w.writeLine(append(w.buf, '\n'))
+	w.buf = nil
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// a failure to output the log doesn't prevent the command from running
+	_, _ = io.WriteString(w.w, w.prefix+string(line))
+}
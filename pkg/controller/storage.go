@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// uploadOutput writes the output to a temporary file and uploads it to S3 or GCS
+func (ctrl *Controller) uploadOutput(ctx context.Context, output string) (string, string) {
+	if ctrl.Config.Storage.Type == "" || ctrl.Config.DryRun {
+		return "", ""
+	}
+	f, err := ioutil.TempFile("", "tfcmt-output-*.txt")
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(err).Warn("create a temporary file to upload the output")
+		return "", ""
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(output); err != nil {
+		f.Close()
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(err).Warn("write the output to a temporary file")
+		return "", ""
+	}
+	if err := f.Close(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(err).Warn("close a temporary file")
+		return "", ""
+	}
+	return ctrl.uploadArtifacts(ctx, f.Name())
+}
+
+// uploadArtifacts uploads the output file and the plan JSON to S3 or GCS and returns their URLs.
+// If it fails to upload a file, the URL is empty
+func (ctrl *Controller) uploadArtifacts(ctx context.Context, outputFile string) (string, string) {
+	storage := ctrl.Config.Storage
+	prefix := "tfcmt"
+	if storage.Prefix != "" {
+		p, err := ctrl.renderTemplate(storage.Prefix)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+			}).WithError(err).Warn("render the prefix of the storage")
+			return "", ""
+		}
+		prefix = p
+	}
+	suffix, err := randomSuffix()
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(err).Warn("generate a random suffix of object keys")
+		return "", ""
+	}
+	prefix = objectKeyPrefix(prefix, ctrl.Config.Vars["target"], time.Now(), suffix)
+	outputURL := ctrl.uploadArtifact(ctx, outputFile, path.Join(prefix, "output.txt"))
+	if storage.PlanJSON == "" {
+		return outputURL, ""
+	}
+	return outputURL, ctrl.uploadArtifact(ctx, storage.PlanJSON, path.Join(prefix, "plan.json"))
+}
+
+// objectKeyPrefix returns the prefix of object keys.
+// The target and the random suffix are added so that concurrent runs in the same second don't overwrite each other's objects
+func objectKeyPrefix(prefix, target string, now time.Time, suffix string) string {
+	return path.Join(prefix, target, now.UTC().Format("20060102T150405Z")+"-"+suffix)
+}
+
+func randomSuffix() (string, error) {
+	b := make([]byte, 4) //nolint:gomnd
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// uploadArtifact uploads the file with the aws or gcloud command and returns the URL of the object
+func (ctrl *Controller) uploadArtifact(ctx context.Context, p, key string) string {
+	storage := ctrl.Config.Storage
+	bin := storage.Command
+	scheme := "s3"
+	if storage.Type == "gcs" {
+		scheme = "gs"
+		if bin == "" {
+			bin = "gcloud"
+		}
+	} else if bin == "" {
+		bin = "aws"
+	}
+	uri := scheme + "://" + storage.Bucket + "/" + key
+	var args []string
+	if storage.Type == "gcs" {
+		args = []string{"storage", "cp", p, uri}
+	} else {
+		args = []string{"s3", "cp", "--only-show-errors", p, uri}
+	}
+	if _, err := runStorageCommand(ctx, bin, args...); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+			"file":    p,
+			"uri":     uri,
+		}).WithError(err).Warn("upload a file to the storage")
+		return ""
+	}
+	if !storage.Presign {
+		return consoleURL(storage.Type, storage.Bucket, key)
+	}
+	u, err := ctrl.presign(ctx, bin, uri)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+			"uri":     uri,
+		}).WithError(err).Warn("create a presigned URL")
+		return consoleURL(storage.Type, storage.Bucket, key)
+	}
+	return u
+}
+
+// presign returns a presigned URL of the object
+func (ctrl *Controller) presign(ctx context.Context, bin, uri string) (string, error) {
+	expiresIn := ctrl.Config.Storage.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = 3600
+	}
+	if ctrl.Config.Storage.Type != "gcs" {
+		out, err := runStorageCommand(ctx, bin, "s3", "presign", uri, "--expires-in", strconv.Itoa(expiresIn))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(out), nil
+	}
+	out, err := runStorageCommand(ctx, bin, "storage", "sign-url", uri, "--duration", strconv.Itoa(expiresIn)+"s")
+	if err != nil {
+		return "", err
+	}
+	// gcloud storage sign-url outputs the signed URL with other fields like "signed_url: https://..."
+	for _, line := range strings.Split(out, "\n") {
+		if u := strings.TrimPrefix(strings.TrimSpace(line), "signed_url:"); u != strings.TrimSpace(line) {
+			return strings.TrimSpace(u), nil
+		}
+	}
+	return "", fmt.Errorf("the signed URL isn't found in the output of gcloud: %s", out)
+}
+
+func runStorageCommand(ctx context.Context, bin string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, bin, args...) //nolint:gosec
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("run %s %s: %w", bin, args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// consoleURL returns the URL of the object in the AWS console or the Google Cloud console
+func consoleURL(typ, bucket, key string) string {
+	if typ == "gcs" {
+		return "https://console.cloud.google.com/storage/browser/_details/" + bucket + "/" + key
+	}
+	return "https://s3.console.aws.amazon.com/s3/object/" + bucket + "?prefix=" + url.QueryEscape(key)
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	SinkStatsD      = "statsd"
+	SinkOTLP        = "otlp"
+	SinkPushgateway = "pushgateway"
+)
+
+// Kind is a kind of metric
+type Kind int
+
+const (
+	// KindCount is a counter which is added to the total
+	KindCount Kind = iota
+	// KindTiming is a duration in milliseconds
+	KindTiming
+)
+
+// Metric is a data point recorded by tfcmt
+type Metric struct {
+	Name  string
+	Kind  Kind
+	Value int64
+	Tags  map[string]string
+}
+
+// Sink is an interface to send metrics.
+// Metrics of one notification are sent at once.
+type Sink interface {
+	Send(ctx context.Context, metrics []Metric) error
+}
+
+// Config is a configuration to create a Sink
+type Config struct {
+	// Sink is "statsd", "otlp", or "pushgateway"
+	Sink string
+	// Endpoint is the address of StatsD like "127.0.0.1:8125", the OTLP/HTTP endpoint like "http://localhost:4318",
+	// or the URL of the Pushgateway like "http://localhost:9091"
+	Endpoint string
+	// Prefix is prepended to metric names. The default value is "tfcmt."
+	Prefix string
+}
+
+const (
+	defaultPrefix        = "tfcmt."
+	defaultStatsDAddress = "127.0.0.1:8125"
+	defaultOTLPEndpoint  = "http://localhost:4318"
+	defaultPushgateway   = "http://localhost:9091"
+	defaultTimeout       = 5 * time.Second
+)
+
+// New returns a Sink. If cfg.Sink is empty, nil is returned and no metric is sent.
+func New(cfg Config) (Sink, error) {
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	switch cfg.Sink {
+	case "":
+		return nil, nil //nolint:nilnil
+	case SinkStatsD:
+		addr := cfg.Endpoint
+		if addr == "" {
+			addr = defaultStatsDAddress
+		}
+		return &StatsD{Address: addr, Prefix: prefix}, nil
+	case SinkOTLP:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = defaultOTLPEndpoint
+		}
+		return &OTLP{Endpoint: endpoint, Prefix: prefix}, nil
+	case SinkPushgateway:
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = defaultPushgateway
+		}
+		return &Pushgateway{Endpoint: endpoint, Prefix: prefix}, nil
+	default:
+		return nil, errors.New(`the metrics sink must be "statsd", "otlp", or "pushgateway": ` + cfg.Sink)
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		cfg  Config
+		exp  Sink
+		ok   bool
+	}{
+		{
+			name: "no sink",
+			ok:   true,
+		},
+		{
+			name: "statsd",
+			cfg:  Config{Sink: "statsd"},
+			exp:  &StatsD{Address: "127.0.0.1:8125", Prefix: "tfcmt."},
+			ok:   true,
+		},
+		{
+			name: "otlp",
+			cfg:  Config{Sink: "otlp", Endpoint: "http://otel:4318", Prefix: "ci."},
+			exp:  &OTLP{Endpoint: "http://otel:4318", Prefix: "ci."},
+			ok:   true,
+		},
+		{
+			name: "pushgateway",
+			cfg:  Config{Sink: "pushgateway"},
+			exp:  &Pushgateway{Endpoint: "http://localhost:9091", Prefix: "tfcmt."},
+			ok:   true,
+		},
+		{
+			name: "invalid sink",
+			cfg:  Config{Sink: "prometheus"},
+			ok:   false,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			sink, err := New(testCase.cfg)
+			if (err == nil) != testCase.ok {
+				t.Fatalf("got error %v", err)
+			}
+			if diff := cmp.Diff(testCase.exp, sink); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
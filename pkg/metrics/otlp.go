@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OTLP sends metrics to the OpenTelemetry collector with OTLP/HTTP in the JSON encoding
+type OTLP struct {
+	Endpoint string
+	Prefix   string
+	Client   *http.Client
+}
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpMetric struct {
+	Name  string     `json:"name"`
+	Unit  string     `json:"unit,omitempty"`
+	Sum   *otlpSum   `json:"sum,omitempty"`
+	Gauge *otlpGauge `json:"gauge,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpDataPoint `json:"dataPoints"`
+	AggregationTemporality int             `json:"aggregationTemporality"`
+	IsMonotonic            bool            `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpDataPoint struct {
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	TimeUnixNano string          `json:"timeUnixNano"`
+	AsInt        string          `json:"asInt"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// aggregationTemporalityDelta is AGGREGATION_TEMPORALITY_DELTA.
+// Each notification is reported as a delta because tfcmt is a short-lived process.
+const aggregationTemporalityDelta = 1
+
+// Send sends metrics to the OpenTelemetry collector
+func (o *OTLP) Send(ctx context.Context, metrics []Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(o.newRequest(metrics, time.Now()))
+	if err != nil {
+		return fmt.Errorf("marshal metrics as JSON: %w", err)
+	}
+	u := strings.TrimSuffix(o.Endpoint, "/") + "/v1/metrics"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a request to send metrics: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := o.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send metrics to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("send metrics to %s: status code %d", u, resp.StatusCode)
+	}
+	return nil
+}
+
+func (o *OTLP) newRequest(metrics []Metric, now time.Time) *otlpRequest {
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+	arr := make([]otlpMetric, len(metrics))
+	for i, metric := range metrics {
+		dp := otlpDataPoint{
+			Attributes:   otlpAttributes(metric.Tags),
+			TimeUnixNano: ts,
+			AsInt:        strconv.FormatInt(metric.Value, 10),
+		}
+		m := otlpMetric{
+			Name: o.Prefix + metric.Name,
+		}
+		if metric.Kind == KindTiming {
+			m.Unit = "ms"
+			m.Gauge = &otlpGauge{DataPoints: []otlpDataPoint{dp}}
+		} else {
+			m.Sum = &otlpSum{
+				DataPoints:             []otlpDataPoint{dp},
+				AggregationTemporality: aggregationTemporalityDelta,
+				IsMonotonic:            true,
+			}
+		}
+		arr[i] = m
+	}
+	return &otlpRequest{
+		ResourceMetrics: []otlpResourceMetrics{
+			{
+				Resource: otlpResource{
+					Attributes: otlpAttributes(map[string]string{"service.name": "tfcmt"}),
+				},
+				ScopeMetrics: []otlpScopeMetrics{
+					{
+						Scope:   otlpScope{Name: "tfcmt"},
+						Metrics: arr,
+					},
+				},
+			},
+		},
+	}
+}
+
+// otlpAttributes converts tags to attributes.
+// Unlike StatsD, values are JSON strings, so characters such as "," and "|" are kept as they are
+func otlpAttributes(tags map[string]string) []otlpAttribute {
+	if len(tags) == 0 {
+		return nil
+	}
+	attrs := make([]otlpAttribute, 0, len(tags))
+	for k, v := range tags {
+		attrs = append(attrs, otlpAttribute{Key: k, Value: otlpAnyValue{StringValue: v}})
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+	return attrs
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOTLPSend(t *testing.T) {
+	t.Parallel()
+	var req otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/metrics" {
+			t.Errorf("path should be /v1/metrics but got %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+	o := &OTLP{Endpoint: server.URL, Prefix: "tfcmt."}
+	if err := o.Send(context.Background(), []Metric{
+		{Name: "resources.add", Value: 3, Tags: map[string]string{"command": "plan", "target": `a,b|c="d"`}},
+		{Name: "notify.duration", Kind: KindTiming, Value: 150},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(metrics) != 2 { //nolint:gomnd
+		t.Fatalf("the number of metrics should be 2 but got %d", len(metrics))
+	}
+	if metrics[0].Name != "tfcmt.resources.add" || metrics[0].Sum == nil || metrics[0].Sum.DataPoints[0].AsInt != "3" {
+		t.Errorf("the counter is wrong: %+v", metrics[0])
+	}
+	if metrics[0].Sum == nil {
+		t.Fatal("the counter should be a sum")
+	}
+	if attrs := metrics[0].Sum.DataPoints[0].Attributes; len(attrs) != 2 || attrs[1].Value.StringValue != `a,b|c="d"` { //nolint:gomnd
+		t.Errorf("the tag value should be kept as it is: %+v", attrs)
+	}
+	if metrics[1].Gauge == nil || metrics[1].Unit != "ms" {
+		t.Errorf("the timing is wrong: %+v", metrics[1])
+	}
+}
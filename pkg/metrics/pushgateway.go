@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Pushgateway pushes metrics to the Prometheus Pushgateway in the text exposition format.
+// Tags are used as the grouping key, so the metrics of different repositories and targets don't overwrite each other
+type Pushgateway struct {
+	Endpoint string
+	Prefix   string
+	Client   *http.Client
+}
+
+// pushgatewayJob is the job label of the grouping key
+const pushgatewayJob = "tfcmt"
+
+var invalidPrometheusNameChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+// prometheusLabelValueReplacer escapes a backslash, a double quote, and a newline in label values
+// according to the text exposition format
+var prometheusLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Send pushes metrics to the Pushgateway. Metrics are grouped by tags and each group is pushed with a request
+func (p *Pushgateway) Send(ctx context.Context, metrics []Metric) error {
+	var keys []string
+	groups := map[string][]Metric{}
+	for _, metric := range metrics {
+		key := p.groupingPath(metric.Tags)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], metric)
+	}
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	for _, key := range keys {
+		u := strings.TrimSuffix(p.Endpoint, "/") + "/metrics/job/" + pushgatewayJob + key
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(p.format(groups[key])))
+		if err != nil {
+			return fmt.Errorf("create a request to push metrics: %w", err)
+		}
+		req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("push metrics to %s: %w", u, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("push metrics to %s: status code %d", u, resp.StatusCode)
+		}
+	}
+	return nil
+}
+
+// groupingPath returns the path of the grouping key like "/repo@base64/b3duZXIvcmVwbw==".
+// Label values are encoded with base64 because they may include slashes
+func (p *Pushgateway) groupingPath(tags map[string]string) string {
+	names := make([]string, 0, len(tags))
+	for k := range tags {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		v := base64.URLEncoding.EncodeToString([]byte(tags[name]))
+		if v == "" {
+			// an empty value is represented as "="
+			v = "="
+		}
+		b.WriteString("/" + prometheusName(name) + "@base64/" + v)
+	}
+	return b.String()
+}
+
+// format returns metrics in the text exposition format.
+// Values are the ones of the notification, so all metrics are gauges. Timings are converted to seconds.
+// Tags are also set as labels, which are same as the grouping key
+func (p *Pushgateway) format(metrics []Metric) string {
+	var b strings.Builder
+	for _, metric := range metrics {
+		name := prometheusName(p.Prefix + metric.Name)
+		value := strconv.FormatInt(metric.Value, 10)
+		if metric.Kind == KindTiming {
+			name += "_seconds"
+			value = strconv.FormatFloat(float64(metric.Value)/1000, 'f', -1, 64) //nolint:gomnd
+		}
+		fmt.Fprintf(&b, "# TYPE %s gauge\n%s%s %s\n", name, name, prometheusLabels(metric.Tags), value)
+	}
+	return b.String()
+}
+
+// prometheusLabels returns labels like `{repo="owner/repo",target="foo"}`
+func prometheusLabels(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(tags))
+	for k := range tags {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = prometheusName(name) + `="` + prometheusLabelValueReplacer.Replace(tags[name]) + `"`
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+// prometheusName replaces characters which can't be used in Prometheus metric and label names with "_"
+func prometheusName(name string) string {
+	return invalidPrometheusNameChars.ReplaceAllString(name, "_")
+}
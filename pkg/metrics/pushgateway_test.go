@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPushgatewaySend(t *testing.T) {
+	t.Parallel()
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		body = string(b)
+	}))
+	defer server.Close()
+	p := &Pushgateway{Endpoint: server.URL, Prefix: "tfcmt."}
+	tags := map[string]string{"repo": "owner/repo", "target": ""}
+	if err := p.Send(context.Background(), []Metric{
+		{Name: "resources.add", Value: 3, Tags: tags},
+		{Name: "notify.duration", Kind: KindTiming, Value: 150, Tags: tags},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("/metrics/job/tfcmt/repo@base64/b3duZXIvcmVwbw==/target@base64/=", path); diff != "" {
+		t.Error(diff)
+	}
+	exp := `# TYPE tfcmt_resources_add gauge
+tfcmt_resources_add{repo="owner/repo",target=""} 3
+# TYPE tfcmt_notify_duration_seconds gauge
+tfcmt_notify_duration_seconds{repo="owner/repo",target=""} 0.15
+`
+	if diff := cmp.Diff(exp, body); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestPushgatewayFormat(t *testing.T) {
+	t.Parallel()
+	p := &Pushgateway{}
+	got := p.format([]Metric{
+		{Name: "parse_error", Value: 1, Tags: map[string]string{"target": "a\\b\"c\nd"}},
+	})
+	exp := `# TYPE parse_error gauge
+parse_error{target="a\\b\"c\nd"} 1
+`
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Error(diff)
+	}
+}
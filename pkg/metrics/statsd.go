@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StatsD sends metrics to StatsD over UDP.
+// Tags are sent in the DogStatsD format.
+type StatsD struct {
+	Address string
+	Prefix  string
+}
+
+// Send sends metrics to StatsD
+func (s *StatsD) Send(ctx context.Context, metrics []Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", s.Address)
+	if err != nil {
+		return fmt.Errorf("connect to StatsD %s: %w", s.Address, err)
+	}
+	defer conn.Close()
+	lines := make([]string, len(metrics))
+	for i, metric := range metrics {
+		lines[i] = s.format(metric)
+	}
+	if _, err := conn.Write([]byte(strings.Join(lines, "\n"))); err != nil {
+		return fmt.Errorf("send metrics to StatsD %s: %w", s.Address, err)
+	}
+	return nil
+}
+
+func (s *StatsD) format(metric Metric) string {
+	typ := "c"
+	if metric.Kind == KindTiming {
+		typ = "ms"
+	}
+	line := s.Prefix + metric.Name + ":" + strconv.FormatInt(metric.Value, 10) + "|" + typ
+	if len(metric.Tags) == 0 {
+		return line
+	}
+	tags := make([]string, 0, len(metric.Tags))
+	for k, v := range metric.Tags {
+		tags = append(tags, k+":"+escapeStatsDTag(v))
+	}
+	sort.Strings(tags)
+	return line + "|#" + strings.Join(tags, ",")
+}
+
+// statsDTagReplacer replaces characters which can't be used in DogStatsD tags.
+// "," separates tags, "|" separates fields, "=" is a separator in some StatsD servers, and a newline separates metrics.
+// DogStatsD has no way to escape them, so they are replaced with "_"
+var statsDTagReplacer = strings.NewReplacer(",", "_", "|", "_", "=", "_", "\n", "_")
+
+func escapeStatsDTag(v string) string {
+	return statsDTagReplacer.Replace(v)
+}
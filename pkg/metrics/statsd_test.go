@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStatsDSend(t *testing.T) {
+	t.Parallel()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	s := &StatsD{Address: conn.LocalAddr().String(), Prefix: "tfcmt."}
+	if err := s.Send(context.Background(), []Metric{
+		{Name: "resources.destroy", Value: 2, Tags: map[string]string{"repo": "foo/bar", "command": "plan"}},
+		{Name: "notify.duration", Kind: KindTiming, Value: 150},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "tfcmt.resources.destroy:2|c|#command:plan,repo:foo/bar\ntfcmt.notify.duration:150|ms"
+	if diff := cmp.Diff(exp, string(buf[:n])); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestStatsDFormat(t *testing.T) {
+	t.Parallel()
+	s := &StatsD{}
+	got := s.format(Metric{Name: "parse_error", Value: 1, Tags: map[string]string{"target": "a,b|c=d\ne"}})
+	exp := "parse_error:1|c|#target:a_b_c_d_e"
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,170 @@
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const apiVersion = "7.0"
+
+// API is Azure DevOps Services REST API interface
+type API interface {
+	CreateThread(ctx context.Context, pullRequestID int, body string) error
+	ListPullRequestsByCommit(ctx context.Context, sha string) ([]*PullRequest, error)
+}
+
+// PullRequest is a subset of the pull request of Azure Repos
+type PullRequest struct {
+	PullRequestID int    `json:"pullRequestId"`
+	Status        string `json:"status"`
+}
+
+// Error is an error response of Azure DevOps Services REST API
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Azure DevOps API returned the status code %d: %s", e.StatusCode, e.Message)
+}
+
+// Credential is used to authenticate Azure DevOps Services REST API.
+// If AccessToken is set, the token is used as a bearer token. Otherwise PersonalAccessToken is used for basic authentication
+type Credential struct {
+	// AccessToken is System.AccessToken of Azure Pipelines
+	AccessToken         string
+	PersonalAccessToken string
+}
+
+// IsEmpty returns true if no credential is set
+func (c *Credential) IsEmpty() bool {
+	return c.AccessToken == "" && c.PersonalAccessToken == ""
+}
+
+// AzureDevOps calls Azure DevOps Services REST API of the repository
+type AzureDevOps struct {
+	client *http.Client
+	// orgURL is the URL of the organization like https://dev.azure.com/example
+	orgURL     string
+	credential Credential
+	project    string
+	repo       string
+}
+
+func newAzureDevOps(client *http.Client, orgURL string, credential Credential, project, repo string) *AzureDevOps {
+	return &AzureDevOps{
+		client:     client,
+		orgURL:     strings.TrimSuffix(orgURL, "/"),
+		credential: credential,
+		project:    project,
+		repo:       repo,
+	}
+}
+
+// thread is the request body to create a thread
+type thread struct {
+	Comments []*comment `json:"comments"`
+	Status   string     `json:"status"`
+}
+
+type comment struct {
+	ParentCommentID int    `json:"parentCommentId"`
+	Content         string `json:"content"`
+	CommentType     string `json:"commentType"`
+}
+
+// CreateThread is a wrapper of https://learn.microsoft.com/en-us/rest/api/azure/devops/git/pull-request-threads/create
+func (a *AzureDevOps) CreateThread(ctx context.Context, pullRequestID int, body string) error {
+	return a.do(ctx, http.MethodPost, "/pullRequests/"+strconv.Itoa(pullRequestID)+"/threads", &thread{
+		Comments: []*comment{
+			{
+				Content:     body,
+				CommentType: "text",
+			},
+		},
+		Status: "active",
+	}, nil)
+}
+
+// ListPullRequestsByCommit is a wrapper of https://learn.microsoft.com/en-us/rest/api/azure/devops/git/pull-request-query/get .
+// Pull requests whose merge commit is the commit are returned
+func (a *AzureDevOps) ListPullRequestsByCommit(ctx context.Context, sha string) ([]*PullRequest, error) {
+	body := map[string]interface{}{
+		"queries": []map[string]interface{}{
+			{
+				"type":  "lastMergeCommit",
+				"items": []string{sha},
+			},
+		},
+	}
+	ret := struct {
+		Results []map[string][]*PullRequest `json:"results"`
+	}{}
+	if err := a.do(ctx, http.MethodPost, "/pullrequestquery", body, &ret); err != nil {
+		return nil, err
+	}
+	var prs []*PullRequest
+	for _, result := range ret.Results {
+		prs = append(prs, result[sha]...)
+	}
+	return prs, nil
+}
+
+// do calls the API of the repository. The request body and the response body are JSON
+func (a *AzureDevOps) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal the request body: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	u := a.orgURL + "/" + url.PathEscape(a.project) + "/_apis/git/repositories/" + url.PathEscape(a.repo) + path + "?api-version=" + apiVersion
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	if a.credential.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.credential.AccessToken)
+	} else {
+		// the user name is empty for a personal access token
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+a.credential.PersonalAccessToken)))
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call Azure DevOps API: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read the response body: %w", err)
+	}
+	// Azure DevOps returns the sign-in page with 203 if the credential is invalid
+	if resp.StatusCode >= http.StatusBadRequest || resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		return &Error{
+			StatusCode: resp.StatusCode,
+			Message:    string(b),
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("parse the response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	threads     map[int][]string
+	prsByCommit map[string][]*PullRequest
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		threads:     map[int][]string{},
+		prsByCommit: map[string][]*PullRequest{},
+	}
+}
+
+func (a *fakeAPI) CreateThread(ctx context.Context, pullRequestID int, body string) error {
+	a.threads[pullRequestID] = append(a.threads[pullRequestID], body)
+	return nil
+}
+
+func (a *fakeAPI) ListPullRequestsByCommit(ctx context.Context, sha string) ([]*PullRequest, error) {
+	return a.prsByCommit[sha], nil
+}
+
+func TestAzureDevOps(t *testing.T) {
+	t.Parallel()
+	type request struct {
+		Method string
+		Path   string
+		Query  string
+		Auth   string
+		Body   map[string]interface{}
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+			Auth:   r.Header.Get("Authorization"),
+		}
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&req.Body)
+		}
+		requests = append(requests, req)
+		switch r.URL.Path {
+		case "/example/infra/_apis/git/repositories/tfcmt/pullRequests/1/threads":
+			_, _ = w.Write([]byte(`{"id":1}`))
+		case "/example/infra/_apis/git/repositories/tfcmt/pullrequestquery":
+			_, _ = w.Write([]byte(`{"results":[{"abcd":[{"pullRequestId":3,"status":"completed"}]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	a := newAzureDevOps(server.Client(), server.URL+"/example/", Credential{AccessToken: "xxx"}, "infra", "tfcmt")
+	if err := a.CreateThread(ctx, 1, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	prs, err := a.ListPullRequestsByCommit(ctx, "abcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*PullRequest{{PullRequestID: 3, Status: "completed"}}, prs); diff != "" {
+		t.Error(diff)
+	}
+	a.credential = Credential{PersonalAccessToken: "pat"}
+	if err := a.CreateThread(ctx, 2, "hello"); err == nil {
+		t.Fatal("error should be returned")
+	}
+	thread := map[string]interface{}{
+		"comments": []interface{}{
+			map[string]interface{}{"parentCommentId": float64(0), "content": "hello", "commentType": "text"},
+		},
+		"status": "active",
+	}
+	exp := []request{
+		{
+			Method: http.MethodPost,
+			Path:   "/example/infra/_apis/git/repositories/tfcmt/pullRequests/1/threads",
+			Query:  "api-version=7.0",
+			Auth:   "Bearer xxx",
+			Body:   thread,
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "/example/infra/_apis/git/repositories/tfcmt/pullrequestquery",
+			Query:  "api-version=7.0",
+			Auth:   "Bearer xxx",
+			Body: map[string]interface{}{
+				"queries": []interface{}{
+					map[string]interface{}{"type": "lastMergeCommit", "items": []interface{}{"abcd"}},
+				},
+			},
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "/example/infra/_apis/git/repositories/tfcmt/pullRequests/2/threads",
+			Query:  "api-version=7.0",
+			// base64 of ":pat"
+			Auth: "Basic OnBhdA==",
+			Body: thread,
+		},
+	}
+	if diff := cmp.Diff(exp, requests); diff != "" {
+		t.Error(diff)
+	}
+}
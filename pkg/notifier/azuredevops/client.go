@@ -0,0 +1,127 @@
+package azuredevops
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	// EnvPersonalAccessToken is the personal access token. The name is same as Azure DevOps CLI
+	EnvPersonalAccessToken = "AZURE_DEVOPS_EXT_PAT" //nolint:gosec
+	// EnvAccessToken is System.AccessToken of Azure Pipelines. The variable must be mapped to the environment variable explicitly
+	EnvAccessToken = "SYSTEM_ACCESSTOKEN" //nolint:gosec
+	// EnvCollectionURI is the URL of the organization set by Azure Pipelines
+	EnvCollectionURI = "SYSTEM_COLLECTIONURI"
+)
+
+// Client is a API client for Azure DevOps
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for Azure DevOps client
+type Config struct {
+	// Credential is used to call Azure DevOps API. If it is empty, the credential is read from the environment variables
+	Credential Credential
+	// OrgURL is the URL of the organization like https://dev.azure.com/example. If this is empty, SYSTEM_COLLECTIONURI is used
+	OrgURL string
+	// Project is the name of the project
+	Project string
+	// Repo is the name or id of the repository
+	Repo string
+	PR   PullRequestInfo
+	CI   string
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// Template is used for all Terraform command output
+	Template           *terraform.Template
+	ParseErrorTemplate *terraform.Template
+	Vars               map[string]string
+	Templates          map[string]string
+	UseRawOutput       bool
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	// MaxResources is the maximum number of resources listed per action in the built-in templates. 0 means unlimited
+	MaxResources int
+	// DryRun renders the comment but doesn't post it.
+	// The comment is written to DryRunOutput. If DryRunOutput is empty, the comment is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+	// Output is the file path where the posted comment is also written. If this is empty, the comment isn't written
+	Output string
+}
+
+// PullRequestInfo represents Azure Repos Pull Request metadata
+type PullRequestInfo struct {
+	Revision string
+	Number   int
+}
+
+// IsNumber returns true if PullRequestInfo is Pull Request build
+func (pr *PullRequestInfo) IsNumber() bool {
+	return pr.Number != 0
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	credential := cfg.Credential
+	if credential.IsEmpty() {
+		credential = Credential{
+			AccessToken:         os.Getenv(EnvAccessToken),
+			PersonalAccessToken: os.Getenv(EnvPersonalAccessToken),
+		}
+	}
+	if credential.IsEmpty() && !cfg.DryRun {
+		return &Client{}, errors.New("azure devops credential is missing. Please set either SYSTEM_ACCESSTOKEN or AZURE_DEVOPS_EXT_PAT")
+	}
+	orgURL := strings.TrimPrefix(cfg.OrgURL, "$")
+	if orgURL == "" || orgURL == EnvCollectionURI {
+		orgURL = os.Getenv(EnvCollectionURI)
+	}
+	if orgURL == "" && !cfg.DryRun {
+		return &Client{}, errors.New("the url of the azure devops organization is missing")
+	}
+	return &Client{
+		Config: cfg,
+		API:    newAzureDevOps(http.DefaultClient, orgURL, credential, cfg.Project, cfg.Repo),
+	}, nil
+}
+
+// NewNotifier returns a notifier.Notifier which posts the result to Azure Repos.
+// If Parser and templates aren't set, the ones for terraform plan are used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	_, isApply := cfg.Parser.(*terraform.ApplyParser)
+	if p, ok := cfg.Parser.(*terraform.JSONParser); ok {
+		isApply = p.Command == terraform.CommandApply
+	}
+	// If AutoParser is used, templates are decided by the detected command
+	_, isAuto := cfg.Parser.(*terraform.AutoParser)
+	if cfg.Template == nil && !isAuto {
+		if isApply {
+			cfg.Template = terraform.NewApplyTemplate("")
+		} else {
+			cfg.Template = terraform.NewPlanTemplate("")
+		}
+	}
+	if cfg.ParseErrorTemplate == nil && !isAuto {
+		if isApply {
+			cfg.ParseErrorTemplate = terraform.NewApplyParseErrorTemplate("")
+		} else {
+			cfg.ParseErrorTemplate = terraform.NewPlanParseErrorTemplate("")
+		}
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
@@ -0,0 +1,87 @@
+package azuredevops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService posts the result as a thread of the pull request
+type NotifyService struct {
+	client *Client
+}
+
+// Notify posts comment optimized for notifications
+func (a *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := a.client.Config
+
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+
+	if result.IsApply && !cfg.DryRun && !cfg.PR.IsNumber() && cfg.PR.Revision != "" {
+		// the pull request is found by the merge commit
+		prs, err := a.client.API.ListPullRequestsByCommit(ctx, cfg.PR.Revision)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+				"sha":     cfg.PR.Revision,
+			}).WithError(err).Warn("list pull requests associated with the commit")
+		} else if pr := findCompletedPR(prs); pr != nil {
+			cfg.PR.Number = pr.PullRequestID
+		}
+	}
+
+	body, err := result.Render(notifier.RenderOption{
+		Template:           cfg.Template,
+		ParseErrorTemplate: cfg.ParseErrorTemplate,
+		Link:               cfg.CI,
+		Vars:               cfg.Vars,
+		Templates:          cfg.Templates,
+		UseRawOutput:       cfg.UseRawOutput,
+		MaxResources:       cfg.MaxResources,
+	})
+	if err != nil || body == "" {
+		return result.ExitCode, err
+	}
+
+	if err := a.post(ctx, &cfg, body); err != nil {
+		return result.ExitCode, err
+	}
+	if err := notifier.WriteOutput(cfg.Output, body); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+// findCompletedPR returns the completed pull request. If no pull request has been completed, the first one is returned
+func findCompletedPR(prs []*PullRequest) *PullRequest {
+	for _, pr := range prs {
+		if pr.Status == "completed" {
+			return pr
+		}
+	}
+	if len(prs) != 0 {
+		return prs[0]
+	}
+	return nil
+}
+
+// post posts a thread of the pull request. Azure Repos doesn't support comments of commits
+func (a *NotifyService) post(ctx context.Context, cfg *Config, body string) error {
+	if cfg.DryRun {
+		return notifier.WriteDryRunOutput(cfg.DryRunOutput, body)
+	}
+	if !cfg.PR.IsNumber() {
+		return errors.New("the pull request id is required because Azure Repos doesn't support comments of commits")
+	}
+	if err := a.client.API.CreateThread(ctx, cfg.PR.Number, body); err != nil {
+		return fmt.Errorf("create a thread of the pull request: %w", err)
+	}
+	return nil
+}
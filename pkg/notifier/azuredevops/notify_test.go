@@ -0,0 +1,104 @@
+package azuredevops
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func newFakeConfig() Config {
+	return Config{
+		Project: "infra",
+		Repo:    "tfcmt",
+		PR: PullRequestInfo{
+			Revision: "abcd",
+			Number:   1,
+		},
+		Parser:             terraform.NewPlanParser(),
+		Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+		ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanParseErrorTemplate),
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		cfg        func(cfg *Config)
+		output     string
+		prs        []*PullRequest
+		expPR      int
+		expContain string
+		isErr      bool
+	}{
+		{
+			name:       "plan",
+			output:     "No changes. Infrastructure is up-to-date.",
+			expPR:      1,
+			expContain: "No changes",
+		},
+		{
+			name: "apply after the pull request is completed",
+			cfg: func(cfg *Config) {
+				cfg.PR.Number = 0
+				cfg.Parser = terraform.NewApplyParser()
+				cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+			},
+			output: "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.",
+			prs: []*PullRequest{
+				{PullRequestID: 2, Status: "abandoned"},
+				{PullRequestID: 3, Status: "completed"},
+			},
+			expPR:      3,
+			expContain: "Apply complete!",
+		},
+		{
+			name: "pull request isn't found",
+			cfg: func(cfg *Config) {
+				cfg.PR.Number = 0
+			},
+			output: "No changes. Infrastructure is up-to-date.",
+			isErr:  true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := newFakeAPI()
+			api.prsByCommit["abcd"] = testCase.prs
+			cfg := newFakeConfig()
+			if testCase.cfg != nil {
+				testCase.cfg(&cfg)
+			}
+			ntf := &NotifyService{
+				client: &Client{
+					Config: cfg,
+					API:    api,
+				},
+			}
+			_, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+			})
+			if err != nil {
+				if !testCase.isErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			if testCase.isErr {
+				t.Fatal("error should be returned")
+			}
+			threads := api.threads[testCase.expPR]
+			if len(threads) != 1 {
+				t.Fatalf("a thread should be created in the pull request %d: %v", testCase.expPR, api.threads)
+			}
+			if !strings.Contains(threads[0], testCase.expContain) {
+				t.Errorf("unexpected thread: %s", threads[0])
+			}
+		})
+	}
+}
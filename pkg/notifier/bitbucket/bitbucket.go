@@ -0,0 +1,140 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// API is Bitbucket Cloud API interface
+type API interface {
+	CreatePullRequestComment(ctx context.Context, id int, body string) error
+	CreateCommitComment(ctx context.Context, sha, body string) error
+	ListPullRequestsByCommit(ctx context.Context, sha string) ([]*PullRequest, error)
+}
+
+// PullRequest is a subset of the pull request of Bitbucket Cloud API
+type PullRequest struct {
+	ID    int    `json:"id"`
+	State string `json:"state"`
+}
+
+// Error is an error response of Bitbucket Cloud API
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Bitbucket API returned the status code %d: %s", e.StatusCode, e.Message)
+}
+
+// Credential is used to authenticate Bitbucket Cloud API.
+// If AccessToken is set, the token is used as a bearer token. Otherwise Username and AppPassword are used for basic authentication
+type Credential struct {
+	Username    string
+	AppPassword string
+	AccessToken string
+}
+
+// IsEmpty returns true if no credential is set
+func (c *Credential) IsEmpty() bool {
+	return c.AccessToken == "" && (c.Username == "" || c.AppPassword == "")
+}
+
+// Bitbucket calls Bitbucket Cloud API 2.0 of the repository
+type Bitbucket struct {
+	client     *http.Client
+	apiURL     string
+	credential Credential
+	workspace  string
+	repo       string
+}
+
+// CreatePullRequestComment is a wrapper of https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-pullrequests-pull-request-id-comments-post
+func (b *Bitbucket) CreatePullRequestComment(ctx context.Context, id int, body string) error {
+	return b.do(ctx, http.MethodPost, "/pullrequests/"+strconv.Itoa(id)+"/comments", newComment(body), nil)
+}
+
+// CreateCommitComment is a wrapper of https://developer.atlassian.com/cloud/bitbucket/rest/api-group-commits/#api-repositories-workspace-repo-slug-commit-commit-comments-post
+func (b *Bitbucket) CreateCommitComment(ctx context.Context, sha, body string) error {
+	return b.do(ctx, http.MethodPost, "/commit/"+url.PathEscape(sha)+"/comments", newComment(body), nil)
+}
+
+// ListPullRequestsByCommit is a wrapper of https://developer.atlassian.com/cloud/bitbucket/rest/api-group-pullrequests/#api-repositories-workspace-repo-slug-commit-commit-pullrequests-get .
+// The first page is only returned
+func (b *Bitbucket) ListPullRequestsByCommit(ctx context.Context, sha string) ([]*PullRequest, error) {
+	page := struct {
+		Values []*PullRequest `json:"values"`
+	}{}
+	if err := b.do(ctx, http.MethodGet, "/commit/"+url.PathEscape(sha)+"/pullrequests", nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Values, nil
+}
+
+type comment struct {
+	Content struct {
+		Raw string `json:"raw"`
+	} `json:"content"`
+}
+
+func newComment(body string) *comment {
+	c := &comment{}
+	c.Content.Raw = body
+	return c
+}
+
+// do calls the API of the repository. The request body and the response body are JSON
+func (b *Bitbucket) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal the request body: %w", err)
+		}
+		reqBody = bytes.NewReader(bs)
+	}
+	u := strings.TrimSuffix(b.apiURL, "/") + "/repositories/" + url.PathEscape(b.workspace) + "/" + url.PathEscape(b.repo) + path
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	if b.credential.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+b.credential.AccessToken)
+	} else {
+		req.SetBasicAuth(b.credential.Username, b.credential.AppPassword)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call Bitbucket API: %w", err)
+	}
+	defer resp.Body.Close()
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read the response body: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &Error{
+			StatusCode: resp.StatusCode,
+			Message:    string(bs),
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(bs, out); err != nil {
+		return fmt.Errorf("parse the response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	API
+	prComments     map[int][]string
+	commitComments map[string][]string
+	prsByCommit    map[string][]*PullRequest
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		prComments:     map[int][]string{},
+		commitComments: map[string][]string{},
+		prsByCommit:    map[string][]*PullRequest{},
+	}
+}
+
+func (b *fakeAPI) CreatePullRequestComment(ctx context.Context, id int, body string) error {
+	b.prComments[id] = append(b.prComments[id], body)
+	return nil
+}
+
+func (b *fakeAPI) CreateCommitComment(ctx context.Context, sha, body string) error {
+	b.commitComments[sha] = append(b.commitComments[sha], body)
+	return nil
+}
+
+func (b *fakeAPI) ListPullRequestsByCommit(ctx context.Context, sha string) ([]*PullRequest, error) {
+	return b.prsByCommit[sha], nil
+}
+
+func TestBitbucket(t *testing.T) {
+	t.Parallel()
+	type request struct {
+		Method string
+		Path   string
+		Auth   string
+		Body   *comment
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Auth:   r.Header.Get("Authorization"),
+		}
+		if r.Method == http.MethodPost {
+			req.Body = &comment{}
+			_ = json.NewDecoder(r.Body).Decode(req.Body)
+		}
+		requests = append(requests, req)
+		switch r.URL.Path {
+		case "/2.0/repositories/suzuki-shunsuke/tfcmt/commit/abcd/pullrequests":
+			_, _ = w.Write([]byte(`{"values":[{"id":3,"state":"MERGED"}]}`))
+		case "/2.0/repositories/suzuki-shunsuke/tfcmt/pullrequests/2/comments":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	b := &Bitbucket{
+		client:     server.Client(),
+		apiURL:     server.URL + "/2.0",
+		credential: Credential{Username: "foo", AppPassword: "bar"},
+		workspace:  "suzuki-shunsuke",
+		repo:       "tfcmt",
+	}
+	if err := b.CreatePullRequestComment(ctx, 1, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	prs, err := b.ListPullRequestsByCommit(ctx, "abcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*PullRequest{{ID: 3, State: "MERGED"}}, prs); diff != "" {
+		t.Error(diff)
+	}
+	b.credential = Credential{AccessToken: "xxx"}
+	if err := b.CreateCommitComment(ctx, "abcd", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.CreatePullRequestComment(ctx, 2, "hello"); err == nil {
+		t.Fatal("error should be returned")
+	}
+	basic := "Basic Zm9vOmJhcg=="
+	exp := []request{
+		{
+			Method: http.MethodPost,
+			Path:   "/2.0/repositories/suzuki-shunsuke/tfcmt/pullrequests/1/comments",
+			Auth:   basic,
+			Body:   newComment("hello"),
+		},
+		{
+			Method: http.MethodGet,
+			Path:   "/2.0/repositories/suzuki-shunsuke/tfcmt/commit/abcd/pullrequests",
+			Auth:   basic,
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "/2.0/repositories/suzuki-shunsuke/tfcmt/commit/abcd/comments",
+			Auth:   "Bearer xxx",
+			Body:   newComment("hello"),
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "/2.0/repositories/suzuki-shunsuke/tfcmt/pullrequests/2/comments",
+			Auth:   "Bearer xxx",
+			Body:   newComment("hello"),
+		},
+	}
+	if diff := cmp.Diff(exp, requests); diff != "" {
+		t.Error(diff)
+	}
+}
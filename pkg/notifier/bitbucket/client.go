@@ -0,0 +1,126 @@
+package bitbucket
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	// EnvUsername is the username of the app password
+	EnvUsername = "BITBUCKET_USERNAME"
+	// EnvAppPassword is the app password
+	EnvAppPassword = "BITBUCKET_APP_PASSWORD" //nolint:gosec
+	// EnvAccessToken is the OAuth access token or the repository access token
+	EnvAccessToken = "BITBUCKET_ACCESS_TOKEN" //nolint:gosec
+)
+
+const defaultAPIURL = "https://api.bitbucket.org/2.0"
+
+// Client is a API client for Bitbucket Cloud
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for Bitbucket Cloud client
+type Config struct {
+	// Credential is used to call Bitbucket Cloud API. If it is empty, the credential is read from the environment variables
+	Credential Credential
+	// Owner is the workspace of the repository
+	Owner string
+	// Repo is the slug of the repository
+	Repo string
+	PR   PullRequestInfo
+	CI   string
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// Template is used for all Terraform command output
+	Template           *terraform.Template
+	ParseErrorTemplate *terraform.Template
+	Vars               map[string]string
+	Templates          map[string]string
+	UseRawOutput       bool
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	// MaxResources is the maximum number of resources listed per action in the built-in templates. 0 means unlimited
+	MaxResources int
+	// DryRun renders the comment but doesn't post it.
+	// The comment is written to DryRunOutput. If DryRunOutput is empty, the comment is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+	// Output is the file path where the posted comment is also written. If this is empty, the comment isn't written
+	Output string
+}
+
+// PullRequestInfo represents Bitbucket Pull Request metadata
+type PullRequestInfo struct {
+	Revision string
+	Number   int
+}
+
+// IsNumber returns true if PullRequestInfo is Pull Request build
+func (pr *PullRequestInfo) IsNumber() bool {
+	return pr.Number != 0
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	credential := cfg.Credential
+	if credential.IsEmpty() {
+		credential = Credential{
+			Username:    os.Getenv(EnvUsername),
+			AppPassword: os.Getenv(EnvAppPassword),
+			AccessToken: os.Getenv(EnvAccessToken),
+		}
+	}
+	if credential.IsEmpty() && !cfg.DryRun {
+		return &Client{}, errors.New("bitbucket credential is missing. Please set either BITBUCKET_ACCESS_TOKEN or both BITBUCKET_USERNAME and BITBUCKET_APP_PASSWORD")
+	}
+	return &Client{
+		Config: cfg,
+		API: &Bitbucket{
+			client:     http.DefaultClient,
+			apiURL:     defaultAPIURL,
+			credential: credential,
+			workspace:  cfg.Owner,
+			repo:       cfg.Repo,
+		},
+	}, nil
+}
+
+// NewNotifier returns a notifier.Notifier which posts the result to Bitbucket Cloud.
+// If Parser and templates aren't set, the ones for terraform plan are used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	_, isApply := cfg.Parser.(*terraform.ApplyParser)
+	if p, ok := cfg.Parser.(*terraform.JSONParser); ok {
+		isApply = p.Command == terraform.CommandApply
+	}
+	// If AutoParser is used, templates are decided by the detected command
+	_, isAuto := cfg.Parser.(*terraform.AutoParser)
+	if cfg.Template == nil && !isAuto {
+		if isApply {
+			cfg.Template = terraform.NewApplyTemplate("")
+		} else {
+			cfg.Template = terraform.NewPlanTemplate("")
+		}
+	}
+	if cfg.ParseErrorTemplate == nil && !isAuto {
+		if isApply {
+			cfg.ParseErrorTemplate = terraform.NewApplyParseErrorTemplate("")
+		} else {
+			cfg.ParseErrorTemplate = terraform.NewPlanParseErrorTemplate("")
+		}
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
@@ -0,0 +1,94 @@
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService posts the result as a comment of the pull request or the commit
+type NotifyService struct {
+	client *Client
+}
+
+// Notify posts comment optimized for notifications
+func (b *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := b.client.Config
+
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+
+	if result.IsApply && !cfg.DryRun && !cfg.PR.IsNumber() && cfg.PR.Revision != "" {
+		// the pull request is found by the merged commit
+		prs, err := b.client.API.ListPullRequestsByCommit(ctx, cfg.PR.Revision)
+		if err != nil {
+			// the commit is commented instead
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+				"sha":     cfg.PR.Revision,
+			}).WithError(err).Warn("list pull requests associated with the commit")
+		} else if pr := findMergedPR(prs); pr != nil {
+			cfg.PR.Number = pr.ID
+		}
+	}
+
+	body, err := result.Render(notifier.RenderOption{
+		Template:           cfg.Template,
+		ParseErrorTemplate: cfg.ParseErrorTemplate,
+		Link:               cfg.CI,
+		Vars:               cfg.Vars,
+		Templates:          cfg.Templates,
+		UseRawOutput:       cfg.UseRawOutput,
+		MaxResources:       cfg.MaxResources,
+	})
+	if err != nil || body == "" {
+		return result.ExitCode, err
+	}
+
+	if err := b.post(ctx, &cfg, body); err != nil {
+		return result.ExitCode, err
+	}
+	if err := notifier.WriteOutput(cfg.Output, body); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+// findMergedPR returns the merged pull request. If no pull request has been merged, the first one is returned
+func findMergedPR(prs []*PullRequest) *PullRequest {
+	for _, pr := range prs {
+		if pr.State == "MERGED" {
+			return pr
+		}
+	}
+	if len(prs) != 0 {
+		return prs[0]
+	}
+	return nil
+}
+
+// post posts a comment of the pull request. If the target isn't a pull request, the result is posted as a comment of the commit
+func (b *NotifyService) post(ctx context.Context, cfg *Config, body string) error {
+	if cfg.DryRun {
+		return notifier.WriteDryRunOutput(cfg.DryRunOutput, body)
+	}
+	if cfg.PR.IsNumber() {
+		if err := b.client.API.CreatePullRequestComment(ctx, cfg.PR.Number, body); err != nil {
+			return fmt.Errorf("create a comment of the pull request: %w", err)
+		}
+		return nil
+	}
+	if cfg.PR.Revision == "" {
+		return errors.New("neither pull request number nor commit SHA is set")
+	}
+	if err := b.client.API.CreateCommitComment(ctx, cfg.PR.Revision, body); err != nil {
+		return fmt.Errorf("create a comment of the commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package bitbucket
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func newFakeConfig() Config {
+	return Config{
+		Owner: "suzuki-shunsuke",
+		Repo:  "tfcmt",
+		PR: PullRequestInfo{
+			Revision: "abcd",
+			Number:   1,
+		},
+		Parser:             terraform.NewPlanParser(),
+		Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+		ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanParseErrorTemplate),
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		cfg           func(cfg *Config)
+		prs           []*PullRequest
+		output        string
+		expPR         int
+		expCommit     string
+		expContaining string
+	}{
+		{
+			name:          "plan",
+			output:        "No changes. Infrastructure is up-to-date.",
+			expPR:         1,
+			expContaining: "No changes",
+		},
+		{
+			name: "plan without pull request",
+			cfg: func(cfg *Config) {
+				cfg.PR.Number = 0
+			},
+			output:        "No changes. Infrastructure is up-to-date.",
+			expCommit:     "abcd",
+			expContaining: "No changes",
+		},
+		{
+			name: "apply finds the merged pull request",
+			cfg: func(cfg *Config) {
+				cfg.PR.Number = 0
+				cfg.Parser = terraform.NewApplyParser()
+				cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+			},
+			prs: []*PullRequest{
+				{ID: 2, State: "DECLINED"},
+				{ID: 3, State: "MERGED"},
+			},
+			output:        "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			expPR:         3,
+			expContaining: "Apply complete!",
+		},
+		{
+			name:          "parse error",
+			output:        "foo",
+			expPR:         1,
+			expContaining: "It failed to parse the result.",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := newFakeAPI()
+			api.prsByCommit["abcd"] = testCase.prs
+			cfg := newFakeConfig()
+			if testCase.cfg != nil {
+				testCase.cfg(&cfg)
+			}
+			ntf := &NotifyService{
+				client: &Client{
+					Config: cfg,
+					API:    api,
+				},
+			}
+			if _, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			var comments []string
+			if testCase.expCommit != "" {
+				comments = api.commitComments[testCase.expCommit]
+			} else {
+				comments = api.prComments[testCase.expPR]
+			}
+			if len(comments) != 1 {
+				t.Fatalf("a comment should be posted: %v %v", api.prComments, api.commitComments)
+			}
+			if !strings.Contains(comments[0], testCase.expContaining) {
+				t.Errorf("unexpected comment: %s", comments[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package codecommit
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	envRegion        = "AWS_REGION"
+	envDefaultRegion = "AWS_DEFAULT_REGION"
+	// EnvSourceRepoURL is the URL of the source repository of CodeBuild like https://git-codecommit.us-east-1.amazonaws.com/v1/repos/tfcmt
+	EnvSourceRepoURL = "CODEBUILD_SOURCE_REPO_URL"
+)
+
+// Client is a API client for AWS CodeCommit
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for AWS CodeCommit client
+type Config struct {
+	// Credential is used to call CodeCommit API.
+	// If it is empty, the credential is read from the environment variables or the container credential endpoint of CodeBuild
+	Credential Credential
+	// Region is the region of the repository. If this is empty, AWS_REGION and AWS_DEFAULT_REGION are used
+	Region string
+	// Repo is the name of the repository. If this is empty, the name is got from CODEBUILD_SOURCE_REPO_URL
+	Repo string
+	PR   PullRequestInfo
+	CI   string
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// Template is used for all Terraform command output
+	Template           *terraform.Template
+	ParseErrorTemplate *terraform.Template
+	Vars               map[string]string
+	Templates          map[string]string
+	UseRawOutput       bool
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	// MaxResources is the maximum number of resources listed per action in the built-in templates. 0 means unlimited
+	MaxResources int
+	// DryRun renders the comment but doesn't post it.
+	// The comment is written to DryRunOutput. If DryRunOutput is empty, the comment is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+	// Output is the file path where the posted comment is also written. If this is empty, the comment isn't written
+	Output string
+}
+
+// PullRequestInfo represents CodeCommit Pull Request metadata
+type PullRequestInfo struct {
+	Revision string
+	Number   int
+}
+
+// IsNumber returns true if PullRequestInfo is Pull Request build
+func (pr *PullRequestInfo) IsNumber() bool {
+	return pr.Number != 0
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	repoRegion, repo := parseRepoURL(os.Getenv(EnvSourceRepoURL))
+	if cfg.Repo == "" {
+		cfg.Repo = repo
+	}
+	region := cfg.Region
+	if region == "" {
+		region = os.Getenv(envRegion)
+	}
+	if region == "" {
+		region = os.Getenv(envDefaultRegion)
+	}
+	if region == "" {
+		region = repoRegion
+	}
+	if cfg.DryRun {
+		return &Client{Config: cfg}, nil
+	}
+	if region == "" {
+		return &Client{}, errors.New("aws region is missing")
+	}
+	if cfg.Repo == "" {
+		return &Client{}, errors.New("the name of the codecommit repository is missing")
+	}
+	credentials, err := newCredentialProvider(http.DefaultClient, cfg.Credential)
+	if err != nil {
+		return &Client{}, err
+	}
+	return &Client{
+		Config: cfg,
+		API: &CodeCommit{
+			client:      http.DefaultClient,
+			endpoint:    "https://codecommit." + region + ".amazonaws.com",
+			region:      region,
+			credentials: credentials,
+			repo:        cfg.Repo,
+			now:         time.Now,
+		},
+	}, nil
+}
+
+// NewNotifier returns a notifier.Notifier which posts the result to AWS CodeCommit.
+// If Parser and templates aren't set, the ones for terraform plan are used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	_, isApply := cfg.Parser.(*terraform.ApplyParser)
+	if p, ok := cfg.Parser.(*terraform.JSONParser); ok {
+		isApply = p.Command == terraform.CommandApply
+	}
+	// If AutoParser is used, templates are decided by the detected command
+	_, isAuto := cfg.Parser.(*terraform.AutoParser)
+	if cfg.Template == nil && !isAuto {
+		if isApply {
+			cfg.Template = terraform.NewApplyTemplate("")
+		} else {
+			cfg.Template = terraform.NewPlanTemplate("")
+		}
+	}
+	if cfg.ParseErrorTemplate == nil && !isAuto {
+		if isApply {
+			cfg.ParseErrorTemplate = terraform.NewApplyParseErrorTemplate("")
+		} else {
+			cfg.ParseErrorTemplate = terraform.NewPlanParseErrorTemplate("")
+		}
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
+
+// IsRepoURL returns true if the URL is the clone URL of a CodeCommit repository
+func IsRepoURL(u string) bool {
+	_, repo := parseRepoURL(u)
+	return repo != ""
+}
+
+// parseRepoURL returns the region and the name of the repository from the HTTPS or GRC clone URL.
+// https://git-codecommit.us-east-1.amazonaws.com/v1/repos/tfcmt and codecommit::us-east-1://tfcmt are supported
+func parseRepoURL(u string) (string, string) {
+	if s := strings.TrimPrefix(u, "codecommit::"); s != u {
+		idx := strings.Index(s, "://")
+		if idx == -1 {
+			return "", ""
+		}
+		repo := s[idx+len("://"):]
+		// the profile can be specified like codecommit::us-east-1://profile@tfcmt
+		if i := strings.Index(repo, "@"); i != -1 {
+			repo = repo[i+1:]
+		}
+		return s[:idx], repo
+	}
+	s := strings.TrimPrefix(u, "https://git-codecommit.")
+	if s == u {
+		return "", ""
+	}
+	idx := strings.Index(s, ".")
+	if idx == -1 {
+		return "", ""
+	}
+	region := s[:idx]
+	i := strings.Index(s, "/v1/repos/")
+	if i == -1 {
+		return "", ""
+	}
+	return region, strings.TrimSuffix(s[i+len("/v1/repos/"):], "/")
+}
@@ -0,0 +1,142 @@
+package codecommit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const targetPrefix = "CodeCommit_20150413."
+
+// API is AWS CodeCommit API interface
+type API interface {
+	GetPullRequest(ctx context.Context, id int) (*PullRequest, error)
+	PostCommentForPullRequest(ctx context.Context, pr *PullRequest, content string) error
+	PostCommentForComparedCommit(ctx context.Context, sha, content string) error
+}
+
+// PullRequest is a subset of the pull request of CodeCommit API
+type PullRequest struct {
+	PullRequestID string               `json:"pullRequestId"`
+	Status        string               `json:"pullRequestStatus"`
+	Targets       []*PullRequestTarget `json:"pullRequestTargets"`
+}
+
+// PullRequestTarget is the source and destination of the pull request
+type PullRequestTarget struct {
+	RepositoryName    string `json:"repositoryName"`
+	SourceCommit      string `json:"sourceCommit"`
+	DestinationCommit string `json:"destinationCommit"`
+}
+
+// Error is an error response of CodeCommit API
+type Error struct {
+	StatusCode int
+	Type       string `json:"__type"`
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("CodeCommit API returned the status code %d: %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+// CodeCommit calls AWS CodeCommit API of the repository
+type CodeCommit struct {
+	client *http.Client
+	// endpoint is the endpoint of CodeCommit API like https://codecommit.us-east-1.amazonaws.com
+	endpoint    string
+	region      string
+	credentials credentialProvider
+	repo        string
+	now         func() time.Time
+}
+
+// GetPullRequest is a wrapper of https://docs.aws.amazon.com/codecommit/latest/APIReference/API_GetPullRequest.html
+func (c *CodeCommit) GetPullRequest(ctx context.Context, id int) (*PullRequest, error) {
+	ret := struct {
+		PullRequest *PullRequest `json:"pullRequest"`
+	}{}
+	if err := c.do(ctx, "GetPullRequest", map[string]string{
+		"pullRequestId": strconv.Itoa(id),
+	}, &ret); err != nil {
+		return nil, err
+	}
+	return ret.PullRequest, nil
+}
+
+// PostCommentForPullRequest is a wrapper of https://docs.aws.amazon.com/codecommit/latest/APIReference/API_PostCommentForPullRequest.html .
+// The comment is posted to the latest commits of the pull request
+func (c *CodeCommit) PostCommentForPullRequest(ctx context.Context, pr *PullRequest, content string) error {
+	body := map[string]string{
+		"pullRequestId":  pr.PullRequestID,
+		"repositoryName": c.repo,
+		"content":        content,
+	}
+	for _, target := range pr.Targets {
+		if target.RepositoryName == c.repo {
+			body["beforeCommitId"] = target.DestinationCommit
+			body["afterCommitId"] = target.SourceCommit
+			break
+		}
+	}
+	return c.do(ctx, "PostCommentForPullRequest", body, nil)
+}
+
+// PostCommentForComparedCommit is a wrapper of https://docs.aws.amazon.com/codecommit/latest/APIReference/API_PostCommentForComparedCommit.html .
+// The comment is posted to the commit
+func (c *CodeCommit) PostCommentForComparedCommit(ctx context.Context, sha, content string) error {
+	return c.do(ctx, "PostCommentForComparedCommit", map[string]string{
+		"repositoryName": c.repo,
+		"afterCommitId":  sha,
+		"content":        content,
+	}, nil)
+}
+
+// do calls the action of CodeCommit API. The protocol is AWS JSON 1.1
+func (c *CodeCommit) do(ctx context.Context, action string, body, out interface{}) error {
+	cred, err := c.credentials.get(ctx)
+	if err != nil {
+		return fmt.Errorf("get AWS credential: %w", err)
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal the request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/", bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", targetPrefix+action)
+	signRequest(req, b, cred, c.region, "codecommit", c.now())
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call CodeCommit API: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read the response body: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		e := &Error{}
+		_ = json.Unmarshal(respBody, e)
+		e.StatusCode = resp.StatusCode
+		if e.Message == "" {
+			e.Message = string(respBody)
+		}
+		return e
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("parse the response body: %w", err)
+	}
+	return nil
+}
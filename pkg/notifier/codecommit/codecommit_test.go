@@ -0,0 +1,204 @@
+package codecommit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	prs            map[int]*PullRequest
+	prComments     map[string][]string
+	commitComments map[string][]string
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		prs:            map[int]*PullRequest{},
+		prComments:     map[string][]string{},
+		commitComments: map[string][]string{},
+	}
+}
+
+func (c *fakeAPI) GetPullRequest(ctx context.Context, id int) (*PullRequest, error) {
+	pr, ok := c.prs[id]
+	if !ok {
+		return nil, &Error{StatusCode: http.StatusBadRequest, Type: "PullRequestDoesNotExistException"}
+	}
+	return pr, nil
+}
+
+func (c *fakeAPI) PostCommentForPullRequest(ctx context.Context, pr *PullRequest, content string) error {
+	c.prComments[pr.PullRequestID] = append(c.prComments[pr.PullRequestID], content)
+	return nil
+}
+
+func (c *fakeAPI) PostCommentForComparedCommit(ctx context.Context, sha, content string) error {
+	c.commitComments[sha] = append(c.commitComments[sha], content)
+	return nil
+}
+
+func TestCodeCommit(t *testing.T) {
+	t.Parallel()
+	type request struct {
+		Target string
+		Token  string
+		Body   map[string]string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/20210101/us-east-1/codecommit/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, Signature=") {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"__type":"InvalidSignatureException","message":"invalid signature"}`))
+			return
+		}
+		req := request{
+			Target: r.Header.Get("X-Amz-Target"),
+			Token:  r.Header.Get("X-Amz-Security-Token"),
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req.Body)
+		requests = append(requests, req)
+		switch req.Target {
+		case "CodeCommit_20150413.GetPullRequest":
+			if req.Body["pullRequestId"] != "1" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"__type":"PullRequestDoesNotExistException","message":"not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"pullRequest":{"pullRequestId":"1","pullRequestStatus":"OPEN","pullRequestTargets":[{"repositoryName":"tfcmt","sourceCommit":"abcd","destinationCommit":"efgh"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := &CodeCommit{
+		client:   server.Client(),
+		endpoint: server.URL,
+		region:   "us-east-1",
+		credentials: &staticCredential{cred: Credential{
+			AccessKeyID:     "AKID",
+			SecretAccessKey: "secret",
+			SessionToken:    "session",
+		}},
+		repo: "tfcmt",
+		now: func() time.Time {
+			return time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		},
+	}
+	pr, err := c.GetPullRequest(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostCommentForPullRequest(ctx, pr, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostCommentForComparedCommit(ctx, "abcd", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetPullRequest(ctx, 2); err == nil {
+		t.Fatal("error should be returned")
+	}
+	exp := []request{
+		{
+			Target: "CodeCommit_20150413.GetPullRequest",
+			Token:  "session",
+			Body:   map[string]string{"pullRequestId": "1"},
+		},
+		{
+			Target: "CodeCommit_20150413.PostCommentForPullRequest",
+			Token:  "session",
+			Body: map[string]string{
+				"pullRequestId":  "1",
+				"repositoryName": "tfcmt",
+				"beforeCommitId": "efgh",
+				"afterCommitId":  "abcd",
+				"content":        "hello",
+			},
+		},
+		{
+			Target: "CodeCommit_20150413.PostCommentForComparedCommit",
+			Token:  "session",
+			Body: map[string]string{
+				"repositoryName": "tfcmt",
+				"afterCommitId":  "abcd",
+				"content":        "hello",
+			},
+		},
+		{
+			Target: "CodeCommit_20150413.GetPullRequest",
+			Token:  "session",
+			Body:   map[string]string{"pullRequestId": "2"},
+		},
+	}
+	if diff := cmp.Diff(exp, requests); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestContainerCredential(t *testing.T) {
+	t.Parallel()
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		_, _ = w.Write([]byte(`{"AccessKeyId":"AKID","SecretAccessKey":"secret","Token":"session","Expiration":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+	}))
+	defer server.Close()
+	c := &containerCredential{client: server.Client(), url: server.URL}
+	for i := 0; i < 2; i++ {
+		cred, err := c.get(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(Credential{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}, cred); diff != "" {
+			t.Error(diff)
+		}
+	}
+	if count != 1 {
+		t.Errorf("the credential should be cached: %d", count)
+	}
+}
+
+func TestParseRepoURL(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		url       string
+		expRegion string
+		expRepo   string
+	}{
+		{
+			name:      "https",
+			url:       "https://git-codecommit.us-east-1.amazonaws.com/v1/repos/tfcmt",
+			expRegion: "us-east-1",
+			expRepo:   "tfcmt",
+		},
+		{
+			name:      "grc",
+			url:       "codecommit::ap-northeast-1://profile@tfcmt",
+			expRegion: "ap-northeast-1",
+			expRepo:   "tfcmt",
+		},
+		{
+			name: "github",
+			url:  "https://github.com/suzuki-shunsuke/tfcmt.git",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			region, repo := parseRepoURL(testCase.url)
+			if region != testCase.expRegion || repo != testCase.expRepo {
+				t.Errorf("got (%s, %s), want (%s, %s)", region, repo, testCase.expRegion, testCase.expRepo)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package codecommit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	envAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	envSecretAccessKey = "AWS_SECRET_ACCESS_KEY" //nolint:gosec
+	envSessionToken    = "AWS_SESSION_TOKEN"     //nolint:gosec
+	// CodeBuild and ECS provide the credential of the role from the endpoint
+	envContainerCredentialsRelativeURI = "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"
+	envContainerCredentialsFullURI     = "AWS_CONTAINER_CREDENTIALS_FULL_URI"
+	envContainerAuthorizationToken     = "AWS_CONTAINER_AUTHORIZATION_TOKEN" //nolint:gosec
+	containerCredentialsHost           = "http://169.254.170.2"
+)
+
+// credentialProvider returns AWS credential
+type credentialProvider interface {
+	get(ctx context.Context) (Credential, error)
+}
+
+type staticCredential struct {
+	cred Credential
+}
+
+func (s *staticCredential) get(ctx context.Context) (Credential, error) {
+	return s.cred, nil
+}
+
+// containerCredential gets the credential from the container credential endpoint and caches it until it expires
+type containerCredential struct {
+	client *http.Client
+	url    string
+	token  string
+	mutex  sync.Mutex
+	cred   Credential
+	expire time.Time
+}
+
+func (c *containerCredential) get(ctx context.Context) (Credential, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// refresh the credential before it expires
+	if !c.cred.IsEmpty() && time.Now().Add(time.Minute).Before(c.expire) {
+		return c.cred, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return Credential{}, fmt.Errorf("create a request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Credential{}, fmt.Errorf("get the credential from the container credential endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return Credential{}, fmt.Errorf("the container credential endpoint returned the status code %d", resp.StatusCode)
+	}
+	ret := struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string
+		Token           string
+		Expiration      time.Time
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&ret); err != nil {
+		return Credential{}, fmt.Errorf("parse the credential: %w", err)
+	}
+	c.cred = Credential{
+		AccessKeyID:     ret.AccessKeyID,
+		SecretAccessKey: ret.SecretAccessKey,
+		SessionToken:    ret.Token,
+	}
+	c.expire = ret.Expiration
+	return c.cred, nil
+}
+
+// newCredentialProvider returns the provider of the credential.
+// The credential is read from the environment variables or the container credential endpoint of CodeBuild and ECS in this order
+func newCredentialProvider(client *http.Client, cred Credential) (credentialProvider, error) {
+	if !cred.IsEmpty() {
+		return &staticCredential{cred: cred}, nil
+	}
+	cred = Credential{
+		AccessKeyID:     os.Getenv(envAccessKeyID),
+		SecretAccessKey: os.Getenv(envSecretAccessKey),
+		SessionToken:    os.Getenv(envSessionToken),
+	}
+	if !cred.IsEmpty() {
+		return &staticCredential{cred: cred}, nil
+	}
+	if uri := os.Getenv(envContainerCredentialsRelativeURI); uri != "" {
+		return &containerCredential{client: client, url: containerCredentialsHost + uri}, nil
+	}
+	if u := os.Getenv(envContainerCredentialsFullURI); u != "" {
+		return &containerCredential{client: client, url: u, token: os.Getenv(envContainerAuthorizationToken)}, nil
+	}
+	return nil, errors.New("aws credential is missing. Please set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or run tfcmt on CodeBuild")
+}
@@ -0,0 +1,71 @@
+package codecommit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService posts the result as a comment of the pull request or the commit
+type NotifyService struct {
+	client *Client
+}
+
+// Notify posts comment optimized for notifications
+func (c *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := c.client.Config
+
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+
+	body, err := result.Render(notifier.RenderOption{
+		Template:           cfg.Template,
+		ParseErrorTemplate: cfg.ParseErrorTemplate,
+		Link:               cfg.CI,
+		Vars:               cfg.Vars,
+		Templates:          cfg.Templates,
+		UseRawOutput:       cfg.UseRawOutput,
+		MaxResources:       cfg.MaxResources,
+	})
+	if err != nil || body == "" {
+		return result.ExitCode, err
+	}
+
+	if err := c.post(ctx, &cfg, body); err != nil {
+		return result.ExitCode, err
+	}
+	if err := notifier.WriteOutput(cfg.Output, body); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+// post posts a comment of the pull request. If the target isn't a pull request, the result is posted as a comment of the commit
+func (c *NotifyService) post(ctx context.Context, cfg *Config, body string) error {
+	if cfg.DryRun {
+		return notifier.WriteDryRunOutput(cfg.DryRunOutput, body)
+	}
+	if cfg.PR.IsNumber() {
+		// the comment requires the latest commits of the pull request
+		pr, err := c.client.API.GetPullRequest(ctx, cfg.PR.Number)
+		if err != nil {
+			return fmt.Errorf("get the pull request: %w", err)
+		}
+		if err := c.client.API.PostCommentForPullRequest(ctx, pr, body); err != nil {
+			return fmt.Errorf("create a comment of the pull request: %w", err)
+		}
+		return nil
+	}
+	if cfg.PR.Revision == "" {
+		return errors.New("neither pull request number nor commit SHA is set")
+	}
+	if err := c.client.API.PostCommentForComparedCommit(ctx, cfg.PR.Revision, body); err != nil {
+		return fmt.Errorf("create a comment of the commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package codecommit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name             string
+		number           int
+		expPRComment     bool
+		expCommitComment bool
+		isErr            bool
+	}{
+		{
+			name:         "pull request",
+			number:       1,
+			expPRComment: true,
+		},
+		{
+			name:             "commit",
+			expCommitComment: true,
+		},
+		{
+			name:   "pull request isn't found",
+			number: 2,
+			isErr:  true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := newFakeAPI()
+			api.prs[1] = &PullRequest{PullRequestID: "1"}
+			ntf := &NotifyService{
+				client: &Client{
+					Config: Config{
+						Repo: "tfcmt",
+						PR: PullRequestInfo{
+							Revision: "abcd",
+							Number:   testCase.number,
+						},
+						Parser:             terraform.NewPlanParser(),
+						Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+						ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanParseErrorTemplate),
+					},
+					API: api,
+				},
+			}
+			_, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: "No changes. Infrastructure is up-to-date.",
+			})
+			if err != nil {
+				if !testCase.isErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			if testCase.isErr {
+				t.Fatal("error should be returned")
+			}
+			if testCase.expPRComment && (len(api.prComments["1"]) != 1 || !strings.Contains(api.prComments["1"][0], "No changes")) {
+				t.Errorf("a comment should be posted to the pull request: %v", api.prComments)
+			}
+			if testCase.expCommitComment && len(api.commitComments["abcd"]) != 1 {
+				t.Errorf("a comment should be posted to the commit: %v", api.commitComments)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package datadog
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	// EnvAPIKey is the API key of Datadog
+	EnvAPIKey = "DD_API_KEY"
+	// EnvSite is the site of Datadog such as "datadoghq.eu"
+	EnvSite = "DD_SITE"
+)
+
+// defaultSite is the site of Datadog if neither Config.Site nor DD_SITE is set
+const defaultSite = "datadoghq.com"
+
+const (
+	// DefaultPlanTemplate is the default template of the text of Datadog events for terraform plan.
+	// The template is minimal because the text of an event is limited to 4000 characters
+	DefaultPlanTemplate = `{{if .ParseErrorMessage}}It failed to parse the result: {{.ParseErrorMessage}}{{else}}{{.Result}}{{end}}
+{{range .DeletedResources}}- ` + "`{{.}}`" + ` will be destroyed
+{{end}}{{range .ReplacedResources}}- ` + "`{{.}}`" + ` will be replaced
+{{end}}{{if .Link}}
+[CI link]({{.Link}}){{end}}`
+
+	// DefaultApplyTemplate is the default template of the text of Datadog events for terraform apply
+	DefaultApplyTemplate = `{{if .ParseErrorMessage}}It failed to parse the result: {{.ParseErrorMessage}}{{else}}{{.Result}}{{end}}
+{{range .FailedResources}}- ` + "`{{.}}`" + ` failed
+{{end}}{{if .Link}}
+[CI link]({{.Link}}){{end}}`
+)
+
+// defaultTriggers returns conditions to send events if no trigger is set
+func defaultTriggers() []string {
+	return []string{notifier.TriggerApplySuccess, notifier.TriggerApplyFailure}
+}
+
+// Client is a client for Datadog
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for Datadog client
+type Config struct {
+	// APIKey is the API key of Datadog. If this is empty, DD_API_KEY is used
+	APIKey string
+	// Site is the site of Datadog such as "datadoghq.eu". If this is empty, DD_SITE or "datadoghq.com" is used
+	Site string
+	// Repo is the repository like "suzuki-shunsuke/tfcmt". This is added to the tags of events as "repo:<Repo>"
+	Repo string
+	CI   string
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// PlanTemplate and ApplyTemplate are templates of the text of events.
+	// If they are nil, DefaultPlanTemplate and DefaultApplyTemplate are used
+	PlanTemplate  *terraform.Template
+	ApplyTemplate *terraform.Template
+	ResultLabels  notifier.ResultLabels
+	Vars          map[string]string
+	Templates     map[string]string
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	// Tags are added to events in addition to the tags of Vars like "target:foo"
+	Tags []string
+	// Triggers are conditions to send events. If this is empty, applies and apply failures are sent
+	Triggers []string
+	// DryRun renders the event but doesn't send it.
+	// The event is written to DryRunOutput as JSON. If DryRunOutput is empty, the event is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv(EnvAPIKey)
+	}
+	if apiKey == "" && !cfg.DryRun {
+		return &Client{}, errors.New("datadog api key is missing")
+	}
+	site := cfg.Site
+	if site == "" {
+		site = os.Getenv(EnvSite)
+	}
+	if site == "" {
+		site = defaultSite
+	}
+	return &Client{
+		Config: cfg,
+		API: &Events{
+			client: http.DefaultClient,
+			url:    "https://api." + site + "/api/v1/events",
+			apiKey: apiKey,
+		},
+	}, nil
+}
+
+// NewNotifier returns a notifier.Notifier which sends the result to Datadog as an event.
+// If Parser isn't set, the parser of terraform plan is used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	if cfg.PlanTemplate == nil {
+		cfg.PlanTemplate = terraform.NewPlanTemplate(DefaultPlanTemplate)
+	}
+	if cfg.ApplyTemplate == nil {
+		cfg.ApplyTemplate = terraform.NewApplyTemplate(DefaultApplyTemplate)
+	}
+	if len(cfg.Triggers) == 0 {
+		cfg.Triggers = defaultTriggers()
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
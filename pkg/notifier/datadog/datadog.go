@@ -0,0 +1,55 @@
+package datadog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// API is the interface to post events to Datadog
+type API interface {
+	PostEvent(ctx context.Context, event *Event) error
+}
+
+// Event is an event of Datadog.
+// https://docs.datadoghq.com/api/latest/events/#post-an-event
+type Event struct {
+	Title     string   `json:"title"`
+	Text      string   `json:"text"`
+	AlertType string   `json:"alert_type"`
+	Tags      []string `json:"tags"`
+}
+
+// Events posts events with the Events API
+type Events struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+// PostEvent posts the event to Datadog
+func (e *Events) PostEvent(ctx context.Context, event *Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal the event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", e.apiKey)
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send an event to Datadog: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("the Events API returned the status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
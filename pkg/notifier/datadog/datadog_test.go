@@ -0,0 +1,53 @@
+package datadog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	events []*Event
+}
+
+func (s *fakeAPI) PostEvent(ctx context.Context, event *Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+	var got *Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("DD-API-KEY") != "xxx" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["Forbidden"]}`))
+			return
+		}
+		got = &Event{}
+		_ = json.NewDecoder(r.Body).Decode(got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	events := &Events{
+		client: server.Client(),
+		url:    server.URL,
+		apiKey: "xxx",
+	}
+	event := newEvent("Terraform apply succeeded", "hello", alertTypeSuccess, []string{"target:foo"})
+	if err := events.PostEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(event, got); diff != "" {
+		t.Error(diff)
+	}
+	events.apiKey = "yyy"
+	if err := events.PostEvent(context.Background(), event); err == nil {
+		t.Fatal("error should be returned")
+	}
+}
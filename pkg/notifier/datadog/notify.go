@@ -0,0 +1,138 @@
+package datadog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService sends the result to Datadog
+type NotifyService struct {
+	client *Client
+}
+
+// Alert types of events
+const (
+	alertTypeSuccess = "success"
+	alertTypeWarning = "warning"
+	alertTypeError   = "error"
+	alertTypeInfo    = "info"
+)
+
+// Datadog limits the length of the text of an event to 4000 characters
+const maxTextLength = 4000
+
+const truncatedMessage = "\n*The event is truncated because it is too long.*"
+
+// Notify sends the result as an event
+func (s *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := s.client.Config
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+	if !result.HasParseError && result.Error != nil {
+		return result.ExitCode, result.Error
+	}
+	if !notifier.MatchTriggers(cfg.Triggers, result) {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).Debug("skip sending an event to Datadog because the result doesn't match any of the triggers")
+		return result.ExitCode, nil
+	}
+
+	template := cfg.ApplyTemplate
+	if result.IsPlan {
+		template = cfg.PlanTemplate
+	}
+	template.SetValue(result.CommonTemplate(notifier.RenderOption{
+		ResultLabels: cfg.ResultLabels,
+		Link:         cfg.CI,
+		Vars:         cfg.Vars,
+		Templates:    cfg.Templates,
+		UseRawOutput: true,
+	}))
+	text, err := template.Execute()
+	if err != nil {
+		return result.ExitCode, err
+	}
+
+	event := newEvent(titleOf(result, cfg.Vars["target"]), text, alertTypeOf(result), tagsOf(&cfg, result))
+	if cfg.DryRun {
+		b, err := json.MarshalIndent(event, "", "  ")
+		if err != nil {
+			return result.ExitCode, fmt.Errorf("marshal the event: %w", err)
+		}
+		return result.ExitCode, notifier.WriteDryRunOutput(cfg.DryRunOutput, string(b))
+	}
+	if err := s.client.API.PostEvent(ctx, event); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+func titleOf(result *notifier.Result, target string) string {
+	title := "Terraform " + result.Command()
+	switch {
+	case result.IsPlan:
+	case result.HasParseError || !result.Succeeded():
+		title += " failed"
+	default:
+		title += " succeeded"
+	}
+	if target != "" {
+		title += " (" + target + ")"
+	}
+	return title
+}
+
+func alertTypeOf(result *notifier.Result) string {
+	switch {
+	case result.HasParseError || !result.Succeeded():
+		return alertTypeError
+	case result.IsPlan && result.HasDestroy:
+		return alertTypeWarning
+	case result.IsPlan:
+		return alertTypeInfo
+	default:
+		return alertTypeSuccess
+	}
+}
+
+// tagsOf returns the tags of the event. Vars are converted to tags like "target:foo" and sorted by the name
+func tagsOf(cfg *Config, result *notifier.Result) []string {
+	tags := append([]string{"source:tfcmt", "command:" + result.Command()}, cfg.Tags...)
+	if cfg.Repo != "" {
+		tags = append(tags, "repo:"+cfg.Repo)
+	}
+	names := make([]string, 0, len(cfg.Vars))
+	for name := range cfg.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tags = append(tags, name+":"+cfg.Vars[name])
+	}
+	return tags
+}
+
+// newEvent returns an event. The text is enclosed in "%%%" so that it is rendered as Markdown
+func newEvent(title, text, alertType string, tags []string) *Event {
+	const prefix, suffix = "%%% \n", "\n %%%"
+	text = strings.TrimSpace(text)
+	if limit := maxTextLength - len(prefix) - len(suffix); len([]rune(text)) > limit {
+		text = string([]rune(text)[:limit-len([]rune(truncatedMessage))]) + truncatedMessage
+	}
+	return &Event{
+		Title:     title,
+		Text:      prefix + text + suffix,
+		AlertType: alertType,
+		Tags:      tags,
+	}
+}
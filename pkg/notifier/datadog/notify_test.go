@@ -0,0 +1,118 @@
+package datadog
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		parser   terraform.Parser
+		triggers []string
+		output   string
+		exitCode int
+		exp      *Event
+	}{
+		{
+			name:   "apply success",
+			parser: terraform.NewApplyParser(),
+			output: "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			exp: &Event{
+				Title:     "Terraform apply succeeded (foo)",
+				Text:      "%%% \nApply complete! Resources: 1 added, 0 changed, 0 destroyed.\n\n[CI link](https://ci.example.com/1)\n %%%",
+				AlertType: alertTypeSuccess,
+				Tags:      []string{"source:tfcmt", "command:apply", "team:platform", "repo:suzuki-shunsuke/tfcmt", "env:prd", "target:foo"},
+			},
+		},
+		{
+			name:     "apply failure",
+			parser:   terraform.NewApplyParser(),
+			output:   "Error: failed to create",
+			exitCode: 1,
+			exp: &Event{
+				Title:     "Terraform apply failed (foo)",
+				Text:      "%%% \nError: failed to create\n\n[CI link](https://ci.example.com/1)\n %%%",
+				AlertType: alertTypeError,
+				Tags:      []string{"source:tfcmt", "command:apply", "team:platform", "repo:suzuki-shunsuke/tfcmt", "env:prd", "target:foo"},
+			},
+		},
+		{
+			name:   "plan isn't sent by default",
+			parser: terraform.NewPlanParser(),
+			output: "Plan: 0 to add, 0 to change, 1 to destroy.",
+		},
+		{
+			name:     "plan is sent if the trigger is set",
+			parser:   terraform.NewPlanParser(),
+			triggers: []string{notifier.TriggerDestroy},
+			output: `  # null_resource.foo will be destroyed
+Plan: 0 to add, 0 to change, 1 to destroy.`,
+			exp: &Event{
+				Title:     "Terraform plan (foo)",
+				Text:      "%%% \nPlan: 0 to add, 0 to change, 1 to destroy.\n- `null_resource.foo` will be destroyed\n\n[CI link](https://ci.example.com/1)\n %%%",
+				AlertType: alertTypeWarning,
+				Tags:      []string{"source:tfcmt", "command:plan", "team:platform", "repo:suzuki-shunsuke/tfcmt", "env:prd", "target:foo"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := &fakeAPI{}
+			ntf, err := NewNotifier(Config{
+				APIKey:   "xxx",
+				Repo:     "suzuki-shunsuke/tfcmt",
+				CI:       "https://ci.example.com/1",
+				Parser:   testCase.parser,
+				Vars:     map[string]string{"target": "foo", "env": "prd"},
+				Tags:     []string{"team:platform"},
+				Triggers: testCase.triggers,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ntf.(*NotifyService).client.API = api
+			exitCode, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != testCase.exitCode {
+				t.Errorf("exit code: got %d, want %d", exitCode, testCase.exitCode)
+			}
+			if testCase.exp == nil {
+				if len(api.events) != 0 {
+					t.Fatalf("no event should be sent: %+v", api.events[0])
+				}
+				return
+			}
+			if len(api.events) != 1 {
+				t.Fatalf("an event should be sent: %d", len(api.events))
+			}
+			if diff := cmp.Diff(testCase.exp, api.events[0]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewEventTruncate(t *testing.T) {
+	t.Parallel()
+	event := newEvent("Terraform plan", strings.Repeat("あ", maxTextLength+1), alertTypeInfo, nil)
+	if n := len([]rune(event.Text)); n != maxTextLength {
+		t.Errorf("the text should be truncated to %d characters: %d", maxTextLength, n)
+	}
+	if !strings.HasSuffix(event.Text, truncatedMessage+"\n %%%") {
+		t.Errorf("the text should end with the message: %s", event.Text[len(event.Text)-100:])
+	}
+}
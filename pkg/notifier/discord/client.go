@@ -0,0 +1,103 @@
+package discord
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// EnvWebhookURL is the URL of the webhook
+const EnvWebhookURL = "DISCORD_WEBHOOK_URL"
+
+const (
+	// DefaultPlanTemplate is the default template of Discord messages for terraform plan.
+	// The template is minimal because the description of an embed is limited to 4096 characters
+	DefaultPlanTemplate = `{{if .ParseErrorMessage}}It failed to parse the result: {{.ParseErrorMessage}}{{else}}{{.Result}}{{end}}
+{{if .HasDestroy}}**This plan contains resource delete operation.**
+{{end}}{{range .DeletedResources}}- ` + "`{{.}}`" + ` will be destroyed
+{{end}}{{range .ReplacedResources}}- ` + "`{{.}}`" + ` will be replaced
+{{end}}`
+
+	// DefaultApplyTemplate is the default template of Discord messages for terraform apply
+	DefaultApplyTemplate = `{{if .ParseErrorMessage}}It failed to parse the result: {{.ParseErrorMessage}}{{else}}{{.Result}}{{end}}
+{{range .FailedResources}}- ` + "`{{.}}`" + ` failed
+{{end}}`
+)
+
+// defaultTriggers returns conditions to send messages if no trigger is set
+func defaultTriggers() []string {
+	return []string{notifier.TriggerApplyFailure, notifier.TriggerDestroy}
+}
+
+// Client is a client for Discord
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for Discord client
+type Config struct {
+	// WebhookURL is the URL of the webhook. If this is empty, DISCORD_WEBHOOK_URL is used
+	WebhookURL string
+	CI         string
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// PlanTemplate and ApplyTemplate are templates of the description of embeds.
+	// If they are nil, DefaultPlanTemplate and DefaultApplyTemplate are used
+	PlanTemplate  *terraform.Template
+	ApplyTemplate *terraform.Template
+	ResultLabels  notifier.ResultLabels
+	Vars          map[string]string
+	Templates     map[string]string
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	// Triggers are conditions to send messages. If this is empty, apply failures and plans with destroyed resources are sent
+	Triggers []string
+	// DryRun renders the message but doesn't send it.
+	// The message is written to DryRunOutput as JSON. If DryRunOutput is empty, the message is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	webhookURL := cfg.WebhookURL
+	if webhookURL == "" {
+		webhookURL = os.Getenv(EnvWebhookURL)
+	}
+	if webhookURL == "" && !cfg.DryRun {
+		return &Client{}, errors.New("discord webhook url is missing")
+	}
+	return &Client{
+		Config: cfg,
+		API: &Webhook{
+			client: http.DefaultClient,
+			url:    webhookURL,
+		},
+	}, nil
+}
+
+// NewNotifier returns a notifier.Notifier which sends the result to Discord.
+// If Parser isn't set, the parser of terraform plan is used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	if cfg.PlanTemplate == nil {
+		cfg.PlanTemplate = terraform.NewPlanTemplate(DefaultPlanTemplate)
+	}
+	if cfg.ApplyTemplate == nil {
+		cfg.ApplyTemplate = terraform.NewApplyTemplate(DefaultApplyTemplate)
+	}
+	if len(cfg.Triggers) == 0 {
+		cfg.Triggers = defaultTriggers()
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
@@ -0,0 +1,65 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// API is the interface to post messages to Discord
+type API interface {
+	PostMessage(ctx context.Context, msg *Message) error
+}
+
+// Message is a message of Discord webhooks.
+// https://discord.com/developers/docs/resources/webhook#execute-webhook
+type Message struct {
+	Content         string           `json:"content,omitempty"`
+	Embeds          []*Embed         `json:"embeds"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions"`
+}
+
+// Embed is an embed of the message
+type Embed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+	Color       int    `json:"color"`
+}
+
+// AllowedMentions controls mentions in the message
+type AllowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// Webhook posts messages with a webhook
+type Webhook struct {
+	client *http.Client
+	url    string
+}
+
+// PostMessage posts the message to the webhook
+func (w *Webhook) PostMessage(ctx context.Context, msg *Message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal the message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send a message to Discord: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("the webhook returned the status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService sends the result to Discord
+type NotifyService struct {
+	client *Client
+}
+
+const (
+	colorSuccess = 0x0e8a16 // green
+	colorWarning = 0xfbca04 // yellow
+	colorFailure = 0xd93f0b // red
+)
+
+// Discord limits the length of the description of an embed to 4096 characters
+const maxDescriptionLength = 4096
+
+const truncatedMessage = "\n*The message is truncated because it is too long.*"
+
+// Notify sends the result as an embed
+func (s *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := s.client.Config
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+	if !result.HasParseError && result.Error != nil {
+		return result.ExitCode, result.Error
+	}
+	if !notifier.MatchTriggers(cfg.Triggers, result) {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).Debug("skip sending a message to Discord because the result doesn't match any of the triggers")
+		return result.ExitCode, nil
+	}
+
+	template := cfg.ApplyTemplate
+	if result.IsPlan {
+		template = cfg.PlanTemplate
+	}
+	template.SetValue(result.CommonTemplate(notifier.RenderOption{
+		ResultLabels: cfg.ResultLabels,
+		Link:         cfg.CI,
+		Vars:         cfg.Vars,
+		Templates:    cfg.Templates,
+		UseRawOutput: true,
+	}))
+	text, err := template.Execute()
+	if err != nil {
+		return result.ExitCode, err
+	}
+
+	msg := newMessage(titleOf(result, cfg.Vars["target"]), text, colorOf(result), cfg.CI)
+	if cfg.DryRun {
+		b, err := json.MarshalIndent(msg, "", "  ")
+		if err != nil {
+			return result.ExitCode, fmt.Errorf("marshal the message: %w", err)
+		}
+		return result.ExitCode, notifier.WriteDryRunOutput(cfg.DryRunOutput, string(b))
+	}
+	if err := s.client.API.PostMessage(ctx, msg); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+func titleOf(result *notifier.Result, target string) string {
+	title := "Apply Result"
+	if result.IsPlan {
+		title = "Plan Result"
+	}
+	if target != "" {
+		title += " (" + target + ")"
+	}
+	return title
+}
+
+func colorOf(result *notifier.Result) int {
+	switch {
+	case result.HasParseError || !result.Succeeded():
+		return colorFailure
+	case result.IsPlan && result.HasDestroy:
+		return colorWarning
+	default:
+		return colorSuccess
+	}
+}
+
+// newMessage returns a message with an embed. Mentions are disabled so that the output of terraform doesn't mention anyone
+func newMessage(title, text string, color int, link string) *Message {
+	text = strings.TrimSpace(text)
+	if r := []rune(text); len(r) > maxDescriptionLength {
+		text = string(r[:maxDescriptionLength-len([]rune(truncatedMessage))]) + truncatedMessage
+	}
+	return &Message{
+		Embeds: []*Embed{
+			{
+				Title:       title,
+				Description: text,
+				URL:         link,
+				Color:       color,
+			},
+		},
+		AllowedMentions: &AllowedMentions{
+			Parse: []string{},
+		},
+	}
+}
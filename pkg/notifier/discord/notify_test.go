@@ -0,0 +1,126 @@
+package discord
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func newEmbedMessage(title, description string, color int) *Message {
+	return &Message{
+		Embeds: []*Embed{
+			{
+				Title:       title,
+				Description: description,
+				URL:         "https://ci.example.com/1",
+				Color:       color,
+			},
+		},
+		AllowedMentions: &AllowedMentions{
+			Parse: []string{},
+		},
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		parser   terraform.Parser
+		triggers []string
+		output   string
+		exitCode int
+		exp      *Message
+	}{
+		{
+			name:   "plan with destroy",
+			parser: terraform.NewPlanParser(),
+			output: `
+Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1"
+    }
+
+Plan: 0 to add, 0 to change, 1 to destroy.
+`,
+			exp: newEmbedMessage("Plan Result (foo)", "Plan: 0 to add, 0 to change, 1 to destroy.\n**This plan contains resource delete operation.**\n- `null_resource.foo` will be destroyed", colorWarning),
+		},
+		{
+			name:     "apply failure",
+			parser:   terraform.NewApplyParser(),
+			output:   "Error: failed to create",
+			exitCode: 1,
+			exp:      newEmbedMessage("Apply Result (foo)", "Error: failed to create", colorFailure),
+		},
+		{
+			name:   "apply success isn't sent by default",
+			parser: terraform.NewApplyParser(),
+			output: "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.",
+		},
+		{
+			name:     "apply success is sent if the trigger is set",
+			parser:   terraform.NewApplyParser(),
+			triggers: []string{notifier.TriggerApplySuccess},
+			output:   "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.",
+			exp:      newEmbedMessage("Apply Result (foo)", "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.", colorSuccess),
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := &fakeAPI{}
+			ntf, err := NewNotifier(Config{
+				WebhookURL: "https://discord.example.com",
+				CI:         "https://ci.example.com/1",
+				Parser:     testCase.parser,
+				Vars:       map[string]string{"target": "foo"},
+				Triggers:   testCase.triggers,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ntf.(*NotifyService).client.API = api
+			exitCode, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != testCase.exitCode {
+				t.Errorf("exit code: got %d, want %d", exitCode, testCase.exitCode)
+			}
+			if testCase.exp == nil {
+				if len(api.messages) != 0 {
+					t.Fatalf("no message should be sent: %+v", api.messages[0])
+				}
+				return
+			}
+			if len(api.messages) != 1 {
+				t.Fatalf("a message should be sent: %d", len(api.messages))
+			}
+			if diff := cmp.Diff(testCase.exp, api.messages[0]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewMessageTruncate(t *testing.T) {
+	t.Parallel()
+	msg := newMessage("Plan Result", strings.Repeat("あ", maxDescriptionLength+1), colorSuccess, "")
+	description := msg.Embeds[0].Description
+	if n := len([]rune(description)); n != maxDescriptionLength {
+		t.Errorf("the description should be truncated to %d characters: %d", maxDescriptionLength, n)
+	}
+	if !strings.HasSuffix(description, truncatedMessage) {
+		t.Errorf("the description should end with the message: %s", description[len(description)-100:])
+	}
+}
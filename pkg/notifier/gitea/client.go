@@ -0,0 +1,167 @@
+package gitea
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// EnvToken is Gitea API Token
+const EnvToken = "GITEA_TOKEN" //nolint:gosec
+
+// EnvBaseURL is the URL of Gitea like https://gitea.example.com
+const EnvBaseURL = "GITEA_BASE_URL"
+
+// Gitea Actions sets GITHUB_SERVER_URL to the URL of the Gitea which runs the workflow
+const (
+	envGiteaActions = "GITEA_ACTIONS"
+	envServerURL    = "GITHUB_SERVER_URL"
+)
+
+const (
+	// OldCommentActionKeep keeps old comments
+	OldCommentActionKeep = "keep"
+	// OldCommentActionMinimize collapses old comments because Gitea can't hide comments
+	OldCommentActionMinimize = "minimize"
+	// OldCommentActionDelete deletes old comments
+	OldCommentActionDelete = "delete"
+)
+
+// Client is a API client for Gitea
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for Gitea client
+type Config struct {
+	Token string
+	// BaseURL is the URL of Gitea like https://gitea.example.com
+	BaseURL string
+	Owner   string
+	Repo    string
+	PR      PullRequestInfo
+	CI      string
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// Template is used for all Terraform command output
+	Template           *terraform.Template
+	ParseErrorTemplate *terraform.Template
+	Vars               map[string]string
+	EmbeddedVarNames   []string
+	Templates          map[string]string
+	UseRawOutput       bool
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	// MaxResources is the maximum number of resources listed per action in the built-in templates. 0 means unlimited
+	MaxResources int
+	// Tag is embedded into comments as the metadata "Program". The default value is "tfcmt"
+	Tag string
+	// OldComment is how to handle old comments of the same command and target
+	OldComment OldComment
+	// DryRun renders the comment but doesn't post it.
+	// The comment is written to DryRunOutput. If DryRunOutput is empty, the comment is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+	// Output is the file path where the posted comment is also written. If this is empty, the comment isn't written
+	Output string
+}
+
+// OldComment is a configuration how to handle old comments of the same command and target
+type OldComment struct {
+	// Action is either "keep", "minimize", or "delete". The default value is "keep"
+	Action string
+	// Classifier is shown as the summary of collapsed comments. The default value is "OUTDATED"
+	Classifier string
+}
+
+// PullRequestInfo represents Gitea Pull Request metadata
+type PullRequestInfo struct {
+	Revision string
+	Number   int
+}
+
+// IsNumber returns true if PullRequestInfo is Pull Request build
+func (pr *PullRequestInfo) IsNumber() bool {
+	return pr.Number != 0
+}
+
+// defaultTag is the default value of Config.Tag
+const defaultTag = "tfcmt"
+
+// program returns the value of the metadata "Program"
+func (cfg *Config) program() string {
+	if cfg.Tag == "" {
+		return defaultTag
+	}
+	return cfg.Tag
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	token := strings.TrimPrefix(cfg.Token, "$")
+	if token == EnvToken || token == "" {
+		token = os.Getenv(EnvToken)
+	}
+	if token == "" && !cfg.DryRun {
+		return &Client{}, errors.New("gitea token is missing")
+	}
+	baseURL := getBaseURL(cfg.BaseURL)
+	if baseURL == "" && !cfg.DryRun {
+		return &Client{}, errors.New("the url of gitea is missing")
+	}
+	return &Client{
+		Config: cfg,
+		API:    newGitea(http.DefaultClient, baseURL+"/api/v1", token, cfg.Owner, cfg.Repo),
+	}, nil
+}
+
+// NewNotifier returns a notifier.Notifier which posts the result to Gitea.
+// If Parser and templates aren't set, the ones for terraform plan are used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	_, isApply := cfg.Parser.(*terraform.ApplyParser)
+	if p, ok := cfg.Parser.(*terraform.JSONParser); ok {
+		isApply = p.Command == terraform.CommandApply
+	}
+	// If AutoParser is used, templates are decided by the detected command
+	_, isAuto := cfg.Parser.(*terraform.AutoParser)
+	if cfg.Template == nil && !isAuto {
+		if isApply {
+			cfg.Template = terraform.NewApplyTemplate("")
+		} else {
+			cfg.Template = terraform.NewPlanTemplate("")
+		}
+	}
+	if cfg.ParseErrorTemplate == nil && !isAuto {
+		if isApply {
+			cfg.ParseErrorTemplate = terraform.NewApplyParseErrorTemplate("")
+		} else {
+			cfg.ParseErrorTemplate = terraform.NewPlanParseErrorTemplate("")
+		}
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
+
+// getBaseURL returns the URL of Gitea.
+// If the base URL isn't set, GITEA_BASE_URL is used. On Gitea Actions, GITHUB_SERVER_URL is also used
+func getBaseURL(baseURL string) string {
+	baseURL = strings.TrimPrefix(baseURL, "$")
+	if baseURL == "" || baseURL == EnvBaseURL {
+		baseURL = os.Getenv(EnvBaseURL)
+	}
+	if baseURL == "" && os.Getenv(envGiteaActions) == "true" {
+		baseURL = os.Getenv(envServerURL)
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
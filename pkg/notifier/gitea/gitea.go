@@ -0,0 +1,149 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// API is Gitea API interface. Forgejo provides the same API
+type API interface {
+	CreateComment(ctx context.Context, number int, body string) error
+	ListComments(ctx context.Context, number int) ([]*Comment, error)
+	EditComment(ctx context.Context, id int64, body string) error
+	DeleteComment(ctx context.Context, id int64) error
+	GetPullRequestByCommit(ctx context.Context, sha string) (*PullRequest, error)
+}
+
+// Comment is a subset of the issue comment of Gitea API
+type Comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// PullRequest is a subset of the pull request of Gitea API
+type PullRequest struct {
+	Number int    `json:"number"`
+	State  string `json:"state"`
+	Merged bool   `json:"merged"`
+}
+
+// Error is an error response of Gitea API
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Gitea API returned the status code %d: %s", e.StatusCode, e.Message)
+}
+
+// Gitea calls Gitea API v1 of the repository
+type Gitea struct {
+	client *http.Client
+	// apiURL is the URL of Gitea API v1 like https://gitea.example.com/api/v1
+	apiURL string
+	token  string
+	owner  string
+	repo   string
+}
+
+func newGitea(client *http.Client, apiURL, token, owner, repo string) *Gitea {
+	return &Gitea{
+		client: client,
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  token,
+		owner:  owner,
+		repo:   repo,
+	}
+}
+
+// CreateComment is a wrapper of https://gitea.com/api/swagger#/issue/issueCreateComment
+func (g *Gitea) CreateComment(ctx context.Context, number int, body string) error {
+	return g.do(ctx, http.MethodPost, "/issues/"+strconv.Itoa(number)+"/comments", map[string]string{
+		"body": body,
+	}, nil)
+}
+
+// ListComments is a wrapper of https://gitea.com/api/swagger#/issue/issueGetComments .
+// Comments are sorted by created time in ascending order
+func (g *Gitea) ListComments(ctx context.Context, number int) ([]*Comment, error) {
+	var comments []*Comment
+	if err := g.do(ctx, http.MethodGet, "/issues/"+strconv.Itoa(number)+"/comments", nil, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// EditComment is a wrapper of https://gitea.com/api/swagger#/issue/issueEditComment
+func (g *Gitea) EditComment(ctx context.Context, id int64, body string) error {
+	return g.do(ctx, http.MethodPatch, "/issues/comments/"+strconv.FormatInt(id, 10), map[string]string{
+		"body": body,
+	}, nil)
+}
+
+// DeleteComment is a wrapper of https://gitea.com/api/swagger#/issue/issueDeleteComment
+func (g *Gitea) DeleteComment(ctx context.Context, id int64) error {
+	return g.do(ctx, http.MethodDelete, "/issues/comments/"+strconv.FormatInt(id, 10), nil, nil)
+}
+
+// GetPullRequestByCommit is a wrapper of https://gitea.com/api/swagger#/repository/repoGetCommitPullRequest .
+// This API is available in Gitea 1.19 or later
+func (g *Gitea) GetPullRequestByCommit(ctx context.Context, sha string) (*PullRequest, error) {
+	pr := &PullRequest{}
+	if err := g.do(ctx, http.MethodGet, "/commits/"+url.PathEscape(sha)+"/pull", nil, pr); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// do calls the API of the repository. The request body and the response body are JSON
+func (g *Gitea) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal the request body: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	u := g.apiURL + "/repos/" + url.PathEscape(g.owner) + "/" + url.PathEscape(g.repo) + path
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+g.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call Gitea API: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read the response body: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &Error{
+			StatusCode: resp.StatusCode,
+			Message:    string(b),
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("parse the response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	API
+	comments    map[int][]*Comment
+	edited      map[int64]string
+	deleted     []int64
+	prsByCommit map[string]*PullRequest
+	nextID      int64
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		comments:    map[int][]*Comment{},
+		edited:      map[int64]string{},
+		prsByCommit: map[string]*PullRequest{},
+		nextID:      100,
+	}
+}
+
+func (g *fakeAPI) CreateComment(ctx context.Context, number int, body string) error {
+	g.nextID++
+	g.comments[number] = append(g.comments[number], &Comment{ID: g.nextID, Body: body})
+	return nil
+}
+
+func (g *fakeAPI) ListComments(ctx context.Context, number int) ([]*Comment, error) {
+	return g.comments[number], nil
+}
+
+func (g *fakeAPI) EditComment(ctx context.Context, id int64, body string) error {
+	g.edited[id] = body
+	return nil
+}
+
+func (g *fakeAPI) DeleteComment(ctx context.Context, id int64) error {
+	g.deleted = append(g.deleted, id)
+	return nil
+}
+
+func (g *fakeAPI) GetPullRequestByCommit(ctx context.Context, sha string) (*PullRequest, error) {
+	pr, ok := g.prsByCommit[sha]
+	if !ok {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+	return pr, nil
+}
+
+func TestGitea(t *testing.T) {
+	t.Parallel()
+	type request struct {
+		Method string
+		Path   string
+		Token  string
+		Body   map[string]string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Token:  r.Header.Get("Authorization"),
+		}
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&req.Body)
+		}
+		requests = append(requests, req)
+		switch r.URL.Path {
+		case "/api/v1/repos/suzuki-shunsuke/tfcmt/issues/1/comments":
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`[{"id":10,"body":"foo"}]`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":11}`))
+		case "/api/v1/repos/suzuki-shunsuke/tfcmt/commits/abcd/pull":
+			_, _ = w.Write([]byte(`{"number":3,"state":"closed","merged":true}`))
+		case "/api/v1/repos/suzuki-shunsuke/tfcmt/issues/comments/10":
+			if r.Method == http.MethodDelete {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":10}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	g := newGitea(server.Client(), server.URL+"/api/v1/", "xxx", "suzuki-shunsuke", "tfcmt")
+	if err := g.CreateComment(ctx, 1, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	comments, err := g.ListComments(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*Comment{{ID: 10, Body: "foo"}}, comments); diff != "" {
+		t.Error(diff)
+	}
+	if err := g.EditComment(ctx, 10, "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.DeleteComment(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := g.GetPullRequestByCommit(ctx, "abcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&PullRequest{Number: 3, State: "closed", Merged: true}, pr); diff != "" {
+		t.Error(diff)
+	}
+	if err := g.CreateComment(ctx, 2, "hello"); err == nil {
+		t.Fatal("error should be returned")
+	}
+	exp := []request{
+		{Method: http.MethodPost, Path: "/api/v1/repos/suzuki-shunsuke/tfcmt/issues/1/comments", Token: "token xxx", Body: map[string]string{"body": "hello"}},
+		{Method: http.MethodGet, Path: "/api/v1/repos/suzuki-shunsuke/tfcmt/issues/1/comments", Token: "token xxx"},
+		{Method: http.MethodPatch, Path: "/api/v1/repos/suzuki-shunsuke/tfcmt/issues/comments/10", Token: "token xxx", Body: map[string]string{"body": "bar"}},
+		{Method: http.MethodDelete, Path: "/api/v1/repos/suzuki-shunsuke/tfcmt/issues/comments/10", Token: "token xxx"},
+		{Method: http.MethodGet, Path: "/api/v1/repos/suzuki-shunsuke/tfcmt/commits/abcd/pull", Token: "token xxx"},
+		{Method: http.MethodPost, Path: "/api/v1/repos/suzuki-shunsuke/tfcmt/issues/2/comments", Token: "token xxx", Body: map[string]string{"body": "hello"}},
+	}
+	if diff := cmp.Diff(exp, requests); diff != "" {
+		t.Error(diff)
+	}
+}
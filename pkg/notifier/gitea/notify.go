@@ -0,0 +1,195 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment-metadata/metadata"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService posts the result as a comment of the pull request
+type NotifyService struct {
+	client *Client
+}
+
+// Notify posts comment optimized for notifications
+func (g *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := g.client.Config
+
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+
+	if result.IsApply && !cfg.DryRun && !cfg.PR.IsNumber() && cfg.PR.Revision != "" {
+		// the pull request is found by the merged commit
+		pr, err := g.client.API.GetPullRequestByCommit(ctx, cfg.PR.Revision)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+				"sha":     cfg.PR.Revision,
+			}).WithError(err).Warn("get the pull request associated with the commit")
+		} else {
+			cfg.PR.Number = pr.Number
+		}
+	}
+
+	body, err := result.Render(notifier.RenderOption{
+		Template:           cfg.Template,
+		ParseErrorTemplate: cfg.ParseErrorTemplate,
+		Link:               cfg.CI,
+		Vars:               cfg.Vars,
+		Templates:          cfg.Templates,
+		UseRawOutput:       cfg.UseRawOutput,
+		MaxResources:       cfg.MaxResources,
+	})
+	if err != nil || body == "" {
+		return result.ExitCode, err
+	}
+
+	var oldComments []*Comment
+	if !cfg.DryRun && cfg.PR.IsNumber() && cfg.OldComment.Action != "" && cfg.OldComment.Action != OldCommentActionKeep {
+		comments, err := g.listOldComments(ctx, cfg.PR.Number, result.Command())
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+			}).WithError(err).Warn("list old comments")
+		}
+		oldComments = comments
+	}
+
+	embeddedComment, err := getEmbeddedComment(&cfg, result.Command())
+	if err != nil {
+		return result.ExitCode, err
+	}
+	// embed HTML tag to hide old comments
+	body += embeddedComment
+
+	if err := g.post(ctx, &cfg, body); err != nil {
+		return result.ExitCode, err
+	}
+	if err := notifier.WriteOutput(cfg.Output, body); err != nil {
+		return result.ExitCode, err
+	}
+	g.handleOldComments(ctx, oldComments)
+	return result.ExitCode, nil
+}
+
+// post posts a comment of the pull request. Gitea doesn't support comments of commits
+func (g *NotifyService) post(ctx context.Context, cfg *Config, body string) error {
+	if cfg.DryRun {
+		return notifier.WriteDryRunOutput(cfg.DryRunOutput, body)
+	}
+	if !cfg.PR.IsNumber() {
+		return errors.New("the pull request number is required because Gitea doesn't support comments of commits")
+	}
+	if err := g.client.API.CreateComment(ctx, cfg.PR.Number, body); err != nil {
+		return fmt.Errorf("create a comment of the pull request: %w", err)
+	}
+	return nil
+}
+
+func getEmbeddedComment(cfg *Config, command string) (string, error) {
+	vars := make(map[string]interface{}, len(cfg.EmbeddedVarNames))
+	for _, name := range cfg.EmbeddedVarNames {
+		vars[name] = cfg.Vars[name]
+	}
+	return metadata.Convert(map[string]interface{}{
+		"Program":  cfg.program(),
+		"Vars":     vars,
+		"SHA1":     cfg.PR.Revision,
+		"PRNumber": cfg.PR.Number,
+		"Link":     cfg.CI,
+		"Target":   cfg.Vars["target"],
+		"Command":  command,
+	})
+}
+
+// commentMetadata is the metadata embedded in comments by tfcmt
+type commentMetadata struct {
+	Program string
+	Command string
+	Target  string
+}
+
+// collapsedCommentPrefix is the prefix of comments collapsed by tfcmt
+const collapsedCommentPrefix = "<details><summary>"
+
+// listOldComments returns comments posted by tfcmt whose command and target match
+func (g *NotifyService) listOldComments(ctx context.Context, number int, command string) ([]*Comment, error) {
+	comments, err := g.client.API.ListComments(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	cfg := g.client.Config
+	program, target := cfg.program(), cfg.Vars["target"]
+	ret := []*Comment{}
+	for _, comment := range comments {
+		meta := &commentMetadata{}
+		f, err := metadata.Extract(comment.Body, meta)
+		if err != nil || !f {
+			continue
+		}
+		if meta.Program != program || meta.Command != command || meta.Target != target {
+			continue
+		}
+		ret = append(ret, comment)
+	}
+	return ret, nil
+}
+
+// handleOldComments collapses or deletes old comments according to the configuration.
+// Gitea can't minimize comments like GitHub, so comments are collapsed with the details tag
+func (g *NotifyService) handleOldComments(ctx context.Context, comments []*Comment) {
+	cfg := g.client.Config
+	classifier := cfg.OldComment.Classifier
+	if classifier == "" {
+		classifier = "OUTDATED"
+	}
+	for _, comment := range comments {
+		logE := logrus.WithFields(logrus.Fields{
+			"program":    "tfcmt",
+			"comment_id": comment.ID,
+		})
+		switch cfg.OldComment.Action {
+		case OldCommentActionMinimize:
+			if isCollapsed(comment.Body) {
+				continue
+			}
+			if err := g.client.API.EditComment(ctx, comment.ID, collapseComment(comment.Body, classifier)); err != nil {
+				logE.WithError(err).Warn("collapse an old comment")
+			}
+		case OldCommentActionDelete:
+			if err := g.client.API.DeleteComment(ctx, comment.ID); err != nil {
+				logE.WithError(err).Warn("delete an old comment")
+			}
+		}
+	}
+}
+
+// isCollapsed returns true if the comment has already been collapsed by tfcmt with any classifier,
+// so comments aren't collapsed again even if the classifier is changed.
+// Comments which start with the details tag of the template aren't regarded as collapsed
+func isCollapsed(body string) bool {
+	for _, classifier := range []string{"OUTDATED", "RESOLVED", "DUPLICATE", "OFF_TOPIC"} {
+		if strings.HasPrefix(body, collapsedSummary(classifier)) {
+			return true
+		}
+	}
+	return false
+}
+
+// collapsedSummary returns the beginning of comments collapsed by tfcmt with the classifier
+func collapsedSummary(classifier string) string {
+	return collapsedCommentPrefix + classifier + "</summary>\n\n"
+}
+
+// collapseComment wraps the comment with the details tag. The embedded metadata is kept
+func collapseComment(body, classifier string) string {
+	return collapsedSummary(classifier) + body + "\n\n</details>"
+}
@@ -0,0 +1,149 @@
+package gitea
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const planNoChanges = `No changes. Infrastructure is up-to-date.`
+
+func newFakeConfig() Config {
+	return Config{
+		Token: "token",
+		Owner: "suzuki-shunsuke",
+		Repo:  "tfcmt",
+		PR: PullRequestInfo{
+			Revision: "abcd",
+			Number:   1,
+		},
+		Parser:             terraform.NewPlanParser(),
+		Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+		ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanParseErrorTemplate),
+		Vars:               map[string]string{"target": "foo"},
+	}
+}
+
+func TestNotifyOldComments(t *testing.T) {
+	t.Parallel()
+	oldPlan := "old\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"foo"} -->`
+	otherTarget := "old\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"bar"} -->`
+	collapsed := collapseComment(oldPlan, "OUTDATED")
+	// the template of the old comment starts with the details tag, but it isn't collapsed by tfcmt
+	details := "<details><summary>Plan Result</summary>\n\nold\n</details>\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"foo"} -->`
+	testCases := []struct {
+		name       string
+		action     string
+		classifier string
+		expEdited  map[int64]string
+		expDeleted []int64
+	}{
+		{
+			name:      "minimize",
+			action:    OldCommentActionMinimize,
+			expEdited: map[int64]string{1: collapsed, 4: collapseComment(details, "OUTDATED")},
+		},
+		{
+			// the comment collapsed with the previous classifier isn't collapsed again
+			name:       "the classifier is changed",
+			action:     OldCommentActionMinimize,
+			classifier: "RESOLVED",
+			expEdited:  map[int64]string{1: collapseComment(oldPlan, "RESOLVED"), 4: collapseComment(details, "RESOLVED")},
+		},
+		{
+			name:       "delete",
+			action:     OldCommentActionDelete,
+			expEdited:  map[int64]string{},
+			expDeleted: []int64{1, 3, 4},
+		},
+		{
+			name:      "keep",
+			action:    OldCommentActionKeep,
+			expEdited: map[int64]string{},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := newFakeAPI()
+			api.comments[1] = []*Comment{
+				{ID: 1, Body: oldPlan},
+				{ID: 2, Body: otherTarget},
+				{ID: 3, Body: collapsed},
+				{ID: 4, Body: details},
+			}
+			cfg := newFakeConfig()
+			cfg.OldComment.Action = testCase.action
+			cfg.OldComment.Classifier = testCase.classifier
+			ntf := &NotifyService{
+				client: &Client{
+					Config: cfg,
+					API:    api,
+				},
+			}
+			if _, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: planNoChanges,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if len(api.comments[1]) != 5 {
+				t.Fatalf("a comment should be posted: %d", len(api.comments[1]))
+			}
+			if body := api.comments[1][4].Body; !strings.Contains(body, "No changes") || !strings.Contains(body, `<!-- github-comment: {"Command":"plan"`) {
+				t.Errorf("unexpected comment: %s", body)
+			}
+			if diff := cmp.Diff(testCase.expEdited, api.edited); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.expDeleted, api.deleted); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNotifyApply(t *testing.T) {
+	t.Parallel()
+	api := newFakeAPI()
+	api.prsByCommit["abcd"] = &PullRequest{Number: 3, State: "closed", Merged: true}
+	cfg := newFakeConfig()
+	cfg.PR.Number = 0
+	cfg.Parser = terraform.NewApplyParser()
+	cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+	ntf := &NotifyService{
+		client: &Client{
+			Config: cfg,
+			API:    api,
+		},
+	}
+	if _, err := ntf.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.comments[3]) != 1 {
+		t.Errorf("the comment should be posted to the merged pull request: %v", api.comments)
+	}
+}
+
+func TestNotifyWithoutPullRequest(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.PR.Number = 0
+	ntf := &NotifyService{
+		client: &Client{
+			Config: cfg,
+			API:    newFakeAPI(),
+		},
+	}
+	if _, err := ntf.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: planNoChanges,
+	}); err == nil {
+		t.Fatal("error should be returned because Gitea doesn't support comments of commits")
+	}
+}
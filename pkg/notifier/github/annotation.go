@@ -0,0 +1,45 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// writeAnnotations writes diagnostics as workflow commands of GitHub Actions like "::error file=main.tf,line=1,title=Unsupported argument::detail".
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
+func writeAnnotations(w io.Writer, diagnostics []terraform.Diagnostic) error {
+	for _, diag := range diagnostics {
+		command := "warning"
+		if diag.Severity == "error" {
+			command = "error"
+		}
+		props := []string{}
+		if diag.Filename != "" {
+			props = append(props, "file="+escapeAnnotationProperty(diag.Filename))
+			if diag.Line != 0 {
+				props = append(props, "line="+strconv.Itoa(diag.Line))
+			}
+		}
+		props = append(props, "title="+escapeAnnotationProperty(diag.Summary))
+		msg := diag.Detail
+		if msg == "" {
+			msg = diag.Summary
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(props, ","), escapeAnnotationData(msg)); err != nil {
+			return fmt.Errorf("write an annotation: %w", err)
+		}
+	}
+	return nil
+}
+
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
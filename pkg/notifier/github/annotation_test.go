@@ -0,0 +1,35 @@
+package github
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestWriteAnnotations(t *testing.T) {
+	t.Parallel()
+	buf := &bytes.Buffer{}
+	err := writeAnnotations(buf, []terraform.Diagnostic{
+		{
+			Severity: "error",
+			Summary:  "Unsupported argument",
+			Detail:   "An argument named \"foo\" is not expected here.\n100% sure",
+			Filename: "main.tf",
+			Line:     3,
+		},
+		{
+			Severity: "warning",
+			Summary:  "Deprecated: a, b",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `::error file=main.tf,line=3,title=Unsupported argument::An argument named "foo" is not expected here.%0A100%25 sure
+::warning title=Deprecated%3A a%2C b::Deprecated: a, b
+`
+	if buf.String() != exp {
+		t.Errorf("got %q, wanted %q", buf.String(), exp)
+	}
+}
@@ -0,0 +1,162 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+	"golang.org/x/oauth2"
+)
+
+// App is a configuration to authenticate as a GitHub App installation
+type App struct {
+	ID             int64
+	InstallationID int64
+	// PrivateKey is the PEM encoded private key of the GitHub App
+	PrivateKey string
+	// BaseURL is the API base URL to create installation access tokens, for example the API of GitHub Enterprise Server.
+	// If this is empty, the base URL of the client is used
+	BaseURL string
+}
+
+// IsEnabled returns true if the GitHub App is configured
+func (app *App) IsEnabled() bool {
+	return app.ID != 0
+}
+
+// appTokenSource mints installation access tokens of a GitHub App.
+// The token is cached and refreshed by oauth2.ReuseTokenSource before it expires.
+type appTokenSource struct {
+	ctx            context.Context
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	baseURL        string
+	uploadURL      string
+}
+
+// jwtExpiration is the lifetime of the JWT to request installation access tokens. The maximum is 10 minutes
+const jwtExpiration = 9 * time.Minute
+
+func newAppTokenSource(ctx context.Context, app App, baseURL, uploadURL string) (oauth2.TokenSource, error) {
+	if app.InstallationID == 0 {
+		return nil, errors.New("the installation id of the GitHub App is missing")
+	}
+	key, err := parsePrivateKey([]byte(app.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse the private key of the GitHub App: %w", err)
+	}
+	return oauth2.ReuseTokenSource(nil, &appTokenSource{
+		ctx:            ctx,
+		appID:          app.ID,
+		installationID: app.InstallationID,
+		key:            key,
+		baseURL:        baseURL,
+		uploadURL:      uploadURL,
+	}), nil
+}
+
+func parsePrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("the private key isn't PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key isn't a RSA key")
+	}
+	return key, nil
+}
+
+// Token creates an installation access token
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	jwt, err := s.jwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	hc := oauth2.NewClient(s.ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: jwt}))
+	client := github.NewClient(hc)
+	if s.baseURL != "" {
+		client, err = github.NewEnterpriseClient(s.baseURL, s.uploadURL, hc)
+		if err != nil {
+			return nil, errors.New("failed to create a new github api client")
+		}
+	}
+	token, _, err := client.Apps.CreateInstallationToken(s.ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create an installation access token of the GitHub App: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		Expiry:      token.GetExpiresAt(),
+	}, nil
+}
+
+// jwt returns a JWT signed by the private key of the GitHub App
+func (s *appTokenSource) jwt(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		// issue the JWT 60 seconds in the past to allow for clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(jwtExpiration).Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("sign a JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// newHTTPClient returns the HTTP client to call GitHub API.
+// If the GitHub App is configured, the client authenticates as the installation. Otherwise the token is used.
+func newHTTPClient(ctx context.Context, cfg *Config, token, baseURL, uploadURL string) (*http.Client, error) {
+	if cfg.App.IsEnabled() {
+		if cfg.App.BaseURL != "" {
+			baseURL = cfg.App.BaseURL
+			uploadURL = getUploadURL(baseURL)
+		}
+		ts, err := newAppTokenSource(ctx, cfg.App, baseURL, uploadURL)
+		if err != nil {
+			return nil, err
+		}
+		return oauth2.NewClient(ctx, ts), nil
+	}
+	if token == "" {
+		// In dry run mode, GitHub API isn't called so the token isn't required
+		return http.DefaultClient, nil
+	}
+	return oauth2.NewClient(ctx, oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: token},
+	)), nil
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+)
+
+func TestAppTokenSource(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048) //nolint:gomnd
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+
+	testCases := []struct {
+		name           string
+		appBaseURL     string
+		expAccessToken string
+	}{
+		{
+			name:           "the base url of the client",
+			expAccessToken: "/api/v3/app/installations/2/access_tokens",
+		},
+		{
+			name:           "the base url of the GitHub App",
+			appBaseURL:     "/ghes/api/v3/",
+			expAccessToken: "/ghes/api/v3/app/installations/2/access_tokens",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				mutex       sync.Mutex
+				tokenCount  int
+				authHeaders []string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				switch r.URL.Path {
+				case testCase.expAccessToken:
+					tokenCount++
+					if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "Bearer ") || strings.Count(auth, ".") != 2 { //nolint:gomnd
+						t.Errorf("the request should be authenticated with JWT: %s", auth)
+					}
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"token":"ghs_xxx","expires_at":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+				default:
+					authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+					_, _ = w.Write([]byte(`{"id":1}`))
+				}
+			}))
+			defer server.Close()
+
+			cfg := newFakeConfig()
+			cfg.Token = ""
+			cfg.BaseURL = server.URL + "/api/v3/"
+			cfg.App = App{
+				ID:             1,
+				InstallationID: 2,
+				PrivateKey:     privateKey,
+			}
+			if testCase.appBaseURL != "" {
+				cfg.App.BaseURL = server.URL + testCase.appBaseURL
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if _, _, err := client.API.IssuesCreateComment(context.Background(), 1, &github.IssueComment{Body: github.String("hello")}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tokenCount != 1 {
+				t.Errorf("the installation access token should be reused but created %d times", tokenCount)
+			}
+			for _, auth := range authHeaders {
+				if auth != "Bearer ghs_xxx" {
+					t.Errorf("the request should be authenticated with the installation access token: %s", auth)
+				}
+			}
+		})
+	}
+}
+
+func TestNewAppTokenSource(t *testing.T) {
+	t.Parallel()
+	if _, err := newAppTokenSource(context.Background(), App{ID: 1, InstallationID: 2, PrivateKey: "invalid"}, "", ""); err == nil {
+		t.Error("an error should be returned if the private key is invalid")
+	}
+	if _, err := newAppTokenSource(context.Background(), App{ID: 1}, "", ""); err == nil {
+		t.Error("an error should be returned if the installation id is missing")
+	}
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	CheckRunConclusionSuccess = "success"
+	CheckRunConclusionFailure = "failure"
+	CheckRunConclusionNeutral = "neutral"
+)
+
+// maxCheckRunSummaryLength is the maximum length of the summary of a check run
+const maxCheckRunSummaryLength = 65535
+
+// checkRunConclusion returns the conclusion of the check run.
+// Errors are "failure", a plan without changes is "neutral", and the others are "success"
+func checkRunConclusion(result terraform.ParseResult) string {
+	switch {
+	case result.HasParseError, result.HasApplyError, !result.Succeeded():
+		return CheckRunConclusionFailure
+	case result.HasNoChanges:
+		return CheckRunConclusionNeutral
+	default:
+		return CheckRunConclusionSuccess
+	}
+}
+
+// checkRunTitle returns the title of the check run output
+func checkRunTitle(result terraform.ParseResult, command string) string {
+	switch {
+	case result.HasParseError:
+		return "Failed to parse the output of terraform " + command
+	case result.HasPlanError:
+		return "Plan failed"
+	case result.HasApplyError:
+		return "Apply failed"
+	case result.HasNoChanges:
+		return "No changes"
+	}
+	// The result is the line like "Plan: 1 to add, 0 to change, 0 to destroy."
+	if line := strings.TrimSpace(headLines(result.Result, 1)); line != "" {
+		return line
+	}
+	return "terraform " + command + " succeeded"
+}
+
+// checkRunName returns the name of the check run.
+// The default name is "<tag> <command>", and the target is appended if it is set
+func (cfg *Config) checkRunName(command string) string {
+	if cfg.CheckRun.Name != "" {
+		return cfg.CheckRun.Name
+	}
+	name := cfg.program() + " " + command
+	if target := cfg.Vars["target"]; target != "" {
+		name += " (" + target + ")"
+	}
+	return name
+}
+
+// truncateCheckRunSummary truncates the summary to the maximum length of the check run summary
+func truncateCheckRunSummary(summary string) string {
+	if len(summary) <= maxCheckRunSummaryLength {
+		return summary
+	}
+	suffix := "\n\n... (truncated)"
+	return strings.ToValidUTF8(summary[:maxCheckRunSummaryLength-len(suffix)], "") + suffix
+}
+
+// createCheckRun creates a completed check run of the result at PR.Revision.
+// The body of the comment is used as the summary, and the details URL is the link to the CI build
+func (g *NotifyService) createCheckRun(ctx context.Context, cfg *Config, command, body string, result terraform.ParseResult) error {
+	opts := github.CreateCheckRunOptions{
+		Name:        cfg.checkRunName(command),
+		HeadSHA:     cfg.PR.Revision,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(checkRunConclusion(result)),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output: &github.CheckRunOutput{
+			Title:   github.String(checkRunTitle(result, command)),
+			Summary: github.String(truncateCheckRunSummary(body)),
+		},
+	}
+	if cfg.CI != "" {
+		opts.DetailsURL = github.String(cfg.CI)
+	}
+	_, _, err := g.client.API.ChecksCreateCheckRun(ctx, opts)
+	return err
+}
+
+// postCheckRun creates the check run and returns true if it is created.
+// Errors are only logged so that the comment is posted anyway
+func (g *NotifyService) postCheckRun(ctx context.Context, cfg *Config, command, body string, result terraform.ParseResult) bool {
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+	if cfg.PR.Revision == "" {
+		logE.Warn("skip creating a check run because the revision is unknown")
+		return false
+	}
+	if err := g.createCheckRun(ctx, cfg, command, body, result); err != nil {
+		logE.WithError(err).Error("create a check run")
+		return false
+	}
+	return true
+}
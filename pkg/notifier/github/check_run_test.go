@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+func TestNotifyCheckRun(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		output        string
+		exitCode      int
+		checkRun      CheckRun
+		revision      string
+		checkRunErr   error
+		expConclusion string
+		expTitle      string
+		expName       string
+		expPosted     bool
+	}{
+		{
+			name:          "changes",
+			output:        "Plan: 1 to add, 0 to change, 0 to destroy.",
+			exitCode:      2,
+			checkRun:      CheckRun{Enabled: true},
+			revision:      "abcd",
+			expConclusion: CheckRunConclusionSuccess,
+			expTitle:      "Plan: 1 to add, 0 to change, 0 to destroy.",
+			expName:       "tfcmt plan (foo)",
+			expPosted:     true,
+		},
+		{
+			name:          "no changes",
+			output:        "No changes. Infrastructure is up-to-date.",
+			checkRun:      CheckRun{Enabled: true, Name: "terraform", SkipComment: true},
+			revision:      "abcd",
+			expConclusion: CheckRunConclusionNeutral,
+			expTitle:      "No changes",
+			expName:       "terraform",
+		},
+		{
+			name:          "plan error",
+			output:        "Error: Invalid reference",
+			exitCode:      1,
+			checkRun:      CheckRun{Enabled: true, SkipComment: true},
+			revision:      "abcd",
+			expConclusion: CheckRunConclusionFailure,
+			expTitle:      "Plan failed",
+			expName:       "tfcmt plan (foo)",
+		},
+		{
+			name:          "parse error",
+			output:        "foo",
+			exitCode:      1,
+			checkRun:      CheckRun{Enabled: true},
+			revision:      "abcd",
+			expConclusion: CheckRunConclusionFailure,
+			expTitle:      "Failed to parse the output of terraform plan",
+			expName:       "tfcmt plan (foo)",
+			expPosted:     true,
+		},
+		{
+			name:        "the comment is posted if the check run can't be created",
+			output:      "No changes. Infrastructure is up-to-date.",
+			checkRun:    CheckRun{Enabled: true, SkipComment: true},
+			revision:    "abcd",
+			checkRunErr: errors.New("resource not accessible by integration"),
+			expPosted:   true,
+		},
+		{
+			name:      "the revision is unknown",
+			output:    "No changes. Infrastructure is up-to-date.",
+			checkRun:  CheckRun{Enabled: true, SkipComment: true},
+			expPosted: true,
+		},
+		{
+			name:      "disabled",
+			output:    "No changes. Infrastructure is up-to-date.",
+			revision:  "abcd",
+			expPosted: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.CI = "https://ci.example.com/builds/1"
+			cfg.CheckRun = testCase.checkRun
+			cfg.PR.Revision = testCase.revision
+			cfg.Vars = map[string]string{"target": "foo"}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				checkRun *github.CreateCheckRunOptions
+				posted   bool
+			)
+			api := newFakeAPI()
+			api.FakeChecksCreateCheckRun = func(ctx context.Context, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+				if testCase.checkRunErr != nil {
+					return nil, nil, testCase.checkRunErr
+				}
+				checkRun = &opts
+				return &github.CheckRun{}, nil, nil
+			}
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				posted = true
+				return comment, nil, nil
+			}
+			client.API = &api
+			exitCode, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != testCase.exitCode {
+				t.Errorf("exit code: got %d, wanted %d", exitCode, testCase.exitCode)
+			}
+			if posted != testCase.expPosted {
+				t.Errorf("posted: got %v, wanted %v", posted, testCase.expPosted)
+			}
+			if testCase.expConclusion == "" {
+				if checkRun != nil {
+					t.Fatalf("a check run shouldn't be created: %v", checkRun)
+				}
+				return
+			}
+			if checkRun == nil {
+				t.Fatal("a check run should be created")
+			}
+			if checkRun.GetConclusion() != testCase.expConclusion {
+				t.Errorf("conclusion: got %q, wanted %q", checkRun.GetConclusion(), testCase.expConclusion)
+			}
+			if checkRun.Name != testCase.expName {
+				t.Errorf("name: got %q, wanted %q", checkRun.Name, testCase.expName)
+			}
+			if checkRun.HeadSHA != testCase.revision {
+				t.Errorf("head sha: got %q, wanted %q", checkRun.HeadSHA, testCase.revision)
+			}
+			if checkRun.GetDetailsURL() != cfg.CI {
+				t.Errorf("details url: got %q, wanted %q", checkRun.GetDetailsURL(), cfg.CI)
+			}
+			if title := checkRun.GetOutput().GetTitle(); title != testCase.expTitle {
+				t.Errorf("title: got %q, wanted %q", title, testCase.expTitle)
+			}
+			if summary := checkRun.GetOutput().GetSummary(); summary == "" || strings.Contains(summary, "<!-- github-comment:") {
+				t.Errorf("the summary should be the rendered result without the metadata: %q", summary)
+			}
+		})
+	}
+}
+
+func TestTruncateCheckRunSummary(t *testing.T) {
+	t.Parallel()
+	if s := truncateCheckRunSummary("foo"); s != "foo" {
+		t.Errorf("a short summary shouldn't be truncated: %q", s)
+	}
+	s := truncateCheckRunSummary(strings.Repeat("あ", maxCheckRunSummaryLength))
+	if len(s) > maxCheckRunSummaryLength {
+		t.Errorf("the summary should be truncated: %d", len(s))
+	}
+	if !strings.HasSuffix(s, "(truncated)") {
+		t.Errorf("the truncated summary should end with the suffix: %q", s[len(s)-30:])
+	}
+}
@@ -3,13 +3,14 @@ package github
 import (
 	"context"
 	"errors"
+	"net/url"
 	"os"
 	"strings"
 
 	"github.com/google/go-github/v39/github"
 	"github.com/shurcooL/githubv4"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
-	"golang.org/x/oauth2"
 )
 
 // EnvToken is GitHub API Token
@@ -18,6 +19,11 @@ const EnvToken = "GITHUB_TOKEN" //nolint:gosec
 // EnvBaseURL is GitHub base URL. This can be set to a domain endpoint to use with GitHub Enterprise.
 const EnvBaseURL = "GITHUB_BASE_URL"
 
+// EnvAPIURL is GitHub API URL. GitHub Actions sets this to the API endpoint of the GitHub Enterprise Server which runs the workflow.
+const EnvAPIURL = "GITHUB_API_URL"
+
+const defaultAPIURL = "https://api.github.com"
+
 // Client is a API client for GitHub
 type Client struct {
 	*github.Client
@@ -34,17 +40,26 @@ type Client struct {
 	v4Client *githubv4.Client
 
 	API API
+
+	// deploymentID is the id of the deployment created by NotifyService.Start
+	deploymentID int64
+	// posted is the comment posted by NotifyService.Notify
+	posted *notifier.PostedComment
 }
 
 // Config is a configuration for GitHub client
 type Config struct {
-	Token   string
-	BaseURL string
-	Owner   string
-	Repo    string
-	PR      PullRequest
-	CI      string
-	Parser  terraform.Parser
+	Token     string
+	BaseURL   string
+	UploadURL string
+	Owner     string
+	Repo      string
+	PR        PullRequest
+	CI        string
+	Parser    terraform.Parser
+	// TargetPRNumber overrides PR.Number. If this is set, the pull request isn't detected automatically.
+	// This is useful to post the result to a tracking pull request of backports
+	TargetPRNumber int
 	// Template is used for all Terraform command output
 	Template           *terraform.Template
 	ParseErrorTemplate *terraform.Template
@@ -52,14 +67,226 @@ type Config struct {
 	ResultLabels     ResultLabels
 	Vars             map[string]string
 	EmbeddedVarNames []string
-	Templates        map[string]string
-	UseRawOutput     bool
+	// Tag is embedded into comments as the metadata "Program" and tfcmt handles only comments with the same tag.
+	// The default value is "tfcmt"
+	Tag string
+	// IdempotencyKey is embedded into comments, and a comment isn't posted if a comment with the same key already exists.
+	// This prevents duplicate comments when a CI job is retried
+	IdempotencyKey string
+	// App is used to authenticate as a GitHub App installation instead of Token
+	App App
+	// TemplateEnvPrefixes is a list of prefixes of environment variables which can be referred as .Env in templates
+	TemplateEnvPrefixes []string
+	Templates           map[string]string
+	UseRawOutput        bool
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output.
+	// By default, they are normalized before the output is parsed and rendered
+	DisableNormalization bool
+	// MaxResources is the maximum number of resources listed per action in the built-in templates. 0 means unlimited
+	MaxResources int
+	// SourceMap is used to link resources in comments to their source locations at PR.Revision
+	SourceMap terraform.SourceMap
+	// SkipDuplicateComment skips posting a plan comment if it is identical to the latest one
+	SkipDuplicateComment bool
+	// SkipDuplicateApply skips posting an apply comment if it is identical to the latest one.
+	// This prevents duplicate comments when the CI job of apply is re-run on the same commit
+	SkipDuplicateApply bool
+	// FailOnDestroy makes Notify return a non-zero exit code if the plan would destroy more resources than DestroyThreshold
+	FailOnDestroy    bool
+	DestroyThreshold int
+	// FailOnCritical makes Notify return a non-zero exit code if the security scan result contains critical findings
+	FailOnCritical bool
+	// FailOnPolicyViolation makes Notify return a non-zero exit code if any policy fails
+	FailOnPolicyViolation bool
+	// OldComment is how to handle old comments posted by tfcmt
+	OldComment OldComment
+	// Review posts a plan comment as a pull request review
+	Review Review
+	// InlineComments posts review comments on the resource blocks changed by the plan.
+	// SourceMap is used to find the resource blocks
+	InlineComments bool
+	// CheckRun creates a check run with the result in addition to or instead of the comment
+	CheckRun CheckRun
+	// CommitStatus sets a commit status with the result
+	CommitStatus CommitStatus
+	// Deployment creates a deployment on terraform apply
+	Deployment Deployment
+	// DriftIssue creates, updates, and closes an issue which tracks the drift of the target instead of posting a plan comment
+	DriftIssue DriftIssue
+	// StepSummary appends the result to the job summary of GitHub Actions.
+	// This is ignored if the environment variable GITHUB_STEP_SUMMARY isn't set
+	StepSummary bool
+	// FmtSuggestion posts suggested changes of terraform fmt as review comments
+	FmtSuggestion FmtSuggestion
+	// Annotations writes diagnostics of terraform validate to the standard output as workflow commands of GitHub Actions
+	Annotations bool
+	// ClosedPRAction is how to post a plan comment if the pull request has been closed.
+	// The default value is "post"
+	ClosedPRAction string
+	// Gist uploads the result to a Gist if the comment is too large
+	Gist Gist
+	// Truncate shrinks the comment if the comment is too large. Gist takes precedence over Truncate
+	Truncate Truncate
+	// PostTriggers posts a plan comment only if the result matches any of them. If this is empty, the comment is always posted.
+	// Labels are updated regardless of PostTriggers
+	PostTriggers []string
+	// SummaryPosition is where the summary of the plan result is rendered, either "top" or "bottom" of the comment.
+	// If this is empty, the summary isn't rendered
+	SummaryPosition string
+	// DryRun renders the comment but doesn't post it and doesn't update labels.
+	// The comment is written to DryRunOutput. If DryRunOutput is empty, the comment is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+	// Output is the file path where the posted comment is also written. If this is empty, the comment isn't written
+	Output string
+	// Hook is called before the template is rendered. If Hook is nil, NopHook is used
+	Hook Hook
+}
+
+func (cfg *Config) hook() Hook {
+	if cfg.Hook == nil {
+		return NopHook{}
+	}
+	return cfg.Hook
+}
+
+// defaultTag is the default value of Config.Tag
+const defaultTag = "tfcmt"
+
+// program returns the value of the metadata "Program"
+func (cfg *Config) program() string {
+	if cfg.Tag == "" {
+		return defaultTag
+	}
+	return cfg.Tag
+}
+
+// Review is a configuration to post a plan comment as a pull request review
+type Review struct {
+	Enabled bool
+	// Event is the review event. The default value is "COMMENT"
+	Event string
+	// EventWhenDestroy is the review event when the plan contains destroy. The default value is Event
+	EventWhenDestroy string
+}
+
+// CheckRun is a configuration to create a check run with the result.
+// The check run is created at PR.Revision, so it is skipped if the revision is unknown
+type CheckRun struct {
+	Enabled bool
+	// Name is the name of the check run. The default value is "<tag> <command>", followed by the target if it is set
+	Name string
+	// SkipComment creates only the check run without posting the comment.
+	// If the check run can't be created, the comment is posted as a fallback
+	SkipComment bool
+}
+
+// CommitStatus is a configuration to set a commit status with the result.
+// The commit status is set at PR.Revision, so it is skipped if the revision is unknown
+type CommitStatus struct {
+	Enabled bool
+	// Context is a template of the context of the commit status. .Command, .Target, and .Vars can be used.
+	// The default value is "<tag>/<command>", followed by "/<target>" if the target is set
+	Context string
+}
+
+// Deployment is a configuration to create a deployment on terraform apply.
+// The deployment is created at PR.Revision or PR.Branch
+type Deployment struct {
+	Enabled bool
+	// Environment is a template of the environment name. .Command, .Target, and .Vars can be used.
+	// The default value is the target
+	Environment string
+}
+
+// DriftIssue is a configuration to manage an issue per target which tracks the drift.
+// The issue is found by the metadata embedded in the body
+type DriftIssue struct {
+	Enabled bool
+	// Title is a template of the title of the issue. .Command, .Target, and .Vars can be used
+	Title string
+	// Labels are added to the issue when the issue is created. They are also used to find the issue
+	Labels []string
+}
+
+const (
+	ReviewEventComment        = "COMMENT"
+	ReviewEventRequestChanges = "REQUEST_CHANGES"
+)
+
+// OldComment is a configuration how to handle old comments of the same command and target
+type OldComment struct {
+	// Action is one of "keep", "minimize", and "delete". The default value is "keep"
+	Action string
+	// Classifier is a classifier to minimize comments. The default value is "OUTDATED"
+	Classifier string
+	// Match is a list of conditions which old comments must match.
+	// The default value is "same_command" and "same_target"
+	Match []string
+}
+
+const (
+	OldCommentActionKeep     = "keep"
+	OldCommentActionMinimize = "minimize"
+	OldCommentActionDelete   = "delete"
+)
+
+const (
+	OldCommentMatchSameCommand = "same_command"
+	OldCommentMatchSameTarget  = "same_target"
+	OldCommentMatchOlderSHA    = "older_sha"
+)
+
+const (
+	ClosedPRActionPost        = "post"
+	ClosedPRActionSkip        = "skip"
+	ClosedPRActionMergeCommit = "merge_commit"
+)
+
+const (
+	SummaryPositionTop    = "top"
+	SummaryPositionBottom = "bottom"
+)
+
+const (
+	PostTriggerPlanError   = "plan_error"
+	PostTriggerParseError  = "parse_error"
+	PostTriggerDestroy     = "destroy"
+	PostTriggerReplace     = "replace"
+	PostTriggerAddOrUpdate = "add_or_update"
+	PostTriggerNoChanges   = "no_changes"
+	PostTriggerImport      = "import"
+)
+
+// Gist is a configuration to upload the result to a Gist if the comment is too large
+type Gist struct {
+	Enabled bool
+	// Threshold is the maximum length of the comment. The default value is 60000
+	Threshold int
+	// Public creates a public Gist. By default, a secret Gist is created
+	Public bool
+	// PlanTemplate and ApplyTemplate are the templates of the compact comment. If they are empty, the default templates are used
+	PlanTemplate  string
+	ApplyTemplate string
+}
+
+// defaultGistThreshold is the default value of Gist.Threshold.
+// The maximum length of GitHub comments is 65536
+const defaultGistThreshold = 60000
+
+func (gist *Gist) threshold() int {
+	if gist.Threshold <= 0 {
+		return defaultGistThreshold
+	}
+	return gist.Threshold
 }
 
 // PullRequest represents GitHub Pull Request metadata
 type PullRequest struct {
 	Revision string
 	Number   int
+	// Branch is the head branch. This is used to find the pull request if neither Revision nor Number is set
+	Branch string
 }
 
 type service struct {
@@ -75,33 +302,41 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 	}
 	if token == "" {
 		token = os.Getenv("GITHUB_TOKEN")
-		if token == "" {
+		if token == "" && !cfg.DryRun && !cfg.App.IsEnabled() {
 			return &Client{}, errors.New("github token is missing")
 		}
 	}
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
 
-	baseURL := cfg.BaseURL
-	baseURL = strings.TrimPrefix(baseURL, "$")
-	if baseURL == EnvBaseURL {
-		baseURL = os.Getenv(EnvBaseURL)
+	if cfg.TargetPRNumber > 0 {
+		cfg.PR.Number = cfg.TargetPRNumber
 	}
+
+	baseURL := getBaseURL(cfg.BaseURL)
+	uploadURL := cfg.UploadURL
+	if baseURL != "" && uploadURL == "" {
+		uploadURL = getUploadURL(baseURL)
+	}
+
+	tc, err := newHTTPClient(ctx, &cfg, token, baseURL, uploadURL)
+	if err != nil {
+		return &Client{}, err
+	}
+	client := github.NewClient(tc)
+
+	v4Client := githubv4.NewClient(tc)
+
 	if baseURL != "" {
-		var err error
-		client, err = github.NewEnterpriseClient(baseURL, baseURL, tc)
+		client, err = github.NewEnterpriseClient(baseURL, uploadURL, tc)
 		if err != nil {
 			return &Client{}, errors.New("failed to create a new github api client")
 		}
+		v4Client = githubv4.NewEnterpriseClient(getGraphQLURL(client.BaseURL), tc)
 	}
 
 	c := &Client{
 		Config:   cfg,
 		Client:   client,
-		v4Client: githubv4.NewClient(tc),
+		v4Client: v4Client,
 	}
 	c.common.client = c
 	c.Comment = (*CommentService)(&c.common)
@@ -110,44 +345,127 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 	c.User = (*UserService)(&c.common)
 
 	c.API = &GitHub{
-		Client: client,
-		owner:  cfg.Owner,
-		repo:   cfg.Repo,
+		Client:   client,
+		v4Client: v4Client,
+		owner:    cfg.Owner,
+		repo:     cfg.Repo,
 	}
 
 	return c, nil
 }
 
-// IsNumber returns true if PullRequest is Pull Request build
-func (pr *PullRequest) IsNumber() bool {
-	return pr.Number != 0
+// NewNotifier returns a notifier.Notifier which posts the result to GitHub.
+// If Parser and templates aren't set, the ones for terraform plan are used.
+// This is the entrypoint to use tfcmt as a library.
+func NewNotifier(ctx context.Context, cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	_, isApply := cfg.Parser.(*terraform.ApplyParser)
+	if p, ok := cfg.Parser.(*terraform.JSONParser); ok {
+		isApply = p.Command == terraform.CommandApply
+	}
+	if _, isTest := cfg.Parser.(*terraform.TestParser); isTest {
+		if cfg.Template == nil {
+			cfg.Template = terraform.NewTestTemplate("")
+		}
+		if cfg.ParseErrorTemplate == nil {
+			cfg.ParseErrorTemplate = terraform.NewTestParseErrorTemplate("")
+		}
+	}
+	if _, isDestroy := cfg.Parser.(*terraform.DestroyParser); isDestroy {
+		if cfg.Template == nil {
+			cfg.Template = terraform.NewDestroyTemplate("")
+		}
+		if cfg.ParseErrorTemplate == nil {
+			cfg.ParseErrorTemplate = terraform.NewDestroyParseErrorTemplate("")
+		}
+	}
+	if _, isFmt := cfg.Parser.(*terraform.FmtParser); isFmt && cfg.Template == nil {
+		cfg.Template = terraform.NewFmtTemplate("")
+	}
+	if _, isValidate := cfg.Parser.(*terraform.ValidateParser); isValidate {
+		if cfg.Template == nil {
+			cfg.Template = terraform.NewValidateTemplate("")
+		}
+		if cfg.ParseErrorTemplate == nil {
+			cfg.ParseErrorTemplate = terraform.NewValidateParseErrorTemplate("")
+		}
+	}
+	// If AutoParser is used, templates are decided by the detected command
+	_, isAuto := cfg.Parser.(*terraform.AutoParser)
+	if cfg.Template == nil && !isAuto {
+		if isApply {
+			cfg.Template = terraform.NewApplyTemplate("")
+		} else {
+			cfg.Template = terraform.NewPlanTemplate("")
+		}
+	}
+	if cfg.ParseErrorTemplate == nil && !isAuto {
+		if isApply {
+			cfg.ParseErrorTemplate = terraform.NewApplyParseErrorTemplate("")
+		} else {
+			cfg.ParseErrorTemplate = terraform.NewPlanParseErrorTemplate("")
+		}
+	}
+	client, err := NewClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.Notify, nil
 }
 
-// ResultLabels represents the labels to add to the PR depending on the plan result
-type ResultLabels struct {
-	AddOrUpdateLabel      string
-	DestroyLabel          string
-	NoChangesLabel        string
-	PlanErrorLabel        string
-	AddOrUpdateLabelColor string
-	DestroyLabelColor     string
-	NoChangesLabelColor   string
-	PlanErrorLabelColor   string
+func getBaseURL(baseURL string) string {
+	baseURL = strings.TrimPrefix(baseURL, "$")
+	if baseURL == EnvBaseURL {
+		return os.Getenv(EnvBaseURL)
+	}
+	if baseURL != "" {
+		return baseURL
+	}
+	// GitHub Actions on GitHub Enterprise Server sets GITHUB_API_URL
+	if apiURL := strings.TrimSuffix(os.Getenv(EnvAPIURL), "/"); apiURL != defaultAPIURL {
+		return apiURL
+	}
+	return ""
 }
 
-// HasAnyLabelDefined returns true if any of the internal labels are set
-func (r *ResultLabels) HasAnyLabelDefined() bool {
-	return r.AddOrUpdateLabel != "" || r.DestroyLabel != "" || r.NoChangesLabel != "" || r.PlanErrorLabel != ""
+// getRepoURL returns the URL of the repository page like https://github.com/suzuki-shunsuke/tfcmt.
+// The URL of GitHub Enterprise Server is got by removing the path "/api/v3" from the API URL.
+func getRepoURL(apiURL *url.URL, owner, repo string) string {
+	if apiURL == nil || apiURL.Host == "api.github.com" {
+		return "https://github.com/" + owner + "/" + repo
+	}
+	u := *apiURL
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v3")
+	return strings.TrimSuffix(u.String(), "/") + "/" + owner + "/" + repo
 }
 
-// IsResultLabel returns true if a label matches any of the internal labels
-func (r *ResultLabels) IsResultLabel(label string) bool {
-	switch label {
-	case "":
-		return false
-	case r.AddOrUpdateLabel, r.DestroyLabel, r.NoChangesLabel, r.PlanErrorLabel:
-		return true
-	default:
-		return false
+// getUploadURL returns the upload URL of GitHub Enterprise Server.
+// go-github appends "api/uploads/" to the URL, so the path "/api/v3" is removed.
+func getUploadURL(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL
 	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v3")
+	return u.String()
+}
+
+func getGraphQLURL(baseURL *url.URL) string {
+	u := *baseURL
+	u.Path = "/api/graphql"
+	if u.Host == "api.github.com" {
+		u.Path = "/graphql"
+	}
+	return u.String()
+}
+
+// IsNumber returns true if PullRequest is Pull Request build
+func (pr *PullRequest) IsNumber() bool {
+	return pr.Number != 0
 }
+
+// ResultLabels represents the labels to add to the PR depending on the plan result.
+// This is an alias of notifier.ResultLabels so that all notifiers share the same semantics
+type ResultLabels = notifier.ResultLabels
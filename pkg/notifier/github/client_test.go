@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"net/url"
 	"os"
 	"testing"
 )
@@ -75,10 +76,13 @@ func TestNewClient(t *testing.T) { //nolint:paralleltest
 
 func TestNewClientWithBaseURL(t *testing.T) { //nolint:paralleltest
 	githubBaseURL := os.Getenv(EnvBaseURL)
+	githubAPIURL := os.Getenv(EnvAPIURL)
 	defer func() {
 		os.Setenv(EnvBaseURL, githubBaseURL)
+		os.Setenv(EnvAPIURL, githubAPIURL)
 	}()
 	os.Setenv(EnvBaseURL, "")
+	os.Setenv(EnvAPIURL, "")
 
 	testCases := []struct {
 		config     Config
@@ -156,6 +160,124 @@ func TestNewClientWithBaseURL(t *testing.T) { //nolint:paralleltest
 	}
 }
 
+func TestNewClientWithAPIURL(t *testing.T) { //nolint:paralleltest
+	githubAPIURL := os.Getenv(EnvAPIURL)
+	defer func() {
+		os.Setenv(EnvAPIURL, githubAPIURL)
+	}()
+	os.Setenv(EnvAPIURL, "")
+
+	testCases := []struct {
+		config    Config
+		envAPIURL string
+		expect    string
+		upload    string
+	}{
+		{
+			// GitHub Enterprise Server on GitHub Actions
+			config:    Config{Token: "abcdefg"},
+			envAPIURL: "https://git.example.com/api/v3",
+			expect:    "https://git.example.com/api/v3/",
+			upload:    "https://git.example.com/api/uploads/",
+		},
+		{
+			// github.com on GitHub Actions
+			config:    Config{Token: "abcdefg"},
+			envAPIURL: "https://api.github.com",
+			expect:    "https://api.github.com/",
+			upload:    "https://uploads.github.com/",
+		},
+		{
+			// the configuration takes precedence over the environment variable
+			config: Config{
+				Token:   "abcdefg",
+				BaseURL: "https://ghe.example.com/api/v3/",
+			},
+			envAPIURL: "https://git.example.com/api/v3",
+			expect:    "https://ghe.example.com/api/v3/",
+			upload:    "https://ghe.example.com/api/uploads/",
+		},
+		{
+			// specify the upload url
+			config: Config{
+				Token:     "abcdefg",
+				BaseURL:   "https://ghe.example.com/api/v3/",
+				UploadURL: "https://upload.example.com/api/uploads/",
+			},
+			envAPIURL: "",
+			expect:    "https://ghe.example.com/api/v3/",
+			upload:    "https://upload.example.com/api/uploads/",
+		},
+	}
+
+	for _, testCase := range testCases {
+		os.Setenv(EnvAPIURL, testCase.envAPIURL)
+		c, err := NewClient(context.Background(), testCase.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url := c.Client.BaseURL.String(); url != testCase.expect {
+			t.Errorf("got %q but want %q", url, testCase.expect)
+		}
+		if url := c.Client.UploadURL.String(); url != testCase.upload {
+			t.Errorf("got %q but want %q", url, testCase.upload)
+		}
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	t.Parallel()
+	ntf, err := NewNotifier(context.Background(), Config{
+		Token: "abcdefg",
+		Owner: "owner",
+		Repo:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, ok := ntf.(*NotifyService)
+	if !ok {
+		t.Fatalf("got %T but want *NotifyService", ntf)
+	}
+	cfg := svc.client.Config
+	if cfg.Parser == nil || cfg.Template == nil || cfg.ParseErrorTemplate == nil {
+		t.Error("the parser and templates should be set by default")
+	}
+}
+
+func TestGetRepoURL(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		apiURL string
+		exp    string
+	}{
+		{
+			name:   "github.com",
+			apiURL: "https://api.github.com/",
+			exp:    "https://github.com/owner/repo",
+		},
+		{
+			name:   "GitHub Enterprise Server",
+			apiURL: "https://ghe.example.com/api/v3/",
+			exp:    "https://ghe.example.com/owner/repo",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			u, err := url.Parse(testCase.apiURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a := getRepoURL(u, "owner", "repo"); a != testCase.exp {
+				t.Errorf("got %q, wanted %q", a, testCase.exp)
+			}
+		})
+	}
+}
+
 func TestIsNumber(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -279,6 +401,40 @@ func TestIsResultLabels(t *testing.T) {
 			label: "",
 			want:  false,
 		},
+		{
+			rl: ResultLabels{
+				DestroyLabel: "destroy",
+				ReplaceLabel: "replace",
+			},
+			label: "replace",
+			want:  true,
+		},
+		{
+			rl: ResultLabels{
+				PlanErrorCategoryLabels: map[string]string{
+					"state_lock": "state-lock",
+				},
+				Prefix: "tfcmt/",
+			},
+			label: "tfcmt/state-lock",
+			want:  true,
+		},
+		{
+			rl: ResultLabels{
+				DestroyLabel: "destroy",
+				Prefix:       "tfcmt/",
+			},
+			label: "tfcmt/destroy",
+			want:  true,
+		},
+		{
+			rl: ResultLabels{
+				DestroyLabel: "destroy",
+				Prefix:       "tfcmt/",
+			},
+			label: "destroy",
+			want:  false,
+		},
 	}
 	for _, testCase := range testCases {
 		if testCase.rl.IsResultLabel(testCase.label) != testCase.want {
@@ -0,0 +1,41 @@
+package github
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// handleClosedPR checks if the pull request has been closed while terraform plan was running.
+// If the pull request has been closed, the comment is skipped or redirected to the merge commit according to the configuration.
+// handleClosedPR returns true if posting the comment should be skipped.
+// If the pull request can't be gotten, the comment is posted as usual
+func (g *NotifyService) handleClosedPR(ctx context.Context, cfg *Config) bool {
+	logE := logrus.WithFields(logrus.Fields{
+		"program":   "tfcmt",
+		"pr_number": cfg.PR.Number,
+	})
+	pr, _, err := g.client.API.PullRequestsGet(ctx, cfg.PR.Number)
+	if err != nil {
+		logE.WithError(err).Warn("get the pull request to check if it has been closed")
+		return false
+	}
+	if pr.GetState() != "closed" {
+		return false
+	}
+	switch cfg.ClosedPRAction {
+	case ClosedPRActionSkip:
+		logE.Info("skip posting a comment because the pull request has been closed")
+		return true
+	case ClosedPRActionMergeCommit:
+		if !pr.GetMerged() || pr.GetMergeCommitSHA() == "" {
+			logE.Info("skip posting a comment because the pull request has been closed without being merged")
+			return true
+		}
+		logE.WithField("sha", pr.GetMergeCommitSHA()).Info("post a comment to the merge commit because the pull request has been merged")
+		// Labels and comments of the pull request aren't touched
+		cfg.PR.Number = 0
+		cfg.PR.Revision = pr.GetMergeCommitSHA()
+	}
+	return false
+}
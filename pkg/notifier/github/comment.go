@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
 )
 
 // CommentService handles communication with the comment related
@@ -18,24 +19,102 @@ type PostOptions struct {
 }
 
 // Post posts comment
-func (g *CommentService) Post(ctx context.Context, body string, opt PostOptions) error {
+func (g *CommentService) Post(ctx context.Context, body string, opt PostOptions) (*notifier.PostedComment, error) {
 	if opt.Number != 0 {
-		_, _, err := g.client.API.IssuesCreateComment(
+		comment, _, err := g.client.API.IssuesCreateComment(
 			ctx,
 			opt.Number,
 			&github.IssueComment{Body: &body},
 		)
-		return err
+		if err != nil {
+			return nil, err
+		}
+		return &notifier.PostedComment{ID: comment.GetID(), URL: comment.GetHTMLURL()}, nil
 	}
 	if opt.Revision != "" {
-		_, _, err := g.client.API.RepositoriesCreateComment(
+		comment, _, err := g.client.API.RepositoriesCreateComment(
 			ctx,
 			opt.Revision,
 			&github.RepositoryComment{Body: &body},
 		)
-		return err
+		if err != nil {
+			return nil, err
+		}
+		return &notifier.PostedComment{ID: comment.GetID(), URL: comment.GetHTMLURL()}, nil
+	}
+	return nil, errors.New("github.comment.post: Number or Revision is required")
+}
+
+// PostReview posts a comment as a pull request review
+func (g *CommentService) PostReview(ctx context.Context, body string, number int, event string) (*notifier.PostedComment, error) {
+	if number == 0 {
+		return nil, errors.New("github.comment.post_review: Number is required")
+	}
+	if event == "" {
+		event = ReviewEventComment
+	}
+	review, _, err := g.client.API.PullRequestsCreateReview(ctx, number, &github.PullRequestReviewRequest{
+		Body:  &body,
+		Event: &event,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &notifier.PostedComment{ID: review.GetID(), URL: review.GetHTMLURL()}, nil
+}
+
+// ListReviews lists reviews of a pull request
+func (g *CommentService) ListReviews(ctx context.Context, number int) ([]*github.PullRequestReview, error) {
+	if number == 0 {
+		return nil, errors.New("github.comment.list_reviews: Number is required")
+	}
+	opt := &github.ListOptions{
+		PerPage: 100, //nolint:gomnd
+	}
+	var reviews []*github.PullRequestReview
+	for {
+		rvs, resp, err := g.client.API.PullRequestsListReviews(ctx, number, opt)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rvs...)
+		if resp == nil || resp.NextPage == 0 {
+			return reviews, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// DismissReview dismisses a pull request review
+func (g *CommentService) DismissReview(ctx context.Context, number int, reviewID int64, message string) error {
+	_, _, err := g.client.API.PullRequestsDismissReview(ctx, number, reviewID, &github.PullRequestReviewDismissalRequest{
+		Message: &message,
+	})
+	return err
+}
+
+// List lists comments of a pull request
+func (g *CommentService) List(ctx context.Context, number int) ([]*github.IssueComment, error) {
+	if number == 0 {
+		return nil, errors.New("github.comment.list: Number is required")
+	}
+	opt := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100, //nolint:gomnd
+		},
+	}
+	var comments []*github.IssueComment
+	for {
+		cmts, resp, err := g.client.API.IssuesListComments(ctx, number, opt)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, cmts...)
+		if resp == nil || resp.NextPage == 0 {
+			return comments, nil
+		}
+		opt.Page = resp.NextPage
 	}
-	return errors.New("github.comment.post: Number or Revision is required")
 }
 
 type ListOptions struct {
@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"testing"
+
+	"github.com/google/go-github/v39/github"
 )
 
 func TestCommentPost(t *testing.T) {
@@ -58,9 +60,63 @@ func TestCommentPost(t *testing.T) {
 		}
 		api := newFakeAPI()
 		client.API = &api
-		err = client.Comment.Post(context.Background(), testCase.body, testCase.opt)
+		_, err = client.Comment.Post(context.Background(), testCase.body, testCase.opt)
 		if (err == nil) != testCase.ok {
 			t.Errorf("got error %q", err)
 		}
 	}
 }
+
+func TestCommentPostReview(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		number int
+		event  string
+		exp    string
+		ok     bool
+	}{
+		{
+			name:   "default event",
+			number: 1,
+			exp:    "COMMENT",
+			ok:     true,
+		},
+		{
+			name:   "request changes",
+			number: 1,
+			event:  "REQUEST_CHANGES",
+			exp:    "REQUEST_CHANGES",
+			ok:     true,
+		},
+		{
+			name:   "no number",
+			number: 0,
+			ok:     false,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			client, err := NewClient(context.Background(), newFakeConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			api := newFakeAPI()
+			var event string
+			api.FakePullRequestsCreateReview = func(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+				event = review.GetEvent()
+				return &github.PullRequestReview{}, nil, nil
+			}
+			client.API = &api
+			_, err = client.Comment.PostReview(context.Background(), "body", testCase.number, testCase.event)
+			if (err == nil) != testCase.ok {
+				t.Errorf("got error %q", err)
+			}
+			if event != testCase.exp {
+				t.Errorf("got %q but want %q", event, testCase.exp)
+			}
+		})
+	}
+}
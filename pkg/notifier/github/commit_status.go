@@ -0,0 +1,103 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	CommitStatusStateSuccess = "success"
+	CommitStatusStateFailure = "failure"
+	CommitStatusStateError   = "error"
+)
+
+// maxCommitStatusDescriptionLength is the maximum length of the description of a commit status
+const maxCommitStatusDescriptionLength = 140
+
+// commitStatusState returns the state of the commit status.
+// If terraform fails the state is "failure", and if tfcmt fails to parse the output the state is "error"
+func commitStatusState(result terraform.ParseResult) string {
+	switch {
+	case result.HasParseError:
+		return CommitStatusStateError
+	case result.HasApplyError, !result.Succeeded():
+		return CommitStatusStateFailure
+	default:
+		return CommitStatusStateSuccess
+	}
+}
+
+// commitStatusContext renders the context of the commit status
+func (cfg *Config) commitStatusContext(command string) (string, error) {
+	if cfg.CommitStatus.Context == "" {
+		if target := cfg.Vars["target"]; target != "" {
+			return cfg.program() + "/" + command + "/" + target, nil
+		}
+		return cfg.program() + "/" + command, nil
+	}
+	s, err := cfg.renderName(cfg.CommitStatus.Context, command)
+	if err != nil {
+		return "", fmt.Errorf("render the commit status context: %w", err)
+	}
+	return s, nil
+}
+
+// renderName renders a template of names such as the context of the commit status.
+// .Command, .Target, and .Vars can be used in the template
+func (cfg *Config) renderName(text, command string) (string, error) {
+	tpl, err := template.New("name").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse the template: %w", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, map[string]interface{}{
+		"Command": command,
+		"Target":  cfg.Vars["target"],
+		"Vars":    cfg.Vars,
+	}); err != nil {
+		return "", fmt.Errorf("execute the template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// createCommitStatus sets the commit status of the result at PR.Revision
+func (g *NotifyService) createCommitStatus(ctx context.Context, cfg *Config, command string, result terraform.ParseResult) error {
+	statusContext, err := cfg.commitStatusContext(command)
+	if err != nil {
+		return err
+	}
+	description := checkRunTitle(result, command)
+	if len(description) > maxCommitStatusDescriptionLength {
+		description = description[:maxCommitStatusDescriptionLength-3] + "..."
+	}
+	status := &github.RepoStatus{
+		State:       github.String(commitStatusState(result)),
+		Context:     github.String(statusContext),
+		Description: github.String(description),
+	}
+	if cfg.CI != "" {
+		status.TargetURL = github.String(cfg.CI)
+	}
+	_, _, err = g.client.API.RepositoriesCreateStatus(ctx, cfg.PR.Revision, status)
+	return err
+}
+
+// postCommitStatus sets the commit status. Errors are only logged so that the comment is posted anyway
+func (g *NotifyService) postCommitStatus(ctx context.Context, cfg *Config, command string, result terraform.ParseResult) {
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+	if cfg.PR.Revision == "" {
+		logE.Warn("skip setting a commit status because the revision is unknown")
+		return
+	}
+	if err := g.createCommitStatus(ctx, cfg, command, result); err != nil {
+		logE.WithError(err).Error("set a commit status")
+	}
+}
@@ -0,0 +1,131 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestNotifyCommitStatus(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		output    string
+		exitCode  int
+		parser    terraform.Parser
+		context   string
+		vars      map[string]string
+		revision  string
+		expRef    string
+		expStatus *github.RepoStatus
+	}{
+		{
+			name:     "changes",
+			output:   "Plan: 1 to add, 0 to change, 0 to destroy.",
+			exitCode: 2,
+			vars:     map[string]string{"target": "foo"},
+			revision: "abcd",
+			expRef:   "abcd",
+			expStatus: &github.RepoStatus{
+				State:       github.String(CommitStatusStateSuccess),
+				Context:     github.String("tfcmt/plan/foo"),
+				Description: github.String("Plan: 1 to add, 0 to change, 0 to destroy."),
+				TargetURL:   github.String("https://ci.example.com/builds/1"),
+			},
+		},
+		{
+			name:     "plan error",
+			output:   "Error: Invalid reference",
+			exitCode: 1,
+			revision: "abcd",
+			expRef:   "abcd",
+			expStatus: &github.RepoStatus{
+				State:       github.String(CommitStatusStateFailure),
+				Context:     github.String("tfcmt/plan"),
+				Description: github.String("Plan failed"),
+				TargetURL:   github.String("https://ci.example.com/builds/1"),
+			},
+		},
+		{
+			name:     "parse error with the custom context",
+			output:   "foo",
+			exitCode: 1,
+			context:  "terraform/{{.Command}}/{{.Vars.env}}",
+			vars:     map[string]string{"env": "prod"},
+			revision: "abcd",
+			expRef:   "abcd",
+			expStatus: &github.RepoStatus{
+				State:       github.String(CommitStatusStateError),
+				Context:     github.String("terraform/plan/prod"),
+				Description: github.String("Failed to parse the output of terraform plan"),
+				TargetURL:   github.String("https://ci.example.com/builds/1"),
+			},
+		},
+		{
+			name:     "apply",
+			output:   "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			parser:   terraform.NewApplyParser(),
+			revision: "abcd",
+			expRef:   "abcd",
+			expStatus: &github.RepoStatus{
+				State:       github.String(CommitStatusStateSuccess),
+				Context:     github.String("tfcmt/apply"),
+				Description: github.String("Apply complete! Resources: 1 added, 0 changed, 0 destroyed."),
+				TargetURL:   github.String("https://ci.example.com/builds/1"),
+			},
+		},
+		{
+			name:   "the revision is unknown",
+			output: "No changes. Infrastructure is up-to-date.",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.CI = "https://ci.example.com/builds/1"
+			cfg.CommitStatus = CommitStatus{Enabled: true, Context: testCase.context}
+			cfg.PR.Revision = testCase.revision
+			cfg.Vars = testCase.vars
+			if testCase.parser != nil {
+				cfg.Parser = testCase.parser
+				cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				ref    string
+				status *github.RepoStatus
+			)
+			api := newFakeAPI()
+			api.FakeRepositoriesCreateStatus = func(ctx context.Context, r string, s *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+				ref = r
+				status = s
+				return s, nil, nil
+			}
+			api.FakePullRequestsList = func(ctx context.Context, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+				return nil, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if ref != testCase.expRef {
+				t.Errorf("ref: got %q, wanted %q", ref, testCase.expRef)
+			}
+			if diff := cmp.Diff(testCase.expStatus, status); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -67,3 +68,66 @@ func (g *CommitsService) MergedPRNumber(ctx context.Context, revision string) (i
 
 	return 0, errors.New("not a merge commit")
 }
+
+// PRNumberByBranch returns the number of the pull request whose head branch is the given branch.
+// An open pull request is preferred. If there is no open pull request, the most recently merged pull request is returned.
+// Pull requests from the repository are searched first, and then open pull requests from forks are searched
+func (g *CommitsService) PRNumberByBranch(ctx context.Context, branch string) (int, error) {
+	if branch == "" {
+		return 0, errors.New("no branch specified")
+	}
+	number, err := g.findPRByHead(ctx, &github.PullRequestListOptions{
+		State:     "all",
+		Head:      g.client.Config.Owner + ":" + branch,
+		Sort:      "updated",
+		Direction: "desc",
+	}, branch)
+	if err != nil {
+		return 0, fmt.Errorf("list pull requests of the branch %s: %w", branch, err)
+	}
+	if number != 0 {
+		return number, nil
+	}
+	// The owner of a fork is unknown, so open pull requests are listed and their head branches are compared.
+	// Closed pull requests aren't listed because all pull requests of the repository would be listed
+	number, err = g.findPRByHead(ctx, &github.PullRequestListOptions{
+		State:     "open",
+		Sort:      "updated",
+		Direction: "desc",
+	}, branch)
+	if err != nil {
+		return 0, fmt.Errorf("list open pull requests to find the branch %s of a fork: %w", branch, err)
+	}
+	if number != 0 {
+		return number, nil
+	}
+	return 0, errors.New("no pull request is associated with the branch " + branch)
+}
+
+// findPRByHead lists pull requests page by page and returns the number of the pull request whose head branch is the given branch.
+// An open pull request is preferred to a merged pull request. If no pull request is found, 0 is returned
+func (g *CommitsService) findPRByHead(ctx context.Context, opt *github.PullRequestListOptions, branch string) (int, error) {
+	opt.PerPage = 100 //nolint:gomnd
+	merged := 0
+	for {
+		prs, resp, err := g.client.API.PullRequestsList(ctx, opt)
+		if err != nil {
+			return 0, err
+		}
+		for _, pr := range prs {
+			if pr.GetHead().GetRef() != branch {
+				continue
+			}
+			if pr.GetState() == "open" {
+				return pr.GetNumber(), nil
+			}
+			if merged == 0 && pr.MergedAt != nil {
+				merged = pr.GetNumber()
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return merged, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
@@ -3,6 +3,9 @@ package github
 import (
 	"context"
 	"testing"
+	"time"
+
+	"github.com/google/go-github/v39/github"
 )
 
 func TestCommitsList(t *testing.T) {
@@ -139,3 +142,144 @@ func TestMergedPRNumber(t *testing.T) {
 		}
 	}
 }
+
+func TestCommitsPRNumberByBranch(t *testing.T) { //nolint:funlen
+	t.Parallel()
+	mergedAt := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	newPR := func(number int, owner, ref, state string, merged bool) *github.PullRequest {
+		pr := &github.PullRequest{
+			Number: github.Int(number),
+			State:  github.String(state),
+			Head: &github.PullRequestBranch{
+				Ref: github.String(ref),
+				Repo: &github.Repository{
+					Owner: &github.User{Login: github.String(owner)},
+				},
+			},
+		}
+		if merged {
+			pr.MergedAt = &mergedAt
+		}
+		return pr
+	}
+	testCases := []struct {
+		name   string
+		branch string
+		// pages of pull requests listed with the head filter
+		repo [][]*github.PullRequest
+		// pages of open pull requests listed without the head filter
+		open   [][]*github.PullRequest
+		number int
+		ok     bool
+	}{
+		{
+			name:   "open pull request",
+			branch: "feature",
+			repo: [][]*github.PullRequest{
+				{newPR(4, "owner", "feature", "closed", true), newPR(5, "owner", "feature", "open", false)},
+			},
+			number: 5,
+			ok:     true,
+		},
+		{
+			name:   "merged pull request",
+			branch: "feature",
+			repo: [][]*github.PullRequest{
+				{newPR(4, "owner", "feature", "closed", false), newPR(3, "owner", "feature", "closed", true)},
+			},
+			number: 3,
+			ok:     true,
+		},
+		{
+			name:   "merged pull request in the second page",
+			branch: "feature",
+			repo: [][]*github.PullRequest{
+				{newPR(4, "owner", "feature", "closed", false)},
+				{newPR(3, "owner", "feature", "closed", true)},
+			},
+			number: 3,
+			ok:     true,
+		},
+		{
+			name:   "open pull request in the second page takes precedence over a merged pull request",
+			branch: "feature",
+			repo: [][]*github.PullRequest{
+				{newPR(3, "owner", "feature", "closed", true)},
+				{newPR(5, "owner", "feature", "open", false)},
+			},
+			number: 5,
+			ok:     true,
+		},
+		{
+			name:   "pull request from a fork",
+			branch: "feature",
+			open: [][]*github.PullRequest{
+				{newPR(6, "owner", "main", "open", false)},
+				{newPR(7, "forker", "feature", "open", false)},
+			},
+			number: 7,
+			ok:     true,
+		},
+		{
+			name:   "no pull request",
+			branch: "feature",
+			repo: [][]*github.PullRequest{
+				{newPR(4, "owner", "feature", "closed", false)},
+			},
+			open: [][]*github.PullRequest{
+				{newPR(6, "owner", "main", "open", false)},
+			},
+			ok: false,
+		},
+		{
+			name: "no branch",
+			ok:   false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			api := newFakeAPI()
+			api.FakePullRequestsList = func(ctx context.Context, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+				pages := testCase.open
+				switch opt.Head {
+				case "":
+					if opt.State != "open" {
+						t.Errorf("state should be open but got %s", opt.State)
+					}
+				case "owner:" + testCase.branch:
+					pages = testCase.repo
+				default:
+					t.Errorf("head should be owner:%s but got %s", testCase.branch, opt.Head)
+				}
+				page := opt.Page
+				if page == 0 {
+					page = 1
+				}
+				if page > len(pages) {
+					return nil, &github.Response{}, nil
+				}
+				resp := &github.Response{}
+				if page < len(pages) {
+					resp.NextPage = page + 1
+				}
+				return pages[page-1], resp, nil
+			}
+			client.API = &api
+			number, err := client.Commits.PRNumberByBranch(context.Background(), testCase.branch)
+			if (err == nil) != testCase.ok {
+				t.Errorf("got error %v", err)
+			}
+			if number != testCase.number {
+				t.Errorf("got %d but want %d", number, testCase.number)
+			}
+		})
+	}
+}
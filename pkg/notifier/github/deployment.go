@@ -0,0 +1,139 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	DeploymentStateInProgress = "in_progress"
+	DeploymentStateSuccess    = "success"
+	DeploymentStateFailure    = "failure"
+	DeploymentStateError      = "error"
+)
+
+// deploymentState returns the state of the deployment after terraform apply.
+// If terraform fails the state is "failure", and if tfcmt fails to parse the output the state is "error"
+func deploymentState(result terraform.ParseResult) string {
+	switch {
+	case result.HasParseError:
+		return DeploymentStateError
+	case result.HasApplyError, !result.Succeeded():
+		return DeploymentStateFailure
+	default:
+		return DeploymentStateSuccess
+	}
+}
+
+// isApplyParser returns true if the parser is for terraform apply.
+// AutoParser returns false because the command can't be detected before the command is run
+func isApplyParser(parser terraform.Parser) bool {
+	if p, ok := parser.(*terraform.JSONParser); ok {
+		return p.Command == terraform.CommandApply
+	}
+	_, ok := parser.(*terraform.ApplyParser)
+	return ok
+}
+
+// deploymentEnvironment returns the environment name of the deployment. The default value is the target
+func (cfg *Config) deploymentEnvironment() (string, error) {
+	if cfg.Deployment.Environment == "" {
+		return cfg.Vars["target"], nil
+	}
+	env, err := cfg.renderName(cfg.Deployment.Environment, terraform.CommandApply)
+	if err != nil {
+		return "", fmt.Errorf("render the environment of the deployment: %w", err)
+	}
+	return env, nil
+}
+
+// Start creates a deployment and sets its status "in_progress" before terraform apply is run.
+// The status is updated by Notify after terraform apply
+func (g *NotifyService) Start(ctx context.Context) error {
+	cfg := &g.client.Config
+	if cfg.DryRun || !cfg.Deployment.Enabled || !isApplyParser(cfg.Parser) {
+		return nil
+	}
+	id, err := g.createDeployment(ctx, cfg)
+	if err != nil || id == 0 {
+		return err
+	}
+	g.client.deploymentID = id
+	return g.createDeploymentStatus(ctx, cfg, id, DeploymentStateInProgress, "terraform apply is running")
+}
+
+// createDeployment creates a deployment of PR.Revision or PR.Branch and returns the id.
+// If the environment or the ref is unknown, the deployment isn't created and 0 is returned
+func (g *NotifyService) createDeployment(ctx context.Context, cfg *Config) (int64, error) {
+	env, err := cfg.deploymentEnvironment()
+	if err != nil {
+		return 0, err
+	}
+	ref := cfg.PR.Revision
+	if ref == "" {
+		ref = cfg.PR.Branch
+	}
+	if env == "" || ref == "" {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).Warn("skip creating a deployment because the environment or the ref is unknown")
+		return 0, nil
+	}
+	deployment, _, err := g.client.API.RepositoriesCreateDeployment(ctx, &github.DeploymentRequest{
+		Ref:         github.String(ref),
+		Environment: github.String(env),
+		Description: github.String("terraform apply"),
+		AutoMerge:   github.Bool(false),
+		// The deployment is created after CI starts, so commit statuses aren't required
+		RequiredContexts: &[]string{},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("create a deployment: %w", err)
+	}
+	return deployment.GetID(), nil
+}
+
+func (g *NotifyService) createDeploymentStatus(ctx context.Context, cfg *Config, id int64, state, description string) error {
+	if len(description) > maxCommitStatusDescriptionLength {
+		description = description[:maxCommitStatusDescriptionLength-3] + "..."
+	}
+	req := &github.DeploymentStatusRequest{
+		State:       github.String(state),
+		Description: github.String(description),
+	}
+	if cfg.CI != "" {
+		req.LogURL = github.String(cfg.CI)
+	}
+	if _, _, err := g.client.API.RepositoriesCreateDeploymentStatus(ctx, id, req); err != nil {
+		return fmt.Errorf("create a deployment status (deployment id: %d, state: %s): %w", id, state, err)
+	}
+	return nil
+}
+
+// finishDeployment sets the status of the deployment by the result of terraform apply.
+// If the deployment hasn't been created by Start, for example the output is read from a file, the deployment is created here.
+// Errors are only logged so that the comment is posted anyway
+func (g *NotifyService) finishDeployment(ctx context.Context, cfg *Config, result terraform.ParseResult) {
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+	id := g.client.deploymentID
+	if id == 0 {
+		i, err := g.createDeployment(ctx, cfg)
+		if err != nil {
+			logE.WithError(err).Error("create a deployment")
+			return
+		}
+		if i == 0 {
+			return
+		}
+		id = i
+	}
+	if err := g.createDeploymentStatus(ctx, cfg, id, deploymentState(result), checkRunTitle(result, terraform.CommandApply)); err != nil {
+		logE.WithError(err).Error("update the deployment status")
+	}
+}
@@ -0,0 +1,145 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestNotifyDeployment(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		output         string
+		exitCode       int
+		start          bool
+		environment    string
+		vars           map[string]string
+		expEnvironment string
+		expStates      []string
+	}{
+		{
+			name:           "success",
+			output:         "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			start:          true,
+			vars:           map[string]string{"target": "foo"},
+			expEnvironment: "foo",
+			expStates:      []string{DeploymentStateInProgress, DeploymentStateSuccess},
+		},
+		{
+			name: "failure",
+			output: `Error: Error creating S3 bucket
+
+  with aws_s3_bucket.foo,
+  on main.tf line 1, in resource "aws_s3_bucket" "foo":
+   1: resource "aws_s3_bucket" "foo" {`,
+			exitCode:       1,
+			start:          true,
+			environment:    "{{.Vars.env}}-{{.Target}}",
+			vars:           map[string]string{"target": "foo", "env": "prod"},
+			expEnvironment: "prod-foo",
+			expStates:      []string{DeploymentStateInProgress, DeploymentStateFailure},
+		},
+		{
+			name:           "the deployment is created after apply if Start isn't called",
+			output:         "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			vars:           map[string]string{"target": "foo"},
+			expEnvironment: "foo",
+			expStates:      []string{DeploymentStateSuccess},
+		},
+		{
+			name:   "the environment is unknown",
+			output: "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			start:  true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.CI = "https://ci.example.com/builds/1"
+			cfg.Parser = terraform.NewApplyParser()
+			cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+			cfg.Deployment = Deployment{Enabled: true, Environment: testCase.environment}
+			cfg.Vars = testCase.vars
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				deployments []*github.DeploymentRequest
+				states      []string
+			)
+			api := newFakeAPI()
+			api.FakeRepositoriesCreateDeployment = func(ctx context.Context, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error) {
+				deployments = append(deployments, request)
+				return &github.Deployment{ID: github.Int64(42)}, nil, nil
+			}
+			api.FakeRepositoriesCreateDeploymentStatus = func(ctx context.Context, deploymentID int64, request *github.DeploymentStatusRequest) (*github.DeploymentStatus, *github.Response, error) {
+				if deploymentID != 42 {
+					t.Errorf("unexpected deployment id: %d", deploymentID)
+				}
+				if request.GetLogURL() != cfg.CI {
+					t.Errorf("log url: got %q, wanted %q", request.GetLogURL(), cfg.CI)
+				}
+				states = append(states, request.GetState())
+				return &github.DeploymentStatus{}, nil, nil
+			}
+			client.API = &api
+			if testCase.start {
+				if err := client.Notify.Start(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.expStates, states); diff != "" {
+				t.Error(diff)
+			}
+			if testCase.expEnvironment == "" {
+				if len(deployments) != 0 {
+					t.Fatalf("a deployment shouldn't be created: %v", deployments)
+				}
+				return
+			}
+			if len(deployments) != 1 {
+				t.Fatalf("a deployment should be created once: %v", deployments)
+			}
+			if env := deployments[0].GetEnvironment(); env != testCase.expEnvironment {
+				t.Errorf("environment: got %q, wanted %q", env, testCase.expEnvironment)
+			}
+			if ref := deployments[0].GetRef(); ref != "abcd" {
+				t.Errorf("ref: got %q, wanted %q", ref, "abcd")
+			}
+		})
+	}
+}
+
+func TestNotifyServiceStartPlan(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.Deployment = Deployment{Enabled: true}
+	cfg.Vars = map[string]string{"target": "foo"}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newFakeAPI()
+	api.FakeRepositoriesCreateDeployment = func(ctx context.Context, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error) {
+		t.Error("a deployment shouldn't be created on terraform plan")
+		return &github.Deployment{}, nil, nil
+	}
+	client.API = &api
+	if err := client.Notify.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
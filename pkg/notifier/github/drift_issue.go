@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// driftIssueTitle returns the title of the drift issue. The default value is "Terraform drift is detected", followed by the target if it is set
+func (cfg *Config) driftIssueTitle() (string, error) {
+	if cfg.DriftIssue.Title == "" {
+		title := "Terraform drift is detected"
+		if target := cfg.Vars["target"]; target != "" {
+			title += " (" + target + ")"
+		}
+		return title, nil
+	}
+	title, err := cfg.renderName(cfg.DriftIssue.Title, terraform.CommandPlan)
+	if err != nil {
+		return "", fmt.Errorf("render the title of the drift issue: %w", err)
+	}
+	return title, nil
+}
+
+// manageDriftIssue creates or updates the issue of the target if the plan has changes, and closes it if the plan has no change.
+// The issue is found by the metadata embedded in the body, so the body must include the metadata.
+// If the plan fails, the issue isn't changed because it is unknown whether the drift exists
+func (g *NotifyService) manageDriftIssue(ctx context.Context, cfg *Config, body string, result terraform.ParseResult) error {
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+	if result.HasParseError || !result.Succeeded() {
+		logE.Warn("skip updating the drift issue because the plan failed")
+		return nil
+	}
+	issue, err := g.findDriftIssue(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("find the drift issue: %w", err)
+	}
+	if !result.HasChanges() {
+		if issue == nil {
+			return nil
+		}
+		return g.closeDriftIssue(ctx, cfg, issue.GetNumber())
+	}
+	title, err := cfg.driftIssueTitle()
+	if err != nil {
+		return err
+	}
+	req := &github.IssueRequest{
+		Title: github.String(title),
+		Body:  github.String(body),
+	}
+	if issue != nil {
+		if _, _, err := g.client.API.IssuesEdit(ctx, issue.GetNumber(), req); err != nil {
+			return fmt.Errorf("update the drift issue (number: %d): %w", issue.GetNumber(), err)
+		}
+		logE.WithField("issue_number", issue.GetNumber()).Info("update the drift issue")
+		return nil
+	}
+	if len(cfg.DriftIssue.Labels) != 0 {
+		labels := cfg.DriftIssue.Labels
+		req.Labels = &labels
+	}
+	created, _, err := g.client.API.IssuesCreate(ctx, req)
+	if err != nil {
+		return fmt.Errorf("create a drift issue: %w", err)
+	}
+	logE.WithField("issue_number", created.GetNumber()).Info("create a drift issue")
+	return nil
+}
+
+// findDriftIssue returns the open issue whose embedded metadata matches the program and the target.
+// If the issue isn't found, nil is returned
+func (g *NotifyService) findDriftIssue(ctx context.Context, cfg *Config) (*github.Issue, error) {
+	opt := &github.IssueListByRepoOptions{
+		State:  "open",
+		Labels: cfg.DriftIssue.Labels,
+		ListOptions: github.ListOptions{
+			PerPage: 100, //nolint:gomnd
+		},
+	}
+	for {
+		issues, resp, err := g.client.API.IssuesListByRepo(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			// The API returns pull requests as well
+			if issue.IsPullRequest() {
+				continue
+			}
+			if _, ok := matchMetadata(issue.GetBody(), cfg.program(), terraform.CommandPlan, cfg.Vars["target"]); ok {
+				return issue, nil
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return nil, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// closeDriftIssue posts a comment that the drift is resolved and closes the issue
+func (g *NotifyService) closeDriftIssue(ctx context.Context, cfg *Config, number int) error {
+	msg := "The drift is resolved. `terraform plan` has no changes."
+	if cfg.CI != "" {
+		msg = "The drift is resolved. [terraform plan](" + cfg.CI + ") has no changes."
+	}
+	if _, _, err := g.client.API.IssuesCreateComment(ctx, number, &github.IssueComment{
+		Body: github.String(msg),
+	}); err != nil {
+		return fmt.Errorf("post a comment to the drift issue (number: %d): %w", number, err)
+	}
+	if _, _, err := g.client.API.IssuesEdit(ctx, number, &github.IssueRequest{
+		State: github.String("closed"),
+	}); err != nil {
+		return fmt.Errorf("close the drift issue (number: %d): %w", number, err)
+	}
+	logrus.WithFields(logrus.Fields{
+		"program":      "tfcmt",
+		"issue_number": number,
+	}).Info("close the drift issue")
+	return nil
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+func TestNotifyDriftIssue(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		output    string
+		exitCode  int
+		exists    bool
+		title     string
+		expCalls  []string
+		expTitle  string
+		expLabels []string
+	}{
+		{
+			name:      "create an issue",
+			output:    "Plan: 1 to add, 0 to change, 0 to destroy.",
+			exitCode:  2,
+			expCalls:  []string{"list", "create"},
+			expTitle:  "Terraform drift is detected (foo)",
+			expLabels: []string{"drift"},
+		},
+		{
+			name:     "update the issue",
+			output:   "Plan: 1 to add, 0 to change, 0 to destroy.",
+			exitCode: 2,
+			exists:   true,
+			title:    "Drift of {{.Target}}",
+			expCalls: []string{"list", "edit:5"},
+			expTitle: "Drift of foo",
+		},
+		{
+			name:     "close the issue",
+			output:   "No changes. Infrastructure is up-to-date.",
+			exists:   true,
+			expCalls: []string{"list", "comment:5", "close:5"},
+		},
+		{
+			name:     "no drift and no issue",
+			output:   "No changes. Infrastructure is up-to-date.",
+			expCalls: []string{"list"},
+		},
+		{
+			name:     "plan error",
+			output:   "Error: Invalid reference",
+			exitCode: 1,
+			exists:   true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.PR = PullRequest{}
+			cfg.Vars = map[string]string{"target": "foo"}
+			cfg.DriftIssue = DriftIssue{Enabled: true, Title: testCase.title, Labels: []string{"drift"}}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			embedded, err := getEmbeddedComment(&cfg, "", "plan")
+			if err != nil {
+				t.Fatal(err)
+			}
+			otherCfg := cfg
+			otherCfg.Vars = map[string]string{"target": "bar"}
+			otherEmbedded, err := getEmbeddedComment(&otherCfg, "", "plan")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				calls   []string
+				request *github.IssueRequest
+			)
+			api := newFakeAPI()
+			api.FakeIssuesListByRepo = func(ctx context.Context, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+				calls = append(calls, "list")
+				issues := []*github.Issue{
+					{Number: github.Int(3), Body: github.String("other target" + otherEmbedded)},
+					{Number: github.Int(4), Body: github.String("pull request" + embedded), PullRequestLinks: &github.PullRequestLinks{}},
+				}
+				if testCase.exists {
+					issues = append(issues, &github.Issue{Number: github.Int(5), Body: github.String("old" + embedded)})
+				}
+				return issues, nil, nil
+			}
+			api.FakeIssuesCreate = func(ctx context.Context, r *github.IssueRequest) (*github.Issue, *github.Response, error) {
+				calls = append(calls, "create")
+				request = r
+				return &github.Issue{Number: github.Int(10)}, nil, nil
+			}
+			api.FakeIssuesEdit = func(ctx context.Context, number int, r *github.IssueRequest) (*github.Issue, *github.Response, error) {
+				if r.GetState() == "closed" {
+					calls = append(calls, "close:"+strconv.Itoa(number))
+					return &github.Issue{}, nil, nil
+				}
+				calls = append(calls, "edit:"+strconv.Itoa(number))
+				request = r
+				return &github.Issue{}, nil, nil
+			}
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				calls = append(calls, "comment:"+strconv.Itoa(number))
+				return &github.IssueComment{}, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.expCalls, calls); diff != "" {
+				t.Error(diff)
+			}
+			if testCase.expTitle == "" {
+				return
+			}
+			if title := request.GetTitle(); title != testCase.expTitle {
+				t.Errorf("title: got %q, wanted %q", title, testCase.expTitle)
+			}
+			if body := request.GetBody(); !strings.Contains(body, embeddedCommentPrefix) {
+				t.Errorf("the metadata should be embedded into the body: %s", body)
+			}
+			if diff := cmp.Diff(testCase.expLabels, request.GetLabels()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"path"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// FmtSuggestion is a configuration to post suggested changes of terraform fmt
+type FmtSuggestion struct {
+	Enabled bool
+	// Dir is the directory where terraform fmt is run from the repository root. File paths of terraform fmt are relative to it
+	Dir string
+}
+
+// buildFmtSuggestions returns review comments with suggested changes of terraform fmt.
+// dir is the directory where terraform fmt is run from the repository root.
+// Hunks out of the diff of the pull request are ignored because GitHub rejects review comments on them
+func buildFmtSuggestions(program, dir string, hunks []terraform.FmtHunk, files map[string][]lineRange) []*github.DraftReviewComment {
+	drafts := []*github.DraftReviewComment{}
+	for _, hunk := range hunks {
+		p := path.Join(dir, hunk.File)
+		if !inRanges(files[p], hunk.StartLine) || !inRanges(files[p], hunk.EndLine) {
+			continue
+		}
+		draft := &github.DraftReviewComment{
+			Path: github.String(p),
+			Line: github.Int(hunk.EndLine),
+			Side: github.String("RIGHT"),
+			Body: github.String("**" + program + "**: this file isn't formatted by terraform fmt\n\n```suggestion\n" + hunk.Suggestion + "\n```"),
+		}
+		if hunk.StartLine != hunk.EndLine {
+			draft.StartLine = github.Int(hunk.StartLine)
+			draft.StartSide = github.String("RIGHT")
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts
+}
+
+// postFmtSuggestions posts a review with suggested changes of terraform fmt.
+// Errors are only logged because the comment has already been posted
+func (g *NotifyService) postFmtSuggestions(ctx context.Context, cfg *Config, result terraform.ParseResult) {
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+	files, err := g.listChangedFiles(ctx, cfg.PR.Number)
+	if err != nil {
+		logE.WithError(err).Error("list files of the pull request")
+		return
+	}
+	drafts := buildFmtSuggestions(cfg.program(), cfg.FmtSuggestion.Dir, result.FmtHunks, files)
+	if len(drafts) == 0 {
+		logE.Debug("no change of terraform fmt is in the diff of the pull request")
+		return
+	}
+	review := &github.PullRequestReviewRequest{
+		Event:    github.String(ReviewEventComment),
+		Comments: drafts,
+	}
+	if cfg.PR.Revision != "" {
+		review.CommitID = github.String(cfg.PR.Revision)
+	}
+	if _, _, err := g.client.API.PullRequestsCreateReview(ctx, cfg.PR.Number, review); err != nil {
+		logE.WithError(err).Error("post suggested changes of terraform fmt")
+	}
+}
@@ -0,0 +1,41 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestBuildFmtSuggestions(t *testing.T) {
+	t.Parallel()
+	hunks := []terraform.FmtHunk{
+		{File: "main.tf", StartLine: 2, EndLine: 2, Suggestion: `  triggers = { a = "b" }`},
+		{File: "variables.tf", StartLine: 1, EndLine: 2, Suggestion: "variable \"foo\" {\n  type = string\n}"},
+		{File: "outputs.tf", StartLine: 1, EndLine: 1, Suggestion: "output \"foo\" {"},
+	}
+	files := map[string][]lineRange{
+		"terraform/main.tf":      {{start: 1, end: 3}},
+		"terraform/variables.tf": {{start: 1, end: 5}},
+	}
+	exp := []*github.DraftReviewComment{
+		{
+			Path: github.String("terraform/main.tf"),
+			Line: github.Int(2),
+			Side: github.String("RIGHT"),
+			Body: github.String("**tfcmt**: this file isn't formatted by terraform fmt\n\n```suggestion\n  triggers = { a = \"b\" }\n```"),
+		},
+		{
+			Path:      github.String("terraform/variables.tf"),
+			StartLine: github.Int(1),
+			StartSide: github.String("RIGHT"),
+			Line:      github.Int(2),
+			Side:      github.String("RIGHT"),
+			Body:      github.String("**tfcmt**: this file isn't formatted by terraform fmt\n\n```suggestion\nvariable \"foo\" {\n  type = string\n}\n```"),
+		},
+	}
+	if diff := cmp.Diff(exp, buildFmtSuggestions("tfcmt", "terraform", hunks, files)); diff != "" {
+		t.Error(diff)
+	}
+}
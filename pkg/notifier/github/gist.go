@@ -0,0 +1,49 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// compactBody renders the compact comment linking to the Gist where the whole result is uploaded
+func compactBody(cfg *Config, gistURL string, isPlan bool, tplValue terraform.CommonTemplate) (string, error) {
+	tplValue.GistURL = gistURL
+	tpl := cfg.Gist.ApplyTemplate
+	if isPlan {
+		tpl = cfg.Gist.PlanTemplate
+	}
+	template := terraform.NewGistTemplate(tpl, isPlan)
+	template.SetValue(tplValue)
+	return template.Execute()
+}
+
+// createGist creates a Gist with the comment and the output of terraform, and returns the URL of the Gist
+func (g *NotifyService) createGist(ctx context.Context, cfg *Config, command, body, output string) (string, error) {
+	description := "tfcmt: the result of terraform " + command + " of " + cfg.Owner + "/" + cfg.Repo
+	if cfg.PR.IsNumber() {
+		description += "#" + strconv.Itoa(cfg.PR.Number)
+	}
+	files := map[github.GistFilename]github.GistFile{
+		github.GistFilename("tfcmt-" + command + ".md"): {Content: &body},
+	}
+	// The content of Gist files must not be empty
+	if output != "" {
+		files[github.GistFilename("terraform-"+command+".log")] = github.GistFile{Content: &output}
+	}
+	gist, _, err := g.client.API.GistsCreate(ctx, &github.Gist{
+		Description: &description,
+		Public:      &cfg.Gist.Public,
+		Files:       files,
+	})
+	if err != nil {
+		return "", err
+	}
+	if gist.GetHTMLURL() == "" {
+		return "", errors.New("the URL of the created gist is empty")
+	}
+	return gist.GetHTMLURL(), nil
+}
@@ -4,23 +4,45 @@ import (
 	"context"
 
 	"github.com/google/go-github/v39/github"
+	"github.com/shurcooL/githubv4"
 )
 
 // API is GitHub API interface
 type API interface {
 	IssuesCreateComment(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	IssuesListComments(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+	IssuesDeleteComment(ctx context.Context, commentID int64) (*github.Response, error)
 	IssuesListLabels(ctx context.Context, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error)
 	IssuesAddLabels(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error)
 	IssuesRemoveLabel(ctx context.Context, number int, label string) (*github.Response, error)
+	IssuesReplaceLabels(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error)
 	IssuesUpdateLabel(ctx context.Context, label, color string) (*github.Label, *github.Response, error)
+	IssuesListByRepo(ctx context.Context, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+	IssuesCreate(ctx context.Context, request *github.IssueRequest) (*github.Issue, *github.Response, error)
+	IssuesEdit(ctx context.Context, number int, request *github.IssueRequest) (*github.Issue, *github.Response, error)
+	PullRequestsList(ctx context.Context, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	PullRequestsGet(ctx context.Context, number int) (*github.PullRequest, *github.Response, error)
+	PullRequestsCreateReview(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error)
+	PullRequestsListReviews(ctx context.Context, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error)
+	PullRequestsDismissReview(ctx context.Context, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error)
+	PullRequestsUpdateReview(ctx context.Context, number int, reviewID int64, body string) (*github.PullRequestReview, *github.Response, error)
+	PullRequestsListFiles(ctx context.Context, number int, opt *github.ListOptions) ([]*github.CommitFile, *github.Response, error)
 	RepositoriesCreateComment(ctx context.Context, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error)
 	RepositoriesListCommits(ctx context.Context, opt *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error)
 	RepositoriesGetCommit(ctx context.Context, sha string) (*github.RepositoryCommit, *github.Response, error)
+	GistsCreate(ctx context.Context, gist *github.Gist) (*github.Gist, *github.Response, error)
+	RepositoriesCreateStatus(ctx context.Context, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error)
+	RepositoriesCreateDeployment(ctx context.Context, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error)
+	RepositoriesCreateDeploymentStatus(ctx context.Context, deploymentID int64, request *github.DeploymentStatusRequest) (*github.DeploymentStatus, *github.Response, error)
+	ChecksCreateCheckRun(ctx context.Context, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error)
+	MinimizeComment(ctx context.Context, nodeID, classifier string) error
+	ListMinimizedComments(ctx context.Context, nodeIDs []string) (map[string]bool, error)
 }
 
 // GitHub represents the attribute information necessary for requesting GitHub API
 type GitHub struct {
 	*github.Client
+	v4Client    *githubv4.Client
 	owner, repo string
 }
 
@@ -29,6 +51,16 @@ func (g *GitHub) IssuesCreateComment(ctx context.Context, number int, comment *g
 	return g.Client.Issues.CreateComment(ctx, g.owner, g.repo, number, comment)
 }
 
+// IssuesListComments is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#IssuesService.ListComments
+func (g *GitHub) IssuesListComments(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	return g.Client.Issues.ListComments(ctx, g.owner, g.repo, number, opt)
+}
+
+// IssuesDeleteComment is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#IssuesService.DeleteComment
+func (g *GitHub) IssuesDeleteComment(ctx context.Context, commentID int64) (*github.Response, error) {
+	return g.Client.Issues.DeleteComment(ctx, g.owner, g.repo, commentID)
+}
+
 // IssuesAddLabels is a wrapper of https://godoc.org/github.com/google/go-github/github#IssuesService.AddLabelsToIssue
 func (g *GitHub) IssuesAddLabels(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
 	return g.Client.Issues.AddLabelsToIssue(ctx, g.owner, g.repo, number, labels)
@@ -44,6 +76,11 @@ func (g *GitHub) IssuesRemoveLabel(ctx context.Context, number int, label string
 	return g.Client.Issues.RemoveLabelForIssue(ctx, g.owner, g.repo, number, label)
 }
 
+// IssuesReplaceLabels is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#IssuesService.ReplaceLabelsForIssue
+func (g *GitHub) IssuesReplaceLabels(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+	return g.Client.Issues.ReplaceLabelsForIssue(ctx, g.owner, g.repo, number, labels)
+}
+
 // IssuesUpdateLabel is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#IssuesService.EditLabel
 func (g *GitHub) IssuesUpdateLabel(ctx context.Context, label, color string) (*github.Label, *github.Response, error) {
 	return g.Client.Issues.EditLabel(ctx, g.owner, g.repo, label, &github.Label{
@@ -51,6 +88,56 @@ func (g *GitHub) IssuesUpdateLabel(ctx context.Context, label, color string) (*g
 	})
 }
 
+// IssuesListByRepo is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#IssuesService.ListByRepo
+func (g *GitHub) IssuesListByRepo(ctx context.Context, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	return g.Client.Issues.ListByRepo(ctx, g.owner, g.repo, opt)
+}
+
+// IssuesCreate is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#IssuesService.Create
+func (g *GitHub) IssuesCreate(ctx context.Context, request *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	return g.Client.Issues.Create(ctx, g.owner, g.repo, request)
+}
+
+// IssuesEdit is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#IssuesService.Edit
+func (g *GitHub) IssuesEdit(ctx context.Context, number int, request *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	return g.Client.Issues.Edit(ctx, g.owner, g.repo, number, request)
+}
+
+// PullRequestsList is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#PullRequestsService.List
+func (g *GitHub) PullRequestsList(ctx context.Context, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	return g.Client.PullRequests.List(ctx, g.owner, g.repo, opt)
+}
+
+// PullRequestsGet is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#PullRequestsService.Get
+func (g *GitHub) PullRequestsGet(ctx context.Context, number int) (*github.PullRequest, *github.Response, error) {
+	return g.Client.PullRequests.Get(ctx, g.owner, g.repo, number)
+}
+
+// PullRequestsCreateReview is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#PullRequestsService.CreateReview
+func (g *GitHub) PullRequestsCreateReview(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+	return g.Client.PullRequests.CreateReview(ctx, g.owner, g.repo, number, review)
+}
+
+// PullRequestsListReviews is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#PullRequestsService.ListReviews
+func (g *GitHub) PullRequestsListReviews(ctx context.Context, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+	return g.Client.PullRequests.ListReviews(ctx, g.owner, g.repo, number, opt)
+}
+
+// PullRequestsDismissReview is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#PullRequestsService.DismissReview
+func (g *GitHub) PullRequestsDismissReview(ctx context.Context, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error) {
+	return g.Client.PullRequests.DismissReview(ctx, g.owner, g.repo, number, reviewID, review)
+}
+
+// PullRequestsUpdateReview is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#PullRequestsService.UpdateReview
+func (g *GitHub) PullRequestsUpdateReview(ctx context.Context, number int, reviewID int64, body string) (*github.PullRequestReview, *github.Response, error) {
+	return g.Client.PullRequests.UpdateReview(ctx, g.owner, g.repo, number, reviewID, body)
+}
+
+// PullRequestsListFiles is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#PullRequestsService.ListFiles
+func (g *GitHub) PullRequestsListFiles(ctx context.Context, number int, opt *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+	return g.Client.PullRequests.ListFiles(ctx, g.owner, g.repo, number, opt)
+}
+
 // RepositoriesCreateComment is a wrapper of https://godoc.org/github.com/google/go-github/github#RepositoriesService.CreateComment
 func (g *GitHub) RepositoriesCreateComment(ctx context.Context, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error) {
 	return g.Client.Repositories.CreateComment(ctx, g.owner, g.repo, sha, comment)
@@ -65,3 +152,80 @@ func (g *GitHub) RepositoriesListCommits(ctx context.Context, opt *github.Commit
 func (g *GitHub) RepositoriesGetCommit(ctx context.Context, sha string) (*github.RepositoryCommit, *github.Response, error) {
 	return g.Client.Repositories.GetCommit(ctx, g.owner, g.repo, sha, nil)
 }
+
+// GistsCreate is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#GistsService.Create
+func (g *GitHub) GistsCreate(ctx context.Context, gist *github.Gist) (*github.Gist, *github.Response, error) {
+	return g.Client.Gists.Create(ctx, gist)
+}
+
+// RepositoriesCreateStatus is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#RepositoriesService.CreateStatus
+func (g *GitHub) RepositoriesCreateStatus(ctx context.Context, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+	return g.Client.Repositories.CreateStatus(ctx, g.owner, g.repo, ref, status)
+}
+
+// RepositoriesCreateDeployment is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#RepositoriesService.CreateDeployment
+func (g *GitHub) RepositoriesCreateDeployment(ctx context.Context, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error) {
+	return g.Client.Repositories.CreateDeployment(ctx, g.owner, g.repo, request)
+}
+
+// RepositoriesCreateDeploymentStatus is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#RepositoriesService.CreateDeploymentStatus
+func (g *GitHub) RepositoriesCreateDeploymentStatus(ctx context.Context, deploymentID int64, request *github.DeploymentStatusRequest) (*github.DeploymentStatus, *github.Response, error) {
+	return g.Client.Repositories.CreateDeploymentStatus(ctx, g.owner, g.repo, deploymentID, request)
+}
+
+// ChecksCreateCheckRun is a wrapper of https://pkg.go.dev/github.com/google/go-github/github#ChecksService.CreateCheckRun
+func (g *GitHub) ChecksCreateCheckRun(ctx context.Context, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	return g.Client.Checks.CreateCheckRun(ctx, g.owner, g.repo, opts)
+}
+
+// MinimizeComment minimizes a comment with GitHub GraphQL API
+// https://docs.github.com/en/graphql/reference/mutations#minimizecomment
+func (g *GitHub) MinimizeComment(ctx context.Context, nodeID, classifier string) error {
+	var m struct {
+		MinimizeComment struct {
+			MinimizedComment struct {
+				IsMinimized bool
+			}
+		} `graphql:"minimizeComment(input: $input)"`
+	}
+	input := githubv4.MinimizeCommentInput{
+		SubjectID:  nodeID,
+		Classifier: githubv4.ReportedContentClassifiers(classifier),
+	}
+	return g.v4Client.Mutate(ctx, &m, input, nil)
+}
+
+// ListMinimizedComments returns node ids of comments which have already been minimized with GitHub GraphQL API
+// https://docs.github.com/en/graphql/reference/queries#nodes
+func (g *GitHub) ListMinimizedComments(ctx context.Context, nodeIDs []string) (map[string]bool, error) {
+	// nodes accepts up to 100 ids at once
+	const maxNodes = 100
+	ret := make(map[string]bool, len(nodeIDs))
+	for start := 0; start < len(nodeIDs); start += maxNodes {
+		end := start + maxNodes
+		if end > len(nodeIDs) {
+			end = len(nodeIDs)
+		}
+		ids := make([]githubv4.ID, 0, end-start)
+		for _, nodeID := range nodeIDs[start:end] {
+			ids = append(ids, githubv4.ID(nodeID))
+		}
+		var q struct {
+			Nodes []struct {
+				IssueComment struct {
+					ID          string
+					IsMinimized bool
+				} `graphql:"... on IssueComment"`
+			} `graphql:"nodes(ids: $ids)"`
+		}
+		if err := g.v4Client.Query(ctx, &q, map[string]interface{}{"ids": ids}); err != nil {
+			return nil, err
+		}
+		for _, node := range q.Nodes {
+			if node.IssueComment.IsMinimized {
+				ret[node.IssueComment.ID] = true
+			}
+		}
+	}
+	return ret, nil
+}
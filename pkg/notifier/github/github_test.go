@@ -9,19 +9,56 @@ import (
 
 type fakeAPI struct {
 	API
-	FakeIssuesCreateComment       func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
-	FakeIssuesListLabels          func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error)
-	FakeIssuesAddLabels           func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error)
-	FakeIssuesRemoveLabel         func(ctx context.Context, number int, label string) (*github.Response, error)
-	FakeRepositoriesCreateComment func(ctx context.Context, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error)
-	FakeRepositoriesListCommits   func(ctx context.Context, opt *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error)
-	FakeRepositoriesGetCommit     func(ctx context.Context, sha string) (*github.RepositoryCommit, *github.Response, error)
+	FakeIssuesCreateComment                func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	FakeIssuesListComments                 func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+	FakeIssuesDeleteComment                func(ctx context.Context, commentID int64) (*github.Response, error)
+	FakeMinimizeComment                    func(ctx context.Context, nodeID, classifier string) error
+	FakeListMinimizedComments              func(ctx context.Context, nodeIDs []string) (map[string]bool, error)
+	FakeIssuesListLabels                   func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error)
+	FakeIssuesAddLabels                    func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error)
+	FakeIssuesRemoveLabel                  func(ctx context.Context, number int, label string) (*github.Response, error)
+	FakeIssuesReplaceLabels                func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error)
+	FakeIssuesUpdateLabel                  func(ctx context.Context, label, color string) (*github.Label, *github.Response, error)
+	FakeIssuesListByRepo                   func(ctx context.Context, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
+	FakeIssuesCreate                       func(ctx context.Context, request *github.IssueRequest) (*github.Issue, *github.Response, error)
+	FakeIssuesEdit                         func(ctx context.Context, number int, request *github.IssueRequest) (*github.Issue, *github.Response, error)
+	FakePullRequestsList                   func(ctx context.Context, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	FakePullRequestsGet                    func(ctx context.Context, number int) (*github.PullRequest, *github.Response, error)
+	FakePullRequestsCreateReview           func(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error)
+	FakePullRequestsListReviews            func(ctx context.Context, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error)
+	FakePullRequestsDismissReview          func(ctx context.Context, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error)
+	FakePullRequestsUpdateReview           func(ctx context.Context, number int, reviewID int64, body string) (*github.PullRequestReview, *github.Response, error)
+	FakePullRequestsListFiles              func(ctx context.Context, number int, opt *github.ListOptions) ([]*github.CommitFile, *github.Response, error)
+	FakeRepositoriesCreateComment          func(ctx context.Context, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error)
+	FakeRepositoriesListCommits            func(ctx context.Context, opt *github.CommitsListOptions) ([]*github.RepositoryCommit, *github.Response, error)
+	FakeRepositoriesGetCommit              func(ctx context.Context, sha string) (*github.RepositoryCommit, *github.Response, error)
+	FakeGistsCreate                        func(ctx context.Context, gist *github.Gist) (*github.Gist, *github.Response, error)
+	FakeRepositoriesCreateStatus           func(ctx context.Context, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error)
+	FakeRepositoriesCreateDeployment       func(ctx context.Context, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error)
+	FakeRepositoriesCreateDeploymentStatus func(ctx context.Context, deploymentID int64, request *github.DeploymentStatusRequest) (*github.DeploymentStatus, *github.Response, error)
+	FakeChecksCreateCheckRun               func(ctx context.Context, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error)
 }
 
 func (g *fakeAPI) IssuesCreateComment(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
 	return g.FakeIssuesCreateComment(ctx, number, comment)
 }
 
+func (g *fakeAPI) IssuesListComments(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	return g.FakeIssuesListComments(ctx, number, opt)
+}
+
+func (g *fakeAPI) IssuesDeleteComment(ctx context.Context, commentID int64) (*github.Response, error) {
+	return g.FakeIssuesDeleteComment(ctx, commentID)
+}
+
+func (g *fakeAPI) MinimizeComment(ctx context.Context, nodeID, classifier string) error {
+	return g.FakeMinimizeComment(ctx, nodeID, classifier)
+}
+
+func (g *fakeAPI) ListMinimizedComments(ctx context.Context, nodeIDs []string) (map[string]bool, error) {
+	return g.FakeListMinimizedComments(ctx, nodeIDs)
+}
+
 func (g *fakeAPI) IssuesListLabels(ctx context.Context, number int, opt *github.ListOptions) ([]*github.Label, *github.Response, error) {
 	return g.FakeIssuesListLabels(ctx, number, opt)
 }
@@ -34,6 +71,54 @@ func (g *fakeAPI) IssuesRemoveLabel(ctx context.Context, number int, label strin
 	return g.FakeIssuesRemoveLabel(ctx, number, label)
 }
 
+func (g *fakeAPI) IssuesReplaceLabels(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+	return g.FakeIssuesReplaceLabels(ctx, number, labels)
+}
+
+func (g *fakeAPI) IssuesUpdateLabel(ctx context.Context, label, color string) (*github.Label, *github.Response, error) {
+	return g.FakeIssuesUpdateLabel(ctx, label, color)
+}
+
+func (g *fakeAPI) IssuesListByRepo(ctx context.Context, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+	return g.FakeIssuesListByRepo(ctx, opt)
+}
+
+func (g *fakeAPI) IssuesCreate(ctx context.Context, request *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	return g.FakeIssuesCreate(ctx, request)
+}
+
+func (g *fakeAPI) IssuesEdit(ctx context.Context, number int, request *github.IssueRequest) (*github.Issue, *github.Response, error) {
+	return g.FakeIssuesEdit(ctx, number, request)
+}
+
+func (g *fakeAPI) PullRequestsList(ctx context.Context, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+	return g.FakePullRequestsList(ctx, opt)
+}
+
+func (g *fakeAPI) PullRequestsGet(ctx context.Context, number int) (*github.PullRequest, *github.Response, error) {
+	return g.FakePullRequestsGet(ctx, number)
+}
+
+func (g *fakeAPI) PullRequestsCreateReview(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+	return g.FakePullRequestsCreateReview(ctx, number, review)
+}
+
+func (g *fakeAPI) PullRequestsListReviews(ctx context.Context, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+	return g.FakePullRequestsListReviews(ctx, number, opt)
+}
+
+func (g *fakeAPI) PullRequestsDismissReview(ctx context.Context, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error) {
+	return g.FakePullRequestsDismissReview(ctx, number, reviewID, review)
+}
+
+func (g *fakeAPI) PullRequestsUpdateReview(ctx context.Context, number int, reviewID int64, body string) (*github.PullRequestReview, *github.Response, error) {
+	return g.FakePullRequestsUpdateReview(ctx, number, reviewID, body)
+}
+
+func (g *fakeAPI) PullRequestsListFiles(ctx context.Context, number int, opt *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+	return g.FakePullRequestsListFiles(ctx, number, opt)
+}
+
 func (g *fakeAPI) RepositoriesCreateComment(ctx context.Context, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error) {
 	return g.FakeRepositoriesCreateComment(ctx, sha, comment)
 }
@@ -46,6 +131,26 @@ func (g *fakeAPI) RepositoriesGetCommit(ctx context.Context, sha string) (*githu
 	return g.FakeRepositoriesGetCommit(ctx, sha)
 }
 
+func (g *fakeAPI) GistsCreate(ctx context.Context, gist *github.Gist) (*github.Gist, *github.Response, error) {
+	return g.FakeGistsCreate(ctx, gist)
+}
+
+func (g *fakeAPI) RepositoriesCreateStatus(ctx context.Context, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+	return g.FakeRepositoriesCreateStatus(ctx, ref, status)
+}
+
+func (g *fakeAPI) RepositoriesCreateDeployment(ctx context.Context, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error) {
+	return g.FakeRepositoriesCreateDeployment(ctx, request)
+}
+
+func (g *fakeAPI) RepositoriesCreateDeploymentStatus(ctx context.Context, deploymentID int64, request *github.DeploymentStatusRequest) (*github.DeploymentStatus, *github.Response, error) {
+	return g.FakeRepositoriesCreateDeploymentStatus(ctx, deploymentID, request)
+}
+
+func (g *fakeAPI) ChecksCreateCheckRun(ctx context.Context, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+	return g.FakeChecksCreateCheckRun(ctx, opts)
+}
+
 func newFakeAPI() fakeAPI {
 	return fakeAPI{
 		FakeIssuesCreateComment: func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
@@ -54,6 +159,24 @@ func newFakeAPI() fakeAPI {
 				Body: github.String("comment 1"),
 			}, nil, nil
 		},
+		FakeIssuesListComments: func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+			comments := []*github.IssueComment{
+				{
+					ID:   github.Int64(371748792),
+					Body: github.String("comment 1"),
+				},
+			}
+			return comments, &github.Response{}, nil
+		},
+		FakeIssuesDeleteComment: func(ctx context.Context, commentID int64) (*github.Response, error) {
+			return nil, nil
+		},
+		FakeMinimizeComment: func(ctx context.Context, nodeID, classifier string) error {
+			return nil
+		},
+		FakeListMinimizedComments: func(ctx context.Context, nodeIDs []string) (map[string]bool, error) {
+			return map[string]bool{}, nil
+		},
 		FakeIssuesListLabels: func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
 			labels := []*github.Label{
 				{
@@ -73,6 +196,69 @@ func newFakeAPI() fakeAPI {
 		FakeIssuesRemoveLabel: func(ctx context.Context, number int, label string) (*github.Response, error) {
 			return nil, nil
 		},
+		FakeIssuesReplaceLabels: func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+			arr := make([]*github.Label, len(labels))
+			for i, label := range labels {
+				arr[i] = &github.Label{Name: github.String(label)}
+			}
+			return arr, nil, nil
+		},
+		FakeIssuesUpdateLabel: func(ctx context.Context, label, color string) (*github.Label, *github.Response, error) {
+			return nil, nil, nil
+		},
+		FakeIssuesListByRepo: func(ctx context.Context, opt *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error) {
+			return nil, nil, nil
+		},
+		FakeIssuesCreate: func(ctx context.Context, request *github.IssueRequest) (*github.Issue, *github.Response, error) {
+			return &github.Issue{
+				Number: github.Int(10),
+			}, nil, nil
+		},
+		FakeIssuesEdit: func(ctx context.Context, number int, request *github.IssueRequest) (*github.Issue, *github.Response, error) {
+			return &github.Issue{
+				Number: github.Int(number),
+			}, nil, nil
+		},
+		FakePullRequestsList: func(ctx context.Context, opt *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error) {
+			return []*github.PullRequest{
+				{
+					Number: github.Int(1),
+					State:  github.String("open"),
+					Head: &github.PullRequestBranch{
+						Ref: github.String("feature"),
+					},
+				},
+			}, nil, nil
+		},
+		FakePullRequestsGet: func(ctx context.Context, number int) (*github.PullRequest, *github.Response, error) {
+			return &github.PullRequest{
+				Number: github.Int(number),
+				State:  github.String("open"),
+			}, nil, nil
+		},
+		FakePullRequestsCreateReview: func(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+			return &github.PullRequestReview{
+				ID:   github.Int64(80),
+				Body: review.Body,
+			}, nil, nil
+		},
+		FakePullRequestsListReviews: func(ctx context.Context, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+			return nil, nil, nil
+		},
+		FakePullRequestsDismissReview: func(ctx context.Context, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error) {
+			return &github.PullRequestReview{
+				ID: github.Int64(reviewID),
+			}, nil, nil
+		},
+		FakePullRequestsUpdateReview: func(ctx context.Context, number int, reviewID int64, body string) (*github.PullRequestReview, *github.Response, error) {
+			return &github.PullRequestReview{
+				ID:   github.Int64(reviewID),
+				Body: github.String(body),
+			}, nil, nil
+		},
+		FakePullRequestsListFiles: func(ctx context.Context, number int, opt *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+			return nil, nil, nil
+		},
 		FakeRepositoriesCreateComment: func(ctx context.Context, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error) {
 			return &github.RepositoryComment{
 				ID:       github.Int64(28427394),
@@ -102,6 +288,29 @@ func newFakeAPI() fakeAPI {
 				},
 			}, nil, nil
 		},
+		FakeGistsCreate: func(ctx context.Context, gist *github.Gist) (*github.Gist, *github.Response, error) {
+			return &github.Gist{
+				HTMLURL: github.String("https://gist.github.com/octocat/aa5a315d61ae9438b18d"),
+			}, nil, nil
+		},
+		FakeRepositoriesCreateStatus: func(ctx context.Context, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
+			return status, nil, nil
+		},
+		FakeRepositoriesCreateDeployment: func(ctx context.Context, request *github.DeploymentRequest) (*github.Deployment, *github.Response, error) {
+			return &github.Deployment{
+				ID: github.Int64(42),
+			}, nil, nil
+		},
+		FakeRepositoriesCreateDeploymentStatus: func(ctx context.Context, deploymentID int64, request *github.DeploymentStatusRequest) (*github.DeploymentStatus, *github.Response, error) {
+			return &github.DeploymentStatus{
+				State: request.State,
+			}, nil, nil
+		},
+		FakeChecksCreateCheckRun: func(ctx context.Context, opts github.CreateCheckRunOptions) (*github.CheckRun, *github.Response, error) {
+			return &github.CheckRun{
+				ID: github.Int64(4),
+			}, nil, nil
+		},
 	}
 }
 
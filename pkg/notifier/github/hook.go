@@ -0,0 +1,23 @@
+package github
+
+import (
+	"context"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// Hook is an extension point to enrich the comment.
+// PostParse is called after the output is parsed and before the template is rendered.
+// It can mutate the template values such as Vars, for example to annotate resources with their owners.
+// If PostParse returns an error, the error is shown in the comment and the notification continues
+type Hook interface {
+	PostParse(ctx context.Context, result terraform.ParseResult, tpl *terraform.CommonTemplate) error
+}
+
+// NopHook is a Hook which does nothing. This is used if Config.Hook isn't set
+type NopHook struct{}
+
+// PostParse does nothing
+func (NopHook) PostParse(ctx context.Context, result terraform.ParseResult, tpl *terraform.CommonTemplate) error {
+	return nil
+}
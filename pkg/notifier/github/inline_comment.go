@@ -0,0 +1,180 @@
+package github
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// hunkHeaderPattern matches the header of a hunk of a unified diff like "@@ -1,3 +1,4 @@"
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// lineRange is a range of lines [start, end] of the new file
+type lineRange struct {
+	start int
+	end   int
+}
+
+// parsePatchRanges returns ranges of lines of the new file which are included in hunks of the patch.
+// GitHub accepts review comments only on these lines
+func parsePatchRanges(patch string) []lineRange {
+	var ranges []lineRange
+	for _, line := range strings.Split(patch, "\n") {
+		arr := hunkHeaderPattern.FindStringSubmatch(line)
+		if arr == nil {
+			continue
+		}
+		start, _ := strconv.Atoi(arr[1])
+		count := 1
+		if arr[2] != "" {
+			count, _ = strconv.Atoi(arr[2])
+		}
+		if count == 0 {
+			continue
+		}
+		ranges = append(ranges, lineRange{start: start, end: start + count - 1})
+	}
+	return ranges
+}
+
+// resourceChange is a changed resource and the action like "created"
+type resourceChange struct {
+	address string
+	action  string
+}
+
+// inlineComment is a review comment on a line of a file
+type inlineComment struct {
+	path    string
+	line    int
+	changes []resourceChange
+}
+
+func (c *inlineComment) body(program string) string {
+	lines := make([]string, 0, len(c.changes)+1)
+	lines = append(lines, "**"+program+"**: this resource block is changed by terraform plan")
+	for _, change := range c.changes {
+		lines = append(lines, "* `"+change.address+"` will be "+change.action)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildInlineComments maps changed resources to lines of files changed in the pull request.
+// Resources whose source locations aren't in the diff are ignored because GitHub rejects review comments on them
+func buildInlineComments(sourceMap terraform.SourceMap, result terraform.ParseResult, files map[string][]lineRange) []*inlineComment {
+	comments := map[string]*inlineComment{}
+	for _, group := range []struct {
+		action    string
+		addresses []string
+	}{
+		{action: "created", addresses: result.CreatedResources},
+		{action: "updated in-place", addresses: result.UpdatedResources},
+		{action: "destroyed", addresses: result.DeletedResources},
+		{action: "replaced", addresses: result.ReplacedResources},
+	} {
+		for _, address := range group.addresses {
+			loc, ok := sourceMap.Find(address)
+			if !ok || loc.File == "" || loc.Line <= 0 {
+				continue
+			}
+			path := strings.TrimPrefix(loc.File, "/")
+			if !inRanges(files[path], loc.Line) {
+				continue
+			}
+			key := path + ":" + strconv.Itoa(loc.Line)
+			comment, ok := comments[key]
+			if !ok {
+				comment = &inlineComment{path: path, line: loc.Line}
+				comments[key] = comment
+			}
+			comment.changes = append(comment.changes, resourceChange{address: address, action: group.action})
+		}
+	}
+	ret := make([]*inlineComment, 0, len(comments))
+	for _, comment := range comments {
+		ret = append(ret, comment)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].path != ret[j].path {
+			return ret[i].path < ret[j].path
+		}
+		return ret[i].line < ret[j].line
+	})
+	return ret
+}
+
+func inRanges(ranges []lineRange, line int) bool {
+	for _, r := range ranges {
+		if r.start <= line && line <= r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// listChangedFiles returns the ranges of lines in the diff per file of the pull request
+func (g *NotifyService) listChangedFiles(ctx context.Context, number int) (map[string][]lineRange, error) {
+	opt := &github.ListOptions{
+		PerPage: 100, //nolint:gomnd
+	}
+	files := map[string][]lineRange{}
+	for {
+		arr, resp, err := g.client.API.PullRequestsListFiles(ctx, number, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range arr {
+			if file.GetStatus() == "removed" {
+				continue
+			}
+			files[file.GetFilename()] = parsePatchRanges(file.GetPatch())
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return files, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// postInlineComments posts a review with comments on the resource blocks changed by the plan.
+// Errors are only logged because the plan comment has already been posted
+func (g *NotifyService) postInlineComments(ctx context.Context, cfg *Config, result terraform.ParseResult) {
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+	files, err := g.listChangedFiles(ctx, cfg.PR.Number)
+	if err != nil {
+		logE.WithError(err).Error("list files of the pull request")
+		return
+	}
+	comments := buildInlineComments(cfg.SourceMap, result, files)
+	if len(comments) == 0 {
+		logE.Debug("no changed resource is defined in the diff of the pull request")
+		return
+	}
+	drafts := make([]*github.DraftReviewComment, len(comments))
+	for i, comment := range comments {
+		drafts[i] = &github.DraftReviewComment{
+			Path: github.String(comment.path),
+			Line: github.Int(comment.line),
+			Side: github.String("RIGHT"),
+			Body: github.String(comment.body(cfg.program())),
+		}
+	}
+	review := &github.PullRequestReviewRequest{
+		Event:    github.String(ReviewEventComment),
+		Comments: drafts,
+	}
+	if cfg.PR.Revision != "" {
+		review.CommitID = github.String(cfg.PR.Revision)
+	}
+	if _, _, err := g.client.API.PullRequestsCreateReview(ctx, cfg.PR.Number, review); err != nil {
+		logE.WithError(err).Error("post inline comments")
+	}
+}
@@ -0,0 +1,136 @@
+package github
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestParsePatchRanges(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name  string
+		patch string
+		exp   []lineRange
+	}{
+		{
+			name: "multiple hunks",
+			patch: `@@ -1,3 +1,4 @@
+ resource "null_resource" "foo" {
++  triggers = {}
+ }
+@@ -10 +11,2 @@ resource "null_resource" "bar" {
+-  count = 1
++  count = 2
++}`,
+			exp: []lineRange{{start: 1, end: 4}, {start: 11, end: 12}},
+		},
+		{
+			name:  "single line",
+			patch: "@@ -0,0 +1 @@\n+foo",
+			exp:   []lineRange{{start: 1, end: 1}},
+		},
+		{
+			name:  "deleted lines",
+			patch: "@@ -1,2 +0,0 @@\n-foo\n-bar",
+		},
+		{
+			name: "binary file",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(testCase.exp, parsePatchRanges(testCase.patch), cmp.AllowUnexported(lineRange{})); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNotifyInlineComments(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.InlineComments = true
+	cfg.SourceMap = terraform.SourceMap{
+		"null_resource.foo":            {File: "main.tf", Line: 2},
+		"null_resource.bar":            {File: "main.tf", Line: 2},
+		"null_resource.baz":            {File: "main.tf", Line: 30},
+		"module.foo.null_resource.foo": {File: "/modules/foo/main.tf", Line: 1},
+		"null_resource.unchanged":      {File: "other.tf", Line: 1},
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var review *github.PullRequestReviewRequest
+	api := newFakeAPI()
+	api.FakePullRequestsListFiles = func(ctx context.Context, number int, opt *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
+		return []*github.CommitFile{
+			{
+				Filename: github.String("main.tf"),
+				Status:   github.String("modified"),
+				Patch:    github.String("@@ -1,3 +1,4 @@\n resource \"null_resource\" \"foo\" {\n+  triggers = {}\n }\n"),
+			},
+			{
+				Filename: github.String("modules/foo/main.tf"),
+				Status:   github.String("added"),
+				Patch:    github.String("@@ -0,0 +1,2 @@\n+resource \"null_resource\" \"foo\" {\n+}"),
+			},
+		}, nil, nil
+	}
+	api.FakePullRequestsCreateReview = func(ctx context.Context, number int, r *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+		review = r
+		return &github.PullRequestReview{}, nil, nil
+	}
+	client.API = &api
+	if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: `Terraform will perform the following actions:
+
+  # module.foo.null_resource.foo will be created
+  + resource "null_resource" "foo" {}
+
+  # null_resource.bar will be destroyed
+  - resource "null_resource" "bar" {}
+
+  # null_resource.baz will be destroyed
+  - resource "null_resource" "baz" {}
+
+  # null_resource.foo must be replaced
+-/+ resource "null_resource" "foo" {}
+
+Plan: 2 to add, 0 to change, 3 to destroy.`,
+		ExitCode: 2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if review == nil {
+		t.Fatal("inline comments should be posted")
+	}
+	exp := &github.PullRequestReviewRequest{
+		CommitID: github.String("abcd"),
+		Event:    github.String("COMMENT"),
+		Comments: []*github.DraftReviewComment{
+			{
+				Path: github.String("main.tf"),
+				Line: github.Int(2),
+				Side: github.String("RIGHT"),
+				Body: github.String("**tfcmt**: this resource block is changed by terraform plan\n* `null_resource.bar` will be destroyed\n* `null_resource.foo` will be replaced"),
+			},
+			{
+				Path: github.String("modules/foo/main.tf"),
+				Line: github.Int(1),
+				Side: github.String("RIGHT"),
+				Body: github.String("**tfcmt**: this resource block is changed by terraform plan\n* `module.foo.null_resource.foo` will be created"),
+			},
+		},
+	}
+	if diff := cmp.Diff(exp, review); diff != "" {
+		t.Error(diff)
+	}
+}
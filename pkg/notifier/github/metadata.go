@@ -0,0 +1,211 @@
+package github
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/github-comment-metadata/metadata"
+)
+
+const embeddedCommentPrefix = "<!-- github-comment: "
+
+// commentMetadata is the metadata embedded in comments by tfcmt
+type commentMetadata struct {
+	Program  string
+	Command  string
+	Target   string
+	Link     string
+	SHA1     string
+	PRNumber int
+	// IdempotencyKey is embedded only if it is configured
+	IdempotencyKey string
+	// Part and Parts are embedded only if the comment is split into multiple comments. Part starts at 1
+	Part  int
+	Parts int
+	// GistURL is embedded only if the whole result is uploaded to a Gist and the comment links to it
+	GistURL string
+}
+
+// gistURLPlaceholder replaces the URL of the Gist when comments are compared, because a new Gist is created every time
+const gistURLPlaceholder = "https://gist.github.com/tfcmt"
+
+// normalize returns the body without volatile parts such as the embedded metadata, the CI link, and the URL of the Gist
+func (m *commentMetadata) normalize(body string) string {
+	if m.GistURL != "" {
+		body = strings.ReplaceAll(body, m.GistURL, gistURLPlaceholder)
+	}
+	return normalizeCommentBody(body, m.Link)
+}
+
+// matchComment returns the metadata of the comment if the comment is posted by the program and its command and target match
+func matchComment(comment *github.IssueComment, program, command, target string) (*commentMetadata, bool) {
+	return matchMetadata(comment.GetBody(), program, command, target)
+}
+
+// matchMetadata returns the metadata embedded in the body if its program, command, and target match
+func matchMetadata(body, program, command, target string) (*commentMetadata, bool) {
+	meta := &commentMetadata{}
+	f, err := metadata.Extract(body, meta)
+	if err != nil || !f {
+		return nil, false
+	}
+	if meta.Program != program || meta.Command != command || meta.Target != target {
+		return nil, false
+	}
+	return meta, true
+}
+
+// findLatestComment returns the latest comment posted by the program whose command and target match.
+// Comments are sorted by created time in ascending order.
+func findLatestComment(comments []*github.IssueComment, program, command, target string) (*github.IssueComment, *commentMetadata) {
+	for i := len(comments) - 1; i >= 0; i-- {
+		if meta, ok := matchComment(comments[i], program, command, target); ok {
+			return comments[i], meta
+		}
+	}
+	return nil, nil
+}
+
+// findLatestCommentParts returns the latest comment posted by the program whose command and target match.
+// If the latest comment is split, the parts from firstPart to the last part are returned in order.
+// If any of them is missing, nil is returned
+func findLatestCommentParts(comments []*github.IssueComment, program, command, target string, firstPart int) ([]*github.IssueComment, []*commentMetadata) {
+	latest, meta := findLatestComment(comments, program, command, target)
+	if latest == nil {
+		return nil, nil
+	}
+	if meta.Parts <= 1 {
+		if firstPart > 1 {
+			return nil, nil
+		}
+		return []*github.IssueComment{latest}, []*commentMetadata{meta}
+	}
+	if meta.Part != meta.Parts || meta.Parts < firstPart {
+		return nil, nil
+	}
+	parts := make([]*github.IssueComment, meta.Parts-firstPart+1)
+	metas := make([]*commentMetadata, len(parts))
+	next := meta.Parts
+	for i := len(comments) - 1; i >= 0; i-- {
+		m, ok := matchComment(comments[i], program, command, target)
+		if !ok {
+			continue
+		}
+		if m.Parts != meta.Parts || m.Part != next {
+			return nil, nil
+		}
+		parts[next-firstPart] = comments[i]
+		metas[next-firstPart] = m
+		if next == firstPart {
+			return parts, metas
+		}
+		next--
+	}
+	return nil, nil
+}
+
+// normalizeCommentBody removes volatile parts such as the embedded metadata and the CI link from a comment body
+func normalizeCommentBody(body, link string) string {
+	lines := strings.Split(body, "\n")
+	ret := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.HasPrefix(line, embeddedCommentPrefix) {
+			continue
+		}
+		ret = append(ret, line)
+	}
+	body = strings.Join(ret, "\n")
+	if link != "" {
+		body = strings.ReplaceAll(body, link, "")
+	}
+	return strings.TrimSpace(body)
+}
+
+// setCIEnv sets the metadata of the CI job.
+// metadata.SetCIEnv doesn't support some CI platforms, so tfcmt sets their metadata by itself.
+func setCIEnv(ciName string, getEnv func(string) string, data map[string]interface{}) error {
+	switch ciName {
+	case "buildkite":
+		data["WorkflowName"] = getEnv("BUILDKITE_PIPELINE_SLUG")
+		data["JobName"] = getEnv("BUILDKITE_LABEL")
+		data["JobID"] = getEnv("BUILDKITE_JOB_ID")
+		return nil
+	case "harness":
+		data["WorkflowName"] = getEnv("HARNESS_PIPELINE_ID")
+		data["JobName"] = getEnv("HARNESS_STEP_ID")
+		data["JobID"] = getEnv("HARNESS_BUILD_ID")
+		return nil
+	}
+	return metadata.SetCIEnv(ciName, getEnv, data)
+}
+
+// ciContext is the context of the CI run which is passed to templates.
+// Fields which the CI platform doesn't provide are empty
+type ciContext struct {
+	RunURL     string
+	JobURL     string
+	RunAttempt string
+	Actor      string
+}
+
+// getCIContext gets the context of the CI run from the environment variables of the CI platform
+func getCIContext(ciName string, getEnv func(string) string) ciContext {
+	switch ciName {
+	case "github-actions":
+		serverURL := getEnv("GITHUB_SERVER_URL")
+		if serverURL == "" {
+			serverURL = "https://github.com"
+		}
+		c := ciContext{
+			RunAttempt: getEnv("GITHUB_RUN_ATTEMPT"),
+			// GITHUB_TRIGGERING_ACTOR is the user who re-runs the workflow
+			Actor: getEnv("GITHUB_TRIGGERING_ACTOR"),
+		}
+		if c.Actor == "" {
+			c.Actor = getEnv("GITHUB_ACTOR")
+		}
+		if runID := getEnv("GITHUB_RUN_ID"); runID != "" {
+			c.RunURL = serverURL + "/" + getEnv("GITHUB_REPOSITORY") + "/actions/runs/" + runID
+			if c.RunAttempt != "" {
+				c.RunURL += "/attempts/" + c.RunAttempt
+			}
+		}
+		return c
+	case "circleci":
+		c := ciContext{
+			JobURL: getEnv("CIRCLE_BUILD_URL"),
+			Actor:  getEnv("CIRCLE_USERNAME"),
+		}
+		if workflowID := getEnv("CIRCLE_WORKFLOW_ID"); workflowID != "" {
+			c.RunURL = "https://app.circleci.com/pipelines/workflows/" + workflowID
+		}
+		return c
+	case "codebuild":
+		// a build of CodeBuild is both a run and a job
+		return ciContext{
+			RunURL: getEnv("CODEBUILD_BUILD_URL"),
+			JobURL: getEnv("CODEBUILD_BUILD_URL"),
+			Actor:  getEnv("CODEBUILD_INITIATOR"),
+		}
+	case "buildkite":
+		c := ciContext{
+			RunURL: getEnv("BUILDKITE_BUILD_URL"),
+			Actor:  getEnv("BUILDKITE_BUILD_CREATOR"),
+		}
+		if jobID := getEnv("BUILDKITE_JOB_ID"); c.RunURL != "" && jobID != "" {
+			c.JobURL = c.RunURL + "#" + jobID
+		}
+		// BUILDKITE_RETRY_COUNT is 0 at the first attempt
+		if n, err := strconv.Atoi(getEnv("BUILDKITE_RETRY_COUNT")); err == nil {
+			c.RunAttempt = strconv.Itoa(n + 1)
+		}
+		return c
+	case "drone":
+		return ciContext{
+			RunURL: getEnv("DRONE_BUILD_LINK"),
+			Actor:  getEnv("DRONE_COMMIT_AUTHOR"),
+		}
+	}
+	return ciContext{}
+}
@@ -0,0 +1,296 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+)
+
+func TestFindLatestComment(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			ID:   github.Int64(1),
+			Body: github.String("foo\n<!-- github-comment: {\"Program\":\"tfcmt\",\"Command\":\"plan\"} -->"),
+		},
+		{
+			ID:   github.Int64(2),
+			Body: github.String("bar\n<!-- github-comment: {\"Program\":\"tfcmt\",\"Command\":\"plan\",\"Target\":\"foo\"} -->"),
+		},
+		{
+			ID:   github.Int64(3),
+			Body: github.String("bar\n<!-- github-comment: {\"Program\":\"tfcmt\",\"Command\":\"apply\"} -->"),
+		},
+		{
+			ID:   github.Int64(4),
+			Body: github.String("hello"),
+		},
+		{
+			ID:   github.Int64(5),
+			Body: github.String("baz\n<!-- github-comment: {\"Program\":\"modules\",\"Command\":\"apply\"} -->"),
+		},
+	}
+	testCases := []struct {
+		name    string
+		program string
+		command string
+		target  string
+		id      int64
+	}{
+		{
+			name:    "no target",
+			command: "plan",
+			id:      1,
+		},
+		{
+			name:    "target",
+			command: "plan",
+			target:  "foo",
+			id:      2,
+		},
+		{
+			name:    "apply",
+			command: "apply",
+			id:      3,
+		},
+		{
+			name:    "tag",
+			program: "modules",
+			command: "apply",
+			id:      5,
+		},
+		{
+			name:    "other tag",
+			program: "envs",
+			command: "apply",
+			id:      0,
+		},
+		{
+			name:    "not found",
+			command: "plan",
+			target:  "bar",
+			id:      0,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			program := testCase.program
+			if program == "" {
+				program = "tfcmt"
+			}
+			comment, _ := findLatestComment(comments, program, testCase.command, testCase.target)
+			if comment.GetID() != testCase.id {
+				t.Errorf("got %d but want %d", comment.GetID(), testCase.id)
+			}
+		})
+	}
+}
+
+func TestFindLatestCommentParts(t *testing.T) {
+	t.Parallel()
+	comment := func(id int64, meta string) *github.IssueComment {
+		return &github.IssueComment{
+			ID:   github.Int64(id),
+			Body: github.String("foo\n<!-- github-comment: {\"Program\":\"tfcmt\",\"Command\":\"plan\"" + meta + "} -->"),
+		}
+	}
+	testCases := []struct {
+		name      string
+		comments  []*github.IssueComment
+		firstPart int
+		ids       []int64
+	}{
+		{
+			name:      "not split",
+			comments:  []*github.IssueComment{comment(1, ""), comment(2, "")},
+			firstPart: 1,
+			ids:       []int64{2},
+		},
+		{
+			name: "split",
+			comments: []*github.IssueComment{
+				comment(1, ""),
+				comment(2, `,"Part":1,"Parts":3`),
+				comment(3, `,"Part":2,"Parts":3`),
+				{ID: github.Int64(4), Body: github.String("hello")},
+				comment(5, `,"Part":3,"Parts":3`),
+			},
+			firstPart: 1,
+			ids:       []int64{2, 3, 5},
+		},
+		{
+			name: "the first part is posted as a review",
+			comments: []*github.IssueComment{
+				comment(1, `,"Part":2,"Parts":3`),
+				comment(2, `,"Part":3,"Parts":3`),
+			},
+			firstPart: 2,
+			ids:       []int64{1, 2},
+		},
+		{
+			name: "a part is missing",
+			comments: []*github.IssueComment{
+				comment(1, `,"Part":1,"Parts":3`),
+				comment(2, `,"Part":3,"Parts":3`),
+			},
+			firstPart: 1,
+		},
+		{
+			name: "the last part is missing",
+			comments: []*github.IssueComment{
+				comment(1, `,"Part":1,"Parts":2`),
+			},
+			firstPart: 1,
+		},
+		{
+			name:      "not split in the review mode",
+			comments:  []*github.IssueComment{comment(1, "")},
+			firstPart: 2,
+		},
+		{
+			name:      "not found",
+			firstPart: 1,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			comments, metas := findLatestCommentParts(testCase.comments, "tfcmt", "plan", "", testCase.firstPart)
+			var ids []int64
+			for _, comment := range comments {
+				ids = append(ids, comment.GetID())
+			}
+			if diff := cmp.Diff(testCase.ids, ids); diff != "" {
+				t.Error(diff)
+			}
+			if len(metas) != len(comments) {
+				t.Errorf("the number of metadata: got %d, wanted %d", len(metas), len(comments))
+			}
+		})
+	}
+}
+
+func TestNormalizeCommentBody(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		body   string
+		link   string
+		expect string
+	}{
+		{
+			name:   "metadata",
+			body:   "## Plan Result\n<!-- github-comment: {\"Program\":\"tfcmt\"} -->",
+			expect: "## Plan Result",
+		},
+		{
+			name:   "link",
+			body:   "## Plan Result\n[CI link](https://example.com/runs/1)\n",
+			link:   "https://example.com/runs/1",
+			expect: "## Plan Result\n[CI link]()",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if body := normalizeCommentBody(testCase.body, testCase.link); body != testCase.expect {
+				t.Errorf("got %q but want %q", body, testCase.expect)
+			}
+		})
+	}
+}
+
+func TestGetCIContext(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		ciName string
+		env    map[string]string
+		exp    ciContext
+	}{
+		{
+			name:   "github actions",
+			ciName: "github-actions",
+			env: map[string]string{
+				"GITHUB_REPOSITORY":       "suzuki-shunsuke/tfcmt",
+				"GITHUB_RUN_ID":           "100",
+				"GITHUB_RUN_ATTEMPT":      "2",
+				"GITHUB_ACTOR":            "octocat",
+				"GITHUB_TRIGGERING_ACTOR": "monalisa",
+			},
+			exp: ciContext{
+				RunURL:     "https://github.com/suzuki-shunsuke/tfcmt/actions/runs/100/attempts/2",
+				RunAttempt: "2",
+				Actor:      "monalisa",
+			},
+		},
+		{
+			name:   "github enterprise server",
+			ciName: "github-actions",
+			env: map[string]string{
+				"GITHUB_SERVER_URL": "https://ghe.example.com",
+				"GITHUB_REPOSITORY": "suzuki-shunsuke/tfcmt",
+				"GITHUB_RUN_ID":     "100",
+				"GITHUB_ACTOR":      "octocat",
+			},
+			exp: ciContext{
+				RunURL: "https://ghe.example.com/suzuki-shunsuke/tfcmt/actions/runs/100",
+				Actor:  "octocat",
+			},
+		},
+		{
+			name:   "circleci",
+			ciName: "circleci",
+			env: map[string]string{
+				"CIRCLE_BUILD_URL":   "https://circleci.com/gh/suzuki-shunsuke/tfcmt/10",
+				"CIRCLE_WORKFLOW_ID": "xxx",
+				"CIRCLE_USERNAME":    "octocat",
+			},
+			exp: ciContext{
+				RunURL: "https://app.circleci.com/pipelines/workflows/xxx",
+				JobURL: "https://circleci.com/gh/suzuki-shunsuke/tfcmt/10",
+				Actor:  "octocat",
+			},
+		},
+		{
+			name:   "buildkite",
+			ciName: "buildkite",
+			env: map[string]string{
+				"BUILDKITE_BUILD_URL":     "https://buildkite.com/org/pipeline/builds/10",
+				"BUILDKITE_JOB_ID":        "yyy",
+				"BUILDKITE_RETRY_COUNT":   "1",
+				"BUILDKITE_BUILD_CREATOR": "octocat",
+			},
+			exp: ciContext{
+				RunURL:     "https://buildkite.com/org/pipeline/builds/10",
+				JobURL:     "https://buildkite.com/org/pipeline/builds/10#yyy",
+				RunAttempt: "2",
+				Actor:      "octocat",
+			},
+		},
+		{
+			name:   "unknown ci",
+			ciName: "jenkins",
+			env: map[string]string{
+				"BUILD_URL": "https://jenkins.example.com/job/tfcmt/1",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			c := getCIContext(testCase.ciName, func(k string) string {
+				return testCase.env[k]
+			})
+			if diff := cmp.Diff(testCase.exp, c); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
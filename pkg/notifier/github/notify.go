@@ -2,128 +2,709 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/google/go-github/v39/github"
 	"github.com/sirupsen/logrus"
 	"github.com/suzuki-shunsuke/github-comment-metadata/metadata"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+	"golang.org/x/sync/errgroup"
 )
 
 // NotifyService handles communication with the notification related
 // methods of GitHub API
 type NotifyService service
 
-// Notify posts comment optimized for notifications
-func (g *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) { //nolint:cyclop
+// PostedComment returns the comment or the pull request review posted by the last notification.
+// If no comment is posted, for example in the dry run mode, nil is returned
+func (g *NotifyService) PostedComment() *notifier.PostedComment {
+	return g.client.posted
+}
+
+// Notify posts comment optimized for notifications. The posted comment is kept in the client
+func (g *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
 	cfg := g.client.Config
-	parser := g.client.Config.Parser
-	template := g.client.Config.Template
+	parsed, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+	result := parsed.ParseResult
+	// The template of AutoParser is decided by the detected command
+	template, err := parsed.Template(notifier.RenderOption{
+		Template:           cfg.Template,
+		ParseErrorTemplate: cfg.ParseErrorTemplate,
+	})
+	if err != nil || template == nil {
+		return result.ExitCode, err
+	}
+	if !cfg.DryRun {
+		// The pull request is resolved first so that labels are updated and the closed pull request is handled
+		// even if the pull request is found only by the branch
+		if err := g.resolvePR(ctx, &cfg, parsed); err != nil {
+			return result.ExitCode, err
+		}
+	}
+	// command is embedded in the metadata and used to find comments of the same command
+	command := parsed.Command()
+	att, errMsgs := parseAttachments(parsed.Param)
+
+	if parsed.IsPlan && !cfg.DryRun && cfg.PR.IsNumber() && cfg.ClosedPRAction != "" && cfg.ClosedPRAction != ClosedPRActionPost {
+		if skip := g.handleClosedPR(ctx, &cfg); skip {
+			return g.finish(parsed, att)
+		}
+	}
+
+	if !cfg.DryRun && cfg.PR.IsNumber() {
+		errMsgs = append(errMsgs, g.updateLabelsOfResult(ctx, &cfg, parsed, att)...)
+	}
+	if result.DetectedCommand == terraform.CommandValidate && cfg.Annotations {
+		if err := writeAnnotations(os.Stdout, result.Diagnostics); err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		}
+	}
+
+	if parsed.IsPlan && len(cfg.PostTriggers) > 0 && !matchPostTriggers(cfg.PostTriggers, result) {
+		g.cleanUpWithoutPost(ctx, &cfg, command)
+		return g.finish(parsed, att)
+	}
+
+	tplValue := g.templateValue(ctx, &cfg, parsed, att, errMsgs)
+	render := func(tplValue terraform.CommonTemplate) (string, error) {
+		template.SetValue(tplValue)
+		body, err := template.Execute()
+		if err != nil {
+			return "", err
+		}
+		if parsed.IsPlan && !result.HasParseError && cfg.SummaryPosition != "" {
+			return addSummary(body, cfg.SummaryPosition, tplValue)
+		}
+		return body, nil
+	}
+	body, err := render(tplValue)
+	if err != nil {
+		return result.ExitCode, err
+	}
+
+	if !cfg.DryRun {
+		done, err := g.notifyWithoutComment(ctx, &cfg, parsed, command, body)
+		if err != nil {
+			return result.ExitCode, err
+		}
+		if done {
+			return g.finish(parsed, att)
+		}
+		if g.shouldSkipComment(ctx, &cfg, command) {
+			return g.finish(parsed, att)
+		}
+	}
+
+	skipped, err := g.postComment(ctx, &cfg, parsed, command, tplValue, body, render)
+	if err != nil {
+		return result.ExitCode, err
+	}
+	if skipped {
+		return g.finish(parsed, att)
+	}
+	if result.DetectedCommand == terraform.CommandFmt && !cfg.DryRun && cfg.FmtSuggestion.Enabled && cfg.PR.IsNumber() && len(result.FmtHunks) != 0 {
+		g.postFmtSuggestions(ctx, &cfg, result)
+	}
+	if parsed.IsPlan && !cfg.DryRun && cfg.InlineComments && cfg.PR.IsNumber() && len(cfg.SourceMap) != 0 {
+		g.postInlineComments(ctx, &cfg, result)
+	}
+	return g.finish(parsed, att)
+}
+
+// attachments are the results of the other tools such as infracost and tflint, which are rendered with the result
+type attachments struct {
+	costEstimate  *terraform.CostEstimate
+	lintResult    *terraform.LintResult
+	securityScan  *terraform.SecurityScan
+	policyResult  terraform.PolicyResult
+	checkovReport *terraform.CheckovReport
+}
+
+// parseAttachments parses the results of the other tools.
+// Errors are returned as messages so that the result is posted even if they can't be parsed
+func parseAttachments(param notifier.ParamExec) (*attachments, []string) {
+	att := &attachments{}
 	var errMsgs []string
+	if param.CostEstimate != "" {
+		cost, err := terraform.ParseCostEstimate([]byte(param.CostEstimate))
+		if err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		} else {
+			att.costEstimate = cost
+		}
+	}
+	if param.LintResult != "" {
+		lint, err := terraform.ParseLintResult([]byte(param.LintResult))
+		if err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		} else {
+			att.lintResult = lint
+		}
+	}
+	if param.SecurityScan != "" {
+		scan, err := terraform.ParseSecurityScan([]byte(param.SecurityScan))
+		if err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		} else {
+			att.securityScan = scan
+		}
+	}
+	if param.PolicyResult != "" {
+		policy, err := terraform.ParsePolicyResult([]byte(param.PolicyResult))
+		if err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		} else {
+			att.policyResult = policy
+		}
+	}
+	if param.Checkov != "" {
+		report, err := terraform.ParseCheckovReport([]byte(param.Checkov))
+		if err != nil {
+			errMsgs = append(errMsgs, err.Error())
+		} else {
+			att.checkovReport = report
+		}
+	}
+	return att, errMsgs
+}
+
+// finish returns the exit code of tfcmt.
+// The exit code of terraform plan can be changed by the result such as destroyed resources and critical findings
+func (g *NotifyService) finish(parsed *notifier.Result, att *attachments) (int, error) {
+	if parsed.IsPlan {
+		return g.exitCode(parsed.ParseResult, att.securityScan, att.policyResult)
+	}
+	return parsed.ExitCode, nil
+}
+
+// updateLabelsOfResult updates labels of the pull request according to the result.
+// Errors are returned as messages so that the result is posted even if labels can't be updated
+func (g *NotifyService) updateLabelsOfResult(ctx context.Context, cfg *Config, parsed *notifier.Result, att *attachments) []string {
+	var errMsgs []string
+	result := parsed.ParseResult
+	if parsed.IsPlan {
+		if cfg.ResultLabels.HasAnyLabelDefined() {
+			errMsgs = append(errMsgs, g.updateLabels(ctx, cfg.PR.Number, result)...)
+		}
+		if att.policyResult != nil && cfg.ResultLabels.PolicyViolationLabel != "" {
+			errMsgs = append(errMsgs, g.toggleLabel(ctx, cfg.PR.Number, cfg.ResultLabels.PolicyViolationLabel, cfg.ResultLabels.PolicyViolationLabelColor, att.policyResult.CountFailures() > 0)...)
+		}
+	}
+	if result.DetectedCommand == terraform.CommandDestroy && cfg.ResultLabels.HasDestroyLabelDefined() {
+		errMsgs = append(errMsgs, g.updateDestroyLabels(ctx, cfg.PR.Number, result)...)
+	}
+	if result.DetectedCommand == terraform.CommandValidate && cfg.ResultLabels.ValidateFailedLabel != "" {
+		errMsgs = append(errMsgs, g.updateValidateLabel(ctx, cfg.PR.Number, result)...)
+	}
+	return errMsgs
+}
 
-	result := parser.Parse(param.CombinedOutput)
-	result.ExitCode = param.ExitCode
+// templateValue returns the template entities of the result.
+// The entities common to all notifiers are built by notifier.Result and the ones only for GitHub are added
+func (g *NotifyService) templateValue(ctx context.Context, cfg *Config, parsed *notifier.Result, att *attachments, errMsgs []string) terraform.CommonTemplate {
+	result := parsed.ParseResult
+	param := parsed.Param
+	// Vars is copied so that the hook doesn't change the embedded metadata
+	vars := make(map[string]string, len(cfg.Vars))
+	for k, v := range cfg.Vars {
+		vars[k] = v
+	}
+	tplValue := parsed.CommonTemplate(notifier.RenderOption{
+		ResultLabels:  cfg.ResultLabels,
+		Link:          cfg.CI,
+		Vars:          vars,
+		Templates:     cfg.Templates,
+		UseRawOutput:  cfg.UseRawOutput,
+		MaxResources:  cfg.MaxResources,
+		ErrorMessages: errMsgs,
+	})
 	if result.HasParseError {
-		template = g.client.Config.ParseErrorTemplate
-	} else {
-		if result.Error != nil {
-			return result.ExitCode, result.Error
-		}
-		if result.Result == "" {
-			return result.ExitCode, result.Error
-		}
-	}
-
-	_, isPlan := parser.(*terraform.PlanParser)
-	if isPlan {
-		if cfg.PR.IsNumber() && cfg.ResultLabels.HasAnyLabelDefined() {
-			errMsgs = append(errMsgs, g.updateLabels(ctx, result)...)
-		}
-	}
-
-	template.SetValue(terraform.CommonTemplate{
-		Result:                 result.Result,
-		ChangedResult:          result.ChangedResult,
-		ChangeOutsideTerraform: result.OutsideTerraform,
-		Warning:                result.Warning,
-		HasDestroy:             result.HasDestroy,
-		Link:                   cfg.CI,
-		UseRawOutput:           cfg.UseRawOutput,
-		Vars:                   cfg.Vars,
-		Templates:              cfg.Templates,
-		Stdout:                 param.Stdout,
-		Stderr:                 param.Stderr,
-		CombinedOutput:         param.CombinedOutput,
-		ExitCode:               param.ExitCode,
-		ErrorMessages:          errMsgs,
-		CreatedResources:       result.CreatedResources,
-		UpdatedResources:       result.UpdatedResources,
-		DeletedResources:       result.DeletedResources,
-		ReplacedResources:      result.ReplacedResources,
+		tplValue.CombinedOutputHead = headLines(param.CombinedOutput, outputSnippetLines)
+		tplValue.CombinedOutputTail = tailLines(param.CombinedOutput, outputSnippetLines)
+	}
+	tplValue.CostDelta = att.costEstimate.Delta()
+	tplValue.CostBreakdown = att.costEstimate.Breakdown()
+	tplValue.CostResources = att.costEstimate.Resources()
+	tplValue.LintSummary = att.lintResult.Summary()
+	tplValue.LintIssues = att.lintResult.Entries()
+	tplValue.SecuritySummary = att.securityScan.Summary()
+	tplValue.SecurityFindings = att.securityScan.Entries()
+	tplValue.PolicySummary = att.policyResult.Summary()
+	tplValue.PolicyViolations = att.policyResult.Violations()
+	tplValue.CheckovResources = att.checkovReport.FailedResources(result)
+	tplValue.ArtifactURL = param.ArtifactURL
+	tplValue.PlanJSONURL = param.PlanJSONURL
+	tplValue.Env = filterEnv(os.Environ(), cfg.TemplateEnvPrefixes)
+	// Resources are linked to their source locations only if the revision is known
+	tplValue.ResourceURLs = cfg.SourceMap.BlobURLs(
+		getRepoURL(g.client.Client.BaseURL, cfg.Owner, cfg.Repo), cfg.PR.Revision,
+		result.CreatedResources, result.UpdatedResources, result.DeletedResources, result.ReplacedResources)
+	ciCtx := getCIContext(param.CIName, os.Getenv)
+	tplValue.RunURL = ciCtx.RunURL
+	tplValue.JobURL = ciCtx.JobURL
+	tplValue.RunAttempt = ciCtx.RunAttempt
+	tplValue.Actor = ciCtx.Actor
+	if err := cfg.hook().PostParse(ctx, result, &tplValue); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(err).Error("run the post parse hook")
+		tplValue.ErrorMessages = append(tplValue.ErrorMessages, "run the post parse hook: "+err.Error())
+	}
+	return tplValue
+}
+
+// notifyWithoutComment notifies the result with features other than the comment such as the job summary and check runs.
+// It returns true if the comment shouldn't be posted because the result has been notified instead
+func (g *NotifyService) notifyWithoutComment(ctx context.Context, cfg *Config, parsed *notifier.Result, command, body string) (bool, error) {
+	result := parsed.ParseResult
+	if parsed.IsApply && cfg.Deployment.Enabled {
+		g.finishDeployment(ctx, cfg, result)
+	}
+
+	if cfg.StepSummary {
+		if err := appendStepSummary(os.Getenv(EnvStepSummary), body); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+			}).WithError(err).Error("write the result to the job summary")
+		}
+	}
+
+	if cfg.CommitStatus.Enabled {
+		g.postCommitStatus(ctx, cfg, command, result)
+	}
+
+	if cfg.CheckRun.Enabled {
+		if created := g.postCheckRun(ctx, cfg, command, body, result); created && cfg.CheckRun.SkipComment {
+			return true, nil
+		}
+	}
+
+	if parsed.IsPlan && cfg.DriftIssue.Enabled {
+		// The drift issue is posted instead of the comment
+		embeddedComment, err := getEmbeddedComment(cfg, parsed.Param.CIName, command)
+		if err != nil {
+			return false, err
+		}
+		if err := g.manageDriftIssue(ctx, cfg, body+embeddedComment, result); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// resolvePR finds the pull request to post the comment if the pull request number isn't given
+func (g *NotifyService) resolvePR(ctx context.Context, cfg *Config, parsed *notifier.Result) error {
+	if cfg.TargetPRNumber > 0 {
+		return nil
+	}
+	// The branch is the last resort to find the pull request of any command
+	if err := g.findPRByBranch(ctx, cfg); err != nil {
+		return err
+	}
+	if parsed.IsApply {
+		return g.findPR(ctx, cfg, parsed.ParseResult)
+	}
+	return nil
+}
+
+// shouldSkipComment returns true if the comment shouldn't be posted because a comment with the same idempotency key has already been posted.
+// If it fails to check it, the comment is posted.
+// Duplicated comments are checked by postComment because they are compared after the comment is truncated, compacted, or split
+func (g *NotifyService) shouldSkipComment(ctx context.Context, cfg *Config, command string) bool {
+	if !cfg.PR.IsNumber() || cfg.IdempotencyKey == "" {
+		return false
+	}
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
 	})
-	body, err := template.Execute()
+	posted, err := g.isPostedWithIdempotencyKey(ctx, cfg, command)
 	if err != nil {
-		return result.ExitCode, err
+		logE.WithError(err).Warn("check whether the comment with the idempotency key has already been posted")
+		return false
 	}
-	if _, isApply := parser.(*terraform.ApplyParser); isApply {
-		prNumber, err := g.client.Commits.MergedPRNumber(ctx, cfg.PR.Revision)
-		if err == nil {
-			cfg.PR.Number = prNumber
-		} else if !cfg.PR.IsNumber() {
-			commits, err := g.client.Commits.List(ctx, cfg.PR.Revision)
-			if err != nil {
-				return result.ExitCode, err
-			}
-			lastRevision, _ := g.client.Commits.lastOne(commits, cfg.PR.Revision)
-			cfg.PR.Revision = lastRevision
-		}
+	if posted {
+		logE.WithField("idempotency_key", cfg.IdempotencyKey).Info("skip posting a comment because a comment with the same idempotency key already exists")
 	}
+	return posted
+}
 
+// isDuplicated returns true if skipping duplicated comments is enabled and the comment is identical to the latest comment.
+// parts are the bodies to be posted without the embedded metadata.
+// If it fails to check it, the comment is posted
+func (g *NotifyService) isDuplicated(ctx context.Context, cfg *Config, parsed *notifier.Result, command string, parts []string) bool {
+	skipDuplicate := cfg.SkipDuplicateComment
+	if parsed.IsApply {
+		skipDuplicate = cfg.SkipDuplicateApply
+	}
+	if !skipDuplicate || cfg.DryRun || !cfg.PR.IsNumber() {
+		return false
+	}
 	logE := logrus.WithFields(logrus.Fields{
 		"program": "tfcmt",
 	})
+	duplicated, err := g.isDuplicatedComment(ctx, cfg, command, parts)
+	if err != nil {
+		logE.WithError(err).Warn("check whether the comment is duplicated")
+		return false
+	}
+	if duplicated {
+		logE.Debug("skip posting a comment because it is identical to the latest comment")
+	}
+	return duplicated
+}
+
+// postComment posts the comment and handles old comments.
+// If the comment is too long, the whole result is uploaded to a Gist or the comment is truncated or split.
+// If the comment is identical to the latest comment, the comment isn't posted and true is returned
+func (g *NotifyService) postComment(ctx context.Context, cfg *Config, parsed *notifier.Result, command string, tplValue terraform.CommonTemplate, body string, render func(terraform.CommonTemplate) (string, error)) (bool, error) { //nolint:cyclop
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+
+	gistURL := ""
+	truncate := cfg.Truncate
+	if !cfg.DryRun && cfg.Gist.Enabled && len(body) > cfg.Gist.threshold() {
+		// A new Gist is created every time, so the compact comment is compared with the placeholder of the URL before the Gist is created
+		compact, err := compactBody(cfg, gistURLPlaceholder, parsed.IsPlan, tplValue)
+		if err != nil {
+			return false, err
+		}
+		if g.isDuplicated(ctx, cfg, parsed, command, []string{compact}) {
+			return true, nil
+		}
+		u, err := g.createGist(ctx, cfg, command, body, tplValue.CombinedOutput)
+		if err != nil {
+			// Fall back to truncating the comment
+			logE.WithError(err).Error("create a gist")
+			tplValue.ErrorMessages = append(tplValue.ErrorMessages, "create a gist: "+err.Error())
+			body, err = render(tplValue)
+			if err != nil {
+				return false, err
+			}
+			if !truncate.enabled() {
+				truncate.Strategy = TruncateStrategyHeadTail
+			}
+		} else {
+			// The compact comment also has the embedded metadata so that tfcmt can find it
+			body, err = compactBody(cfg, u, parsed.IsPlan, tplValue)
+			if err != nil {
+				return false, err
+			}
+			gistURL = u
+		}
+	}
 
-	embeddedComment, err := getEmbeddedComment(&cfg, param.CIName, isPlan)
+	embeddedComment, err := getGistEmbeddedComment(cfg, parsed.Param.CIName, command, gistURL)
 	if err != nil {
-		return result.ExitCode, err
+		return false, err
 	}
 	logE.WithFields(logrus.Fields{
 		"comment": embeddedComment,
 	}).Debug("embedded HTML comment")
-	// embed HTML tag to hide old comments
-	body += embeddedComment
+	if gistURL == "" && truncate.enabled() && len(body)+len(embeddedComment) > truncate.maxLength() {
+		body, err = shrinkBody(&truncate, body, truncate.maxLength()-len(embeddedComment), tplValue, render)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	var parts []string
+	if !cfg.DryRun && len(body)+len(embeddedComment) > maxCommentLength {
+		// The body is split into multiple comments because GitHub rejects too long comments
+		parts, err = splitParts(cfg, body, parsed.Param.CIName, command)
+		if err != nil {
+			return false, err
+		}
+	}
+	if gistURL == "" {
+		// The comment is compared after it is truncated or split so that it is compared with what was actually posted
+		compared := parts
+		if compared == nil {
+			compared = []string{body}
+		}
+		if g.isDuplicated(ctx, cfg, parsed, command, compared) {
+			return true, nil
+		}
+	}
+
+	oldComments, oldReviews := g.listOldCommentsAndReviews(ctx, cfg, command)
+
+	if parts != nil {
+		posted, err := g.postParts(ctx, cfg, parts, parsed.Param.CIName, command, parsed.HasDestroy)
+		if err != nil {
+			return false, err
+		}
+		g.client.posted = posted
+	} else {
+		// embed HTML tag to hide old comments
+		body += embeddedComment
+		posted, err := g.post(ctx, cfg, body, command, parsed.HasDestroy)
+		if err != nil {
+			return false, err
+		}
+		g.client.posted = posted
+	}
+	// If the comment is split, the whole body is written instead of each part
+	if err := notifier.WriteOutput(cfg.Output, body); err != nil {
+		return false, err
+	}
+	g.handleOldComments(ctx, oldComments)
+	g.handleOldReviews(ctx, cfg, oldReviews)
+	return false, nil
+}
+
+// listOldCommentsAndReviews lists old comments and reviews to hide before the comment is posted.
+// If it fails to list them, they aren't hidden
+func (g *NotifyService) listOldCommentsAndReviews(ctx context.Context, cfg *Config, command string) ([]*github.IssueComment, []*github.PullRequestReview) {
+	if cfg.DryRun || !cfg.PR.IsNumber() || cfg.OldComment.Action == "" || cfg.OldComment.Action == OldCommentActionKeep {
+		return nil, nil
+	}
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+	comments, err := g.listOldComments(ctx, cfg.PR.Number, command)
+	if err != nil {
+		logE.WithError(err).Warn("list old comments")
+	}
+	if !cfg.isReviewMode(command) {
+		return comments, nil
+	}
+	reviews, err := g.listMatchingReviews(ctx, cfg, command)
+	if err != nil {
+		logE.WithError(err).Warn("list old reviews")
+	}
+	return comments, reviews
+}
+
+var destroyCountPattern = regexp.MustCompile(`(\d+) to destroy`)
+
+// countDestroyedResources returns the number of resources to be destroyed.
+// Replaced resources are also counted.
+// The lists of resources take precedence over the summary "Plan: 0 to add, 0 to change, 1 to destroy.",
+// and the summary is used only if the lists are empty. If the result has no destroy, 0 is returned
+func countDestroyedResources(result terraform.ParseResult) int {
+	if !result.HasDestroy {
+		return 0
+	}
+	if cnt := len(result.DeletedResources) + len(result.ReplacedResources); cnt > 0 {
+		return cnt
+	}
+	if arr := destroyCountPattern.FindStringSubmatch(result.Result); len(arr) == 2 { //nolint:gomnd
+		if n, err := strconv.Atoi(arr[1]); err == nil {
+			return n
+		}
+	}
+	return 0
+}
 
-	if err := g.client.Comment.Post(ctx, body, PostOptions{
+// exitCode returns the exit code of Notify.
+// A non-zero exit code is returned if the plan would destroy too many resources, the security scan result contains critical findings,
+// or any policy fails
+func (g *NotifyService) exitCode(result terraform.ParseResult, securityScan *terraform.SecurityScan, policyResult terraform.PolicyResult) (int, error) {
+	if code, err := g.failOnDestroy(result); err != nil {
+		return code, err
+	}
+	cfg := g.client.Config
+	if !result.Succeeded() {
+		return result.ExitCode, nil
+	}
+	if cnt := securityScan.CountCritical(); cfg.FailOnCritical && cnt > 0 {
+		return apperr.ExitCodeCriticalFinding, fmt.Errorf("the security scan result contains %d critical findings", cnt)
+	}
+	if cnt := policyResult.CountFailures(); cfg.FailOnPolicyViolation && cnt > 0 {
+		return apperr.ExitCodePolicyViolation, fmt.Errorf("%d policies failed", cnt)
+	}
+	return result.ExitCode, nil
+}
+
+// findPRByBranch finds the pull request by the head branch if neither the commit SHA nor the pull request number is set
+func (g *NotifyService) findPRByBranch(ctx context.Context, cfg *Config) error {
+	if cfg.PR.Revision != "" || cfg.PR.IsNumber() || cfg.PR.Branch == "" {
+		return nil
+	}
+	prNumber, err := g.client.Commits.PRNumberByBranch(ctx, cfg.PR.Branch)
+	if err != nil {
+		return err
+	}
+	logrus.WithFields(logrus.Fields{
+		"program":   "tfcmt",
+		"branch":    cfg.PR.Branch,
+		"pr_number": prNumber,
+	}).Info("the pull request is found by the branch")
+	cfg.PR.Number = prNumber
+	return nil
+}
+
+// findPR finds the pull request of the apply by the merge commit
+func (g *NotifyService) findPR(ctx context.Context, cfg *Config, result terraform.ParseResult) error {
+	if cfg.PR.Revision == "" {
+		return nil
+	}
+	prNumber, err := g.client.Commits.MergedPRNumber(ctx, cfg.PR.Revision)
+	switch {
+	case err == nil:
+		cfg.PR.Number = prNumber
+		if cfg.ResultLabels.HasApplyLabelDefined() {
+			g.updateApplyLabels(ctx, prNumber, result)
+		}
+	case cfg.PR.IsNumber():
+		// the pull request number is given
+	default:
+		commits, err := g.client.Commits.List(ctx, cfg.PR.Revision)
+		if err != nil {
+			return err
+		}
+		lastRevision, _ := g.client.Commits.lastOne(commits, cfg.PR.Revision)
+		cfg.PR.Revision = lastRevision
+	}
+	return nil
+}
+
+// failOnDestroy returns a non-zero exit code if the plan would destroy more resources than the threshold.
+func (g *NotifyService) failOnDestroy(result terraform.ParseResult) (int, error) {
+	cfg := g.client.Config
+	if !cfg.FailOnDestroy || !result.Succeeded() {
+		return result.ExitCode, nil
+	}
+	if cnt := countDestroyedResources(result); cnt > cfg.DestroyThreshold {
+		return apperr.ExitCodeDestroy, fmt.Errorf("the plan would destroy %d resources (threshold: %d)", cnt, cfg.DestroyThreshold)
+	}
+	return result.ExitCode, nil
+}
+
+// post posts a comment. If the review mode is enabled, the plan result is posted as a pull request review.
+// Before the review is posted, old reviews requesting changes are dismissed.
+// Otherwise or if the target isn't a pull request, the result is posted as a regular comment.
+// In the dry run mode, the posted comment is nil
+func (g *NotifyService) post(ctx context.Context, cfg *Config, body, command string, hasDestroy bool) (*notifier.PostedComment, error) {
+	if cfg.DryRun {
+		return nil, notifier.WriteDryRunOutput(cfg.DryRunOutput, body)
+	}
+	if cfg.isReviewMode(command) {
+		event := cfg.Review.Event
+		if hasDestroy && cfg.Review.EventWhenDestroy != "" {
+			event = cfg.Review.EventWhenDestroy
+		}
+		g.dismissOldReviews(ctx, cfg, command)
+		return g.client.Comment.PostReview(ctx, body, cfg.PR.Number, event)
+	}
+	return g.client.Comment.Post(ctx, body, PostOptions{
 		Number:   cfg.PR.Number,
 		Revision: cfg.PR.Revision,
-	}); err != nil {
-		return result.ExitCode, err
+	})
+}
+
+// outputSnippetLines is the number of lines of the output shown to debug the parse error
+const outputSnippetLines = 20
+
+// headLines returns the first n lines of the text
+func headLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[:n]
 	}
-	return result.ExitCode, nil
+	return strings.Join(lines, "\n")
+}
+
+// tailLines returns the last n lines of the text
+func tailLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
 }
 
-func getEmbeddedComment(cfg *Config, ciName string, isPlan bool) (string, error) {
+// filterEnv returns environment variables whose names start with any of the prefixes.
+// To prevent secrets from leaking, no environment variable is exposed if no prefix is given.
+func filterEnv(environ, prefixes []string) map[string]string {
+	env := map[string]string{}
+	if len(prefixes) == 0 {
+		return env
+	}
+	for _, kv := range environ {
+		i := strings.Index(kv, "=")
+		if i == -1 {
+			continue
+		}
+		k, v := kv[:i], kv[i+1:]
+		for _, prefix := range prefixes {
+			if prefix != "" && strings.HasPrefix(k, prefix) {
+				env[k] = v
+				break
+			}
+		}
+	}
+	return env
+}
+
+func getEmbeddedComment(cfg *Config, ciName, command string) (string, error) {
+	return newEmbeddedComment(cfg, ciName, command, nil)
+}
+
+// getPartEmbeddedComment returns the embedded metadata of a part of the split comment.
+// Part and Parts are embedded only if the comment is split into multiple parts
+func getPartEmbeddedComment(cfg *Config, ciName, command string, part, parts int) (string, error) {
+	if parts > 1 {
+		return newEmbeddedComment(cfg, ciName, command, map[string]interface{}{
+			"Part":  part,
+			"Parts": parts,
+		})
+	}
+	return newEmbeddedComment(cfg, ciName, command, nil)
+}
+
+// getGistEmbeddedComment returns the embedded metadata of the comment linking to the Gist.
+// The URL of the Gist is embedded so that it can be ignored when the comment is compared with a new comment.
+// If the URL is empty, it isn't embedded
+func getGistEmbeddedComment(cfg *Config, ciName, command, gistURL string) (string, error) {
+	if gistURL == "" {
+		return getEmbeddedComment(cfg, ciName, command)
+	}
+	return newEmbeddedComment(cfg, ciName, command, map[string]interface{}{
+		"GistURL": gistURL,
+	})
+}
+
+// newEmbeddedComment returns the embedded metadata. extra is added to the metadata
+func newEmbeddedComment(cfg *Config, ciName, command string, extra map[string]interface{}) (string, error) {
 	vars := make(map[string]interface{}, len(cfg.EmbeddedVarNames))
 	for _, name := range cfg.EmbeddedVarNames {
 		vars[name] = cfg.Vars[name]
 	}
 
+	// Target is always embedded even if it is empty.
+	// Target is the anchor of the comment, and tfcmt never touches comments of other targets.
 	data := map[string]interface{}{
-		"Program":  "tfcmt",
+		"Program":  cfg.program(),
 		"Vars":     vars,
 		"SHA1":     cfg.PR.Revision,
 		"PRNumber": cfg.PR.Number,
+		"Link":     cfg.CI,
+		"Target":   cfg.Vars["target"],
+		"Command":  command,
 	}
-	if target := cfg.Vars["target"]; target != "" {
-		data["Target"] = target
+	if cfg.IdempotencyKey != "" {
+		data["IdempotencyKey"] = cfg.IdempotencyKey
 	}
-	if isPlan {
-		data["Command"] = "plan"
-	} else {
-		data["Command"] = "apply"
+	for k, v := range extra {
+		data[k] = v
 	}
-	if err := metadata.SetCIEnv(ciName, os.Getenv, data); err != nil {
+	if err := setCIEnv(ciName, os.Getenv, data); err != nil {
 		return "", err
 	}
 	embeddedComment, err := metadata.Convert(data)
@@ -133,52 +714,294 @@ func getEmbeddedComment(cfg *Config, ciName string, isPlan bool) (string, error)
 	return embeddedComment, nil
 }
 
-func (g *NotifyService) updateLabels(ctx context.Context, result terraform.ParseResult) []string { //nolint:cyclop
-	cfg := g.client.Config
+// isDuplicatedComment returns true if the comment is identical to the latest comment of the same command and target.
+// parts are the bodies to be posted without the embedded metadata. If the comment is split, all parts are compared.
+// In the review mode, the comment or the first part of the split comment is compared with the latest review
+func (g *NotifyService) isDuplicatedComment(ctx context.Context, cfg *Config, command string, parts []string) (bool, error) {
 	var (
-		labelToAdd string
-		labelColor string
+		bodies []string
+		metas  []*commentMetadata
 	)
+	firstPart := 1
+	if cfg.isReviewMode(command) {
+		body, meta, err := g.latestReview(ctx, cfg, command)
+		if err != nil || meta == nil {
+			return false, err
+		}
+		bodies = append(bodies, body)
+		metas = append(metas, meta)
+		if len(parts) == 1 {
+			return sameCommentParts(bodies, metas, parts, cfg.CI), nil
+		}
+		firstPart = 2
+	}
+	comments, err := g.client.Comment.List(ctx, cfg.PR.Number)
+	if err != nil {
+		return false, err
+	}
+	latest, latestMetas := findLatestCommentParts(comments, cfg.program(), command, cfg.Vars["target"], firstPart)
+	if latest == nil {
+		return false, nil
+	}
+	for _, comment := range latest {
+		bodies = append(bodies, comment.GetBody())
+	}
+	return sameCommentParts(bodies, append(metas, latestMetas...), parts, cfg.CI), nil
+}
 
-	switch {
-	case result.HasAddOrUpdateOnly:
-		labelToAdd = cfg.ResultLabels.AddOrUpdateLabel
-		labelColor = cfg.ResultLabels.AddOrUpdateLabelColor
-	case result.HasDestroy:
-		labelToAdd = cfg.ResultLabels.DestroyLabel
-		labelColor = cfg.ResultLabels.DestroyLabelColor
-	case result.HasNoChanges:
-		labelToAdd = cfg.ResultLabels.NoChangesLabel
-		labelColor = cfg.ResultLabels.NoChangesLabelColor
-	case result.HasPlanError:
-		labelToAdd = cfg.ResultLabels.PlanErrorLabel
-		labelColor = cfg.ResultLabels.PlanErrorLabelColor
+// sameCommentParts returns true if the posted comments are identical to the parts except for volatile parts such as the CI link
+func sameCommentParts(bodies []string, metas []*commentMetadata, parts []string, link string) bool {
+	if len(bodies) != len(parts) {
+		return false
 	}
+	for i, part := range parts {
+		if metas[i].normalize(bodies[i]) != normalizeCommentBody(part, link) {
+			return false
+		}
+	}
+	return true
+}
 
-	errMsgs := []string{}
+// isPostedWithIdempotencyKey returns true if a comment of the same command and target with the same idempotency key already exists
+func (g *NotifyService) isPostedWithIdempotencyKey(ctx context.Context, cfg *Config, command string) (bool, error) {
+	if cfg.isReviewMode(command) {
+		return g.isReviewPostedWithIdempotencyKey(ctx, cfg, command)
+	}
+	comments, err := g.client.Comment.List(ctx, cfg.PR.Number)
+	if err != nil {
+		return false, err
+	}
+	for _, comment := range comments {
+		if meta, ok := matchComment(comment, cfg.program(), command, cfg.Vars["target"]); ok && meta.IdempotencyKey == cfg.IdempotencyKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// labelWorkerCount is the maximum number of concurrent API calls to update labels
+const labelWorkerCount = 5
+
+func (g *NotifyService) updateLabels(ctx context.Context, number int, result terraform.ParseResult) []string {
+	cfg := g.client.Config
+	labelToAdd, labelColor := cfg.ResultLabels.LabelOf(result)
+	return g.swapResultLabel(ctx, number, cfg.ResultLabels.Name(labelToAdd), labelColor)
+}
+
+// updateApplyLabels replaces labels of the plan result with the label of the apply result.
+// If the label of the apply result isn't set, labels aren't changed.
+// Errors are only logged because the comment has already been rendered
+func (g *NotifyService) updateApplyLabels(ctx context.Context, number int, result terraform.ParseResult) {
+	cfg := g.client.Config
+	labelToAdd := cfg.ResultLabels.AppliedLabel
+	labelColor := cfg.ResultLabels.AppliedLabelColor
+	if result.HasApplyError || result.ExitCode != terraform.ExitPass {
+		labelToAdd = cfg.ResultLabels.ApplyFailedLabel
+		labelColor = cfg.ResultLabels.ApplyFailedLabelColor
+	}
+	if labelToAdd == "" {
+		return
+	}
+	g.swapResultLabel(ctx, number, cfg.ResultLabels.Name(labelToAdd), labelColor)
+}
+
+// updateDestroyLabels replaces labels of the plan result with the label of the destroy result.
+// If the label of the destroy result isn't set, labels aren't changed
+func (g *NotifyService) updateDestroyLabels(ctx context.Context, number int, result terraform.ParseResult) []string {
+	cfg := g.client.Config
+	labelToAdd := cfg.ResultLabels.DestroyedLabel
+	labelColor := cfg.ResultLabels.DestroyedLabelColor
+	if result.HasParseError || result.HasApplyError || result.ExitCode != terraform.ExitPass {
+		labelToAdd = cfg.ResultLabels.DestroyFailedLabel
+		labelColor = cfg.ResultLabels.DestroyFailedLabelColor
+	}
+	if labelToAdd == "" {
+		return nil
+	}
+	return g.swapResultLabel(ctx, number, cfg.ResultLabels.Name(labelToAdd), labelColor)
+}
+
+// updateValidateLabel adds the label if terraform validate fails and removes it if terraform validate succeeds.
+// The labels of the plan result aren't changed
+func (g *NotifyService) updateValidateLabel(ctx context.Context, number int, result terraform.ParseResult) []string {
+	labels := g.client.Config.ResultLabels
+	return g.toggleLabel(ctx, number, labels.ValidateFailedLabel, labels.ValidateFailedLabelColor, result.HasParseError || result.ExitCode != terraform.ExitPass)
+}
+
+// toggleLabel adds the label if add is true and removes it otherwise. The prefix of labels is prepended to the label
+func (g *NotifyService) toggleLabel(ctx context.Context, number int, label, color string, add bool) []string {
+	label = g.client.Config.ResultLabels.Name(label)
+	if add {
+		return g.addLabel(ctx, number, label, color, "")
+	}
+	resp, err := g.client.API.IssuesRemoveLabel(ctx, number, label)
+	// Ignore 404 errors, which are from the PR not having the label
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+			"label":   label,
+		}).WithError(err).Error("remove a label")
+		return []string{"remove a label " + label + ": " + err.Error()}
+	}
+	return nil
+}
+
+// swapResultLabel removes result labels from the pull request except for labelToAdd, and adds labelToAdd
+func (g *NotifyService) swapResultLabel(ctx context.Context, number int, labelToAdd, labelColor string) []string {
+	var (
+		errMsgs []string
+		mutex   sync.Mutex
+	)
+	addErrMsg := func(msg string) {
+		mutex.Lock()
+		errMsgs = append(errMsgs, msg)
+		mutex.Unlock()
+	}
 
 	logE := logrus.WithFields(logrus.Fields{
 		"program": "tfcmt",
 	})
 
-	currentLabelColor, err := g.removeResultLabels(ctx, labelToAdd)
+	currentLabelColor, labelsToRemove, labelsToKeep, err := g.listResultLabels(ctx, number, labelToAdd)
 	if err != nil {
-		msg := "remove labels: " + err.Error()
-		logE.WithError(err).Error("remove labels")
-		errMsgs = append(errMsgs, msg)
+		logE.WithError(err).WithField("operation", "list labels").Error("remove labels")
+		addErrMsg("remove labels: " + err.Error())
 	}
 
-	if labelToAdd == "" {
-		return errMsgs
+	if err == nil && len(labelsToRemove) >= minLabelsToReplace {
+		msgs, err := g.replaceLabels(ctx, number, labelsToRemove, labelsToKeep, labelToAdd, labelColor, currentLabelColor)
+		if err == nil {
+			return msgs
+		}
+		// Fall back to removing labels one by one
+		logE.WithError(err).Warn("replace labels")
+	}
+
+	var eg errgroup.Group
+	sem := make(chan struct{}, labelWorkerCount)
+	run := func(f func()) {
+		eg.Go(func() error {
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+			}()
+			f()
+			return nil
+		})
+	}
+
+	for _, label := range labelsToRemove {
+		label := label
+		run(func() {
+			resp, err := g.client.API.IssuesRemoveLabel(ctx, number, label)
+			// Ignore 404 errors, which are from the PR not having the label
+			if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+				logE.WithError(err).WithFields(logrus.Fields{
+					"label": label,
+				}).Error("remove labels")
+				addErrMsg("remove labels: " + err.Error())
+			}
+		})
+	}
+
+	if labelToAdd != "" {
+		run(func() {
+			for _, msg := range g.addLabel(ctx, number, labelToAdd, labelColor, currentLabelColor) {
+				addErrMsg(msg)
+			}
+		})
+	}
+
+	_ = eg.Wait()
+	// sort error messages because the order of API calls isn't deterministic
+	sort.Strings(errMsgs)
+	if errMsgs == nil {
+		return []string{}
+	}
+	return errMsgs
+}
+
+// minLabelsToReplace is the minimum number of labels to remove with a single API call to replace labels.
+// Replacing labels takes two API calls to list and replace labels, so fewer labels are removed one by one
+const minLabelsToReplace = 2
+
+// errLabelsChanged is returned if labels of the pull request are changed while tfcmt updates labels
+var errLabelsChanged = errors.New("labels of the pull request have been changed by others")
+
+// replaceLabels replaces labels of the pull request with labelsToKeep and labelToAdd by a single API call.
+// Replacing labels would remove labels which are added by others after labels are listed,
+// so labels are listed again right before they are replaced and errLabelsChanged is returned if they have been changed.
+// If an error is returned, the caller should fall back to removing labels one by one
+func (g *NotifyService) replaceLabels(ctx context.Context, number int, labelsToRemove, labelsToKeep []string, labelToAdd, labelColor, currentLabelColor string) ([]string, error) {
+	labels, err := g.listLabels(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if !sameLabels(labels, append(append([]string{}, labelsToRemove...), labelsToKeep...)) {
+		return nil, errLabelsChanged
 	}
+	labelNames := labelsToKeep
+	if labelToAdd != "" && !containsString(labelsToKeep, labelToAdd) {
+		labelNames = append(labelNames, labelToAdd)
+	}
+	replaced, _, err := g.client.API.IssuesReplaceLabels(ctx, number, labelNames)
+	if err != nil {
+		return nil, err
+	}
+	errMsgs := []string{}
+	if labelToAdd == "" || labelColor == "" || labelColor == currentLabelColor {
+		return errMsgs, nil
+	}
+	// set the color of label
+	for _, label := range replaced {
+		if labelToAdd == label.GetName() && label.GetColor() != labelColor {
+			if msg := g.updateLabelColor(ctx, labelToAdd, labelColor); msg != "" {
+				errMsgs = append(errMsgs, msg)
+			}
+		}
+	}
+	return errMsgs, nil
+}
+
+// sameLabels returns true if the names of labels are same as names regardless of the order
+func sameLabels(labels []*github.Label, names []string) bool {
+	if len(labels) != len(names) {
+		return false
+	}
+	m := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		m[name] = struct{}{}
+	}
+	for _, label := range labels {
+		if _, ok := m[label.GetName()]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(arr []string, s string) bool {
+	for _, a := range arr {
+		if a == s {
+			return true
+		}
+	}
+	return false
+}
+
+// addLabel adds a label and updates the color of the label
+func (g *NotifyService) addLabel(ctx context.Context, number int, labelToAdd, labelColor, currentLabelColor string) []string {
+	errMsgs := []string{}
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+		"label":   labelToAdd,
+	})
 
 	if currentLabelColor == "" {
-		labels, _, err := g.client.API.IssuesAddLabels(ctx, cfg.PR.Number, []string{labelToAdd})
+		labels, _, err := g.client.API.IssuesAddLabels(ctx, number, []string{labelToAdd})
 		if err != nil {
 			msg := "add a label " + labelToAdd + ": " + err.Error()
-			logE.WithError(err).WithFields(logrus.Fields{
-				"label": labelToAdd,
-			}).Error("add a label")
+			logE.WithError(err).Error("add a label")
 			errMsgs = append(errMsgs, msg)
 		}
 		if labelColor != "" {
@@ -186,12 +1009,7 @@ func (g *NotifyService) updateLabels(ctx context.Context, result terraform.Parse
 			for _, label := range labels {
 				if labelToAdd == label.GetName() {
 					if label.GetColor() != labelColor {
-						if _, _, err := g.client.API.IssuesUpdateLabel(ctx, labelToAdd, labelColor); err != nil {
-							msg := "update a label color (name: " + labelToAdd + ", color: " + labelColor + "): " + err.Error()
-							logE.WithError(err).WithFields(logrus.Fields{
-								"label": labelToAdd,
-								"color": labelColor,
-							}).Error("update a label color")
+						if msg := g.updateLabelColor(ctx, labelToAdd, labelColor); msg != "" {
 							errMsgs = append(errMsgs, msg)
 						}
 					}
@@ -200,40 +1018,91 @@ func (g *NotifyService) updateLabels(ctx context.Context, result terraform.Parse
 		}
 	} else if labelColor != "" && labelColor != currentLabelColor {
 		// set the color of label
-		if _, _, err := g.client.API.IssuesUpdateLabel(ctx, labelToAdd, labelColor); err != nil {
-			msg := "update a label color (name: " + labelToAdd + ", color: " + labelColor + "): " + err.Error()
-			logE.WithError(err).WithFields(logrus.Fields{
-				"label": labelToAdd,
-				"color": labelColor,
-			}).Error("update a label color")
+		if msg := g.updateLabelColor(ctx, labelToAdd, labelColor); msg != "" {
 			errMsgs = append(errMsgs, msg)
 		}
 	}
 	return errMsgs
 }
 
-func (g *NotifyService) removeResultLabels(ctx context.Context, label string) (string, error) {
+func (g *NotifyService) updateLabelColor(ctx context.Context, label, color string) string {
+	if _, _, err := g.client.API.IssuesUpdateLabel(ctx, label, color); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+			"label":   label,
+			"color":   color,
+		}).WithError(err).Error("update a label color")
+		return "update a label color (name: " + label + ", color: " + color + "): " + err.Error()
+	}
+	return ""
+}
+
+// listResultLabels returns the current color of the label to add, result labels to remove, and the other labels
+func (g *NotifyService) listResultLabels(ctx context.Context, number int, label string) (string, []string, []string, error) {
 	cfg := g.client.Config
-	labels, _, err := g.client.API.IssuesListLabels(ctx, cfg.PR.Number, nil)
+	labels, err := g.listLabels(ctx, number)
 	if err != nil {
-		return "", err
+		return "", nil, nil, err
 	}
 
 	labelColor := ""
+	labelsToRemove := []string{}
+	labelsToKeep := []string{}
 	for _, l := range labels {
 		labelText := l.GetName()
 		if labelText == label {
 			labelColor = l.GetColor()
+			labelsToKeep = append(labelsToKeep, labelText)
 			continue
 		}
 		if cfg.ResultLabels.IsResultLabel(labelText) {
-			resp, err := g.client.API.IssuesRemoveLabel(ctx, cfg.PR.Number, labelText)
-			// Ignore 404 errors, which are from the PR not having the label
-			if err != nil && resp.StatusCode != http.StatusNotFound {
-				return labelColor, err
+			if cfg.ResultLabels.IsPreserved(labelText) {
+				logrus.WithFields(logrus.Fields{
+					"program": "tfcmt",
+					"label":   labelText,
+				}).Info("leave the result label in place because it is preserved")
+				labelsToKeep = append(labelsToKeep, labelText)
+				continue
 			}
+			labelsToRemove = append(labelsToRemove, labelText)
+			continue
 		}
+		labelsToKeep = append(labelsToKeep, labelText)
 	}
 
-	return labelColor, nil
+	return labelColor, labelsToRemove, labelsToKeep, nil
+}
+
+// listLabels returns all labels of the pull request.
+// All pages are fetched because labels which aren't fetched would be removed by replacing labels
+func (g *NotifyService) listLabels(ctx context.Context, number int) ([]*github.Label, error) {
+	opt := &github.ListOptions{
+		PerPage: 100, //nolint:gomnd
+	}
+	var labels []*github.Label
+	for {
+		arr, resp, err := g.client.API.IssuesListLabels(ctx, number, opt)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, arr...)
+		if resp == nil || resp.NextPage == 0 {
+			return labels, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
+// addSummary renders the summary of the result and puts it at the top or the bottom of the body
+func addSummary(body, position string, tplValue terraform.CommonTemplate) (string, error) {
+	tpl := terraform.NewSummaryTemplate()
+	tpl.SetValue(tplValue)
+	summary, err := tpl.Execute()
+	if err != nil {
+		return "", fmt.Errorf("render the summary: %w", err)
+	}
+	if position == SummaryPositionTop {
+		return summary + "\n\n" + strings.TrimLeft(body, "\n"), nil
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + summary, nil
 }
@@ -2,14 +2,27 @@ package github
 
 import (
 	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
 )
 
 func TestNotifyNotify(t *testing.T) {
 	t.Parallel()
+	ignoringParser := terraform.NewPlanParser()
+	ignoringParser.IgnoredResources = []string{"null_resource"}
 	testCases := []struct {
 		name      string
 		config    Config
@@ -246,6 +259,142 @@ func TestNotifyNotify(t *testing.T) {
 			ok:       true,
 			exitCode: 0,
 		},
+		{
+			name: "case 11",
+			// fail because the plan contains destroy
+			config: Config{
+				Token: "token",
+				Owner: "owner",
+				Repo:  "repo",
+				PR: PullRequest{
+					Revision: "",
+					Number:   1,
+				},
+				Parser:             terraform.NewPlanParser(),
+				Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+				ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanTemplate),
+				FailOnDestroy:      true,
+			},
+			paramExec: notifier.ParamExec{
+				CombinedOutput: "Plan: 1 to add, 0 to change, 1 to destroy.",
+				ExitCode:       2,
+			},
+			ok:       false,
+			exitCode: 3,
+		},
+		{
+			name: "case 12",
+			// the number of destroyed resources doesn't exceed the threshold
+			config: Config{
+				Token: "token",
+				Owner: "owner",
+				Repo:  "repo",
+				PR: PullRequest{
+					Revision: "",
+					Number:   1,
+				},
+				Parser:             terraform.NewPlanParser(),
+				Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+				ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanTemplate),
+				FailOnDestroy:      true,
+				DestroyThreshold:   1,
+			},
+			paramExec: notifier.ParamExec{
+				CombinedOutput: "Plan: 1 to add, 0 to change, 1 to destroy.",
+				ExitCode:       2,
+			},
+			ok:       true,
+			exitCode: 2,
+		},
+		{
+			name: "ignored resources aren't counted as destroyed resources",
+			config: Config{
+				Token: "token",
+				Owner: "owner",
+				Repo:  "repo",
+				PR: PullRequest{
+					Revision: "",
+					Number:   1,
+				},
+				Parser:             ignoringParser,
+				Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+				ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanTemplate),
+				FailOnDestroy:      true,
+				DestroyThreshold:   1,
+			},
+			paramExec: notifier.ParamExec{
+				CombinedOutput: `Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1" -> null
+    }
+
+  # aws_instance.web will be destroyed
+  - resource "aws_instance" "web" {
+      - id = "i-1" -> null
+    }
+
+Plan: 0 to add, 0 to change, 2 to destroy.`,
+				ExitCode: 2,
+			},
+			ok:       true,
+			exitCode: 2,
+		},
+		{
+			name: "fail if not ignored resources are destroyed",
+			config: Config{
+				Token: "token",
+				Owner: "owner",
+				Repo:  "repo",
+				PR: PullRequest{
+					Revision: "",
+					Number:   1,
+				},
+				Parser:             ignoringParser,
+				Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+				ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanTemplate),
+				FailOnDestroy:      true,
+			},
+			paramExec: notifier.ParamExec{
+				CombinedOutput: `Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1" -> null
+    }
+
+  # aws_instance.web will be destroyed
+  - resource "aws_instance" "web" {
+      - id = "i-1" -> null
+    }
+
+Plan: 0 to add, 0 to change, 2 to destroy.`,
+				ExitCode: 2,
+			},
+			ok:       false,
+			exitCode: 3,
+		},
+		{
+			name: "find the pull request by the branch",
+			config: Config{
+				Token: "token",
+				Owner: "owner",
+				Repo:  "repo",
+				PR: PullRequest{
+					Branch: "feature",
+				},
+				Parser:             terraform.NewApplyParser(),
+				Template:           terraform.NewApplyTemplate(terraform.DefaultApplyTemplate),
+				ParseErrorTemplate: terraform.NewApplyParseErrorTemplate(terraform.DefaultApplyTemplate),
+			},
+			paramExec: notifier.ParamExec{
+				CombinedOutput: "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+				ExitCode:       0,
+			},
+			ok:       true,
+			exitCode: 0,
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -271,3 +420,2161 @@ func TestNotifyNotify(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEmbeddedComment(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		target string
+		find   string
+		found  bool
+	}{
+		{
+			name:  "no target",
+			found: true,
+		},
+		{
+			name:   "same target",
+			target: "foo",
+			find:   "foo",
+			found:  true,
+		},
+		{
+			name:   "other target",
+			target: "foo",
+			find:   "bar",
+			found:  false,
+		},
+		{
+			name:   "target and no target",
+			target: "foo",
+			find:   "",
+			found:  false,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Vars = map[string]string{
+				"target": testCase.target,
+			}
+			body, err := getEmbeddedComment(&cfg, "", "plan")
+			if err != nil {
+				t.Fatal(err)
+			}
+			comment, _ := findLatestComment([]*github.IssueComment{
+				{Body: &body},
+			}, "tfcmt", "plan", testCase.find)
+			if (comment != nil) != testCase.found {
+				t.Errorf("got %v but want %v", comment != nil, testCase.found)
+			}
+		})
+	}
+}
+
+func TestCountDestroyedResources(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		result terraform.ParseResult
+		exp    int
+	}{
+		{
+			name: "no destroy",
+			result: terraform.ParseResult{
+				Result: "Plan: 1 to add, 0 to change, 0 to destroy.",
+			},
+			exp: 0,
+		},
+		{
+			name: "summary",
+			result: terraform.ParseResult{
+				Result:     "Plan: 1 to add, 0 to change, 2 to destroy.",
+				HasDestroy: true,
+			},
+			exp: 2,
+		},
+		{
+			name: "deleted and replaced resources",
+			result: terraform.ParseResult{
+				Result:            "Plan: 1 to add, 0 to change, 2 to destroy.",
+				HasDestroy:        true,
+				DeletedResources:  []string{"null_resource.foo"},
+				ReplacedResources: []string{"null_resource.bar"},
+			},
+			exp: 2,
+		},
+		{
+			name: "the lists of resources take precedence over the summary",
+			result: terraform.ParseResult{
+				Result:           "Plan: 1 to add, 0 to change, 3 to destroy.",
+				HasDestroy:       true,
+				DeletedResources: []string{"null_resource.foo"},
+			},
+			exp: 1,
+		},
+		{
+			name: "the summary is ignored if the result has no destroy",
+			result: terraform.ParseResult{
+				Result:     "Plan: 1 to add, 0 to change, 2 to destroy.",
+				HasDestroy: false,
+			},
+			exp: 0,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if cnt := countDestroyedResources(testCase.result); cnt != testCase.exp {
+				t.Errorf("got %d but want %d", cnt, testCase.exp)
+			}
+		})
+	}
+}
+
+func TestNotifyPost(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		review     Review
+		number     int
+		hasDestroy bool
+		exp        string
+	}{
+		{
+			name:   "comment",
+			number: 1,
+			exp:    "comment",
+		},
+		{
+			name:   "review",
+			review: Review{Enabled: true},
+			number: 1,
+			exp:    "review:COMMENT",
+		},
+		{
+			name:       "review with destroy",
+			review:     Review{Enabled: true, EventWhenDestroy: "REQUEST_CHANGES"},
+			number:     1,
+			hasDestroy: true,
+			exp:        "review:REQUEST_CHANGES",
+		},
+		{
+			name:   "not pull request",
+			review: Review{Enabled: true},
+			number: 0,
+			exp:    "commit",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Review = testCase.review
+			cfg.PR.Number = testCase.number
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var posted string
+			api := newFakeAPI()
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				posted = "comment"
+				return comment, nil, nil
+			}
+			api.FakeRepositoriesCreateComment = func(ctx context.Context, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error) {
+				posted = "commit"
+				return comment, nil, nil
+			}
+			api.FakePullRequestsCreateReview = func(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+				posted = "review:" + review.GetEvent()
+				return &github.PullRequestReview{}, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.post(context.Background(), &cfg, "body", terraform.CommandPlan, testCase.hasDestroy); err != nil {
+				t.Fatal(err)
+			}
+			if posted != testCase.exp {
+				t.Errorf("got %q but want %q", posted, testCase.exp)
+			}
+		})
+	}
+}
+
+func TestNotifyDryRun(t *testing.T) {
+	t.Parallel()
+	output := filepath.Join(t.TempDir(), "comment.md")
+	cfg := newFakeConfig()
+	cfg.Token = ""
+	cfg.DryRun = true
+	cfg.DryRunOutput = output
+	cfg.ResultLabels = ResultLabels{
+		DestroyLabel: "destroy",
+	}
+	cfg.SkipDuplicateComment = true
+	cfg.OldComment = OldComment{Action: OldCommentActionDelete}
+	cfg.FailOnDestroy = true
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	called := false
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		called = true
+		return comment, nil, nil
+	}
+	api.FakeIssuesListComments = func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+		called = true
+		return nil, nil, nil
+	}
+	api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+		called = true
+		return nil, nil, nil
+	}
+	api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		called = true
+		return nil, nil, nil
+	}
+	client.API = &api
+	exitCode, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Plan: 0 to add, 0 to change, 1 to destroy.",
+		ExitCode:       2,
+	})
+	if err == nil {
+		t.Error("an error should be returned because the plan would destroy resources")
+	}
+	if exitCode != apperr.ExitCodeDestroy {
+		t.Errorf("got %d but want %d", exitCode, apperr.ExitCodeDestroy)
+	}
+	if called {
+		t.Error("GitHub API must not be called in the dry run mode")
+	}
+	b, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "## Plan Result") {
+		t.Errorf("the rendered comment isn't written: %s", string(b))
+	}
+}
+
+func TestNotifyOutput(t *testing.T) {
+	t.Parallel()
+	output := filepath.Join(t.TempDir(), "comment.md")
+	cfg := newFakeConfig()
+	cfg.Output = output
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	posted := ""
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		posted = comment.GetBody()
+		return comment, nil, nil
+	}
+	client.API = &api
+	if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+		ExitCode:       2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(posted+"\n", string(b)); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNotifyPostedComment(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		dryRun bool
+		exp    *notifier.PostedComment
+	}{
+		{
+			name: "posted",
+			exp: &notifier.PostedComment{
+				ID:  10,
+				URL: "https://github.com/owner/repo/pull/1#issuecomment-10",
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.DryRun = testCase.dryRun
+			cfg.DryRunOutput = filepath.Join(t.TempDir(), "comment.md")
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			api := newFakeAPI()
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				comment.ID = github.Int64(10)
+				comment.HTMLURL = github.String("https://github.com/owner/repo/pull/1#issuecomment-10")
+				return comment, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+				ExitCode:       2,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.exp, client.Notify.PostedComment()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNotifyFailOnCritical(t *testing.T) {
+	t.Parallel()
+	output := filepath.Join(t.TempDir(), "comment.md")
+	cfg := newFakeConfig()
+	cfg.Token = ""
+	cfg.DryRun = true
+	cfg.DryRunOutput = output
+	cfg.FailOnCritical = true
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exitCode, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+		ExitCode:       2,
+		SecurityScan:   `{"Results": [{"Target": "main.tf", "Misconfigurations": [{"ID": "AVD-AWS-0086", "Message": "No public access block", "Severity": "CRITICAL", "Status": "FAIL"}]}]}`,
+	})
+	if err == nil {
+		t.Error("an error should be returned because the security scan result contains critical findings")
+	}
+	if exitCode != apperr.ExitCodeCriticalFinding {
+		t.Errorf("got %d but want %d", exitCode, apperr.ExitCodeCriticalFinding)
+	}
+	b, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "### Security findings") {
+		t.Errorf("the security findings aren't rendered: %s", string(b))
+	}
+}
+
+func TestNotifyFailOnPolicyViolation(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.FailOnPolicyViolation = true
+	cfg.ResultLabels = ResultLabels{
+		PolicyViolationLabel:      "policy-violation",
+		PolicyViolationLabelColor: "b60205",
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var added []string
+	api := newFakeAPI()
+	api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		added = append(added, labels...)
+		return nil, nil, nil
+	}
+	client.API = &api
+	exitCode, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+		ExitCode:       2,
+		PolicyResult:   `[{"namespace": "main", "failures": [{"msg": "aws_s3_bucket.foo must have tags"}]}]`,
+	})
+	if err == nil {
+		t.Error("an error should be returned because the policy fails")
+	}
+	if exitCode != apperr.ExitCodePolicyViolation {
+		t.Errorf("got %d but want %d", exitCode, apperr.ExitCodePolicyViolation)
+	}
+	if diff := cmp.Diff([]string{"policy-violation"}, added); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNotifyParseError(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.ParseErrorTemplate = terraform.NewPlanParseErrorTemplate("")
+	cfg.UseRawOutput = true
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body string
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		body = comment.GetBody()
+		return comment, nil, nil
+	}
+	client.API = &api
+	if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Initializing the backend...\nsegmentation fault",
+		ExitCode:       1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`:warning: cannot parse plan result: no line starts with "Plan: ", "No changes.", or "Error: "`,
+		"The last lines of the output:\n\n```hcl\nInitializing the backend...\nsegmentation fault\n```",
+	} {
+		if !strings.Contains(body, s) {
+			t.Errorf("the comment should contain %q: %s", s, body)
+		}
+	}
+}
+
+func TestNotifyTargetPRNumber(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.TargetPRNumber = 10
+	cfg.Parser = terraform.NewApplyParser()
+	cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var number int
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, n int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		number = n
+		return comment, nil, nil
+	}
+	api.FakeRepositoriesGetCommit = func(ctx context.Context, sha string) (*github.RepositoryCommit, *github.Response, error) {
+		t.Error("the pull request must not be detected if the target pull request number is given")
+		return nil, nil, errors.New("not found")
+	}
+	client.API = &api
+	if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if number != 10 {
+		t.Errorf("the comment should be posted to the target pull request: got %d", number)
+	}
+}
+
+func TestNotifyFindPRByBranch(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		parser   terraform.Parser
+		template *terraform.Template
+		output   string
+	}{
+		{
+			name:     "plan",
+			parser:   terraform.NewPlanParser(),
+			template: terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+			output:   "Plan: 1 to add, 0 to change, 0 to destroy.",
+		},
+		{
+			name:     "apply",
+			parser:   terraform.NewApplyParser(),
+			template: terraform.NewApplyTemplate(terraform.DefaultApplyTemplate),
+			output:   "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.PR = PullRequest{Branch: "feature"}
+			cfg.Parser = testCase.parser
+			cfg.Template = testCase.template
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var number int
+			api := newFakeAPI()
+			api.FakeIssuesCreateComment = func(ctx context.Context, n int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				number = n
+				return comment, nil, nil
+			}
+			api.FakeRepositoriesGetCommit = func(ctx context.Context, sha string) (*github.RepositoryCommit, *github.Response, error) {
+				t.Errorf("the merge commit must not be looked up if the commit SHA is empty: %q", sha)
+				return nil, nil, errors.New("not found")
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if number != 1 {
+				t.Errorf("the comment should be posted to the pull request of the branch: got %d", number)
+			}
+		})
+	}
+}
+
+func TestNotifyFindPRByBranchLabels(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		state   string
+		labels  []string
+		comment bool
+	}{
+		{
+			name:    "open",
+			state:   "open",
+			labels:  []string{"add-or-update"},
+			comment: true,
+		},
+		{
+			name:  "closed",
+			state: "closed",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.PR = PullRequest{Branch: "feature"}
+			cfg.ClosedPRAction = ClosedPRActionSkip
+			cfg.ResultLabels = ResultLabels{
+				AddOrUpdateLabel: "add-or-update",
+				DestroyLabel:     "destroy",
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				labels  []string
+				comment bool
+			)
+			api := newFakeAPI()
+			api.FakePullRequestsGet = func(ctx context.Context, n int) (*github.PullRequest, *github.Response, error) {
+				return &github.PullRequest{
+					Number: github.Int(n),
+					State:  github.String(testCase.state),
+				}, nil, nil
+			}
+			api.FakeIssuesAddLabels = func(ctx context.Context, n int, l []string) ([]*github.Label, *github.Response, error) {
+				if n != 1 {
+					t.Errorf("the label should be added to the pull request of the branch: got %d", n)
+				}
+				labels = append(labels, l...)
+				return nil, nil, nil
+			}
+			api.FakeIssuesCreateComment = func(ctx context.Context, n int, c *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				comment = true
+				return c, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.labels, labels); diff != "" {
+				t.Error(diff)
+			}
+			if comment != testCase.comment {
+				t.Errorf("the comment is posted: got %v, wanted %v", comment, testCase.comment)
+			}
+		})
+	}
+}
+
+func TestNotifyClosedPR(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		action   string
+		state    string
+		merged   bool
+		number   int
+		revision string
+	}{
+		{
+			name:   "open",
+			action: ClosedPRActionSkip,
+			state:  "open",
+			number: 1,
+		},
+		{
+			name:   "post",
+			action: ClosedPRActionPost,
+			state:  "closed",
+			merged: true,
+			number: 1,
+		},
+		{
+			name:   "skip",
+			action: ClosedPRActionSkip,
+			state:  "closed",
+			merged: true,
+		},
+		{
+			name:     "merge commit",
+			action:   ClosedPRActionMergeCommit,
+			state:    "closed",
+			merged:   true,
+			revision: "merged",
+		},
+		{
+			name:   "closed without being merged",
+			action: ClosedPRActionMergeCommit,
+			state:  "closed",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.ClosedPRAction = testCase.action
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				number   int
+				revision string
+			)
+			api := newFakeAPI()
+			api.FakePullRequestsGet = func(ctx context.Context, n int) (*github.PullRequest, *github.Response, error) {
+				if testCase.action == ClosedPRActionPost {
+					t.Error("the pull request should not be gotten")
+				}
+				return &github.PullRequest{
+					Number:         github.Int(n),
+					State:          github.String(testCase.state),
+					Merged:         github.Bool(testCase.merged),
+					MergeCommitSHA: github.String("merged"),
+				}, nil, nil
+			}
+			api.FakeIssuesCreateComment = func(ctx context.Context, n int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				number = n
+				return comment, nil, nil
+			}
+			api.FakeRepositoriesCreateComment = func(ctx context.Context, sha string, comment *github.RepositoryComment) (*github.RepositoryComment, *github.Response, error) {
+				revision = sha
+				return comment, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if number != testCase.number {
+				t.Errorf("the pull request number: got %d, wanted %d", number, testCase.number)
+			}
+			if revision != testCase.revision {
+				t.Errorf("the commit: got %q, wanted %q", revision, testCase.revision)
+			}
+		})
+	}
+}
+
+func TestNotifyGist(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		gistErr  error
+		template string
+		parser   terraform.Parser
+		output   string
+		files    []string
+		exp      []string
+		notExp   []string
+	}{
+		{
+			name: "gist",
+			exp: []string{
+				"[the Gist](https://gist.github.com/octocat/aa5a315d61ae9438b18d)",
+				"* Create: 1",
+				"<!-- github-comment: ",
+			},
+			notExp: []string{"Details (Click me)"},
+		},
+		{
+			name:    "fall back to the whole comment",
+			gistErr: errors.New("forbidden"),
+			exp: []string{
+				"Details (Click me)",
+				"create a gist: forbidden",
+			},
+			notExp: []string{"the Gist"},
+		},
+		{
+			name:    "fall back to the truncated comment",
+			gistErr: errors.New("forbidden"),
+			output: `Terraform will perform the following actions:
+
+  # null_resource.foo will be created
+  + resource "null_resource" "foo" {
+      + id    = (known after apply)
+      + value = "` + strings.Repeat("a", maxCommentLength) + `"
+    }
+
+Plan: 1 to add, 0 to change, 0 to destroy.`,
+			exp: []string{
+				"create a gist: forbidden",
+				":scissors: The comment is too long",
+			},
+			notExp: []string{"**Part 1/"},
+		},
+		{
+			name: "destroy warning",
+			output: `Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1" -> null
+    }
+
+Plan: 0 to add, 0 to change, 1 to destroy.`,
+			exp: []string{
+				"Resource Deletion will happen",
+				"* Delete: 1",
+			},
+		},
+		{
+			name:     "destroy warning in the custom template",
+			template: `{{template "gist_summary" .}}`,
+			output: `Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1" -> null
+    }
+
+Plan: 0 to add, 0 to change, 1 to destroy.`,
+			exp: []string{
+				"Resource Deletion will happen",
+				"[the Gist](https://gist.github.com/octocat/aa5a315d61ae9438b18d)",
+			},
+		},
+		{
+			name:     "custom template",
+			template: "The result is uploaded to {{.GistURL}}",
+			exp: []string{
+				"The result is uploaded to https://gist.github.com/octocat/aa5a315d61ae9438b18d",
+				"<!-- github-comment: ",
+			},
+			notExp: []string{"* Create: 1"},
+		},
+		{
+			name:   "destroy",
+			parser: terraform.NewDestroyParser(),
+			output: `null_resource.foo: Destroying... [id=1]
+null_resource.foo: Destruction complete after 0s
+null_resource.bar: Destroying... [id=2]
+null_resource.bar: Destruction complete after 0s
+
+Destroy complete! Resources: 2 destroyed.`,
+			files: []string{"terraform-destroy.log", "tfcmt-destroy.md"},
+			exp: []string{
+				"[the Gist](https://gist.github.com/octocat/aa5a315d61ae9438b18d)",
+				`"Command":"destroy"`,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Gist = Gist{
+				Enabled:      true,
+				Threshold:    100,
+				PlanTemplate: testCase.template,
+			}
+			if testCase.parser != nil {
+				cfg.Parser = testCase.parser
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				body     string
+				files    []string
+				comments int
+			)
+			api := newFakeAPI()
+			api.FakeGistsCreate = func(ctx context.Context, gist *github.Gist) (*github.Gist, *github.Response, error) {
+				for name := range gist.Files {
+					files = append(files, string(name))
+				}
+				if testCase.gistErr != nil {
+					return nil, nil, testCase.gistErr
+				}
+				return &github.Gist{
+					HTMLURL: github.String("https://gist.github.com/octocat/aa5a315d61ae9438b18d"),
+				}, nil, nil
+			}
+			api.FakeIssuesCreateComment = func(ctx context.Context, n int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				body = comment.GetBody()
+				comments++
+				return comment, nil, nil
+			}
+			client.API = &api
+			output := testCase.output
+			if output == "" {
+				output = `Terraform will perform the following actions:
+
+  # null_resource.foo will be created
+  + resource "null_resource" "foo" {
+      + id = (known after apply)
+    }
+
+Plan: 1 to add, 0 to change, 0 to destroy.`
+			}
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: output,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if comments != 1 {
+				t.Errorf("a single comment should be posted: got %d", comments)
+			}
+			sort.Strings(files)
+			expFiles := testCase.files
+			if expFiles == nil {
+				expFiles = []string{"terraform-plan.log", "tfcmt-plan.md"}
+			}
+			if diff := cmp.Diff(expFiles, files); diff != "" {
+				t.Error(diff)
+			}
+			for _, s := range testCase.exp {
+				if !strings.Contains(body, s) {
+					t.Errorf("the comment should contain %q: %s", s, body)
+				}
+			}
+			for _, s := range testCase.notExp {
+				if strings.Contains(body, s) {
+					t.Errorf("the comment should not contain %q: %s", s, body)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyPostTriggers(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		output   string
+		triggers []string
+		posted   bool
+		deleted  bool
+	}{
+		{
+			name:     "add only",
+			output:   "Plan: 1 to add, 0 to change, 0 to destroy.",
+			triggers: []string{PostTriggerPlanError, PostTriggerDestroy, PostTriggerParseError},
+			deleted:  true,
+		},
+		{
+			name:     "destroy",
+			output:   "Plan: 0 to add, 0 to change, 1 to destroy.",
+			triggers: []string{PostTriggerPlanError, PostTriggerDestroy, PostTriggerParseError},
+			posted:   true,
+		},
+		{
+			name:     "parse error",
+			output:   "foo",
+			triggers: []string{PostTriggerPlanError, PostTriggerDestroy, PostTriggerParseError},
+			posted:   true,
+		},
+		{
+			name:     "add only with the trigger add_or_update",
+			output:   "Plan: 1 to add, 0 to change, 0 to destroy.",
+			triggers: []string{PostTriggerAddOrUpdate},
+			posted:   true,
+		},
+		{
+			name:   "no trigger",
+			output: "Plan: 1 to add, 0 to change, 0 to destroy.",
+			posted: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.PostTriggers = testCase.triggers
+			cfg.OldComment = OldComment{Action: OldCommentActionDelete}
+			cfg.ResultLabels = ResultLabels{
+				AddOrUpdateLabel: "add-or-update",
+				DestroyLabel:     "destroy",
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				posted  bool
+				deleted bool
+				labeled bool
+			)
+			api := newFakeAPI()
+			api.FakeIssuesCreateComment = func(ctx context.Context, n int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				posted = true
+				return comment, nil, nil
+			}
+			api.FakeIssuesListComments = func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+				return []*github.IssueComment{
+					{
+						ID:   github.Int64(1),
+						Body: github.String("foo\n<!-- github-comment: {\"Program\":\"tfcmt\",\"Command\":\"plan\"} -->"),
+					},
+				}, nil, nil
+			}
+			api.FakeIssuesDeleteComment = func(ctx context.Context, commentID int64) (*github.Response, error) {
+				deleted = true
+				return nil, nil
+			}
+			api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+				labeled = true
+				return nil, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if posted != testCase.posted {
+				t.Errorf("posted: got %v, wanted %v", posted, testCase.posted)
+			}
+			if !posted && deleted != testCase.deleted {
+				t.Errorf("the old comment should be deleted")
+			}
+			if testCase.name != "parse error" && !labeled {
+				t.Error("labels should be updated even if the comment isn't posted")
+			}
+		})
+	}
+}
+
+func TestNotifySummaryPosition(t *testing.T) {
+	t.Parallel()
+	output := `Terraform will perform the following actions:
+
+  # null_resource.foo will be created
+  + resource "null_resource" "foo" {}
+
+Plan: 1 to add, 0 to change, 0 to destroy.`
+	summary := "**Summary:** 1 to add, 0 to change, 0 to destroy, 0 to replace (label: `tfcmt/add-or-update`)"
+	testCases := []struct {
+		name     string
+		position string
+		prefix   string
+		suffix   string
+	}{
+		{
+			name:   "no summary",
+			prefix: "## Plan Result\n\nPlan: 1 to add",
+		},
+		{
+			name:     "top",
+			position: SummaryPositionTop,
+			prefix:   summary + "\n\n## Plan Result",
+		},
+		{
+			name:     "bottom",
+			position: SummaryPositionBottom,
+			prefix:   "## Plan Result",
+			suffix:   "\n\n" + summary,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Template = terraform.NewPlanTemplate("## Plan Result\n\n{{.Result}}\n")
+			cfg.SummaryPosition = testCase.position
+			cfg.ResultLabels = ResultLabels{
+				AddOrUpdateLabel: "add-or-update",
+				Prefix:           "tfcmt/",
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body string
+			api := newFakeAPI()
+			api.FakeIssuesCreateComment = func(ctx context.Context, n int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				body = comment.GetBody()
+				return comment, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: output,
+				ExitCode:       2,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(body, testCase.prefix) {
+				t.Errorf("the comment should start with %q: %s", testCase.prefix, body)
+			}
+			// the embedded metadata is appended after the body
+			if testCase.suffix != "" && !strings.Contains(body, testCase.suffix+"\n") {
+				t.Errorf("the summary should be at the bottom %q: %s", testCase.suffix, body)
+			}
+			if testCase.position == "" && strings.Contains(body, "**Summary:**") {
+				t.Errorf("the summary shouldn't be rendered: %s", body)
+			}
+		})
+	}
+}
+
+func TestNotifyIdempotencyKey(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		key    string
+		posted bool
+	}{
+		{
+			name:   "same key",
+			key:    "run-1",
+			posted: false,
+		},
+		{
+			name:   "other key",
+			key:    "run-2",
+			posted: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.IdempotencyKey = testCase.key
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body string
+			api := newFakeAPI()
+			api.FakeIssuesListComments = func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+				return []*github.IssueComment{
+					{
+						ID:   github.Int64(1),
+						Body: github.String("## Plan Result\n<!-- github-comment: {\"Program\":\"tfcmt\",\"Command\":\"plan\",\"IdempotencyKey\":\"run-1\"} -->"),
+					},
+				}, &github.Response{}, nil
+			}
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				body = comment.GetBody()
+				return comment, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+				ExitCode:       2,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if posted := body != ""; posted != testCase.posted {
+				t.Fatalf("posted: got %v but want %v", posted, testCase.posted)
+			}
+			if testCase.posted && !strings.Contains(body, `"IdempotencyKey":"`+testCase.key+`"`) {
+				t.Errorf("the idempotency key should be embedded: %s", body)
+			}
+		})
+	}
+}
+
+func TestNotifyOldCommentsOfCommand(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		parser    terraform.Parser
+		template  *terraform.Template
+		output    string
+		command   string
+		minimized []string
+	}{
+		{
+			name:      "test",
+			parser:    terraform.NewTestParser(),
+			template:  terraform.NewTestTemplate(""),
+			output:    "Success! 2 passed, 0 failed.",
+			command:   terraform.CommandTest,
+			minimized: []string{"node-test"},
+		},
+		{
+			name:      "destroy",
+			parser:    terraform.NewDestroyParser(),
+			template:  terraform.NewDestroyTemplate(""),
+			output:    "Destroy complete! Resources: 1 destroyed.",
+			command:   terraform.CommandDestroy,
+			minimized: []string{"node-destroy"},
+		},
+		{
+			name:      "validate",
+			parser:    terraform.NewValidateParser(),
+			template:  terraform.NewValidateTemplate(""),
+			output:    `{"valid": false, "error_count": 1, "warning_count": 0, "diagnostics": [{"severity": "error", "summary": "Unsupported argument"}]}`,
+			command:   terraform.CommandValidate,
+			minimized: []string{"node-validate"},
+		},
+		{
+			name:      "fmt",
+			parser:    terraform.NewFmtParser(),
+			template:  terraform.NewFmtTemplate(""),
+			output:    "main.tf",
+			command:   terraform.CommandFmt,
+			minimized: []string{"node-fmt"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Parser = testCase.parser
+			cfg.Template = testCase.template
+			cfg.OldComment = OldComment{Action: OldCommentActionMinimize}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body string
+			var minimized []string
+			api := newFakeAPI()
+			api.FakeIssuesListComments = func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+				comments := []*github.IssueComment{}
+				for _, command := range []string{"plan", "apply", testCase.command} {
+					comments = append(comments, &github.IssueComment{
+						ID:     github.Int64(int64(len(comments) + 1)),
+						NodeID: github.String("node-" + command),
+						Body:   github.String("foo\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"` + command + `","Target":""} -->`),
+					})
+				}
+				return comments, &github.Response{}, nil
+			}
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				body = comment.GetBody()
+				return comment, nil, nil
+			}
+			api.FakeMinimizeComment = func(ctx context.Context, nodeID, classifier string) error {
+				minimized = append(minimized, nodeID)
+				return nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(body, `"Command":"`+testCase.command+`"`) {
+				t.Errorf("the command %q should be embedded: %s", testCase.command, body)
+			}
+			if diff := cmp.Diff(minimized, testCase.minimized); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNotifyAutoParser(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		output  string
+		title   string
+		command string
+	}{
+		{
+			name:    "plan",
+			output:  "Plan: 1 to add, 0 to change, 0 to destroy.",
+			title:   "## Plan Result",
+			command: "plan",
+		},
+		{
+			name:    "apply",
+			output:  "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			title:   "## :white_check_mark: Apply Result",
+			command: "apply",
+		},
+		{
+			name:    "unknown",
+			output:  "Initializing the backend...",
+			title:   "## Apply Result",
+			command: "apply",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Parser = terraform.NewAutoParser()
+			cfg.Template = nil
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body string
+			api := newFakeAPI()
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				body = comment.GetBody()
+				return comment, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(body, testCase.title) {
+				t.Errorf("the comment should contain %q: %s", testCase.title, body)
+			}
+			// the template should be consistent with the command of the metadata
+			if meta := `"Command":"` + testCase.command + `"`; !strings.Contains(body, meta) {
+				t.Errorf("the comment should contain %q: %s", meta, body)
+			}
+		})
+	}
+}
+
+func TestNotifyNormalizeOutput(t *testing.T) {
+	t.Parallel()
+	client, err := NewClient(context.Background(), newFakeConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body string
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		body = comment.GetBody()
+		return comment, nil, nil
+	}
+	client.API = &api
+	exitCode, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "  \x1b[32m# null_resource.foo\x1b[0m will be created\r\n\x1b[1mPlan:\x1b[0m 1 to add, 0 to change, 0 to destroy.\r\n",
+		ExitCode:       2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exitCode != 2 {
+		t.Errorf("got %d but want 2", exitCode)
+	}
+	if strings.Contains(body, "\x1b") || strings.Contains(body, "\r") {
+		t.Errorf("escape sequences and CR should be removed: %q", body)
+	}
+	if !strings.Contains(body, "* Create\n  * null_resource.foo") {
+		t.Errorf("the created resource should be listed: %s", body)
+	}
+}
+
+type fakeHook struct {
+	err error
+}
+
+func (h *fakeHook) PostParse(ctx context.Context, result terraform.ParseResult, tpl *terraform.CommonTemplate) error {
+	if h.err != nil {
+		return h.err
+	}
+	tpl.Vars["owner"] = "team-" + result.CreatedResources[0]
+	return nil
+}
+
+func TestNotifyHook(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		hook Hook
+		exp  string
+	}{
+		{
+			name: "no hook",
+			exp:  "owner: ",
+		},
+		{
+			name: "enrich vars",
+			hook: &fakeHook{},
+			exp:  "owner: team-null_resource.foo",
+		},
+		{
+			name: "error",
+			hook: &fakeHook{err: errors.New("CMDB is unavailable")},
+			exp:  "owner: \nrun the post parse hook: CMDB is unavailable",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Vars = map[string]string{"target": "foo"}
+			cfg.Hook = testCase.hook
+			cfg.Template = terraform.NewPlanTemplate("owner: {{index .Vars \"owner\"}}{{range .ErrorMessages}}\n{{.}}{{end}}")
+			cfg.UseRawOutput = true
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body string
+			api := newFakeAPI()
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				body = comment.GetBody()
+				return comment, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: "  # null_resource.foo will be created\nPlan: 1 to add, 0 to change, 0 to destroy.",
+				ExitCode:       2,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(body, testCase.exp+"\n<!-- github-comment: ") {
+				t.Errorf("got %q but want the prefix %q", body, testCase.exp)
+			}
+			if _, ok := cfg.Vars["owner"]; ok {
+				t.Error("the hook must not change Config.Vars")
+			}
+		})
+	}
+}
+
+func TestNotifyCombinedOutputFile(t *testing.T) {
+	t.Parallel()
+	p := filepath.Join(t.TempDir(), "plan.txt")
+	if err := ioutil.WriteFile(p, []byte("Plan: 1 to add, 0 to change, 0 to destroy."), 0o644); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	client, err := NewClient(context.Background(), newFakeConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body string
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		body = comment.GetBody()
+		return comment, nil, nil
+	}
+	client.API = &api
+	exitCode, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutputFile: p,
+		ExitCode:           2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exitCode != 2 {
+		t.Errorf("got %d but want %d", exitCode, 2)
+	}
+	if !strings.Contains(body, "Plan: 1 to add, 0 to change, 0 to destroy.") {
+		t.Errorf("the output isn't read from the file: %s", body)
+	}
+
+	if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutputFile: filepath.Join(t.TempDir(), "not_found.txt"),
+	}); err == nil {
+		t.Error("an error should be returned if the file isn't found")
+	}
+}
+
+func TestUpdateLabels(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.ResultLabels = ResultLabels{
+		AddOrUpdateLabel: "add-or-update",
+		DestroyLabel:     "destroy",
+		NoChangesLabel:   "no-changes",
+		PlanErrorLabel:   "error",
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newFakeAPI()
+	api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+		return []*github.Label{
+			{Name: github.String("destroy")},
+			{Name: github.String("error")},
+			{Name: github.String("no-changes")},
+			{Name: github.String("enhancement")},
+		}, nil, nil
+	}
+	var (
+		removed []string
+		added   []string
+		mutex   sync.Mutex
+	)
+	api.FakeIssuesRemoveLabel = func(ctx context.Context, number int, label string) (*github.Response, error) {
+		mutex.Lock()
+		removed = append(removed, label)
+		mutex.Unlock()
+		if label == "no-changes" {
+			return nil, nil
+		}
+		return &github.Response{Response: &http.Response{StatusCode: http.StatusInternalServerError}}, errors.New("internal server error")
+	}
+	api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		mutex.Lock()
+		added = append(added, labels...)
+		mutex.Unlock()
+		return nil, nil, nil
+	}
+	// labels are removed one by one if labels can't be replaced
+	api.FakeIssuesReplaceLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		return nil, nil, errors.New("forbidden")
+	}
+	client.API = &api
+	errMsgs := client.Notify.updateLabels(context.Background(), cfg.PR.Number, terraform.ParseResult{
+		HasAddOrUpdateOnly: true,
+	})
+	sort.Strings(removed)
+	if diff := cmp.Diff(removed, []string{"destroy", "error", "no-changes"}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(added, []string{"add-or-update"}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(errMsgs, []string{
+		"remove labels: internal server error",
+		"remove labels: internal server error",
+	}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestUpdateLabelsListError(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.ResultLabels = ResultLabels{
+		AddOrUpdateLabel: "add-or-update",
+		DestroyLabel:     "destroy",
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newFakeAPI()
+	api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+		return nil, nil, errors.New("forbidden")
+	}
+	client.API = &api
+	errMsgs := client.Notify.updateLabels(context.Background(), cfg.PR.Number, terraform.ParseResult{
+		HasAddOrUpdateOnly: true,
+	})
+	if diff := cmp.Diff([]string{"remove labels: forbidden"}, errMsgs); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestUpdateLabelsBatch(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.ResultLabels = ResultLabels{
+		AddOrUpdateLabel:      "add-or-update",
+		AddOrUpdateLabelColor: "1d76db",
+		DestroyLabel:          "destroy",
+		NoChangesLabel:        "no-changes",
+		PlanErrorLabel:        "error",
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newFakeAPI()
+	api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+		// labels are paginated
+		if opts.Page == 0 {
+			return []*github.Label{
+				{Name: github.String("destroy")},
+				{Name: github.String("enhancement")},
+			}, &github.Response{NextPage: 2}, nil
+		}
+		return []*github.Label{
+			{Name: github.String("no-changes")},
+			{Name: github.String("team/infra")},
+		}, &github.Response{}, nil
+	}
+	api.FakeIssuesRemoveLabel = func(ctx context.Context, number int, label string) (*github.Response, error) {
+		t.Errorf("labels should be replaced rather than removed one by one: %s", label)
+		return nil, nil
+	}
+	api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		t.Errorf("labels should be replaced rather than added: %v", labels)
+		return nil, nil, nil
+	}
+	var replaced, colored []string
+	api.FakeIssuesReplaceLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		replaced = labels
+		arr := make([]*github.Label, len(labels))
+		for i, label := range labels {
+			arr[i] = &github.Label{Name: github.String(label), Color: github.String("ededed")}
+		}
+		return arr, nil, nil
+	}
+	api.FakeIssuesUpdateLabel = func(ctx context.Context, label, color string) (*github.Label, *github.Response, error) {
+		colored = append(colored, label+":"+color)
+		return nil, nil, nil
+	}
+	client.API = &api
+	errMsgs := client.Notify.updateLabels(context.Background(), cfg.PR.Number, terraform.ParseResult{
+		HasAddOrUpdateOnly: true,
+	})
+	// labels other than result labels are kept
+	if diff := cmp.Diff([]string{"enhancement", "team/infra", "add-or-update"}, replaced); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]string{"add-or-update:1d76db"}, colored); diff != "" {
+		t.Error(diff)
+	}
+	if len(errMsgs) != 0 {
+		t.Errorf("unexpected errors: %v", errMsgs)
+	}
+}
+
+func TestUpdateLabelsChangedByOthers(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.ResultLabels = ResultLabels{
+		AddOrUpdateLabel: "add-or-update",
+		DestroyLabel:     "destroy",
+		NoChangesLabel:   "no-changes",
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newFakeAPI()
+	var (
+		listed  int
+		removed []string
+		added   []string
+		mutex   sync.Mutex
+	)
+	api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+		listed++
+		labels := []*github.Label{
+			{Name: github.String("destroy")},
+			{Name: github.String("no-changes")},
+		}
+		if listed > 1 {
+			// a label is added by others after labels are listed
+			labels = append(labels, &github.Label{Name: github.String("enhancement")})
+		}
+		return labels, nil, nil
+	}
+	api.FakeIssuesReplaceLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		t.Errorf("labels should not be replaced because the label added by others would be removed: %v", labels)
+		return nil, nil, nil
+	}
+	api.FakeIssuesRemoveLabel = func(ctx context.Context, number int, label string) (*github.Response, error) {
+		mutex.Lock()
+		removed = append(removed, label)
+		mutex.Unlock()
+		return nil, nil
+	}
+	api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		mutex.Lock()
+		added = append(added, labels...)
+		mutex.Unlock()
+		return nil, nil, nil
+	}
+	client.API = &api
+	errMsgs := client.Notify.updateLabels(context.Background(), cfg.PR.Number, terraform.ParseResult{
+		HasAddOrUpdateOnly: true,
+	})
+	sort.Strings(removed)
+	if diff := cmp.Diff([]string{"destroy", "no-changes"}, removed); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]string{"add-or-update"}, added); diff != "" {
+		t.Error(diff)
+	}
+	if len(errMsgs) != 0 {
+		t.Errorf("unexpected errors: %v", errMsgs)
+	}
+}
+
+func TestUpdateLabelsPrefix(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.ResultLabels = ResultLabels{
+		AddOrUpdateLabel: "add-or-update",
+		DestroyLabel:     "destroy",
+		Prefix:           "tfcmt/",
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newFakeAPI()
+	api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+		return []*github.Label{
+			{Name: github.String("tfcmt/add-or-update")},
+			{Name: github.String("destroy")},
+		}, nil, nil
+	}
+	var (
+		removed []string
+		added   []string
+		mutex   sync.Mutex
+	)
+	api.FakeIssuesRemoveLabel = func(ctx context.Context, number int, label string) (*github.Response, error) {
+		mutex.Lock()
+		removed = append(removed, label)
+		mutex.Unlock()
+		return nil, nil
+	}
+	api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		mutex.Lock()
+		added = append(added, labels...)
+		mutex.Unlock()
+		return nil, nil, nil
+	}
+	client.API = &api
+	errMsgs := client.Notify.updateLabels(context.Background(), cfg.PR.Number, terraform.ParseResult{
+		HasDestroy: true,
+	})
+	if diff := cmp.Diff(removed, []string{"tfcmt/add-or-update"}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(added, []string{"tfcmt/destroy"}); diff != "" {
+		t.Error(diff)
+	}
+	if len(errMsgs) != 0 {
+		t.Errorf("unexpected errors: %v", errMsgs)
+	}
+}
+
+func TestUpdateLabelsPreserved(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.ResultLabels = ResultLabels{
+		AddOrUpdateLabel: "add-or-update",
+		DestroyLabel:     "destroy",
+		NoChangesLabel:   "no-changes",
+		Preserved:        []string{"destroy"},
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := newFakeAPI()
+	api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+		return []*github.Label{
+			{Name: github.String("destroy")},
+			{Name: github.String("no-changes")},
+		}, nil, nil
+	}
+	var (
+		removed []string
+		added   []string
+		mutex   sync.Mutex
+	)
+	api.FakeIssuesRemoveLabel = func(ctx context.Context, number int, label string) (*github.Response, error) {
+		mutex.Lock()
+		removed = append(removed, label)
+		mutex.Unlock()
+		return nil, nil
+	}
+	api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+		mutex.Lock()
+		added = append(added, labels...)
+		mutex.Unlock()
+		return nil, nil, nil
+	}
+	client.API = &api
+	errMsgs := client.Notify.updateLabels(context.Background(), cfg.PR.Number, terraform.ParseResult{
+		HasAddOrUpdateOnly: true,
+	})
+	// the preserved label isn't removed
+	if diff := cmp.Diff(removed, []string{"no-changes"}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(added, []string{"add-or-update"}); diff != "" {
+		t.Error(diff)
+	}
+	if len(errMsgs) != 0 {
+		t.Errorf("unexpected errors: %v", errMsgs)
+	}
+}
+
+func TestUpdateLabelsErrorCategory(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		category string
+		removed  []string
+		added    []string
+	}{
+		{
+			name:     "category label",
+			category: terraform.ErrorCategoryStateLock,
+			added:    []string{"state-lock"},
+		},
+		{
+			name:     "category label isn't set",
+			category: terraform.ErrorCategoryConfig,
+			removed:  []string{"state-lock"},
+			added:    []string{"error"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.ResultLabels = ResultLabels{
+				PlanErrorLabel: "error",
+				PlanErrorCategoryLabels: map[string]string{
+					terraform.ErrorCategoryStateLock: "state-lock",
+				},
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			api := newFakeAPI()
+			api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+				return []*github.Label{
+					{Name: github.String("state-lock")},
+				}, nil, nil
+			}
+			var removed, added []string
+			api.FakeIssuesRemoveLabel = func(ctx context.Context, number int, label string) (*github.Response, error) {
+				removed = append(removed, label)
+				return nil, nil
+			}
+			api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+				added = append(added, labels...)
+				return nil, nil, nil
+			}
+			client.API = &api
+			client.Notify.updateLabels(context.Background(), cfg.PR.Number, terraform.ParseResult{
+				HasPlanError:  true,
+				ErrorCategory: testCase.category,
+			})
+			if diff := cmp.Diff(testCase.removed, removed); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.added, added); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFilterEnv(t *testing.T) {
+	t.Parallel()
+	environ := []string{
+		"TFCMT_JOB_NAME=plan",
+		"CI_RUNBOOK_URL=https://example.com/runbook",
+		"GITHUB_TOKEN=secret",
+		"INVALID",
+	}
+	testCases := []struct {
+		name     string
+		prefixes []string
+		exp      map[string]string
+	}{
+		{
+			name: "no prefix",
+			exp:  map[string]string{},
+		},
+		{
+			name:     "prefixes",
+			prefixes: []string{"TFCMT_", "CI_"},
+			exp: map[string]string{
+				"TFCMT_JOB_NAME": "plan",
+				"CI_RUNBOOK_URL": "https://example.com/runbook",
+			},
+		},
+		{
+			name:     "empty prefix is ignored",
+			prefixes: []string{""},
+			exp:      map[string]string{},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(testCase.exp, filterEnv(environ, testCase.prefixes)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestHeadTailLines(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		text string
+		head string
+		tail string
+	}{
+		{
+			name: "short",
+			text: "a\nb\n",
+			head: "a\nb",
+			tail: "a\nb",
+		},
+		{
+			name: "long",
+			text: "a\nb\nc\nd\ne\n",
+			head: "a\nb",
+			tail: "d\ne",
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if head := headLines(testCase.text, 2); head != testCase.head {
+				t.Errorf("head: got %q but want %q", head, testCase.head)
+			}
+			if tail := tailLines(testCase.text, 2); tail != testCase.tail {
+				t.Errorf("tail: got %q but want %q", tail, testCase.tail)
+			}
+		})
+	}
+}
+
+func TestUpdateLabelsReplace(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name         string
+		replaceLabel string
+		deleted      []string
+		exp          []string
+	}{
+		{
+			name:         "replace label",
+			replaceLabel: "replace",
+			exp:          []string{"replace"},
+		},
+		{
+			name: "replace label isn't set",
+			exp:  []string{"destroy"},
+		},
+		{
+			name:         "destroy takes precedence over replace",
+			replaceLabel: "replace",
+			deleted:      []string{"aws_instance.bar"},
+			exp:          []string{"destroy"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.ResultLabels = ResultLabels{
+				DestroyLabel: "destroy",
+				ReplaceLabel: testCase.replaceLabel,
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var added []string
+			api := newFakeAPI()
+			api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+				added = append(added, labels...)
+				return nil, nil, nil
+			}
+			client.API = &api
+			client.Notify.updateLabels(context.Background(), cfg.PR.Number, terraform.ParseResult{
+				HasDestroy:        true,
+				DeletedResources:  testCase.deleted,
+				ReplacedResources: []string{"aws_instance.foo"},
+			})
+			if diff := cmp.Diff(testCase.exp, added); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestUpdateApplyLabels(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		result  terraform.ParseResult
+		added   []string
+		removed []string
+	}{
+		{
+			name:    "applied",
+			result:  terraform.ParseResult{ExitCode: terraform.ExitPass},
+			added:   []string{"applied"},
+			removed: []string{"add-or-update"},
+		},
+		{
+			name:    "apply failed",
+			result:  terraform.ParseResult{ExitCode: terraform.ExitFail, HasApplyError: true},
+			added:   []string{"apply-failed"},
+			removed: []string{"add-or-update"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.ResultLabels = ResultLabels{
+				AddOrUpdateLabel: "add-or-update",
+				AppliedLabel:     "applied",
+				ApplyFailedLabel: "apply-failed",
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var added, removed []string
+			api := newFakeAPI()
+			api.FakeIssuesListLabels = func(ctx context.Context, number int, opts *github.ListOptions) ([]*github.Label, *github.Response, error) {
+				return []*github.Label{
+					{Name: github.String("add-or-update")},
+					{Name: github.String("team/infra")},
+				}, nil, nil
+			}
+			api.FakeIssuesAddLabels = func(ctx context.Context, number int, labels []string) ([]*github.Label, *github.Response, error) {
+				if number != 2 {
+					t.Errorf("the label should be added to the merged pull request: %d", number)
+				}
+				added = append(added, labels...)
+				return nil, nil, nil
+			}
+			api.FakeIssuesRemoveLabel = func(ctx context.Context, number int, label string) (*github.Response, error) {
+				removed = append(removed, label)
+				return nil, nil
+			}
+			client.API = &api
+			client.Notify.updateApplyLabels(context.Background(), 2, testCase.result)
+			if diff := cmp.Diff(testCase.added, added); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.removed, removed); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNotifySkipDuplicateComment(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.Vars = map[string]string{"target": "foo"}
+	cfg.CI = "https://ci.example.com/1"
+	cfg.SkipDuplicateComment = true
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var comments []*github.IssueComment
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		comments = append(comments, comment)
+		return comment, nil, nil
+	}
+	api.FakeIssuesListComments = func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+		return comments, nil, nil
+	}
+	client.API = &api
+	param := notifier.ParamExec{
+		CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+		ExitCode:       2,
+	}
+	if _, err := client.Notify.Notify(context.Background(), param); err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("the first comment should be posted: %d comments", len(comments))
+	}
+	if !strings.Contains(comments[0].GetBody(), `"Program":"tfcmt"`) || !strings.Contains(comments[0].GetBody(), `"Command":"plan"`) || !strings.Contains(comments[0].GetBody(), `"Target":"foo"`) {
+		t.Fatalf("the metadata should be embedded: %s", comments[0].GetBody())
+	}
+	// the body is identical except the CI link of the retried job
+	client.Config.CI = "https://ci.example.com/2"
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		t.Errorf("the duplicated comment shouldn't be posted: %s", comment.GetBody())
+		return comment, nil, nil
+	}
+	exitCode, err := client.Notify.Notify(context.Background(), param)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exitCode != 2 {
+		t.Errorf("exit code: got %d, want 2", exitCode)
+	}
+}
+
+func TestNotifySkipDuplicateApply(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.TargetPRNumber = 1
+	cfg.Parser = terraform.NewApplyParser()
+	cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+	cfg.SkipDuplicateApply = true
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var comments []*github.IssueComment
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		comments = append(comments, comment)
+		return comment, nil, nil
+	}
+	api.FakeIssuesListComments = func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+		return comments, nil, nil
+	}
+	client.API = &api
+	param := notifier.ParamExec{
+		CombinedOutput: "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.Notify.Notify(context.Background(), param); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(comments) != 1 {
+		t.Errorf("the duplicated apply comment shouldn't be posted: %d comments", len(comments))
+	}
+	param.CombinedOutput = "Apply complete! Resources: 2 added, 0 changed, 0 destroyed."
+	if _, err := client.Notify.Notify(context.Background(), param); err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 {
+		t.Errorf("the different apply comment should be posted: %d comments", len(comments))
+	}
+}
+
+func TestNotifySkipDuplicateLongComment(t *testing.T) {
+	t.Parallel()
+	line := strings.Repeat("a", 99) + "\n"
+	output := strings.Repeat(line, 1000) + "Plan: 1 to add, 0 to change, 0 to destroy."
+	testCases := []struct {
+		name     string
+		truncate Truncate
+		gist     bool
+		comments int
+		gists    int
+	}{
+		{
+			name:     "truncated",
+			truncate: Truncate{Strategy: TruncateStrategyTail, MaxLength: 10000},
+			comments: 1,
+		},
+		{
+			name:     "split",
+			comments: 2,
+		},
+		{
+			name:     "gist",
+			gist:     true,
+			comments: 1,
+			gists:    1,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.CI = "https://ci.example.com/1"
+			cfg.SkipDuplicateComment = true
+			cfg.Template = terraform.NewPlanTemplate("{{.CombinedOutput}}")
+			cfg.Truncate = testCase.truncate
+			if testCase.gist {
+				cfg.Gist = Gist{
+					Enabled:   true,
+					Threshold: 100,
+				}
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				comments []*github.IssueComment
+				gists    int
+			)
+			api := newFakeAPI()
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				comments = append(comments, comment)
+				return comment, nil, nil
+			}
+			api.FakeIssuesListComments = func(ctx context.Context, number int, opt *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+				return comments, nil, nil
+			}
+			api.FakeGistsCreate = func(ctx context.Context, gist *github.Gist) (*github.Gist, *github.Response, error) {
+				gists++
+				// a new Gist is created every time
+				return &github.Gist{
+					HTMLURL: github.String("https://gist.github.com/octocat/" + strconv.Itoa(gists)),
+				}, nil, nil
+			}
+			client.API = &api
+			param := notifier.ParamExec{
+				CombinedOutput: output,
+				ExitCode:       2,
+			}
+			if _, err := client.Notify.Notify(context.Background(), param); err != nil {
+				t.Fatal(err)
+			}
+			if len(comments) != testCase.comments {
+				t.Fatalf("the number of posted comments: got %d, wanted %d", len(comments), testCase.comments)
+			}
+			// the comment of the retried job is identical to what was posted except the CI link
+			client.Config.CI = "https://ci.example.com/2"
+			if _, err := client.Notify.Notify(context.Background(), param); err != nil {
+				t.Fatal(err)
+			}
+			if len(comments) != testCase.comments {
+				t.Errorf("the duplicated comment shouldn't be posted: got %d comments", len(comments))
+			}
+			if gists != testCase.gists {
+				t.Errorf("the number of created Gists: got %d, wanted %d", gists, testCase.gists)
+			}
+			// the different comment is posted
+			param.CombinedOutput = strings.Repeat(line, 1000) + "Plan: 2 to add, 0 to change, 0 to destroy."
+			if _, err := client.Notify.Notify(context.Background(), param); err != nil {
+				t.Fatal(err)
+			}
+			if len(comments) != 2*testCase.comments {
+				t.Errorf("the different comment should be posted: got %d comments", len(comments))
+			}
+		})
+	}
+}
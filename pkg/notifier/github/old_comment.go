@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment-metadata/metadata"
+)
+
+// listOldComments returns comments posted by tfcmt which match the conditions of OldComment.Match
+func (g *NotifyService) listOldComments(ctx context.Context, number int, command string) ([]*github.IssueComment, error) {
+	cfg := g.client.Config
+	comments, err := g.client.Comment.List(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return filterOldComments(comments, &commentMetadata{
+		Program: cfg.program(),
+		Command: command,
+		Target:  cfg.Vars["target"],
+		SHA1:    cfg.PR.Revision,
+	}, cfg.OldComment.matchConditions()), nil
+}
+
+// matchConditions returns the conditions to match old comments.
+// The default conditions are "same_command" and "same_target"
+func (old *OldComment) matchConditions() []string {
+	if len(old.Match) == 0 {
+		return []string{OldCommentMatchSameCommand, OldCommentMatchSameTarget}
+	}
+	return old.Match
+}
+
+// filterOldComments returns comments posted by the same program which match all conditions.
+// current is the metadata of the comment to be posted
+func filterOldComments(comments []*github.IssueComment, current *commentMetadata, conditions []string) []*github.IssueComment {
+	ret := []*github.IssueComment{}
+	for _, comment := range comments {
+		if matchOldComment(comment, current, conditions) {
+			ret = append(ret, comment)
+		}
+	}
+	return ret
+}
+
+func matchOldComment(comment *github.IssueComment, current *commentMetadata, conditions []string) bool {
+	meta := &commentMetadata{}
+	f, err := metadata.Extract(comment.GetBody(), meta)
+	if err != nil || !f || meta.Program != current.Program {
+		return false
+	}
+	for _, condition := range conditions {
+		switch condition {
+		case OldCommentMatchSameCommand:
+			if meta.Command != current.Command {
+				return false
+			}
+		case OldCommentMatchSameTarget:
+			if meta.Target != current.Target {
+				return false
+			}
+		case OldCommentMatchOlderSHA:
+			// Comments of the same commit such as comments of retried jobs aren't old
+			if current.SHA1 != "" && meta.SHA1 == current.SHA1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// handleOldComments minimizes or deletes old comments according to the configuration
+func (g *NotifyService) handleOldComments(ctx context.Context, comments []*github.IssueComment) {
+	cfg := g.client.Config
+	classifier := cfg.OldComment.Classifier
+	if classifier == "" {
+		classifier = "OUTDATED"
+	}
+	minimized := g.listMinimizedComments(ctx, comments)
+	for _, comment := range comments {
+		logE := logrus.WithFields(logrus.Fields{
+			"program":    "tfcmt",
+			"comment_id": comment.GetID(),
+		})
+		switch cfg.OldComment.Action {
+		case OldCommentActionMinimize:
+			// Comments which have already been minimized aren't minimized again
+			if minimized[comment.GetNodeID()] {
+				continue
+			}
+			if err := g.client.API.MinimizeComment(ctx, comment.GetNodeID(), classifier); err != nil {
+				logE.WithError(err).Warn("minimize an old comment")
+			}
+		case OldCommentActionDelete:
+			resp, err := g.client.API.IssuesDeleteComment(ctx, comment.GetID())
+			// Ignore 404 errors, which are from the comment already deleted
+			if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+				logE.WithError(err).Warn("delete an old comment")
+			}
+		}
+	}
+}
+
+// listMinimizedComments returns node ids of comments which have already been minimized.
+// If it fails to get them, all comments are minimized as before
+func (g *NotifyService) listMinimizedComments(ctx context.Context, comments []*github.IssueComment) map[string]bool {
+	if g.client.Config.OldComment.Action != OldCommentActionMinimize || len(comments) == 0 {
+		return nil
+	}
+	nodeIDs := make([]string, len(comments))
+	for i, comment := range comments {
+		nodeIDs[i] = comment.GetNodeID()
+	}
+	minimized, err := g.client.API.ListMinimizedComments(ctx, nodeIDs)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(err).Warn("list minimized comments")
+		return nil
+	}
+	return minimized
+}
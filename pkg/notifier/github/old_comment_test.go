@@ -0,0 +1,175 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+)
+
+func TestHandleOldComments(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			ID:     github.Int64(1),
+			NodeID: github.String("node-1"),
+		},
+		{
+			ID:     github.Int64(2),
+			NodeID: github.String("node-2"),
+		},
+	}
+	testCases := []struct {
+		name      string
+		old       OldComment
+		already   map[string]bool
+		listErr   error
+		minimized []string
+		deleted   []int64
+	}{
+		{
+			name: "keep",
+			old:  OldComment{Action: OldCommentActionKeep},
+		},
+		{
+			name:      "minimize",
+			old:       OldComment{Action: OldCommentActionMinimize},
+			minimized: []string{"node-1:OUTDATED", "node-2:OUTDATED"},
+		},
+		{
+			name:      "minimize with classifier",
+			old:       OldComment{Action: OldCommentActionMinimize, Classifier: "RESOLVED"},
+			minimized: []string{"node-1:RESOLVED", "node-2:RESOLVED"},
+		},
+		{
+			name:      "skip already minimized comments",
+			old:       OldComment{Action: OldCommentActionMinimize},
+			already:   map[string]bool{"node-1": true},
+			minimized: []string{"node-2:OUTDATED"},
+		},
+		{
+			name:      "minimize all comments if it fails to list minimized comments",
+			old:       OldComment{Action: OldCommentActionMinimize},
+			listErr:   errors.New("graphql error"),
+			minimized: []string{"node-1:OUTDATED", "node-2:OUTDATED"},
+		},
+		{
+			name:    "delete",
+			old:     OldComment{Action: OldCommentActionDelete},
+			deleted: []int64{1, 2},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.OldComment = testCase.old
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var minimized []string
+			var deleted []int64
+			api := newFakeAPI()
+			api.FakeListMinimizedComments = func(ctx context.Context, nodeIDs []string) (map[string]bool, error) {
+				return testCase.already, testCase.listErr
+			}
+			api.FakeMinimizeComment = func(ctx context.Context, nodeID, classifier string) error {
+				minimized = append(minimized, nodeID+":"+classifier)
+				return nil
+			}
+			api.FakeIssuesDeleteComment = func(ctx context.Context, commentID int64) (*github.Response, error) {
+				deleted = append(deleted, commentID)
+				// the comment has already been deleted
+				return &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, errors.New("not found")
+			}
+			client.API = &api
+			client.Notify.handleOldComments(context.Background(), comments)
+			if diff := cmp.Diff(minimized, testCase.minimized); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(deleted, testCase.deleted); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestFilterOldComments(t *testing.T) {
+	t.Parallel()
+	comments := []*github.IssueComment{
+		{
+			ID:   github.Int64(1),
+			Body: github.String("foo\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"foo","SHA1":"old"} -->`),
+		},
+		{
+			ID:   github.Int64(2),
+			Body: github.String("foo\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"foo","SHA1":"new"} -->`),
+		},
+		{
+			ID:   github.Int64(3),
+			Body: github.String("foo\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"bar","SHA1":"old"} -->`),
+		},
+		{
+			ID:   github.Int64(4),
+			Body: github.String("foo\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"apply","Target":"foo","SHA1":"old"} -->`),
+		},
+		{
+			ID:   github.Int64(5),
+			Body: github.String("foo\n" + `<!-- github-comment: {"Program":"github-comment","Command":"plan","Target":"foo","SHA1":"old"} -->`),
+		},
+		{
+			ID:   github.Int64(6),
+			Body: github.String("foo"),
+		},
+	}
+	current := &commentMetadata{
+		Program: "tfcmt",
+		Command: "plan",
+		Target:  "foo",
+		SHA1:    "new",
+	}
+	testCases := []struct {
+		name       string
+		conditions []string
+		exp        []int64
+	}{
+		{
+			name:       "default",
+			conditions: (&OldComment{}).matchConditions(),
+			exp:        []int64{1, 2},
+		},
+		{
+			name:       "older sha",
+			conditions: []string{OldCommentMatchSameCommand, OldCommentMatchSameTarget, OldCommentMatchOlderSHA},
+			exp:        []int64{1},
+		},
+		{
+			name:       "all targets",
+			conditions: []string{OldCommentMatchSameCommand},
+			exp:        []int64{1, 2, 3},
+		},
+		{
+			name:       "all commands",
+			conditions: []string{OldCommentMatchSameTarget},
+			exp:        []int64{1, 2, 4},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			ids := []int64{}
+			for _, comment := range filterOldComments(comments, current, testCase.conditions) {
+				ids = append(ids, comment.GetID())
+			}
+			if diff := cmp.Diff(testCase.exp, ids); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// matchPostTriggers returns true if the plan result matches any of the triggers
+func matchPostTriggers(triggers []string, result terraform.ParseResult) bool {
+	for _, trigger := range triggers {
+		switch trigger {
+		case PostTriggerPlanError:
+			if result.HasPlanError {
+				return true
+			}
+		case PostTriggerParseError:
+			if result.HasParseError {
+				return true
+			}
+		case PostTriggerDestroy:
+			if result.HasDestroy {
+				return true
+			}
+		case PostTriggerReplace:
+			if len(result.ReplacedResources) > 0 {
+				return true
+			}
+		case PostTriggerAddOrUpdate:
+			if result.HasAddOrUpdateOnly {
+				return true
+			}
+		case PostTriggerNoChanges:
+			if result.HasNoChanges {
+				return true
+			}
+		case PostTriggerImport:
+			if result.HasImportOnly {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cleanUpWithoutPost handles old comments according to OldComment without posting a new comment.
+// This removes the stale comment of the previous failure after the plan gets fine
+func (g *NotifyService) cleanUpWithoutPost(ctx context.Context, cfg *Config, command string) {
+	logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	}).Info("skip posting a comment because the result doesn't match any of the post triggers")
+	if !cfg.DryRun && cfg.Review.Enabled && cfg.PR.IsNumber() {
+		// the review of the old plan result shouldn't block the pull request even if the new result isn't posted
+		g.dismissOldReviews(ctx, cfg, command)
+	}
+	if cfg.DryRun || !cfg.PR.IsNumber() || cfg.OldComment.Action == "" || cfg.OldComment.Action == OldCommentActionKeep {
+		return
+	}
+	comments, err := g.listOldComments(ctx, cfg.PR.Number, command)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(err).Warn("list old comments")
+		return
+	}
+	g.handleOldComments(ctx, comments)
+}
@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// reviewStateChangesRequested is the state of pull request reviews which request changes
+const reviewStateChangesRequested = "CHANGES_REQUESTED"
+
+// dismissOldReviews dismisses reviews which were posted by tfcmt with the same command and target and request changes,
+// so the pull request isn't blocked by the review of an old plan result.
+// Reviews are found by the embedded metadata. Errors are only logged because the new result should be posted anyway
+func (g *NotifyService) dismissOldReviews(ctx context.Context, cfg *Config, command string) {
+	logE := logrus.WithFields(logrus.Fields{
+		"program": "tfcmt",
+	})
+	reviews, err := g.client.Comment.ListReviews(ctx, cfg.PR.Number)
+	if err != nil {
+		logE.WithError(err).Warn("list pull request reviews")
+		return
+	}
+	program, target := cfg.program(), cfg.Vars["target"]
+	for _, review := range reviews {
+		if review.GetState() != reviewStateChangesRequested {
+			continue
+		}
+		if _, ok := matchMetadata(review.GetBody(), program, command, target); !ok {
+			continue
+		}
+		if err := g.client.Comment.DismissReview(ctx, cfg.PR.Number, review.GetID(), "The plan result is outdated"); err != nil {
+			logE.WithField("review_id", review.GetID()).WithError(err).Warn("dismiss an old review")
+		}
+	}
+}
+
+// isReviewMode returns true if the result of the command is posted as a pull request review
+func (cfg *Config) isReviewMode(command string) bool {
+	return command == terraform.CommandPlan && cfg.Review.Enabled && cfg.PR.IsNumber()
+}
+
+// listMatchingReviews returns reviews posted by tfcmt with the same command and target in ascending order of creation
+func (g *NotifyService) listMatchingReviews(ctx context.Context, cfg *Config, command string) ([]*github.PullRequestReview, error) {
+	reviews, err := g.client.Comment.ListReviews(ctx, cfg.PR.Number)
+	if err != nil {
+		return nil, err
+	}
+	program, target := cfg.program(), cfg.Vars["target"]
+	ret := []*github.PullRequestReview{}
+	for _, review := range reviews {
+		if _, ok := matchMetadata(review.GetBody(), program, command, target); ok {
+			ret = append(ret, review)
+		}
+	}
+	return ret, nil
+}
+
+// latestReview returns the body and the metadata of the latest review of the same command and target.
+// If no review is found, the metadata is nil
+func (g *NotifyService) latestReview(ctx context.Context, cfg *Config, command string) (string, *commentMetadata, error) {
+	reviews, err := g.listMatchingReviews(ctx, cfg, command)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(reviews) == 0 {
+		return "", nil, nil
+	}
+	latest := reviews[len(reviews)-1].GetBody()
+	meta, _ := matchMetadata(latest, cfg.program(), command, cfg.Vars["target"])
+	return latest, meta, nil
+}
+
+// isReviewPostedWithIdempotencyKey returns true if a review of the same command and target with the same idempotency key already exists
+func (g *NotifyService) isReviewPostedWithIdempotencyKey(ctx context.Context, cfg *Config, command string) (bool, error) {
+	reviews, err := g.listMatchingReviews(ctx, cfg, command)
+	if err != nil {
+		return false, err
+	}
+	for _, review := range reviews {
+		if meta, ok := matchMetadata(review.GetBody(), cfg.program(), command, cfg.Vars["target"]); ok && meta.IdempotencyKey == cfg.IdempotencyKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// handleOldReviews hides the bodies of old reviews according to old_comment.action.
+// Submitted reviews can be neither minimized nor deleted, so the body is replaced instead.
+// The embedded metadata is removed so that the review isn't handled again
+func (g *NotifyService) handleOldReviews(ctx context.Context, cfg *Config, reviews []*github.PullRequestReview) {
+	for _, review := range reviews {
+		body := "The plan result is outdated."
+		if cfg.OldComment.Action == OldCommentActionMinimize {
+			body = "<details><summary>The plan result is outdated</summary>\n\n" + normalizeCommentBody(review.GetBody(), "") + "\n\n</details>"
+		}
+		if _, _, err := g.client.API.PullRequestsUpdateReview(ctx, cfg.PR.Number, review.GetID(), body); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program":   "tfcmt",
+				"review_id": review.GetID(),
+			}).WithError(err).Warn("hide an old review")
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package github
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+func TestNotifyDismissOldReviews(t *testing.T) {
+	t.Parallel()
+	reviews := []*github.PullRequestReview{
+		{
+			ID:    github.Int64(1),
+			State: github.String("CHANGES_REQUESTED"),
+			Body:  github.String("old\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"foo"} -->`),
+		},
+		{
+			// the review of the other target
+			ID:    github.Int64(2),
+			State: github.String("CHANGES_REQUESTED"),
+			Body:  github.String("old\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"bar"} -->`),
+		},
+		{
+			// the review which doesn't request changes can't be dismissed
+			ID:    github.Int64(3),
+			State: github.String("COMMENTED"),
+			Body:  github.String("old\n" + `<!-- github-comment: {"Program":"tfcmt","Command":"plan","Target":"foo"} -->`),
+		},
+		{
+			// the review isn't posted by tfcmt
+			ID:    github.Int64(4),
+			State: github.String("CHANGES_REQUESTED"),
+			Body:  github.String("Please fix the typo"),
+		},
+	}
+	testCases := []struct {
+		name      string
+		review    Review
+		triggers  []string
+		output    string
+		dismissed []int64
+		posted    []string
+	}{
+		{
+			name:      "dismiss old reviews before the new review is posted",
+			review:    Review{Enabled: true, Event: ReviewEventComment},
+			output:    "Plan: 1 to add, 0 to change, 0 to destroy.",
+			dismissed: []int64{1},
+			posted:    []string{"dismiss:1", "review:COMMENT"},
+		},
+		{
+			name:      "the new review requests changes",
+			review:    Review{Enabled: true, Event: ReviewEventComment, EventWhenDestroy: ReviewEventRequestChanges},
+			output:    "Plan: 0 to add, 0 to change, 1 to destroy.",
+			dismissed: []int64{1},
+			posted:    []string{"dismiss:1", "review:REQUEST_CHANGES"},
+		},
+		{
+			name:      "dismiss old reviews even if the new result isn't posted",
+			review:    Review{Enabled: true, Event: ReviewEventComment},
+			triggers:  []string{PostTriggerDestroy},
+			output:    "Plan: 1 to add, 0 to change, 0 to destroy.",
+			dismissed: []int64{1},
+			posted:    []string{"dismiss:1"},
+		},
+		{
+			name:   "review is disabled",
+			output: "Plan: 1 to add, 0 to change, 0 to destroy.",
+			posted: []string{"comment"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Review = testCase.review
+			cfg.PostTriggers = testCase.triggers
+			cfg.Vars = map[string]string{
+				"target": "foo",
+			}
+			client, err := NewClient(context.Background(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				dismissed []int64
+				posted    []string
+			)
+			api := newFakeAPI()
+			api.FakePullRequestsListReviews = func(ctx context.Context, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+				return reviews, nil, nil
+			}
+			api.FakePullRequestsDismissReview = func(ctx context.Context, number int, reviewID int64, review *github.PullRequestReviewDismissalRequest) (*github.PullRequestReview, *github.Response, error) {
+				dismissed = append(dismissed, reviewID)
+				posted = append(posted, "dismiss:"+strconv.FormatInt(reviewID, 10))
+				return &github.PullRequestReview{}, nil, nil
+			}
+			api.FakePullRequestsCreateReview = func(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+				posted = append(posted, "review:"+review.GetEvent())
+				return &github.PullRequestReview{}, nil, nil
+			}
+			api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				posted = append(posted, "comment")
+				return comment, nil, nil
+			}
+			client.API = &api
+			if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.dismissed, dismissed); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.posted, posted); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNotifyReviewPostedTwice(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		setConfig func(cfg *Config)
+		// reviews is the number of reviews posted by the second run
+		reviews int
+		updated []string
+	}{
+		{
+			name: "skip the duplicated review",
+			setConfig: func(cfg *Config) {
+				cfg.SkipDuplicateComment = true
+			},
+		},
+		{
+			name: "skip the review with the same idempotency key",
+			setConfig: func(cfg *Config) {
+				cfg.IdempotencyKey = "run-1"
+			},
+		},
+		{
+			name: "hide the old review",
+			setConfig: func(cfg *Config) {
+				cfg.OldComment = OldComment{Action: OldCommentActionMinimize}
+			},
+			reviews: 1,
+			updated: []string{"<details><summary>The plan result is outdated</summary>"},
+		},
+		{
+			name: "replace the body of the old review",
+			setConfig: func(cfg *Config) {
+				cfg.OldComment = OldComment{Action: OldCommentActionDelete}
+			},
+			reviews: 1,
+			updated: []string{"The plan result is outdated."},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := newFakeConfig()
+			cfg.Review = Review{Enabled: true, Event: ReviewEventComment}
+			testCase.setConfig(&cfg)
+			var (
+				reviews []*github.PullRequestReview
+				updated []string
+			)
+			api := newFakeAPI()
+			api.FakePullRequestsListReviews = func(ctx context.Context, number int, opt *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error) {
+				return reviews, nil, nil
+			}
+			api.FakePullRequestsCreateReview = func(ctx context.Context, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
+				r := &github.PullRequestReview{
+					ID:    github.Int64(int64(len(reviews) + 1)),
+					State: github.String("COMMENTED"),
+					Body:  review.Body,
+				}
+				reviews = append(reviews, r)
+				return r, nil, nil
+			}
+			api.FakePullRequestsUpdateReview = func(ctx context.Context, number int, reviewID int64, body string) (*github.PullRequestReview, *github.Response, error) {
+				if reviewID != 1 {
+					t.Errorf("only the old review should be updated: %d", reviewID)
+				}
+				updated = append(updated, strings.SplitN(body, "\n", 2)[0]) //nolint:gomnd
+				return &github.PullRequestReview{}, nil, nil
+			}
+			for i := 0; i < 2; i++ {
+				client, err := NewClient(context.Background(), cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				client.API = &api
+				if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+					CombinedOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if n := len(reviews) - 1; n != testCase.reviews {
+				t.Errorf("the second run should post %d reviews but posted %d", testCase.reviews, n)
+			}
+			if diff := cmp.Diff(testCase.updated, updated); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
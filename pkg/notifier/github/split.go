@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// maxCommentLength is the maximum length of GitHub comments
+const maxCommentLength = 65536
+
+// partHeaderLength is the length reserved for the header of split comments like "**Part 1/3**"
+const partHeaderLength = 32
+
+// postParts posts the parts of the split comment as sequential comments.
+// Each part has the embedded metadata with the part number, so old comments are handled as one unit.
+// The first part is returned as the posted comment
+func (g *NotifyService) postParts(ctx context.Context, cfg *Config, parts []string, ciName, command string, hasDestroy bool) (*notifier.PostedComment, error) {
+	var first *notifier.PostedComment
+	for i, part := range parts {
+		embeddedComment, err := getPartEmbeddedComment(cfg, ciName, command, i+1, len(parts))
+		if err != nil {
+			return nil, err
+		}
+		part += embeddedComment
+		if i == 0 {
+			// the first part is posted as a pull request review if the review mode is enabled
+			first, err = g.post(ctx, cfg, part, command, hasDestroy)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := g.client.Comment.Post(ctx, part, PostOptions{
+			Number:   cfg.PR.Number,
+			Revision: cfg.PR.Revision,
+		}); err != nil {
+			return nil, fmt.Errorf("post the part %d/%d of the comment: %w", i+1, len(parts), err)
+		}
+	}
+	return first, nil
+}
+
+// splitParts splits the body into parts with the header like "**Part 1/3**".
+// The embedded metadata isn't added, so the parts can be compared with the latest comment before they are posted
+func splitParts(cfg *Config, body, ciName, command string) ([]string, error) {
+	// the metadata of the last part is the longest because the part number has the most digits
+	longest, err := getPartEmbeddedComment(cfg, ciName, command, maxCommentLength, maxCommentLength)
+	if err != nil {
+		return nil, err
+	}
+	parts := splitComment(body, maxCommentLength-len(longest)-partHeaderLength)
+	for i, part := range parts {
+		parts[i] = fmt.Sprintf("**Part %d/%d**\n\n", i+1, len(parts)) + part
+	}
+	return parts, nil
+}
+
+// splitComment splits the body at line breaks into parts whose lengths are at most maxLength.
+// If a part ends in a code block or <details>, they are closed at the end of the part and reopened at the beginning of the next part
+func splitComment(body string, maxLength int) []string {
+	if len(body) <= maxLength {
+		return []string{body}
+	}
+	var parts []string
+	buf := &strings.Builder{}
+	fence := ""
+	details := 0
+	reopened := 0
+	for _, line := range splitLongLines(strings.Split(body, "\n"), maxLength/2) { //nolint:gomnd
+		nextFence, nextDetails := nextBlocks(line, fence, details)
+		// The last line break of the part is trimmed, so the length of the part is buf + line + closing
+		if buf.Len() > reopened && buf.Len()+len(line)+len(closeBlocks(nextFence, nextDetails)) > maxLength {
+			buf.WriteString(closeBlocks(fence, details))
+			parts = append(parts, strings.TrimSuffix(buf.String(), "\n"))
+			buf.Reset()
+			buf.WriteString(reopenBlocks(fence, details))
+			reopened = buf.Len()
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+		fence, details = nextFence, nextDetails
+	}
+	return append(parts, strings.TrimSuffix(buf.String(), "\n"))
+}
+
+// nextBlocks returns the opening line of the code block and the depth of <details> after the line
+func nextBlocks(line, fence string, details int) (string, int) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "```") {
+		if fence == "" {
+			return trimmed, details
+		}
+		return "", details
+	}
+	if fence != "" {
+		return fence, details
+	}
+	details += strings.Count(line, "<details>") - strings.Count(line, "</details>")
+	if details < 0 {
+		return fence, 0
+	}
+	return fence, details
+}
+
+func closeBlocks(fence string, details int) string {
+	s := ""
+	if fence != "" {
+		s = "```\n"
+	}
+	return s + strings.Repeat("</details>\n", details)
+}
+
+func reopenBlocks(fence string, details int) string {
+	s := strings.Repeat("<details><summary>(continued)</summary>\n\n", details)
+	if fence != "" {
+		s += fence + "\n"
+	}
+	return s
+}
+
+// splitLongLines splits lines longer than maxLength at rune boundaries
+func splitLongLines(lines []string, maxLength int) []string {
+	ret := make([]string, 0, len(lines))
+	for _, line := range lines {
+		for len(line) > maxLength {
+			i := maxLength
+			for i > 0 && !utf8.RuneStart(line[i]) {
+				i--
+			}
+			ret = append(ret, line[:i])
+			line = line[i:]
+		}
+		ret = append(ret, line)
+	}
+	return ret
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v39/github"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestSplitComment(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		body      string
+		maxLength int
+		exp       []string
+	}{
+		{
+			name:      "short body",
+			body:      "foo\nbar",
+			maxLength: 100,
+			exp:       []string{"foo\nbar"},
+		},
+		{
+			name:      "split at line breaks",
+			body:      "aaaa\nbbbb\ncccc",
+			maxLength: 10,
+			exp:       []string{"aaaa\nbbbb", "cccc"},
+		},
+		{
+			name:      "code block",
+			body:      "```hcl\naaaa\nbbbb\ncccc\n```",
+			maxLength: 20,
+			exp:       []string{"```hcl\naaaa\nbbbb\n```", "```hcl\ncccc\n```"},
+		},
+		{
+			name:      "details",
+			body:      "<details><summary>x</summary>\n\naaaaaaaaaa\nbbbbbbbbbb\ncccccccccc\n</details>",
+			maxLength: 64,
+			exp: []string{
+				"<details><summary>x</summary>\n\naaaaaaaaaa\nbbbbbbbbbb\n</details>",
+				"<details><summary>(continued)</summary>\n\ncccccccccc\n</details>",
+			},
+		},
+		{
+			name:      "long line",
+			body:      "aaaaaaaaaaaa",
+			maxLength: 10,
+			exp:       []string{"aaaaa", "aaaaa\naa"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			parts := splitComment(testCase.body, testCase.maxLength)
+			if diff := cmp.Diff(testCase.exp, parts); diff != "" {
+				t.Error(diff)
+			}
+			for _, part := range parts {
+				if len(part) > testCase.maxLength {
+					t.Errorf("the part is too long: %d > %d", len(part), testCase.maxLength)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifySplitComment(t *testing.T) {
+	t.Parallel()
+	cfg := newFakeConfig()
+	cfg.Template = terraform.NewPlanTemplate("{{.CombinedOutput}}")
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var posted []string
+	api := newFakeAPI()
+	api.FakeIssuesCreateComment = func(ctx context.Context, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+		posted = append(posted, comment.GetBody())
+		return comment, nil, nil
+	}
+	client.API = &api
+	line := strings.Repeat("a", 99) + "\n"
+	if _, err := client.Notify.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: strings.Repeat(line, 1000) + "Plan: 1 to add, 0 to change, 0 to destroy.",
+		ExitCode:       2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 2 {
+		t.Fatalf("the comment should be split into 2 parts but %d comments are posted", len(posted))
+	}
+	for i, body := range posted {
+		if len(body) > maxCommentLength {
+			t.Errorf("the part %d is too long: %d", i+1, len(body))
+		}
+		meta, ok := matchMetadata(body, "tfcmt", "plan", "")
+		if !ok {
+			t.Fatalf("the metadata isn't embedded in the part %d", i+1)
+		}
+		if meta.Part != i+1 || meta.Parts != 2 {
+			t.Errorf("got part %d/%d but want %d/2", meta.Part, meta.Parts, i+1)
+		}
+	}
+	if !strings.HasPrefix(posted[1], "**Part 2/2**") {
+		t.Errorf("the header of the part is wrong: %s", posted[1][:20])
+	}
+}
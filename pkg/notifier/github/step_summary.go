@@ -0,0 +1,27 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvStepSummary is the path of the file of the job summary. GitHub Actions sets this
+const EnvStepSummary = "GITHUB_STEP_SUMMARY"
+
+// appendStepSummary appends the body to the job summary of GitHub Actions.
+// If the path is empty, nothing is done because the job isn't run on GitHub Actions
+func appendStepSummary(p, body string) error {
+	if p == "" {
+		return nil
+	}
+	f, err := os.OpenFile(p, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec,gomnd
+	if err != nil {
+		return fmt.Errorf("open the job summary %s: %w", p, err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprint(f, strings.TrimRight(body, "\n")+"\n\n"); err != nil {
+		return fmt.Errorf("write the job summary %s: %w", p, err)
+	}
+	return nil
+}
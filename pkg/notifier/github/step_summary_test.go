@@ -0,0 +1,32 @@
+package github
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendStepSummary(t *testing.T) {
+	t.Parallel()
+	p := filepath.Join(t.TempDir(), "summary.md")
+	if err := ioutil.WriteFile(p, []byte("## Test\n\n"), 0o644); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	if err := appendStepSummary(p, "## Plan Result (foo)\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := appendStepSummary(p, "## Plan Result (bar)"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "## Test\n\n## Plan Result (foo)\n\n## Plan Result (bar)\n\n"
+	if string(b) != exp {
+		t.Errorf("got %q, wanted %q", string(b), exp)
+	}
+	if err := appendStepSummary("", "foo"); err != nil {
+		t.Errorf("nothing should be done if the path is empty: %v", err)
+	}
+}
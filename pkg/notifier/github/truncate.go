@@ -0,0 +1,160 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// Strategies to truncate the comment
+const (
+	TruncateStrategyHead     = "head"
+	TruncateStrategyTail     = "tail"
+	TruncateStrategyHeadTail = "head_tail"
+)
+
+// Truncate is a configuration to shrink the comment if it is longer than the maximum length of GitHub comments.
+// If neither Strategy nor DropSections is set, the comment is split into multiple comments
+type Truncate struct {
+	// Strategy is one of "head", "tail", and "head_tail"
+	Strategy string
+	// DropSections are the names of template variables such as "ChangeOutsideTerraform" which are emptied in order before the comment is truncated
+	DropSections []string
+	// MaxLength is the maximum length of the comment. The default is the maximum length of GitHub comments
+	MaxLength int
+}
+
+// truncateNoteLength is the length reserved for the note of omitted bytes
+const truncateNoteLength = 128
+
+func (truncate *Truncate) enabled() bool {
+	return truncate.Strategy != "" || len(truncate.DropSections) != 0
+}
+
+func (truncate *Truncate) maxLength() int {
+	if truncate.MaxLength <= 0 || truncate.MaxLength > maxCommentLength {
+		return maxCommentLength
+	}
+	return truncate.MaxLength
+}
+
+// shrinkBody empties DropSections in order and re-renders the comment until the comment fits in maxLength.
+// If the comment is still too long, the comment is truncated by Strategy. The omitted byte count is noted in the comment
+func shrinkBody(truncate *Truncate, body string, maxLength int, tplValue terraform.CommonTemplate, render func(terraform.CommonTemplate) (string, error)) (string, error) {
+	omitted := 0
+	var dropped []string
+	for _, section := range truncate.DropSections {
+		if len(body) <= maxLength {
+			break
+		}
+		n := dropSection(&tplValue, section)
+		if n == 0 {
+			continue
+		}
+		b, err := render(tplValue)
+		if err != nil {
+			return "", err
+		}
+		body = b
+		omitted += n
+		dropped = append(dropped, section)
+	}
+	if len(body) > maxLength && truncate.Strategy != "" {
+		b, n := truncateBody(body, truncate.Strategy, maxLength-truncateNoteLength)
+		body = b
+		omitted += n
+	}
+	if omitted == 0 {
+		return body, nil
+	}
+	note := fmt.Sprintf("The comment is too long, so %d bytes are omitted.", omitted)
+	if len(dropped) != 0 {
+		note += " Omitted sections: " + strings.Join(dropped, ", ")
+	}
+	return body + "\n\n:scissors: " + note, nil
+}
+
+// dropSection empties the template variable and returns the number of omitted bytes
+func dropSection(tplValue *terraform.CommonTemplate, section string) int {
+	var field *string
+	switch section {
+	case "ChangedResult":
+		field = &tplValue.ChangedResult
+	case "ChangeOutsideTerraform":
+		field = &tplValue.ChangeOutsideTerraform
+	case "Warning":
+		field = &tplValue.Warning
+	case "Result":
+		field = &tplValue.Result
+	case "Stdout":
+		field = &tplValue.Stdout
+	case "Stderr":
+		field = &tplValue.Stderr
+	case "CombinedOutput":
+		field = &tplValue.CombinedOutput
+	default:
+		return 0
+	}
+	n := len(*field)
+	*field = ""
+	return n
+}
+
+// truncateBody truncates the body at line breaks so that the length is at most maxLength, and returns the number of omitted bytes.
+// Code blocks and <details> which are cut are closed or reopened
+func truncateBody(body, strategy string, maxLength int) (string, int) {
+	lines := splitLongLines(strings.Split(body, "\n"), maxLength/2) //nolint:gomnd
+	type blocks struct {
+		fence   string
+		details int
+	}
+	// states[i] is the state of blocks before lines[i]
+	states := make([]blocks, len(lines)+1)
+	for i, line := range lines {
+		fence, details := nextBlocks(line, states[i].fence, states[i].details)
+		states[i+1] = blocks{fence: fence, details: details}
+	}
+	// head returns the number of lines kept from the beginning
+	head := func(maxLength int) int {
+		length := 0
+		for i, line := range lines {
+			length += len(line) + 1
+			if length-1+len(closeBlocks(states[i+1].fence, states[i+1].details)) > maxLength {
+				return i
+			}
+		}
+		return len(lines)
+	}
+	// tail returns the index of the first line kept at the end
+	tail := func(maxLength, minIndex int) int {
+		length := -1
+		for i := len(lines) - 1; i >= minIndex; i-- {
+			length += len(lines[i]) + 1
+			if length+len(reopenBlocks(states[i].fence, states[i].details)) > maxLength {
+				return i + 1
+			}
+		}
+		return minIndex
+	}
+	keep := func(start, end int) string {
+		s := reopenBlocks(states[start].fence, states[start].details) + strings.Join(lines[start:end], "\n")
+		if closing := closeBlocks(states[end].fence, states[end].details); closing != "" {
+			s += "\n" + strings.TrimSuffix(closing, "\n")
+		}
+		return s
+	}
+	switch strategy {
+	case TruncateStrategyTail:
+		start := tail(maxLength, 0)
+		return keep(start, len(lines)), len(body) - len(strings.Join(lines[start:], "\n"))
+	case TruncateStrategyHeadTail:
+		end := head(maxLength / 2)      //nolint:gomnd
+		start := tail(maxLength/2, end) //nolint:gomnd
+		kept := len(strings.Join(lines[:end], "\n")) + len(strings.Join(lines[start:], "\n"))
+		return keep(0, end) + "\n\n...\n\n" + keep(start, len(lines)), len(body) - kept
+	default:
+		end := head(maxLength)
+		return keep(0, end), len(body) - len(strings.Join(lines[:end], "\n"))
+	}
+}
@@ -0,0 +1,143 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestTruncateBody(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		body      string
+		strategy  string
+		maxLength int
+		exp       string
+		omitted   int
+	}{
+		{
+			name:      "head",
+			body:      "aaaa\nbbbb\ncccc\ndddd",
+			strategy:  TruncateStrategyHead,
+			maxLength: 10,
+			exp:       "aaaa\nbbbb",
+			omitted:   10,
+		},
+		{
+			name:      "tail",
+			body:      "aaaa\nbbbb\ncccc\ndddd",
+			strategy:  TruncateStrategyTail,
+			maxLength: 10,
+			exp:       "cccc\ndddd",
+			omitted:   10,
+		},
+		{
+			name:      "head_tail",
+			body:      "aaaa\nbbbb\ncccc\ndddd",
+			strategy:  TruncateStrategyHeadTail,
+			maxLength: 10,
+			exp:       "aaaa\n\n...\n\ndddd",
+			omitted:   11,
+		},
+		{
+			name:      "close the code block",
+			body:      "```hcl\naaaa\nbbbb\ncccc\n```",
+			strategy:  TruncateStrategyHead,
+			maxLength: 20,
+			exp:       "```hcl\naaaa\nbbbb\n```",
+			omitted:   9,
+		},
+		{
+			name:      "reopen the code block",
+			body:      "```hcl\naaaa\nbbbb\ncccc\n```",
+			strategy:  TruncateStrategyTail,
+			maxLength: 20,
+			exp:       "```hcl\nbbbb\ncccc\n```",
+			omitted:   12,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			body, omitted := truncateBody(testCase.body, testCase.strategy, testCase.maxLength)
+			if diff := cmp.Diff(testCase.exp, body); diff != "" {
+				t.Fatal(diff)
+			}
+			if omitted != testCase.omitted {
+				t.Fatalf("omitted = %d, wanted %d", omitted, testCase.omitted)
+			}
+		})
+	}
+}
+
+func TestShrinkBody(t *testing.T) {
+	t.Parallel()
+	render := func(tplValue terraform.CommonTemplate) (string, error) {
+		return tplValue.ChangedResult + "\n" + tplValue.ChangeOutsideTerraform, nil
+	}
+	testCases := []struct {
+		name      string
+		truncate  Truncate
+		tplValue  terraform.CommonTemplate
+		maxLength int
+		exp       string
+	}{
+		{
+			name: "drop a section",
+			truncate: Truncate{
+				DropSections: []string{"ChangeOutsideTerraform", "ChangedResult"},
+			},
+			tplValue: terraform.CommonTemplate{
+				ChangedResult:          "foo",
+				ChangeOutsideTerraform: strings.Repeat("x", 100),
+			},
+			maxLength: 10,
+			exp:       "foo\n\n\n:scissors: The comment is too long, so 100 bytes are omitted. Omitted sections: ChangeOutsideTerraform",
+		},
+		{
+			name: "truncate after dropping sections",
+			truncate: Truncate{
+				Strategy:     TruncateStrategyHead,
+				DropSections: []string{"ChangeOutsideTerraform"},
+			},
+			tplValue: terraform.CommonTemplate{
+				ChangedResult:          strings.Repeat("a\n", 200),
+				ChangeOutsideTerraform: "bar",
+			},
+			maxLength: 138,
+			exp:       "a\na\na\na\na\n\n:scissors: The comment is too long, so 395 bytes are omitted. Omitted sections: ChangeOutsideTerraform",
+		},
+		{
+			name: "short body",
+			truncate: Truncate{
+				Strategy: TruncateStrategyTail,
+			},
+			tplValue: terraform.CommonTemplate{
+				ChangedResult: "foo",
+			},
+			maxLength: 100,
+			exp:       "foo\n",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			body, err := render(testCase.tplValue)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err = shrinkBody(&testCase.truncate, body, testCase.maxLength, testCase.tplValue, render)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.exp, body); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package gitlab
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// EnvToken is GitLab API Token
+const EnvToken = "GITLAB_TOKEN" //nolint:gosec
+
+// EnvJobToken is the job token of GitLab CI. The job token can't be used because it doesn't have the permission to create notes
+const EnvJobToken = "CI_JOB_TOKEN" //nolint:gosec
+
+// EnvBaseURL is the URL of GitLab. This can be set to the URL of a self-hosted GitLab.
+const EnvBaseURL = "GITLAB_BASE_URL"
+
+// EnvAPIURL is the URL of GitLab REST API v4. GitLab CI sets this to the API endpoint of the GitLab which runs the pipeline.
+const EnvAPIURL = "CI_API_V4_URL"
+
+const defaultBaseURL = "https://gitlab.com"
+
+// Client is a API client for GitLab
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for GitLab client
+type Config struct {
+	Token string
+	// BaseURL is the URL of GitLab like https://gitlab.example.com. The default value is https://gitlab.com
+	BaseURL string
+	// Owner is the namespace of the project, which can include subgroups
+	Owner string
+	Repo  string
+	MR    MergeRequestInfo
+	CI    string
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// Template is used for all Terraform command output
+	Template           *terraform.Template
+	ParseErrorTemplate *terraform.Template
+	// ResultLabels is a set of labels to apply depending on the plan result
+	ResultLabels     notifier.ResultLabels
+	Vars             map[string]string
+	EmbeddedVarNames []string
+	Templates        map[string]string
+	UseRawOutput     bool
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	// MaxResources is the maximum number of resources listed per action in the built-in templates. 0 means unlimited
+	MaxResources int
+	// Tag is embedded into comments as the metadata "Program". The default value is "tfcmt"
+	Tag string
+	// DryRun renders the comment but doesn't post it and doesn't update labels.
+	// The comment is written to DryRunOutput. If DryRunOutput is empty, the comment is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+	// Output is the file path where the posted comment is also written. If this is empty, the comment isn't written
+	Output string
+}
+
+// MergeRequestInfo represents GitLab Merge Request metadata
+type MergeRequestInfo struct {
+	Revision string
+	// Number is the internal id (iid) of the merge request
+	Number int
+}
+
+// IsNumber returns true if MergeRequestInfo is Merge Request build
+func (mr *MergeRequestInfo) IsNumber() bool {
+	return mr.Number != 0
+}
+
+// defaultTag is the default value of Config.Tag
+const defaultTag = "tfcmt"
+
+// program returns the value of the metadata "Program"
+func (cfg *Config) program() string {
+	if cfg.Tag == "" {
+		return defaultTag
+	}
+	return cfg.Tag
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	token := strings.TrimPrefix(cfg.Token, "$")
+	if token == EnvToken || token == "" {
+		token = os.Getenv(EnvToken)
+	}
+	if token == "" && !cfg.DryRun {
+		return &Client{}, errors.New("gitlab token is missing")
+	}
+	if !cfg.DryRun && isJobToken(token) {
+		return &Client{}, errors.New("CI_JOB_TOKEN can't create notes of merge requests. Please set a project access token or a personal access token with the scope api to GITLAB_TOKEN")
+	}
+	return &Client{
+		Config: cfg,
+		API:    newGitLab(http.DefaultClient, getAPIURL(cfg.BaseURL), token, cfg.Owner+"/"+cfg.Repo),
+	}, nil
+}
+
+// isJobToken returns true if the token is the job token of GitLab CI
+func isJobToken(token string) bool {
+	if token == EnvJobToken {
+		return true
+	}
+	jobToken := os.Getenv(EnvJobToken)
+	return jobToken != "" && token == jobToken
+}
+
+// NewNotifier returns a notifier.Notifier which posts the result to GitLab.
+// If Parser and templates aren't set, the ones for terraform plan are used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	_, isApply := cfg.Parser.(*terraform.ApplyParser)
+	if p, ok := cfg.Parser.(*terraform.JSONParser); ok {
+		isApply = p.Command == terraform.CommandApply
+	}
+	// If AutoParser is used, templates are decided by the detected command
+	_, isAuto := cfg.Parser.(*terraform.AutoParser)
+	if cfg.Template == nil && !isAuto {
+		if isApply {
+			cfg.Template = terraform.NewApplyTemplate("")
+		} else {
+			cfg.Template = terraform.NewPlanTemplate("")
+		}
+	}
+	if cfg.ParseErrorTemplate == nil && !isAuto {
+		if isApply {
+			cfg.ParseErrorTemplate = terraform.NewApplyParseErrorTemplate("")
+		} else {
+			cfg.ParseErrorTemplate = terraform.NewPlanParseErrorTemplate("")
+		}
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
+
+// getAPIURL returns the URL of GitLab REST API v4.
+// If the base URL isn't set, GITLAB_BASE_URL and CI_API_V4_URL are used in this order
+func getAPIURL(baseURL string) string {
+	baseURL = strings.TrimPrefix(baseURL, "$")
+	if baseURL == "" || baseURL == EnvBaseURL {
+		baseURL = os.Getenv(EnvBaseURL)
+	}
+	if baseURL == "" {
+		if apiURL := os.Getenv(EnvAPIURL); apiURL != "" {
+			return strings.TrimSuffix(apiURL, "/")
+		}
+		baseURL = defaultBaseURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/api/v4"
+}
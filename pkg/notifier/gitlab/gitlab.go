@@ -0,0 +1,158 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// API is GitLab API interface
+type API interface {
+	CreateMergeRequestNote(ctx context.Context, iid int, body string) error
+	CreateCommitComment(ctx context.Context, sha, note string) error
+	GetMergeRequest(ctx context.Context, iid int) (*MergeRequest, error)
+	ListMergeRequestsByCommit(ctx context.Context, sha string) ([]*MergeRequest, error)
+	UpdateMergeRequestLabels(ctx context.Context, iid int, labelsToAdd, labelsToRemove []string) error
+	CreateLabel(ctx context.Context, name, color string) error
+}
+
+// MergeRequest is a subset of the merge request of GitLab API
+type MergeRequest struct {
+	IID    int      `json:"iid"`
+	State  string   `json:"state"`
+	Labels []string `json:"labels"`
+}
+
+// Error is an error response of GitLab API
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("GitLab API returned the status code %d: %s", e.StatusCode, e.Message)
+}
+
+// GitLab calls GitLab REST API v4 of the project
+type GitLab struct {
+	client *http.Client
+	// apiURL is the URL of GitLab REST API v4 like https://gitlab.com/api/v4
+	apiURL  string
+	token   string
+	project string
+}
+
+// newGitLab returns GitLab. The project is the path with namespace like "suzuki-shunsuke/tfcmt"
+func newGitLab(client *http.Client, apiURL, token, project string) *GitLab {
+	return &GitLab{
+		client:  client,
+		apiURL:  strings.TrimSuffix(apiURL, "/"),
+		token:   token,
+		project: url.PathEscape(project),
+	}
+}
+
+// CreateMergeRequestNote is a wrapper of https://docs.gitlab.com/ee/api/notes.html#create-new-merge-request-note
+func (g *GitLab) CreateMergeRequestNote(ctx context.Context, iid int, body string) error {
+	return g.do(ctx, http.MethodPost, "/merge_requests/"+strconv.Itoa(iid)+"/notes", map[string]string{
+		"body": body,
+	}, nil)
+}
+
+// CreateCommitComment is a wrapper of https://docs.gitlab.com/ee/api/commits.html#post-comment-to-commit
+func (g *GitLab) CreateCommitComment(ctx context.Context, sha, note string) error {
+	return g.do(ctx, http.MethodPost, "/repository/commits/"+url.PathEscape(sha)+"/comments", map[string]string{
+		"note": note,
+	}, nil)
+}
+
+// GetMergeRequest is a wrapper of https://docs.gitlab.com/ee/api/merge_requests.html#get-single-mr
+func (g *GitLab) GetMergeRequest(ctx context.Context, iid int) (*MergeRequest, error) {
+	mr := &MergeRequest{}
+	if err := g.do(ctx, http.MethodGet, "/merge_requests/"+strconv.Itoa(iid), nil, mr); err != nil {
+		return nil, err
+	}
+	return mr, nil
+}
+
+// ListMergeRequestsByCommit is a wrapper of https://docs.gitlab.com/ee/api/commits.html#list-merge-requests-associated-with-a-commit
+func (g *GitLab) ListMergeRequestsByCommit(ctx context.Context, sha string) ([]*MergeRequest, error) {
+	var mrs []*MergeRequest
+	if err := g.do(ctx, http.MethodGet, "/repository/commits/"+url.PathEscape(sha)+"/merge_requests", nil, &mrs); err != nil {
+		return nil, err
+	}
+	return mrs, nil
+}
+
+// UpdateMergeRequestLabels is a wrapper of https://docs.gitlab.com/ee/api/merge_requests.html#update-mr .
+// Labels which don't exist in the project are created automatically
+func (g *GitLab) UpdateMergeRequestLabels(ctx context.Context, iid int, labelsToAdd, labelsToRemove []string) error {
+	return g.do(ctx, http.MethodPut, "/merge_requests/"+strconv.Itoa(iid), map[string]string{
+		"add_labels":    strings.Join(labelsToAdd, ","),
+		"remove_labels": strings.Join(labelsToRemove, ","),
+	}, nil)
+}
+
+// CreateLabel is a wrapper of https://docs.gitlab.com/ee/api/labels.html#create-a-new-label .
+// If the label already exists, the label isn't changed and no error is returned
+func (g *GitLab) CreateLabel(ctx context.Context, name, color string) error {
+	err := g.do(ctx, http.MethodPost, "/labels", map[string]string{
+		"name":  name,
+		"color": color,
+	}, nil)
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusConflict {
+		return nil
+	}
+	return err
+}
+
+// do calls the API of the project. The request body and the response body are JSON
+func (g *GitLab) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal the request body: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.apiURL+"/projects/"+g.project+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call GitLab API: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read the response body: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &Error{
+			StatusCode: resp.StatusCode,
+			Message:    string(b),
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("parse the response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	API
+	notes          map[int][]string
+	commitComments map[string][]string
+	labels         map[int][]string
+	createdLabels  map[string]string
+	mrsByCommit    map[string][]*MergeRequest
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		notes:          map[int][]string{},
+		commitComments: map[string][]string{},
+		labels:         map[int][]string{},
+		createdLabels:  map[string]string{},
+		mrsByCommit:    map[string][]*MergeRequest{},
+	}
+}
+
+func (g *fakeAPI) CreateMergeRequestNote(ctx context.Context, iid int, body string) error {
+	g.notes[iid] = append(g.notes[iid], body)
+	return nil
+}
+
+func (g *fakeAPI) CreateCommitComment(ctx context.Context, sha, note string) error {
+	g.commitComments[sha] = append(g.commitComments[sha], note)
+	return nil
+}
+
+func (g *fakeAPI) GetMergeRequest(ctx context.Context, iid int) (*MergeRequest, error) {
+	return &MergeRequest{
+		IID:    iid,
+		Labels: g.labels[iid],
+	}, nil
+}
+
+func (g *fakeAPI) ListMergeRequestsByCommit(ctx context.Context, sha string) ([]*MergeRequest, error) {
+	return g.mrsByCommit[sha], nil
+}
+
+func (g *fakeAPI) UpdateMergeRequestLabels(ctx context.Context, iid int, labelsToAdd, labelsToRemove []string) error {
+	labels := []string{}
+	for _, label := range g.labels[iid] {
+		if !containsString(labelsToRemove, label) {
+			labels = append(labels, label)
+		}
+	}
+	g.labels[iid] = append(labels, labelsToAdd...)
+	return nil
+}
+
+func (g *fakeAPI) CreateLabel(ctx context.Context, name, color string) error {
+	g.createdLabels[name] = color
+	return nil
+}
+
+func containsString(arr []string, s string) bool {
+	for _, a := range arr {
+		if a == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGitLab(t *testing.T) {
+	t.Parallel()
+	type request struct {
+		Method string
+		Path   string
+		Token  string
+		Body   map[string]string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{
+			Method: r.Method,
+			Path:   r.URL.EscapedPath(),
+			Token:  r.Header.Get("PRIVATE-TOKEN"),
+		}
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&req.Body)
+		}
+		requests = append(requests, req)
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/suzuki-shunsuke%2Finfra%2Ftfcmt/labels":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"Label already exists"}`))
+		case "/api/v4/projects/suzuki-shunsuke%2Finfra%2Ftfcmt/merge_requests/1":
+			_, _ = w.Write([]byte(`{"iid":1,"state":"opened","labels":["destroy"]}`))
+		case "/api/v4/projects/suzuki-shunsuke%2Finfra%2Ftfcmt/merge_requests/2/notes":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Not found"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	g := newGitLab(server.Client(), server.URL+"/api/v4/", "xxx", "suzuki-shunsuke/infra/tfcmt")
+	if err := g.CreateMergeRequestNote(ctx, 1, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CreateLabel(ctx, "destroy", "#d93f0b"); err != nil {
+		t.Fatalf("conflict should be ignored: %v", err)
+	}
+	mr, err := g.GetMergeRequest(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&MergeRequest{IID: 1, State: "opened", Labels: []string{"destroy"}}, mr); diff != "" {
+		t.Error(diff)
+	}
+	if err := g.UpdateMergeRequestLabels(ctx, 1, []string{"no-changes"}, []string{"destroy", "add-or-update"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CreateMergeRequestNote(ctx, 2, "hello"); err == nil {
+		t.Fatal("error should be returned")
+	}
+	exp := []request{
+		{
+			Method: http.MethodPost,
+			Path:   "/api/v4/projects/suzuki-shunsuke%2Finfra%2Ftfcmt/merge_requests/1/notes",
+			Token:  "xxx",
+			Body:   map[string]string{"body": "hello"},
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "/api/v4/projects/suzuki-shunsuke%2Finfra%2Ftfcmt/labels",
+			Token:  "xxx",
+			Body:   map[string]string{"name": "destroy", "color": "#d93f0b"},
+		},
+		{
+			Method: http.MethodGet,
+			Path:   "/api/v4/projects/suzuki-shunsuke%2Finfra%2Ftfcmt/merge_requests/1",
+			Token:  "xxx",
+		},
+		{
+			Method: http.MethodPut,
+			Path:   "/api/v4/projects/suzuki-shunsuke%2Finfra%2Ftfcmt/merge_requests/1",
+			Token:  "xxx",
+			Body:   map[string]string{"add_labels": "no-changes", "remove_labels": "destroy,add-or-update"},
+		},
+		{
+			Method: http.MethodPost,
+			Path:   "/api/v4/projects/suzuki-shunsuke%2Finfra%2Ftfcmt/merge_requests/2/notes",
+			Token:  "xxx",
+			Body:   map[string]string{"body": "hello"},
+		},
+	}
+	if diff := cmp.Diff(exp, requests); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,180 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/github-comment-metadata/metadata"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService posts the result as a note of the merge request or a comment of the commit
+type NotifyService struct {
+	client *Client
+}
+
+// Notify posts comment optimized for notifications
+func (g *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := g.client.Config
+	var errMsgs []string
+
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+
+	if result.IsPlan && !cfg.DryRun && cfg.MR.IsNumber() && cfg.ResultLabels.HasAnyLabelDefined() {
+		label, color := cfg.ResultLabels.LabelOf(result.ParseResult)
+		errMsgs = append(errMsgs, g.swapResultLabel(ctx, cfg.MR.Number, cfg.ResultLabels.Name(label), color)...)
+	}
+
+	if result.IsApply && !cfg.DryRun && !cfg.MR.IsNumber() && cfg.MR.Revision != "" {
+		// the merge request is found by the merged commit
+		mrs, err := g.client.API.ListMergeRequestsByCommit(ctx, cfg.MR.Revision)
+		if err != nil {
+			return result.ExitCode, fmt.Errorf("list merge requests associated with the commit: %w", err)
+		}
+		if mr := findMergedMR(mrs); mr != nil {
+			cfg.MR.Number = mr.IID
+			if cfg.ResultLabels.HasApplyLabelDefined() {
+				label, color := cfg.ResultLabels.AppliedLabel, cfg.ResultLabels.AppliedLabelColor
+				if result.HasApplyError {
+					label, color = cfg.ResultLabels.ApplyFailedLabel, cfg.ResultLabels.ApplyFailedLabelColor
+				}
+				g.swapResultLabel(ctx, mr.IID, cfg.ResultLabels.Name(label), color)
+			}
+		}
+	}
+
+	body, err := result.Render(notifier.RenderOption{
+		Template:           cfg.Template,
+		ParseErrorTemplate: cfg.ParseErrorTemplate,
+		ResultLabels:       cfg.ResultLabels,
+		Link:               cfg.CI,
+		Vars:               cfg.Vars,
+		Templates:          cfg.Templates,
+		UseRawOutput:       cfg.UseRawOutput,
+		MaxResources:       cfg.MaxResources,
+		ErrorMessages:      errMsgs,
+	})
+	if err != nil || body == "" {
+		return result.ExitCode, err
+	}
+
+	embeddedComment, err := getEmbeddedComment(&cfg, result.Command())
+	if err != nil {
+		return result.ExitCode, err
+	}
+	// embed HTML tag so that the note can be found by the metadata
+	body += embeddedComment
+
+	if err := g.post(ctx, &cfg, body); err != nil {
+		return result.ExitCode, err
+	}
+	if err := notifier.WriteOutput(cfg.Output, body); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+// findMergedMR returns the merged merge request. If no merge request has been merged, nil is returned
+// so that the result isn't posted to merge requests which are open or closed without being merged
+func findMergedMR(mrs []*MergeRequest) *MergeRequest {
+	for _, mr := range mrs {
+		if mr.State == "merged" {
+			return mr
+		}
+	}
+	return nil
+}
+
+// post posts a note of the merge request. If the target isn't a merge request, the result is posted as a comment of the commit
+func (g *NotifyService) post(ctx context.Context, cfg *Config, body string) error {
+	if cfg.DryRun {
+		return notifier.WriteDryRunOutput(cfg.DryRunOutput, body)
+	}
+	if cfg.MR.IsNumber() {
+		if err := g.client.API.CreateMergeRequestNote(ctx, cfg.MR.Number, body); err != nil {
+			return fmt.Errorf("create a note of the merge request: %w", err)
+		}
+		return nil
+	}
+	if cfg.MR.Revision == "" {
+		return errors.New("neither merge request number nor commit SHA is set")
+	}
+	if err := g.client.API.CreateCommitComment(ctx, cfg.MR.Revision, body); err != nil {
+		return fmt.Errorf("create a comment of the commit: %w", err)
+	}
+	return nil
+}
+
+// swapResultLabel removes result labels except for the label to add and adds the label.
+// Errors are logged and returned as messages so that the result is posted even if labels can't be updated
+func (g *NotifyService) swapResultLabel(ctx context.Context, iid int, labelToAdd, labelColor string) []string {
+	cfg := g.client.Config
+	logE := logrus.WithFields(logrus.Fields{
+		"program":       "tfcmt",
+		"merge_request": iid,
+	})
+	mr, err := g.client.API.GetMergeRequest(ctx, iid)
+	if err != nil {
+		logE.WithError(err).Error("get the merge request")
+		return []string{"get the merge request: " + err.Error()}
+	}
+	var labelsToAdd, labelsToRemove []string
+	hasLabel := false
+	for _, label := range mr.Labels {
+		if label == labelToAdd {
+			hasLabel = true
+			continue
+		}
+		if !cfg.ResultLabels.IsResultLabel(label) {
+			continue
+		}
+		if cfg.ResultLabels.IsPreserved(label) {
+			logE.WithField("label", label).Info("leave the result label in place because it is preserved")
+			continue
+		}
+		labelsToRemove = append(labelsToRemove, label)
+	}
+	var errMsgs []string
+	if labelToAdd != "" && !hasLabel {
+		if labelColor != "" {
+			// the label is created with the color. If the label already exists, the color isn't changed
+			if err := g.client.API.CreateLabel(ctx, labelToAdd, "#"+strings.TrimPrefix(labelColor, "#")); err != nil {
+				logE.WithError(err).WithField("label", labelToAdd).Error("create a label")
+				errMsgs = append(errMsgs, "create a label "+labelToAdd+": "+err.Error())
+			}
+		}
+		labelsToAdd = []string{labelToAdd}
+	}
+	if len(labelsToAdd) == 0 && len(labelsToRemove) == 0 {
+		return errMsgs
+	}
+	if err := g.client.API.UpdateMergeRequestLabels(ctx, iid, labelsToAdd, labelsToRemove); err != nil {
+		logE.WithError(err).Error("update labels of the merge request")
+		errMsgs = append(errMsgs, "update labels of the merge request: "+err.Error())
+	}
+	return errMsgs
+}
+
+func getEmbeddedComment(cfg *Config, command string) (string, error) {
+	vars := make(map[string]interface{}, len(cfg.EmbeddedVarNames))
+	for _, name := range cfg.EmbeddedVarNames {
+		vars[name] = cfg.Vars[name]
+	}
+	data := map[string]interface{}{
+		"Program":  cfg.program(),
+		"Vars":     vars,
+		"SHA1":     cfg.MR.Revision,
+		"PRNumber": cfg.MR.Number,
+		"Link":     cfg.CI,
+		"Target":   cfg.Vars["target"],
+		"Command":  command,
+	}
+	return metadata.Convert(data)
+}
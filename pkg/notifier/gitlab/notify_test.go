@@ -0,0 +1,273 @@
+package gitlab
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const planNoChanges = `No changes. Infrastructure is up-to-date.`
+
+const planDestroy = `
+Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1"
+    }
+
+Plan: 0 to add, 0 to change, 1 to destroy.
+`
+
+func newFakeConfig() Config {
+	return Config{
+		Token: "token",
+		Owner: "suzuki-shunsuke",
+		Repo:  "tfcmt",
+		MR: MergeRequestInfo{
+			Revision: "abcd",
+			Number:   1,
+		},
+		Parser:             terraform.NewPlanParser(),
+		Template:           terraform.NewPlanTemplate(terraform.DefaultPlanTemplate),
+		ParseErrorTemplate: terraform.NewPlanParseErrorTemplate(terraform.DefaultPlanParseErrorTemplate),
+		ResultLabels: notifier.ResultLabels{
+			AddOrUpdateLabel:    "add-or-update",
+			DestroyLabel:        "destroy",
+			NoChangesLabel:      "no-changes",
+			PlanErrorLabel:      "error",
+			DestroyLabelColor:   "d93f0b",
+			NoChangesLabelColor: "0e8a16",
+			Preserved:           []string{"error"},
+		},
+	}
+}
+
+func TestNotifyPlan(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name           string
+		output         string
+		labels         []string
+		expLabels      []string
+		expCreated     map[string]string
+		expNoteContain string
+	}{
+		{
+			name:           "destroy",
+			output:         planDestroy,
+			labels:         []string{"no-changes", "foo"},
+			expLabels:      []string{"foo", "destroy"},
+			expCreated:     map[string]string{"destroy": "#d93f0b"},
+			expNoteContain: "null_resource.foo",
+		},
+		{
+			name:           "no changes and the preserved label is kept",
+			output:         planNoChanges,
+			labels:         []string{"error", "no-changes"},
+			expLabels:      []string{"error", "no-changes"},
+			expCreated:     map[string]string{},
+			expNoteContain: "No changes",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := newFakeAPI()
+			api.labels[1] = testCase.labels
+			ntf := &NotifyService{
+				client: &Client{
+					Config: newFakeConfig(),
+					API:    api,
+				},
+			}
+			if _, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.expLabels, api.labels[1]); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.expCreated, api.createdLabels); diff != "" {
+				t.Error(diff)
+			}
+			if len(api.notes[1]) != 1 {
+				t.Fatalf("a note should be posted: %v", api.notes)
+			}
+			if note := api.notes[1][0]; !strings.Contains(note, testCase.expNoteContain) || !strings.Contains(note, "<!-- github-comment:") {
+				t.Errorf("unexpected note: %s", note)
+			}
+		})
+	}
+}
+
+func TestNotifyApply(t *testing.T) {
+	t.Parallel()
+	api := newFakeAPI()
+	api.mrsByCommit["abcd"] = []*MergeRequest{
+		{IID: 2, State: "closed"},
+		{IID: 3, State: "merged"},
+	}
+	api.labels[3] = []string{"destroy"}
+	cfg := newFakeConfig()
+	cfg.MR.Number = 0
+	cfg.Parser = terraform.NewApplyParser()
+	cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+	cfg.ResultLabels.AppliedLabel = "applied"
+	ntf := &NotifyService{
+		client: &Client{
+			Config: cfg,
+			API:    api,
+		},
+	}
+	if _, err := ntf.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"applied"}, api.labels[3]); diff != "" {
+		t.Error(diff)
+	}
+	if len(api.notes[3]) != 1 {
+		t.Errorf("the note should be posted to the merged merge request: %v", api.notes)
+	}
+}
+
+func TestNotifyApplyNoMergedMR(t *testing.T) {
+	t.Parallel()
+	api := newFakeAPI()
+	api.mrsByCommit["abcd"] = []*MergeRequest{
+		{IID: 2, State: "closed"},
+	}
+	api.labels[2] = []string{"destroy"}
+	cfg := newFakeConfig()
+	cfg.MR.Number = 0
+	cfg.Parser = terraform.NewApplyParser()
+	cfg.Template = terraform.NewApplyTemplate(terraform.DefaultApplyTemplate)
+	cfg.ResultLabels.AppliedLabel = "applied"
+	ntf := &NotifyService{
+		client: &Client{
+			Config: cfg,
+			API:    api,
+		},
+	}
+	if _, err := ntf.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"destroy"}, api.labels[2]); diff != "" {
+		t.Error(diff)
+	}
+	if len(api.notes[2]) != 0 {
+		t.Errorf("the note shouldn't be posted to the merge request which isn't merged: %v", api.notes)
+	}
+	if len(api.commitComments["abcd"]) != 1 {
+		t.Errorf("the comment should be posted to the commit: %v", api.commitComments)
+	}
+}
+
+func TestNotifyCommitComment(t *testing.T) {
+	t.Parallel()
+	api := newFakeAPI()
+	cfg := newFakeConfig()
+	cfg.MR.Number = 0
+	ntf := &NotifyService{
+		client: &Client{
+			Config: cfg,
+			API:    api,
+		},
+	}
+	if _, err := ntf.Notify(context.Background(), notifier.ParamExec{
+		CombinedOutput: planNoChanges,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.commitComments["abcd"]) != 1 {
+		t.Errorf("the comment should be posted to the commit: %v", api.commitComments)
+	}
+}
+
+func TestNewClientJobToken(t *testing.T) { //nolint:paralleltest
+	os.Setenv(EnvJobToken, "job-token")
+	defer os.Unsetenv(EnvJobToken)
+	testCases := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{
+			name:  "access token",
+			token: "token",
+			ok:    true,
+		},
+		{
+			name:  "job token",
+			token: "job-token",
+		},
+		{
+			name:  "reference to the job token",
+			token: "$CI_JOB_TOKEN",
+		},
+	}
+	for _, testCase := range testCases {
+		cfg := newFakeConfig()
+		cfg.Token = testCase.token
+		_, err := NewClient(cfg)
+		if testCase.ok && err != nil {
+			t.Errorf("%s: %v", testCase.name, err)
+		}
+		if !testCase.ok && err == nil {
+			t.Errorf("%s: error should be returned", testCase.name)
+		}
+	}
+}
+
+func TestGetAPIURL(t *testing.T) { //nolint:paralleltest
+	testCases := []struct {
+		name    string
+		baseURL string
+		env     map[string]string
+		exp     string
+	}{
+		{
+			name: "default",
+			exp:  "https://gitlab.com/api/v4",
+		},
+		{
+			name:    "self-hosted",
+			baseURL: "https://gitlab.example.com/",
+			env:     map[string]string{EnvAPIURL: "https://gitlab.example.org/api/v4"},
+			exp:     "https://gitlab.example.com/api/v4",
+		},
+		{
+			name: "GitLab CI",
+			env:  map[string]string{EnvAPIURL: "https://gitlab.example.org/api/v4"},
+			exp:  "https://gitlab.example.org/api/v4",
+		},
+		{
+			name: "GITLAB_BASE_URL",
+			env: map[string]string{
+				EnvBaseURL: "https://gitlab.example.com",
+				EnvAPIURL:  "https://gitlab.example.org/api/v4",
+			},
+			exp: "https://gitlab.example.com/api/v4",
+		},
+	}
+	for _, testCase := range testCases {
+		os.Setenv(EnvBaseURL, testCase.env[EnvBaseURL])
+		os.Setenv(EnvAPIURL, testCase.env[EnvAPIURL])
+		if u := getAPIURL(testCase.baseURL); u != testCase.exp {
+			t.Errorf("%s: got %s, want %s", testCase.name, u, testCase.exp)
+		}
+	}
+	os.Setenv(EnvBaseURL, "")
+	os.Setenv(EnvAPIURL, "")
+}
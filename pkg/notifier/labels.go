@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// ResultLabels represents the labels to add to the pull request depending on the plan result
+type ResultLabels struct {
+	AddOrUpdateLabel      string
+	DestroyLabel          string
+	NoChangesLabel        string
+	PlanErrorLabel        string
+	ReplaceLabel          string
+	ImportLabel           string
+	DriftLabel            string
+	AddOrUpdateLabelColor string
+	DestroyLabelColor     string
+	NoChangesLabelColor   string
+	PlanErrorLabelColor   string
+	ReplaceLabelColor     string
+	ImportLabelColor      string
+	DriftLabelColor       string
+	// CheckFailedLabel is added if any check fails. This takes precedence over the other labels of the plan result
+	CheckFailedLabel      string
+	CheckFailedLabelColor string
+	// AppliedLabel and ApplyFailedLabel replace the labels of the plan result after terraform apply
+	AppliedLabel          string
+	ApplyFailedLabel      string
+	AppliedLabelColor     string
+	ApplyFailedLabelColor string
+	// DestroyedLabel and DestroyFailedLabel replace the labels of the plan result after terraform destroy
+	DestroyedLabel          string
+	DestroyFailedLabel      string
+	DestroyedLabelColor     string
+	DestroyFailedLabelColor string
+	// ValidateFailedLabel is added when terraform validate fails and removed when it succeeds.
+	// This isn't a result label, so the labels of the plan result are kept
+	ValidateFailedLabel      string
+	ValidateFailedLabelColor string
+	// PolicyViolationLabel is added when any policy fails and removed when all policies pass.
+	// This isn't a result label either
+	PolicyViolationLabel      string
+	PolicyViolationLabelColor string
+	// Prefix is prepended to all label names
+	Prefix string
+	// Preserved is a list of label names which are never removed even if they are result labels.
+	// This protects labels added by humans
+	Preserved []string
+	// PlanErrorCategoryLabels maps error categories such as terraform.ErrorCategoryStateLock to labels.
+	// If the category of the plan error isn't in the map, PlanErrorLabel is used
+	PlanErrorCategoryLabels map[string]string
+}
+
+// HasAnyLabelDefined returns true if any of the internal labels are set
+func (r *ResultLabels) HasAnyLabelDefined() bool {
+	return r.AddOrUpdateLabel != "" || r.DestroyLabel != "" || r.NoChangesLabel != "" || r.PlanErrorLabel != "" || r.ReplaceLabel != "" || r.ImportLabel != "" || r.DriftLabel != "" || r.CheckFailedLabel != "" || len(r.PlanErrorCategoryLabels) != 0
+}
+
+// HasApplyLabelDefined returns true if any of the labels of the apply result are set
+func (r *ResultLabels) HasApplyLabelDefined() bool {
+	return r.AppliedLabel != "" || r.ApplyFailedLabel != ""
+}
+
+// HasDestroyLabelDefined returns true if any of the labels of the destroy result are set
+func (r *ResultLabels) HasDestroyLabelDefined() bool {
+	return r.DestroyedLabel != "" || r.DestroyFailedLabel != ""
+}
+
+// IsPreserved returns true if the label must not be removed
+func (r *ResultLabels) IsPreserved(label string) bool {
+	for _, l := range r.Preserved {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelOf returns the label and its color of the plan result. The prefix isn't prepended
+func (r *ResultLabels) LabelOf(result terraform.ParseResult) (string, string) {
+	switch {
+	case len(result.Checks) != 0 && r.CheckFailedLabel != "":
+		return r.CheckFailedLabel, r.CheckFailedLabelColor
+	case result.HasDriftDetected():
+		return r.DriftLabel, r.DriftLabelColor
+	case result.HasAddOrUpdateOnly:
+		return r.AddOrUpdateLabel, r.AddOrUpdateLabelColor
+	case result.HasDestroy && len(result.DeletedResources) > 0:
+		// Pure deletions take precedence over replacements because deleted resources aren't created again
+		return r.DestroyLabel, r.DestroyLabelColor
+	case len(result.ReplacedResources) > 0 && r.ReplaceLabel != "":
+		return r.ReplaceLabel, r.ReplaceLabelColor
+	case result.HasDestroy:
+		return r.DestroyLabel, r.DestroyLabelColor
+	case result.HasImportOnly:
+		return r.ImportLabel, r.ImportLabelColor
+	case result.HasNoChanges:
+		return r.NoChangesLabel, r.NoChangesLabelColor
+	case result.HasPlanError:
+		if label, ok := r.PlanErrorCategoryLabels[result.ErrorCategory]; ok && label != "" {
+			return label, r.PlanErrorLabelColor
+		}
+		return r.PlanErrorLabel, r.PlanErrorLabelColor
+	}
+	return "", ""
+}
+
+// Name returns the label name with the prefix. If the label is empty, an empty string is returned
+func (r *ResultLabels) Name(label string) string {
+	if label == "" {
+		return ""
+	}
+	return r.Prefix + label
+}
+
+// IsResultLabel returns true if a label matches any of the internal labels
+func (r *ResultLabels) IsResultLabel(label string) bool {
+	switch label {
+	case "":
+		return false
+	case r.Name(r.AddOrUpdateLabel), r.Name(r.DestroyLabel), r.Name(r.NoChangesLabel), r.Name(r.PlanErrorLabel), r.Name(r.ReplaceLabel),
+		r.Name(r.ImportLabel), r.Name(r.DriftLabel), r.Name(r.CheckFailedLabel), r.Name(r.AppliedLabel), r.Name(r.ApplyFailedLabel),
+		r.Name(r.DestroyedLabel), r.Name(r.DestroyFailedLabel):
+		return true
+	default:
+		for _, l := range r.PlanErrorCategoryLabels {
+			if label == r.Name(l) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,81 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/metrics"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// MetricsRecorder sends metrics of the notification after the notifier notifies the result.
+// Failing to send metrics doesn't fail the notification.
+// The duration of the command is sent only if the command is run by tfcmt
+type MetricsRecorder struct {
+	Notifier Notifier
+	Sink     metrics.Sink
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser               terraform.Parser
+	DisableNormalization bool
+	// Repo is the repository like "owner/repo"
+	Repo   string
+	Target string
+}
+
+// Notify notifies the result with the notifier and sends metrics
+func (r *MetricsRecorder) Notify(ctx context.Context, param ParamExec) (int, error) {
+	start := time.Now()
+	exitCode, err := r.Notifier.Notify(ctx, param)
+	duration := time.Since(start)
+	parser := r.Parser
+	if parser == nil {
+		parser = terraform.NewPlanParser()
+	}
+	result, e := Parse(parser, param, r.DisableNormalization)
+	if e != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(e).Warn("parse the output to send metrics")
+		return exitCode, err
+	}
+	if e := r.Sink.Send(ctx, r.metrics(result, duration)); e != nil {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).WithError(e).Warn("send metrics")
+	}
+	return exitCode, err
+}
+
+// Start calls Start of the notifier if the notifier implements Starter
+func (r *MetricsRecorder) Start(ctx context.Context) error {
+	return start(ctx, r.Notifier)
+}
+
+// PostedComment returns the comment posted by the notifier
+func (r *MetricsRecorder) PostedComment() *PostedComment {
+	return postedComment(r.Notifier)
+}
+
+func (r *MetricsRecorder) metrics(result *Result, duration time.Duration) []metrics.Metric {
+	tags := map[string]string{
+		"repo":    r.Repo,
+		"command": result.Command(),
+		"target":  r.Target,
+	}
+	parseError := 0
+	if result.HasParseError {
+		parseError = 1
+	}
+	list := []metrics.Metric{
+		{Name: "resources.add", Value: int64(len(result.CreatedResources)), Tags: tags},
+		{Name: "resources.change", Value: int64(len(result.UpdatedResources)), Tags: tags},
+		{Name: "resources.destroy", Value: int64(len(result.DeletedResources) + len(result.ReplacedResources)), Tags: tags},
+		{Name: "parse_error", Value: int64(parseError), Tags: tags},
+		{Name: "notify.duration", Kind: metrics.KindTiming, Value: duration.Milliseconds(), Tags: tags},
+	}
+	if result.Param.Duration > 0 {
+		list = append(list, metrics.Metric{Name: "command.duration", Kind: metrics.KindTiming, Value: result.Param.Duration.Milliseconds(), Tags: tags})
+	}
+	return list
+}
@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/metrics"
+)
+
+type fakeSink struct {
+	metrics []metrics.Metric
+}
+
+func (s *fakeSink) Send(ctx context.Context, metrics []metrics.Metric) error {
+	s.metrics = append(s.metrics, metrics...)
+	return nil
+}
+
+func TestMetricsRecorder(t *testing.T) {
+	t.Parallel()
+	sink := &fakeSink{}
+	r := &MetricsRecorder{
+		Notifier: &fakeNotifier{exitCode: 2},
+		Sink:     sink,
+		Repo:     "owner/repo",
+		Target:   "foo",
+	}
+	exitCode, err := r.Notify(context.Background(), ParamExec{
+		CombinedOutput: `  # null_resource.foo will be created
+  # null_resource.bar will be destroyed
+Plan: 1 to add, 0 to change, 1 to destroy.`,
+		ExitCode: 2,
+		Duration: 3 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exitCode != 2 {
+		t.Errorf("exit code: got %d, want 2", exitCode)
+	}
+	tags := map[string]string{
+		"repo":    "owner/repo",
+		"command": "plan",
+		"target":  "foo",
+	}
+	exp := []metrics.Metric{
+		{Name: "resources.add", Value: 1, Tags: tags},
+		{Name: "resources.change", Value: 0, Tags: tags},
+		{Name: "resources.destroy", Value: 1, Tags: tags},
+		{Name: "parse_error", Value: 0, Tags: tags},
+		{Name: "notify.duration", Kind: metrics.KindTiming, Tags: tags},
+		{Name: "command.duration", Kind: metrics.KindTiming, Value: 3000, Tags: tags},
+	}
+	if len(sink.metrics) == len(exp) {
+		// the duration isn't stable
+		sink.metrics[4].Value = 0
+	}
+	if diff := cmp.Diff(exp, sink.metrics); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Multi notifies the result with all notifiers in order.
+// The highest exit code of the notifiers is returned,
+// so exit codes such as the destroy check of the GitHub notifier aren't lost wherever the notifier is in the list.
+// Errors of all notifiers are joined and returned, but an error doesn't change the exit code,
+// so an outage of a chat service doesn't fail the build
+type Multi []Notifier
+
+// Notify calls Notify of all notifiers
+func (m Multi) Notify(ctx context.Context, param ParamExec) (int, error) {
+	var (
+		exitCode int
+		errs     multiError
+	)
+	for i, ntf := range m {
+		code, err := ntf.Notify(ctx, param)
+		if i == 0 || code > exitCode {
+			exitCode = code
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return exitCode, nil
+	}
+	return exitCode, errs
+}
+
+// multiError is errors of multiple notifiers
+type multiError []error
+
+func (errs multiError) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Is returns true if any error matches the target
+func (errs multiError) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Start calls Start of all notifiers which implement Starter.
+// The error of the first notifier is returned, and errors of the other notifiers are logged
+func (m Multi) Start(ctx context.Context) error {
+	var err error
+	for i, ntf := range m {
+		starter, ok := ntf.(Starter)
+		if !ok {
+			continue
+		}
+		e := starter.Start(ctx)
+		if i == 0 {
+			err = e
+			continue
+		}
+		if e != nil {
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+			}).WithError(e).Error("notify the start of the command")
+		}
+	}
+	return err
+}
+
+// PostedComment returns the comment posted by the first notifier which tells the posted comment
+func (m Multi) PostedComment() *PostedComment {
+	for _, ntf := range m {
+		if comment := postedComment(ntf); comment != nil {
+			return comment
+		}
+	}
+	return nil
+}
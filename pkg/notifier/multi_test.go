@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeNotifier struct {
+	exitCode int
+	err      error
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, param ParamExec) (int, error) {
+	return f.exitCode, f.err
+}
+
+func TestMultiNotify(t *testing.T) {
+	t.Parallel()
+	errDestroy := errors.New("the plan would destroy 1 resources")
+	errChat := errors.New("the chat service is unavailable")
+	testCases := []struct {
+		name        string
+		notifiers   Multi
+		expExitCode int
+		expErr      error
+		// expErrs are errors which are also returned in addition to expErr
+		expErrs []error
+	}{
+		{
+			name: "github is first",
+			notifiers: Multi{
+				&fakeNotifier{exitCode: 3, err: errDestroy},
+				&fakeNotifier{},
+			},
+			expExitCode: 3,
+			expErr:      errDestroy,
+		},
+		{
+			name: "github is second",
+			notifiers: Multi{
+				&fakeNotifier{},
+				&fakeNotifier{exitCode: 3, err: errDestroy},
+			},
+			expExitCode: 3,
+			expErr:      errDestroy,
+		},
+		{
+			name: "the error of the other notifier doesn't change the exit code",
+			notifiers: Multi{
+				&fakeNotifier{},
+				&fakeNotifier{err: errChat},
+			},
+			expExitCode: 0,
+			expErr:      errChat,
+		},
+		{
+			name: "the error of the notifier which returns the lower exit code is returned",
+			notifiers: Multi{
+				&fakeNotifier{exitCode: 1, err: errChat},
+				&fakeNotifier{exitCode: 2},
+			},
+			expExitCode: 2,
+			expErr:      errChat,
+		},
+		{
+			name: "all errors are returned",
+			notifiers: Multi{
+				&fakeNotifier{exitCode: 3, err: errDestroy},
+				&fakeNotifier{err: errChat},
+			},
+			expExitCode: 3,
+			expErr:      errChat,
+			expErrs:     []error{errDestroy},
+		},
+		{
+			name: "no error",
+			notifiers: Multi{
+				&fakeNotifier{exitCode: 2},
+				&fakeNotifier{},
+			},
+			expExitCode: 2,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			exitCode, err := testCase.notifiers.Notify(context.Background(), ParamExec{})
+			if exitCode != testCase.expExitCode {
+				t.Errorf("exit code: got %d, want %d", exitCode, testCase.expExitCode)
+			}
+			if !errors.Is(err, testCase.expErr) {
+				t.Errorf("error: got %v, want %v", err, testCase.expErr)
+			}
+			for _, e := range testCase.expErrs {
+				if !errors.Is(err, e) {
+					t.Errorf("error: got %v, want %v", err, e)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package notifier
 import (
 	"context"
 	"os/exec"
+	"time"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
 )
 
 // Notifier is a notification interface
@@ -10,11 +13,44 @@ type Notifier interface {
 	Notify(ctx context.Context, param ParamExec) (int, error)
 }
 
+// Starter is implemented by notifiers which notify that the command starts before it is run
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
 type ParamExec struct {
 	Stdout         string
 	Stderr         string
 	CombinedOutput string
-	CIName         string
-	Cmd            *exec.Cmd
-	ExitCode       int
+	// CombinedOutputFile is the file path of the combined output. If this is set, the file is read instead of CombinedOutput
+	CombinedOutputFile string
+	CIName             string
+	Cmd                *exec.Cmd
+	ExitCode           int
+	// CostEstimate is the output of `infracost breakdown --format json`. This is optional
+	CostEstimate string
+	// LintResult is the output of `tflint --format json`. This is optional
+	LintResult string
+	// SecurityScan is the output of `trivy config --format json` or `tfsec --format json`. This is optional
+	SecurityScan string
+	// PolicyResult is the output of `conftest test --output json` against the plan JSON. This is optional
+	PolicyResult string
+	// Checkov is the output of `checkov --output json`. This is optional
+	Checkov string
+	// ArtifactURL and PlanJSONURL are the URLs of the output and the plan JSON uploaded to S3 or GCS. They are optional
+	ArtifactURL string
+	PlanJSONURL string
+	// Product is either terraform.ProductTerraform or terraform.ProductOpenTofu.
+	// If this is empty, the product is detected from the output
+	Product string
+	// Duration is the time taken to run the command. This is zero if the command isn't run by tfcmt
+	Duration time.Duration
+}
+
+// DetectProduct returns Product if it is set, otherwise the product is detected from the output
+func (p *ParamExec) DetectProduct() string {
+	if p.Product != "" {
+		return p.Product
+	}
+	return terraform.DetectProduct(p.CombinedOutput)
 }
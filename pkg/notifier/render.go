@@ -0,0 +1,205 @@
+package notifier
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// Result is the parsed output of terraform.
+// This is shared by notifiers other than GitHub to render the result in the same way
+type Result struct {
+	terraform.ParseResult
+	Param   ParamExec
+	IsPlan  bool
+	IsApply bool
+}
+
+// Parse reads and parses the output of terraform.
+// If the output file is given, the output is read from the file.
+// ANSI escape sequences and CRLF line endings are normalized unless disableNormalization is true
+func Parse(parser terraform.Parser, param ParamExec, disableNormalization bool) (*Result, error) {
+	if param.CombinedOutputFile != "" {
+		b, err := ioutil.ReadFile(param.CombinedOutputFile)
+		if err != nil {
+			return nil, fmt.Errorf("read the output of the command from the file %s: %w", param.CombinedOutputFile, err)
+		}
+		param.CombinedOutput = string(b)
+	}
+	if !disableNormalization {
+		param.CombinedOutput = terraform.NormalizeOutput(param.CombinedOutput)
+		param.Stdout = terraform.NormalizeOutput(param.Stdout)
+		param.Stderr = terraform.NormalizeOutput(param.Stderr)
+	}
+	result := parser.Parse(param.CombinedOutput)
+	result.ExitCode = param.ExitCode
+	_, isPlan := parser.(*terraform.PlanParser)
+	_, isApply := parser.(*terraform.ApplyParser)
+	switch result.DetectedCommand {
+	case terraform.CommandPlan:
+		isPlan = true
+	case terraform.CommandApply:
+		isApply = true
+	}
+	return &Result{
+		ParseResult: result,
+		Param:       param,
+		IsPlan:      isPlan,
+		IsApply:     isApply,
+	}, nil
+}
+
+// Command returns either "plan", "apply", "test", "destroy", "validate", or "fmt"
+func (r *Result) Command() string {
+	if r.IsPlan {
+		return terraform.CommandPlan
+	}
+	switch r.DetectedCommand {
+	case terraform.CommandTest, terraform.CommandDestroy, terraform.CommandValidate, terraform.CommandFmt:
+		return r.DetectedCommand
+	}
+	return terraform.CommandApply
+}
+
+// RenderOption is the option to render the result
+type RenderOption struct {
+	Template           *terraform.Template
+	ParseErrorTemplate *terraform.Template
+	ResultLabels       ResultLabels
+	Link               string
+	Vars               map[string]string
+	Templates          map[string]string
+	UseRawOutput       bool
+	MaxResources       int
+	// ErrorMessages are errors which occurred before the result is rendered, such as failures to update labels
+	ErrorMessages []string
+}
+
+// Render renders the result with the template.
+// If the output can't be parsed, the template for parse errors is used.
+// An empty string is returned if there is nothing to notify
+func (r *Result) Render(opt RenderOption) (string, error) {
+	template, err := r.Template(opt)
+	if err != nil || template == nil {
+		return "", err
+	}
+	template.SetValue(r.CommonTemplate(opt))
+	return template.Execute()
+}
+
+// Template returns the template to render the result.
+// If the output can't be parsed, the template for parse errors is used.
+// If templates aren't set, the default templates of the command are used.
+// nil is returned if there is nothing to notify
+func (r *Result) Template(opt RenderOption) (*terraform.Template, error) {
+	template := opt.Template
+	if r.HasParseError {
+		template = opt.ParseErrorTemplate
+		if template == nil {
+			// If AutoParser can't detect the command, the command is regarded as apply as with Command
+			if r.IsPlan {
+				template = terraform.NewPlanParseErrorTemplate("")
+			} else {
+				template = terraform.NewApplyParseErrorTemplate("")
+			}
+		}
+	} else {
+		if r.Error != nil {
+			return nil, r.Error
+		}
+		if r.Result == "" {
+			return nil, nil //nolint:nilnil
+		}
+	}
+	if template == nil {
+		if r.IsPlan {
+			template = terraform.NewPlanTemplate("")
+		} else {
+			template = terraform.NewApplyTemplate("")
+		}
+	}
+	return template, nil
+}
+
+// CommonTemplate returns the template entities of the result
+func (r *Result) CommonTemplate(opt RenderOption) terraform.CommonTemplate {
+	var parseErrorMessage string
+	if r.HasParseError && r.Error != nil {
+		parseErrorMessage = r.Error.Error()
+	}
+	tplValue := terraform.CommonTemplate{
+		Result:                 r.Result,
+		ChangedResult:          r.ChangedResult,
+		ChangeOutsideTerraform: r.OutsideTerraform,
+		Warning:                r.Warning,
+		HasDestroy:             r.HasDestroy,
+		HasChanges:             r.HasChanges(),
+		Succeeded:              r.Succeeded(),
+		Link:                   opt.Link,
+		UseRawOutput:           opt.UseRawOutput,
+		MaxResources:           opt.MaxResources,
+		Vars:                   opt.Vars,
+		Templates:              opt.Templates,
+		Stdout:                 r.Param.Stdout,
+		Stderr:                 r.Param.Stderr,
+		CombinedOutput:         r.Param.CombinedOutput,
+		ParseErrorMessage:      parseErrorMessage,
+		ExitCode:               r.Param.ExitCode,
+		ErrorMessages:          opt.ErrorMessages,
+		CreatedResources:       r.CreatedResources,
+		UpdatedResources:       r.UpdatedResources,
+		DeletedResources:       r.DeletedResources,
+		ReplacedResources:      r.ReplacedResources,
+		ModuleChanges:          r.ModuleChanges,
+		DriftedResources:       r.DriftedResources,
+		HasApplyError:          r.HasApplyError,
+		AppliedResources:       r.AppliedResources,
+		FailedResources:        r.FailedResources,
+		Outputs:                r.Outputs,
+		Product:                r.Param.DetectProduct(),
+		TerragruntModules:      r.TerragruntModules,
+		MovedResources:         r.MovedResources,
+		ImportedResources:      r.ImportedResources,
+		IsRefreshOnly:          r.IsRefreshOnly,
+		Checks:                 r.Checks,
+		TestPassed:             r.TestPassed,
+		TestFailed:             r.TestFailed,
+		TestSkipped:            r.TestSkipped,
+		FailedTests:            r.FailedTests,
+		Diagnostics:            r.Diagnostics,
+		UnformattedFiles:       r.UnformattedFiles,
+		ErrorCategory:          r.ErrorCategory,
+	}
+	if r.IsPlan {
+		label, _ := opt.ResultLabels.LabelOf(r.ParseResult)
+		tplValue.ResultLabel = opt.ResultLabels.Name(label)
+	}
+	return tplValue
+}
+
+// WriteDryRunOutput writes the message to the file instead of posting it.
+// If the file path is empty, the message is written to the standard output.
+func WriteDryRunOutput(p, body string) error {
+	if p == "" {
+		_, err := fmt.Fprintln(os.Stdout, body)
+		return err
+	}
+	if err := ioutil.WriteFile(p, []byte(body+"\n"), 0o644); err != nil { //nolint:gosec,gomnd
+		return fmt.Errorf("write the comment to the file %s: %w", p, err)
+	}
+	return nil
+}
+
+// WriteOutput writes the posted comment to the file so that following steps can reuse it.
+// If the file path is empty, nothing is done.
+func WriteOutput(p, body string) error {
+	if p == "" {
+		return nil
+	}
+	if err := ioutil.WriteFile(p, []byte(body+"\n"), 0o644); err != nil { //nolint:gosec,gomnd
+		return fmt.Errorf("write the posted comment to the file %s: %w", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// ResultFormatJSON is the format of the result written by --result-format json
+const ResultFormatJSON = "json"
+
+// Report is the machine-readable result of the notification.
+// Other steps of the pipeline can make decisions with it without parsing the output of terraform again
+type Report struct {
+	Command            string       `json:"command"`
+	ExitCode           int          `json:"exit_code"`
+	HasAddOrUpdateOnly bool         `json:"has_add_or_update_only"`
+	HasDestroy         bool         `json:"has_destroy"`
+	HasNoChanges       bool         `json:"has_no_changes"`
+	HasPlanError       bool         `json:"has_plan_error"`
+	HasApplyError      bool         `json:"has_apply_error"`
+	HasParseError      bool         `json:"has_parse_error"`
+	Counts             ReportCounts `json:"counts"`
+	CreatedResources   []string     `json:"created_resources"`
+	UpdatedResources   []string     `json:"updated_resources"`
+	DeletedResources   []string     `json:"deleted_resources"`
+	ReplacedResources  []string     `json:"replaced_resources"`
+	ImportedResources  []string     `json:"imported_resources"`
+	DriftedResources   []string     `json:"drifted_resources"`
+	AppliedResources   []string     `json:"applied_resources"`
+	FailedResources    []string     `json:"failed_resources"`
+	// Comment is the posted comment. This is null if no comment is posted, for example in the dry run mode
+	// or if the notifier doesn't tell the posted comment
+	Comment *PostedComment `json:"comment"`
+}
+
+// ReportCounts is the numbers of changed resources
+type ReportCounts struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+	Replace int `json:"replace"`
+	Import  int `json:"import"`
+}
+
+// PostedComment is the comment posted by the notifier
+type PostedComment struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+}
+
+// CommentReporter is implemented by notifiers which tell the comment posted by the last notification
+type CommentReporter interface {
+	PostedComment() *PostedComment
+}
+
+// ReportWriter writes the machine-readable result after the notifier notifies the result.
+// The result is written even if the notification fails so that following steps can know the exit code.
+// If the output can't be read, nothing is written
+type ReportWriter struct {
+	Notifier Notifier
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser               terraform.Parser
+	DisableNormalization bool
+	// Output is the file path where the result is written. If this is empty, the result is written to the standard output
+	Output string
+}
+
+// Notify notifies the result with the notifier and writes the machine-readable result
+func (w *ReportWriter) Notify(ctx context.Context, param ParamExec) (int, error) {
+	exitCode, err := w.Notifier.Notify(ctx, param)
+	parser := w.Parser
+	if parser == nil {
+		parser = terraform.NewPlanParser()
+	}
+	result, e := Parse(parser, param, w.DisableNormalization)
+	if e != nil {
+		return exitCode, err
+	}
+	report := newReport(result)
+	report.ExitCode = exitCode
+	report.Comment = postedComment(w.Notifier)
+	if e := writeReport(w.Output, report); e != nil {
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"program": "tfcmt",
+			}).WithError(e).Warn("write the result")
+			return exitCode, err
+		}
+		if exitCode == apperr.ExitCodeOK {
+			exitCode = apperr.ExitCodeError
+		}
+		return exitCode, e
+	}
+	return exitCode, err
+}
+
+// Start calls Start of the notifier if the notifier implements Starter
+func (w *ReportWriter) Start(ctx context.Context) error {
+	return start(ctx, w.Notifier)
+}
+
+// start calls Start of the notifier if the notifier implements Starter.
+// This is used by notifiers which wrap another notifier so that the start of the command is still notified
+func start(ctx context.Context, ntf Notifier) error {
+	if starter, ok := ntf.(Starter); ok {
+		return starter.Start(ctx)
+	}
+	return nil
+}
+
+// postedComment returns the comment posted by the notifier. If the notifier doesn't implement CommentReporter, nil is returned
+func postedComment(ntf Notifier) *PostedComment {
+	if reporter, ok := ntf.(CommentReporter); ok {
+		return reporter.PostedComment()
+	}
+	return nil
+}
+
+func newReport(result *Result) *Report {
+	return &Report{
+		Command:            result.Command(),
+		HasAddOrUpdateOnly: result.HasAddOrUpdateOnly,
+		HasDestroy:         result.HasDestroy,
+		HasNoChanges:       result.HasNoChanges,
+		HasPlanError:       result.HasPlanError,
+		HasApplyError:      result.HasApplyError,
+		HasParseError:      result.HasParseError,
+		Counts: ReportCounts{
+			Add:     len(result.CreatedResources),
+			Change:  len(result.UpdatedResources),
+			Destroy: len(result.DeletedResources),
+			Replace: len(result.ReplacedResources),
+			Import:  len(result.ImportedResources),
+		},
+		CreatedResources:  nonNilStrings(result.CreatedResources),
+		UpdatedResources:  nonNilStrings(result.UpdatedResources),
+		DeletedResources:  nonNilStrings(result.DeletedResources),
+		ReplacedResources: nonNilStrings(result.ReplacedResources),
+		ImportedResources: nonNilStrings(result.ImportedResources),
+		DriftedResources:  nonNilStrings(result.DriftedResources),
+		AppliedResources:  nonNilStrings(result.AppliedResources),
+		FailedResources:   nonNilStrings(result.FailedResources),
+	}
+}
+
+// nonNilStrings returns an empty slice instead of nil so that the list is encoded as [] rather than null
+func nonNilStrings(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// writeReport writes the result to the file. If the file path is empty, the result is written to the standard output
+func writeReport(p string, report *Report) error {
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode the result as JSON: %w", err)
+	}
+	if p == "" {
+		_, err := fmt.Fprintln(os.Stdout, string(b))
+		return err
+	}
+	if err := ioutil.WriteFile(p, append(b, '\n'), 0o644); err != nil { //nolint:gosec,gomnd
+		return fmt.Errorf("write the result to the file %s: %w", p, err)
+	}
+	return nil
+}
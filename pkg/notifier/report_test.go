@@ -0,0 +1,126 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+type fakeCommentNotifier struct {
+	exitCode int
+	err      error
+	comment  *PostedComment
+}
+
+func (f *fakeCommentNotifier) Notify(ctx context.Context, param ParamExec) (int, error) {
+	return f.exitCode, f.err
+}
+
+func (f *fakeCommentNotifier) PostedComment() *PostedComment {
+	return f.comment
+}
+
+func TestReportWriter(t *testing.T) {
+	t.Parallel()
+	errDestroy := errors.New("the plan would destroy 1 resources")
+	testCases := []struct {
+		name     string
+		notifier Notifier
+		parser   terraform.Parser
+		param    ParamExec
+		expErr   error
+		exp      *Report
+	}{
+		{
+			name: "plan",
+			notifier: &fakeCommentNotifier{
+				exitCode: 3,
+				err:      errDestroy,
+				comment: &PostedComment{
+					ID:  10,
+					URL: "https://github.com/owner/repo/pull/1#issuecomment-10",
+				},
+			},
+			param: ParamExec{
+				CombinedOutput: `  # null_resource.foo will be created
+  # null_resource.bar will be destroyed
+Plan: 1 to add, 0 to change, 1 to destroy.`,
+				ExitCode: 2,
+			},
+			expErr: errDestroy,
+			exp: &Report{
+				Command:    "plan",
+				ExitCode:   3,
+				HasDestroy: true,
+				Counts: ReportCounts{
+					Add:     1,
+					Destroy: 1,
+				},
+				CreatedResources:  []string{"null_resource.foo"},
+				UpdatedResources:  []string{},
+				DeletedResources:  []string{"null_resource.bar"},
+				ReplacedResources: []string{},
+				ImportedResources: []string{},
+				DriftedResources:  []string{},
+				AppliedResources:  []string{},
+				FailedResources:   []string{},
+				Comment: &PostedComment{
+					ID:  10,
+					URL: "https://github.com/owner/repo/pull/1#issuecomment-10",
+				},
+			},
+		},
+		{
+			name:     "the notifier doesn't tell the posted comment",
+			notifier: &fakeNotifier{},
+			parser:   terraform.NewApplyParser(),
+			param: ParamExec{
+				CombinedOutput: "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			},
+			exp: &Report{
+				Command:           "apply",
+				CreatedResources:  []string{},
+				UpdatedResources:  []string{},
+				DeletedResources:  []string{},
+				ReplacedResources: []string{},
+				ImportedResources: []string{},
+				DriftedResources:  []string{},
+				AppliedResources:  []string{},
+				FailedResources:   []string{},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			output := filepath.Join(t.TempDir(), "result.json")
+			w := &ReportWriter{
+				Notifier: testCase.notifier,
+				Parser:   testCase.parser,
+				Output:   output,
+			}
+			_, err := w.Notify(context.Background(), testCase.param)
+			if !errors.Is(err, testCase.expErr) {
+				t.Errorf("error: got %v, want %v", err, testCase.expErr)
+			}
+			b, err := ioutil.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := &Report{}
+			if err := json.Unmarshal(b, report); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.exp, report); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package slack
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	// EnvWebhookURL is the URL of the incoming webhook
+	EnvWebhookURL = "SLACK_WEBHOOK_URL"
+	// EnvToken is the bot token. The bot requires the scope chat:write
+	EnvToken = "SLACK_BOT_TOKEN" //nolint:gosec
+)
+
+const (
+	// DefaultPlanTemplate is the default template of Slack messages for terraform plan.
+	// The text is Slack's mrkdwn, not Markdown
+	DefaultPlanTemplate = `*Plan Result{{if .Vars.target}} ({{.Vars.target}}){{end}}*{{if .Link}} <{{.Link}}|CI link>{{end}}
+{{if .ParseErrorMessage}}:x: It failed to parse the result: {{.ParseErrorMessage}}{{else}}{{if not .Succeeded}}:x: {{end}}{{.Result}}{{end}}
+{{if .HasDestroy}}:warning: *This plan contains resource delete operation.*
+{{end}}{{range .DeletedResources}}• {{.}} will be destroyed
+{{end}}{{range .ReplacedResources}}• {{.}} will be replaced
+{{end}}`
+
+	// DefaultApplyTemplate is the default template of Slack messages for terraform apply
+	DefaultApplyTemplate = `*Apply Result{{if .Vars.target}} ({{.Vars.target}}){{end}}*{{if .Link}} <{{.Link}}|CI link>{{end}}
+{{if .ParseErrorMessage}}:x: It failed to parse the result: {{.ParseErrorMessage}}{{else}}{{if .Succeeded}}:white_check_mark:{{else}}:x:{{end}} {{.Result}}{{end}}
+{{range .FailedResources}}• {{.}} failed
+{{end}}`
+)
+
+// Client is a API client for Slack
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for Slack client
+type Config struct {
+	// WebhookURL is the URL of the incoming webhook. If this is empty, SLACK_WEBHOOK_URL is used
+	WebhookURL string
+	// Token is the bot token. If this is empty, SLACK_BOT_TOKEN is used. The webhook takes precedence over the token
+	Token string
+	// Channel is the channel id or name. This is required if the bot token is used
+	Channel string
+	CI      string
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// PlanTemplate and ApplyTemplate are templates of messages. The text is Slack's mrkdwn.
+	// If they are nil, DefaultPlanTemplate and DefaultApplyTemplate are used
+	PlanTemplate  *terraform.Template
+	ApplyTemplate *terraform.Template
+	ResultLabels  notifier.ResultLabels
+	Vars          map[string]string
+	Templates     map[string]string
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	// Triggers are conditions to send messages such as notifier.TriggerApplyFailure. If this is empty, all results are sent
+	Triggers []string
+	// DryRun renders the message but doesn't send it.
+	// The message is written to DryRunOutput as JSON. If DryRunOutput is empty, the message is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	webhookURL := cfg.WebhookURL
+	if webhookURL == "" {
+		webhookURL = os.Getenv(EnvWebhookURL)
+	}
+	token := cfg.Token
+	if token == "" {
+		token = os.Getenv(EnvToken)
+	}
+	client := &Client{
+		Config: cfg,
+	}
+	switch {
+	case webhookURL != "":
+		client.API = &Webhook{
+			client: http.DefaultClient,
+			url:    webhookURL,
+		}
+	case token != "":
+		client.API = &Bot{
+			client: http.DefaultClient,
+			apiURL: defaultAPIURL,
+			token:  token,
+		}
+	case !cfg.DryRun:
+		return &Client{}, errors.New("slack webhook url or bot token is missing")
+	}
+	return client, nil
+}
+
+// NewNotifier returns a notifier.Notifier which sends the result to Slack.
+// If Parser isn't set, the parser of terraform plan is used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	if cfg.PlanTemplate == nil {
+		cfg.PlanTemplate = terraform.NewPlanTemplate(DefaultPlanTemplate)
+	}
+	if cfg.ApplyTemplate == nil {
+		cfg.ApplyTemplate = terraform.NewApplyTemplate(DefaultApplyTemplate)
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
@@ -0,0 +1,165 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService sends the result to Slack
+type NotifyService struct {
+	client *Client
+}
+
+const (
+	colorSuccess = "#0e8a16" // green
+	colorWarning = "#fbca04" // yellow
+	colorFailure = "#d93f0b" // red
+)
+
+// Slack limits the length of the text of a section block to 3000 characters and the number of blocks to 50
+const (
+	maxSectionLength = 3000
+	maxBlocks        = 50
+)
+
+// Notify sends the result as a message of Block Kit
+func (s *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := s.client.Config
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+	if !result.HasParseError && result.Error != nil {
+		return result.ExitCode, result.Error
+	}
+	if !notifier.MatchTriggers(cfg.Triggers, result) {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).Debug("skip sending a message to Slack because the result doesn't match any of the triggers")
+		return result.ExitCode, nil
+	}
+
+	template := cfg.ApplyTemplate
+	if result.IsPlan {
+		template = cfg.PlanTemplate
+	}
+	tplValue := result.CommonTemplate(notifier.RenderOption{
+		ResultLabels: cfg.ResultLabels,
+		Link:         cfg.CI,
+		Vars:         cfg.Vars,
+		Templates:    cfg.Templates,
+		// mrkdwn isn't HTML
+		UseRawOutput: true,
+	})
+	tplValue.Result = escape(tplValue.Result)
+	tplValue.ChangedResult = escape(tplValue.ChangedResult)
+	tplValue.ChangeOutsideTerraform = escape(tplValue.ChangeOutsideTerraform)
+	tplValue.Warning = escape(tplValue.Warning)
+	tplValue.ParseErrorMessage = escape(tplValue.ParseErrorMessage)
+	template.SetValue(tplValue)
+	text, err := template.Execute()
+	if err != nil {
+		return result.ExitCode, err
+	}
+
+	msg := newMessage(cfg.Channel, text, colorOf(result))
+	if cfg.DryRun {
+		b, err := json.MarshalIndent(msg, "", "  ")
+		if err != nil {
+			return result.ExitCode, fmt.Errorf("marshal the message: %w", err)
+		}
+		return result.ExitCode, notifier.WriteDryRunOutput(cfg.DryRunOutput, string(b))
+	}
+	if err := s.client.API.PostMessage(ctx, msg); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+// escape escapes control characters of mrkdwn.
+// https://api.slack.com/reference/surfaces/formatting#escaping
+func escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func colorOf(result *notifier.Result) string {
+	switch {
+	case result.HasParseError || !result.Succeeded():
+		return colorFailure
+	case result.IsPlan && result.HasDestroy:
+		return colorWarning
+	default:
+		return colorSuccess
+	}
+}
+
+// newMessage returns a message whose text is split into section blocks.
+// The first line of the text is used as the fallback text of notifications
+func newMessage(channel, text, color string) *Message {
+	text = strings.TrimSpace(text)
+	fallback := text
+	if idx := strings.Index(text, "\n"); idx != -1 {
+		fallback = text[:idx]
+	}
+	chunks := splitText(text, maxSectionLength)
+	if len(chunks) > maxBlocks {
+		chunks = append(chunks[:maxBlocks-1], "_The message is truncated because it is too long._")
+	}
+	blocks := make([]*Block, len(chunks))
+	for i, chunk := range chunks {
+		blocks[i] = &Block{
+			Type: "section",
+			Text: &Text{
+				Type: "mrkdwn",
+				Text: chunk,
+			},
+		}
+	}
+	return &Message{
+		Channel: channel,
+		Text:    fallback,
+		Attachments: []*Attachment{
+			{
+				Color:  color,
+				Blocks: blocks,
+			},
+		},
+	}
+}
+
+// splitText splits the text into chunks at line breaks so that each chunk is at most max characters.
+// A line longer than max is split in the middle
+func splitText(text string, max int) []string {
+	var chunks []string
+	current := ""
+	for _, line := range strings.Split(text, "\n") {
+		for len([]rune(line)) > max {
+			if current != "" {
+				chunks = append(chunks, current)
+				current = ""
+			}
+			r := []rune(line)
+			chunks = append(chunks, string(r[:max]))
+			line = string(r[max:])
+		}
+		switch {
+		case current == "":
+			current = line
+		case len([]rune(current))+1+len([]rune(line)) > max:
+			chunks = append(chunks, current)
+			current = line
+		default:
+			current += "\n" + line
+		}
+	}
+	if current != "" {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
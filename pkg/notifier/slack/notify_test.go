@@ -0,0 +1,168 @@
+package slack
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		parser   terraform.Parser
+		triggers []string
+		output   string
+		exitCode int
+		exp      *Message
+	}{
+		{
+			name:   "plan with destroy",
+			parser: terraform.NewPlanParser(),
+			output: `
+Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1"
+    }
+
+Plan: 0 to add, 0 to change, 1 to destroy.
+`,
+			exp: &Message{
+				Channel: "tfcmt",
+				Text:    "*Plan Result (foo)* <https://ci.example.com/1|CI link>",
+				Attachments: []*Attachment{
+					{
+						Color: colorWarning,
+						Blocks: []*Block{
+							{
+								Type: "section",
+								Text: &Text{
+									Type: "mrkdwn",
+									Text: "*Plan Result (foo)* <https://ci.example.com/1|CI link>\nPlan: 0 to add, 0 to change, 1 to destroy.\n:warning: *This plan contains resource delete operation.*\n• null_resource.foo will be destroyed",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "apply failure",
+			parser:   terraform.NewApplyParser(),
+			triggers: []string{notifier.TriggerApplyFailure},
+			output:   "Error: <error>",
+			exitCode: 1,
+			exp: &Message{
+				Channel: "tfcmt",
+				Text:    "*Apply Result (foo)* <https://ci.example.com/1|CI link>",
+				Attachments: []*Attachment{
+					{
+						Color: colorFailure,
+						Blocks: []*Block{
+							{
+								Type: "section",
+								Text: &Text{
+									Type: "mrkdwn",
+									Text: "*Apply Result (foo)* <https://ci.example.com/1|CI link>\n:x: Error: &lt;error&gt;",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "apply success isn't sent",
+			parser:   terraform.NewApplyParser(),
+			triggers: []string{notifier.TriggerApplyFailure, notifier.TriggerDestroy},
+			output:   "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := &fakeAPI{}
+			ntf := &NotifyService{
+				client: &Client{
+					Config: Config{
+						Channel:       "tfcmt",
+						CI:            "https://ci.example.com/1",
+						Parser:        testCase.parser,
+						PlanTemplate:  terraform.NewPlanTemplate(DefaultPlanTemplate),
+						ApplyTemplate: terraform.NewApplyTemplate(DefaultApplyTemplate),
+						Vars:          map[string]string{"target": "foo"},
+						Triggers:      testCase.triggers,
+					},
+					API: api,
+				},
+			}
+			exitCode, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != testCase.exitCode {
+				t.Errorf("exit code: got %d, want %d", exitCode, testCase.exitCode)
+			}
+			if testCase.exp == nil {
+				if len(api.messages) != 0 {
+					t.Fatalf("no message should be sent: %+v", api.messages[0])
+				}
+				return
+			}
+			if len(api.messages) != 1 {
+				t.Fatalf("a message should be sent: %d", len(api.messages))
+			}
+			if diff := cmp.Diff(testCase.exp, api.messages[0]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		text string
+		max  int
+		exp  []string
+	}{
+		{
+			name: "short",
+			text: "foo\nbar",
+			max:  10,
+			exp:  []string{"foo\nbar"},
+		},
+		{
+			name: "split at line breaks",
+			text: "foo\nbar\nbaz",
+			max:  7,
+			exp:  []string{"foo\nbar", "baz"},
+		},
+		{
+			name: "long line",
+			text: "foo\n" + strings.Repeat("a", 12),
+			max:  5,
+			exp:  []string{"foo", "aaaaa", "aaaaa", "aa"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(testCase.exp, splitText(testCase.text, testCase.max)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
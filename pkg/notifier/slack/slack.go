@@ -0,0 +1,129 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// API is Slack API interface
+type API interface {
+	PostMessage(ctx context.Context, msg *Message) error
+}
+
+// Message is a message of Slack. Blocks are wrapped in an attachment to show the color bar
+type Message struct {
+	Channel     string        `json:"channel,omitempty"`
+	Text        string        `json:"text"`
+	Attachments []*Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is an attachment of Slack messages
+type Attachment struct {
+	Color  string   `json:"color,omitempty"`
+	Blocks []*Block `json:"blocks"`
+}
+
+// Block is a block of Block Kit. Only section and context blocks are used
+type Block struct {
+	Type     string  `json:"type"`
+	Text     *Text   `json:"text,omitempty"`
+	Elements []*Text `json:"elements,omitempty"`
+}
+
+// Text is a text object of Block Kit
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Webhook posts messages with an incoming webhook
+type Webhook struct {
+	client *http.Client
+	url    string
+}
+
+// PostMessage posts the message to the incoming webhook.
+// The channel of the message is ignored because the channel is decided by the webhook
+func (w *Webhook) PostMessage(ctx context.Context, msg *Message) error {
+	resp, err := post(ctx, w.client, w.url, "", msg)
+	if err != nil {
+		return err
+	}
+	if resp.statusCode >= http.StatusBadRequest {
+		return fmt.Errorf("the incoming webhook returned the status code %d: %s", resp.statusCode, resp.body)
+	}
+	return nil
+}
+
+const defaultAPIURL = "https://slack.com/api"
+
+// Bot posts messages with a bot token
+type Bot struct {
+	client *http.Client
+	apiURL string
+	token  string
+}
+
+// PostMessage is a wrapper of https://api.slack.com/methods/chat.postMessage
+func (b *Bot) PostMessage(ctx context.Context, msg *Message) error {
+	if msg.Channel == "" {
+		return errors.New("the channel is required to post a message with a bot token")
+	}
+	resp, err := post(ctx, b.client, b.apiURL+"/chat.postMessage", b.token, msg)
+	if err != nil {
+		return err
+	}
+	if resp.statusCode >= http.StatusBadRequest {
+		return fmt.Errorf("slack API returned the status code %d: %s", resp.statusCode, resp.body)
+	}
+	// Slack API returns 200 even if the request fails
+	ret := struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}{}
+	if err := json.Unmarshal(resp.body, &ret); err != nil {
+		return fmt.Errorf("parse the response body of Slack API: %w", err)
+	}
+	if !ret.OK {
+		return fmt.Errorf("post a message to Slack: %s", ret.Error)
+	}
+	return nil
+}
+
+type response struct {
+	statusCode int
+	body       []byte
+}
+
+func post(ctx context.Context, client *http.Client, u, token string, msg *Message) (*response, error) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal the message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send a message to Slack: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read the response body: %w", err)
+	}
+	return &response{
+		statusCode: resp.StatusCode,
+		body:       body,
+	}, nil
+}
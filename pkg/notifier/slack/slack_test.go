@@ -0,0 +1,85 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	messages []*Message
+}
+
+func (s *fakeAPI) PostMessage(ctx context.Context, msg *Message) error {
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+	var got *Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = &Message{}
+		_ = json.NewDecoder(r.Body).Decode(got)
+		if got.Text == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid_payload"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	wh := &Webhook{
+		client: server.Client(),
+		url:    server.URL,
+	}
+	msg := newMessage("", "hello", colorSuccess)
+	if err := wh.PostMessage(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(msg, got); diff != "" {
+		t.Error(diff)
+	}
+	if err := wh.PostMessage(context.Background(), &Message{Text: "fail"}); err == nil {
+		t.Fatal("error should be returned")
+	}
+}
+
+func TestBot(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat.postMessage" || r.Header.Get("Authorization") != "Bearer xoxb-xxx" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		msg := &Message{}
+		_ = json.NewDecoder(r.Body).Decode(msg)
+		if msg.Channel == "unknown" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	bot := &Bot{
+		client: server.Client(),
+		apiURL: server.URL + "/api",
+		token:  "xoxb-xxx",
+	}
+	ctx := context.Background()
+	if err := bot.PostMessage(ctx, newMessage("tfcmt", "hello", colorSuccess)); err != nil {
+		t.Fatal(err)
+	}
+	if err := bot.PostMessage(ctx, newMessage("unknown", "hello", colorSuccess)); err == nil || err.Error() != "post a message to Slack: channel_not_found" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bot.PostMessage(ctx, newMessage("", "hello", colorSuccess)); err == nil {
+		t.Fatal("the channel should be required")
+	}
+}
@@ -0,0 +1,136 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService sends the result to Microsoft Teams
+type NotifyService struct {
+	client *Client
+}
+
+// maxTextLength is the maximum length of the text of the card.
+// The size of a message of incoming webhooks is limited to about 28 KB
+const maxTextLength = 20000
+
+// Notify sends the result as an Adaptive Card
+func (s *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := s.client.Config
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+	if !result.HasParseError && result.Error != nil {
+		return result.ExitCode, result.Error
+	}
+	if !notifier.MatchTriggers(cfg.Triggers, result) {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).Debug("skip sending a message to Microsoft Teams because the result doesn't match any of the triggers")
+		return result.ExitCode, nil
+	}
+
+	template := cfg.ApplyTemplate
+	if result.IsPlan {
+		template = cfg.PlanTemplate
+	}
+	template.SetValue(result.CommonTemplate(notifier.RenderOption{
+		ResultLabels: cfg.ResultLabels,
+		Link:         cfg.CI,
+		Vars:         cfg.Vars,
+		Templates:    cfg.Templates,
+		UseRawOutput: true,
+	}))
+	text, err := template.Execute()
+	if err != nil {
+		return result.ExitCode, err
+	}
+
+	msg := newMessage(titleOf(result, cfg.Vars["target"]), text, colorOf(result), cfg.CI)
+	if cfg.DryRun {
+		b, err := json.MarshalIndent(msg, "", "  ")
+		if err != nil {
+			return result.ExitCode, fmt.Errorf("marshal the message: %w", err)
+		}
+		return result.ExitCode, notifier.WriteDryRunOutput(cfg.DryRunOutput, string(b))
+	}
+	if err := s.client.API.PostMessage(ctx, msg); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+func titleOf(result *notifier.Result, target string) string {
+	title := "Apply Result"
+	if result.IsPlan {
+		title = "Plan Result"
+	}
+	if target != "" {
+		title += " (" + target + ")"
+	}
+	return title
+}
+
+// colorOf returns the color of the title. https://adaptivecards.io/explorer/TextBlock.html
+func colorOf(result *notifier.Result) string {
+	switch {
+	case result.HasParseError || !result.Succeeded():
+		return "Attention"
+	case result.IsPlan && result.HasDestroy:
+		return "Warning"
+	default:
+		return "Good"
+	}
+}
+
+func newMessage(title, text, color, link string) *Message {
+	text = strings.TrimSpace(text)
+	if r := []rune(text); len(r) > maxTextLength {
+		text = string(r[:maxTextLength]) + "\n\n*The message is truncated because it is too long.*"
+	}
+	card := &Card{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []*TextBlock{
+			{
+				Type:   "TextBlock",
+				Text:   title,
+				Wrap:   true,
+				Weight: "Bolder",
+				Size:   "Medium",
+				Color:  color,
+			},
+			{
+				Type: "TextBlock",
+				Text: text,
+				Wrap: true,
+			},
+		},
+	}
+	if link != "" {
+		card.Actions = []*Action{
+			{
+				Type:  "Action.OpenUrl",
+				Title: "CI link",
+				URL:   link,
+			},
+		}
+	}
+	return &Message{
+		Type: "message",
+		Attachments: []*Attachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content:     card,
+			},
+		},
+	}
+}
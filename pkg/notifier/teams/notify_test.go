@@ -0,0 +1,131 @@
+package teams
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func newCard(title, text, color string) *Message {
+	return &Message{
+		Type: "message",
+		Attachments: []*Attachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: &Card{
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body: []*TextBlock{
+						{Type: "TextBlock", Text: title, Wrap: true, Weight: "Bolder", Size: "Medium", Color: color},
+						{Type: "TextBlock", Text: text, Wrap: true},
+					},
+					Actions: []*Action{
+						{Type: "Action.OpenUrl", Title: "CI link", URL: "https://ci.example.com/1"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		parser   terraform.Parser
+		triggers []string
+		output   string
+		exitCode int
+		exp      *Message
+	}{
+		{
+			name:     "plan with destroy",
+			parser:   terraform.NewPlanParser(),
+			triggers: []string{notifier.TriggerDestroy},
+			output: `
+Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1"
+    }
+
+Plan: 0 to add, 0 to change, 1 to destroy.
+`,
+			exp: newCard("Plan Result (foo)", "Plan: 0 to add, 0 to change, 1 to destroy.\n\n**This plan contains resource delete operation.**\n\n- null_resource.foo will be destroyed", "Warning"),
+		},
+		{
+			name:     "apply failure",
+			parser:   terraform.NewApplyParser(),
+			triggers: []string{notifier.TriggerApplyFailure},
+			output:   "Error: failed to create",
+			exitCode: 1,
+			exp:      newCard("Apply Result (foo)", "Error: failed to create", "Attention"),
+		},
+		{
+			name:     "plan without destroy isn't sent",
+			parser:   terraform.NewPlanParser(),
+			triggers: []string{notifier.TriggerDestroy},
+			output:   "No changes. Infrastructure is up-to-date.",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := &fakeAPI{}
+			ntf := &NotifyService{
+				client: &Client{
+					Config: Config{
+						CI:            "https://ci.example.com/1",
+						Parser:        testCase.parser,
+						PlanTemplate:  terraform.NewPlanTemplate(DefaultPlanTemplate),
+						ApplyTemplate: terraform.NewApplyTemplate(DefaultApplyTemplate),
+						Vars:          map[string]string{"target": "foo"},
+						Triggers:      testCase.triggers,
+					},
+					API: api,
+				},
+			}
+			exitCode, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != testCase.exitCode {
+				t.Errorf("exit code: got %d, want %d", exitCode, testCase.exitCode)
+			}
+			if testCase.exp == nil {
+				if len(api.messages) != 0 {
+					t.Fatalf("no message should be sent: %+v", api.messages[0])
+				}
+				return
+			}
+			if len(api.messages) != 1 {
+				t.Fatalf("a message should be sent: %d", len(api.messages))
+			}
+			if diff := cmp.Diff(testCase.exp, api.messages[0]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestNewMessageTruncate(t *testing.T) {
+	t.Parallel()
+	msg := newMessage("Plan Result", strings.Repeat("a", maxTextLength+1), "Good", "")
+	card := msg.Attachments[0].Content
+	if card.Actions != nil {
+		t.Errorf("actions should be empty if the link is empty: %+v", card.Actions)
+	}
+	if text := card.Body[1].Text; !strings.HasSuffix(text, "*The message is truncated because it is too long.*") {
+		t.Errorf("the text should be truncated: %s", text[len(text)-100:])
+	}
+}
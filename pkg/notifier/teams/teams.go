@@ -0,0 +1,82 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// API is the interface to post messages to Microsoft Teams
+type API interface {
+	PostMessage(ctx context.Context, msg *Message) error
+}
+
+// Message is a message of Microsoft Teams which has an Adaptive Card
+type Message struct {
+	Type        string        `json:"type"`
+	Attachments []*Attachment `json:"attachments"`
+}
+
+// Attachment is an attachment of the message
+type Attachment struct {
+	ContentType string `json:"contentType"`
+	Content     *Card  `json:"content"`
+}
+
+// Card is an Adaptive Card. https://adaptivecards.io/explorer/AdaptiveCard.html
+type Card struct {
+	Schema  string       `json:"$schema"`
+	Type    string       `json:"type"`
+	Version string       `json:"version"`
+	Body    []*TextBlock `json:"body"`
+	Actions []*Action    `json:"actions,omitempty"`
+}
+
+// TextBlock is a text block of Adaptive Cards. The text supports a subset of Markdown
+type TextBlock struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Wrap   bool   `json:"wrap"`
+	Weight string `json:"weight,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Color  string `json:"color,omitempty"`
+}
+
+// Action is an action of Adaptive Cards. Only Action.OpenUrl is used
+type Action struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Webhook posts messages with an incoming webhook
+type Webhook struct {
+	client *http.Client
+	url    string
+}
+
+// PostMessage posts the message to the incoming webhook
+func (w *Webhook) PostMessage(ctx context.Context, msg *Message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal the message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send a message to Microsoft Teams: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("the incoming webhook returned the status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	messages []*Message
+}
+
+func (s *fakeAPI) PostMessage(ctx context.Context, msg *Message) error {
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+	var got *Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = &Message{}
+		_ = json.NewDecoder(r.Body).Decode(got)
+		if got.Type != "message" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("Bad payload received by generic incoming webhook."))
+			return
+		}
+		_, _ = w.Write([]byte("1"))
+	}))
+	defer server.Close()
+
+	wh := &Webhook{
+		client: server.Client(),
+		url:    server.URL,
+	}
+	msg := newMessage("Plan Result", "hello", "Good", "https://ci.example.com/1")
+	if err := wh.PostMessage(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(msg, got); diff != "" {
+		t.Error(diff)
+	}
+	if err := wh.PostMessage(context.Background(), &Message{}); err == nil {
+		t.Fatal("error should be returned")
+	}
+}
@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+)
+
+// defaultTimeout is the default timeout of the whole notification
+const defaultTimeout = 5 * time.Minute
+
+// Deadline cancels the notification including API calls when the timeout is exceeded,
+// so an unresponsive service doesn't hang the build
+type Deadline struct {
+	Notifier Notifier
+	// Timeout is the timeout of the whole notification. If this isn't positive, defaultTimeout is used
+	Timeout time.Duration
+}
+
+// Notify notifies the result with the notifier within the timeout
+func (d *Deadline) Notify(ctx context.Context, param ParamExec) (int, error) {
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	exitCode, err := d.Notifier.Notify(ctx, param)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		if exitCode == apperr.ExitCodeOK {
+			exitCode = apperr.ExitCodeError
+		}
+		err = fmt.Errorf("the notification timed out (timeout: %s): %w", timeout, err)
+	}
+	return exitCode, err
+}
+
+// Start calls Start of the notifier if the notifier implements Starter
+func (d *Deadline) Start(ctx context.Context) error {
+	return start(ctx, d.Notifier)
+}
+
+// PostedComment returns the comment posted by the notifier
+func (d *Deadline) PostedComment() *PostedComment {
+	return postedComment(d.Notifier)
+}
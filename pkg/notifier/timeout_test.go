@@ -0,0 +1,33 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type blockingNotifier struct{}
+
+func (b *blockingNotifier) Notify(ctx context.Context, param ParamExec) (int, error) {
+	<-ctx.Done()
+	return param.ExitCode, ctx.Err()
+}
+
+func TestDeadline(t *testing.T) {
+	t.Parallel()
+	d := &Deadline{
+		Notifier: &blockingNotifier{},
+		Timeout:  10 * time.Millisecond,
+	}
+	exitCode, err := d.Notify(context.Background(), ParamExec{})
+	if err == nil {
+		t.Fatal("an error should be returned because the notification timed out")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("the error should be context.DeadlineExceeded: %v", err)
+	}
+	if exitCode == 0 {
+		t.Error("exit code should be non zero")
+	}
+}
@@ -0,0 +1,106 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+// Triggers decide whether the result is notified
+const (
+	TriggerPlanError    = "plan_error"
+	TriggerParseError   = "parse_error"
+	TriggerDestroy      = "destroy"
+	TriggerReplace      = "replace"
+	TriggerAddOrUpdate  = "add_or_update"
+	TriggerNoChanges    = "no_changes"
+	TriggerImport       = "import"
+	TriggerApplySuccess = "apply_success"
+	TriggerApplyFailure = "apply_failure"
+)
+
+// MatchTriggers returns true if the result matches any of the triggers.
+// If no trigger is given, true is returned
+func MatchTriggers(triggers []string, result *Result) bool { //nolint:cyclop
+	if len(triggers) == 0 {
+		return true
+	}
+	for _, trigger := range triggers {
+		switch trigger {
+		case TriggerPlanError:
+			if result.IsPlan && result.HasPlanError {
+				return true
+			}
+		case TriggerParseError:
+			if result.HasParseError {
+				return true
+			}
+		case TriggerDestroy:
+			if result.IsPlan && result.HasDestroy {
+				return true
+			}
+		case TriggerReplace:
+			if result.IsPlan && len(result.ReplacedResources) > 0 {
+				return true
+			}
+		case TriggerAddOrUpdate:
+			if result.IsPlan && result.HasAddOrUpdateOnly {
+				return true
+			}
+		case TriggerNoChanges:
+			if result.IsPlan && result.HasNoChanges {
+				return true
+			}
+		case TriggerImport:
+			if result.IsPlan && result.HasImportOnly {
+				return true
+			}
+		case TriggerApplySuccess:
+			if result.IsApply && !result.HasParseError && result.Succeeded() {
+				return true
+			}
+		case TriggerApplyFailure:
+			if result.IsApply && (result.HasParseError || !result.Succeeded()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Conditional notifies the result with the notifier only if the result matches any of the triggers.
+// If the result doesn't match, the exit code of the command is returned
+type Conditional struct {
+	Notifier Notifier
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser               terraform.Parser
+	Triggers             []string
+	DisableNormalization bool
+}
+
+// Notify notifies the result if the result matches any of the triggers
+func (c *Conditional) Notify(ctx context.Context, param ParamExec) (int, error) {
+	parser := c.Parser
+	if parser == nil {
+		parser = terraform.NewPlanParser()
+	}
+	result, err := Parse(parser, param, c.DisableNormalization)
+	if err != nil {
+		// the error is handled by the notifier
+		return c.Notifier.Notify(ctx, param)
+	}
+	if !MatchTriggers(c.Triggers, result) {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).Debug("skip notifying the result because the result doesn't match any of the triggers")
+		return param.ExitCode, nil
+	}
+	return c.Notifier.Notify(ctx, param)
+}
+
+// PostedComment returns the comment posted by the notifier.
+// If the result doesn't match the triggers, nil is returned because no comment is posted
+func (c *Conditional) PostedComment() *PostedComment {
+	return postedComment(c.Notifier)
+}
@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestMatchTriggers(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		triggers []string
+		result   *Result
+		exp      bool
+	}{
+		{
+			name: "no trigger",
+			result: &Result{
+				IsPlan: true,
+			},
+			exp: true,
+		},
+		{
+			name:     "destroy",
+			triggers: []string{TriggerApplyFailure, TriggerDestroy},
+			result: &Result{
+				ParseResult: terraform.ParseResult{HasDestroy: true},
+				IsPlan:      true,
+			},
+			exp: true,
+		},
+		{
+			name:     "destroy isn't a trigger of apply",
+			triggers: []string{TriggerDestroy},
+			result: &Result{
+				ParseResult: terraform.ParseResult{HasDestroy: true},
+				IsApply:     true,
+			},
+			exp: false,
+		},
+		{
+			name:     "import only",
+			triggers: []string{TriggerAddOrUpdate, TriggerImport},
+			result: &Result{
+				ParseResult: terraform.ParseResult{HasImportOnly: true},
+				IsPlan:      true,
+			},
+			exp: true,
+		},
+		{
+			name:     "apply failure",
+			triggers: []string{TriggerApplyFailure},
+			result: &Result{
+				ParseResult: terraform.ParseResult{ExitCode: 1, HasApplyError: true},
+				IsApply:     true,
+			},
+			exp: true,
+		},
+		{
+			name:     "apply success",
+			triggers: []string{TriggerApplyFailure},
+			result: &Result{
+				IsApply: true,
+			},
+			exp: false,
+		},
+		{
+			name:     "parse error of apply is a failure",
+			triggers: []string{TriggerApplyFailure},
+			result: &Result{
+				ParseResult: terraform.ParseResult{HasParseError: true},
+				IsApply:     true,
+			},
+			exp: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if got := MatchTriggers(testCase.triggers, testCase.result); got != testCase.exp {
+				t.Errorf("got %v, want %v", got, testCase.exp)
+			}
+		})
+	}
+}
+
+type countNotifier struct {
+	count int
+}
+
+func (c *countNotifier) Notify(ctx context.Context, param ParamExec) (int, error) {
+	c.count++
+	return 2, nil
+}
+
+func TestConditional(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		parser      terraform.Parser
+		output      string
+		exitCode    int
+		expCount    int
+		expExitCode int
+	}{
+		{
+			name:        "apply failure is notified",
+			parser:      terraform.NewApplyParser(),
+			output:      "Error: failed to create",
+			exitCode:    1,
+			expCount:    1,
+			expExitCode: 2,
+		},
+		{
+			name:        "apply success is skipped",
+			parser:      terraform.NewApplyParser(),
+			output:      "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			expCount:    0,
+			expExitCode: 0,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			ntf := &countNotifier{}
+			c := &Conditional{
+				Notifier: ntf,
+				Parser:   testCase.parser,
+				Triggers: []string{TriggerApplyFailure},
+			}
+			exitCode, err := c.Notify(context.Background(), ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != testCase.expExitCode {
+				t.Errorf("exit code: got %d, want %d", exitCode, testCase.expExitCode)
+			}
+			if ntf.count != testCase.expCount {
+				t.Errorf("the notifier is called %d times, want %d", ntf.count, testCase.expCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+const (
+	// EnvURL is the URL of the webhook
+	EnvURL = "TFCMT_WEBHOOK_URL"
+	// EnvSecret is the secret to sign the request body
+	EnvSecret = "TFCMT_WEBHOOK_SECRET" //nolint:gosec
+)
+
+// Client is a client for the webhook
+type Client struct {
+	Config Config
+	API    API
+}
+
+// Config is a configuration for the webhook client
+type Config struct {
+	// URL is the URL of the webhook. If this is empty, TFCMT_WEBHOOK_URL is used
+	URL string
+	// Headers are added to requests. Environment variables in the values are expanded
+	Headers map[string]string
+	// Secret is used to sign the request body. If this is empty, TFCMT_WEBHOOK_SECRET is used.
+	// If both are empty, the request isn't signed
+	Secret string
+	CI     CI
+	// Parser is used to parse the output of terraform. If this is nil, the parser of terraform plan is used
+	Parser terraform.Parser
+	// Template and ParseErrorTemplate are used to render the body of the payload
+	Template           *terraform.Template
+	ParseErrorTemplate *terraform.Template
+	ResultLabels       notifier.ResultLabels
+	Vars               map[string]string
+	Templates          map[string]string
+	UseRawOutput       bool
+	// DisableNormalization keeps ANSI escape sequences and CRLF line endings of the output
+	DisableNormalization bool
+	MaxResources         int
+	// Tag is the value of the field "program" of the payload. The default value is "tfcmt"
+	Tag string
+	// Triggers are conditions to send payloads such as notifier.TriggerApplyFailure. If this is empty, all results are sent
+	Triggers []string
+	// DryRun renders the payload but doesn't send it.
+	// The payload is written to DryRunOutput. If DryRunOutput is empty, the payload is written to the standard output
+	DryRun       bool
+	DryRunOutput string
+	// Output is the file path where the sent body is also written. If this is empty, the body isn't written
+	Output string
+}
+
+// defaultTag is the default value of Config.Tag
+const defaultTag = "tfcmt"
+
+// program returns the value of the field "program"
+func (cfg *Config) program() string {
+	if cfg.Tag == "" {
+		return defaultTag
+	}
+	return cfg.Tag
+}
+
+// NewClient returns Client initialized with Config
+func NewClient(cfg Config) (*Client, error) {
+	u := cfg.URL
+	if u == "" {
+		u = os.Getenv(EnvURL)
+	}
+	if u == "" && !cfg.DryRun {
+		return &Client{}, errors.New("webhook url is missing")
+	}
+	secret := cfg.Secret
+	if secret == "" {
+		secret = os.Getenv(EnvSecret)
+	}
+	headers := make(map[string]string, len(cfg.Headers))
+	for k, v := range cfg.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+	return &Client{
+		Config: cfg,
+		API: &Webhook{
+			client:  http.DefaultClient,
+			url:     u,
+			headers: headers,
+			secret:  secret,
+		},
+	}, nil
+}
+
+// NewNotifier returns a notifier.Notifier which sends the result to the webhook.
+// If Parser isn't set, the parser of terraform plan is used.
+func NewNotifier(cfg Config) (notifier.Notifier, error) {
+	if cfg.Parser == nil {
+		cfg.Parser = terraform.NewPlanParser()
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyService{client: client}, nil
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/apperr"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+)
+
+// NotifyService sends the result to the webhook
+type NotifyService struct {
+	client *Client
+}
+
+// Notify sends the parsed result and the rendered body as JSON
+func (s *NotifyService) Notify(ctx context.Context, param notifier.ParamExec) (int, error) {
+	cfg := s.client.Config
+	result, err := notifier.Parse(cfg.Parser, param, cfg.DisableNormalization)
+	if err != nil {
+		return apperr.ExitCodeError, err
+	}
+	if !result.HasParseError && result.Error != nil {
+		return result.ExitCode, result.Error
+	}
+	if !notifier.MatchTriggers(cfg.Triggers, result) {
+		logrus.WithFields(logrus.Fields{
+			"program": "tfcmt",
+		}).Debug("skip sending the result to the webhook because the result doesn't match any of the triggers")
+		return result.ExitCode, nil
+	}
+
+	body, err := result.Render(notifier.RenderOption{
+		Template:           cfg.Template,
+		ParseErrorTemplate: cfg.ParseErrorTemplate,
+		ResultLabels:       cfg.ResultLabels,
+		Link:               cfg.CI.Link,
+		Vars:               cfg.Vars,
+		Templates:          cfg.Templates,
+		UseRawOutput:       cfg.UseRawOutput,
+		MaxResources:       cfg.MaxResources,
+	})
+	if err != nil {
+		return result.ExitCode, err
+	}
+
+	payload := newPayload(&cfg, result, body)
+	if cfg.DryRun {
+		b, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return result.ExitCode, fmt.Errorf("marshal the payload: %w", err)
+		}
+		return result.ExitCode, notifier.WriteDryRunOutput(cfg.DryRunOutput, string(b))
+	}
+	if err := s.client.API.Send(ctx, payload); err != nil {
+		return result.ExitCode, err
+	}
+	if err := notifier.WriteOutput(cfg.Output, body); err != nil {
+		return result.ExitCode, err
+	}
+	return result.ExitCode, nil
+}
+
+func newPayload(cfg *Config, result *notifier.Result, body string) *Payload {
+	var errMsg, errCategory string
+	if result.Error != nil {
+		errMsg = result.Error.Error()
+	}
+	if result.IsPlan {
+		errCategory = result.ErrorCategory
+	}
+	ci := cfg.CI
+	return &Payload{
+		Program:  cfg.program(),
+		Command:  result.Command(),
+		Target:   cfg.Vars["target"],
+		ExitCode: result.ExitCode,
+		Result: &Result{
+			Summary:           result.Result,
+			Succeeded:         result.Succeeded(),
+			HasChanges:        result.HasChanges(),
+			HasDestroy:        result.HasDestroy,
+			HasParseError:     result.HasParseError,
+			Error:             errMsg,
+			ErrorCategory:     errCategory,
+			CreatedResources:  nonNil(result.CreatedResources),
+			UpdatedResources:  nonNil(result.UpdatedResources),
+			DeletedResources:  nonNil(result.DeletedResources),
+			ReplacedResources: nonNil(result.ReplacedResources),
+			DriftedResources:  nonNil(result.DriftedResources),
+			AppliedResources:  nonNil(result.AppliedResources),
+			FailedResources:   nonNil(result.FailedResources),
+			Outputs:           result.Outputs,
+		},
+		Body: body,
+		CI:   &ci,
+		Vars: cfg.Vars,
+	}
+}
+
+// nonNil returns an empty slice instead of nil so that the list is encoded as [] instead of null
+func nonNil(arr []string) []string {
+	if arr == nil {
+		return []string{}
+	}
+	return arr
+}
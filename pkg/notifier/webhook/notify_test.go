@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/notifier"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/terraform"
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		parser   terraform.Parser
+		triggers []string
+		output   string
+		exitCode int
+		exp      *Payload
+	}{
+		{
+			name:   "plan with destroy",
+			parser: terraform.NewPlanParser(),
+			output: `
+Terraform will perform the following actions:
+
+  # null_resource.foo will be destroyed
+  - resource "null_resource" "foo" {
+      - id = "1"
+    }
+
+Plan: 0 to add, 0 to change, 1 to destroy.
+`,
+			exp: &Payload{
+				Program: "tfcmt",
+				Command: "plan",
+				Target:  "foo",
+				Result: &Result{
+					Summary:           "Plan: 0 to add, 0 to change, 1 to destroy.",
+					Succeeded:         true,
+					HasChanges:        true,
+					HasDestroy:        true,
+					CreatedResources:  []string{},
+					UpdatedResources:  []string{},
+					DeletedResources:  []string{"null_resource.foo"},
+					ReplacedResources: []string{},
+					DriftedResources:  []string{},
+					AppliedResources:  []string{},
+					FailedResources:   []string{},
+				},
+				Body: "plan Plan: 0 to add, 0 to change, 1 to destroy.",
+				CI: &CI{
+					Name:     "github-actions",
+					Owner:    "suzuki-shunsuke",
+					Repo:     "tfcmt",
+					PRNumber: 1,
+					Link:     "https://ci.example.com/1",
+				},
+				Vars: map[string]string{"target": "foo"},
+			},
+		},
+		{
+			name:     "apply success isn't sent",
+			parser:   terraform.NewApplyParser(),
+			triggers: []string{notifier.TriggerApplyFailure},
+			output:   "Apply complete! Resources: 0 added, 0 changed, 1 destroyed.",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			api := &fakeAPI{}
+			ntf := &NotifyService{
+				client: &Client{
+					Config: Config{
+						CI: CI{
+							Name:     "github-actions",
+							Owner:    "suzuki-shunsuke",
+							Repo:     "tfcmt",
+							PRNumber: 1,
+							Link:     "https://ci.example.com/1",
+						},
+						Parser:   testCase.parser,
+						Template: terraform.NewPlanTemplate("plan {{.Result}}"),
+						Vars:     map[string]string{"target": "foo"},
+						Triggers: testCase.triggers,
+					},
+					API: api,
+				},
+			}
+			exitCode, err := ntf.Notify(context.Background(), notifier.ParamExec{
+				CombinedOutput: testCase.output,
+				ExitCode:       testCase.exitCode,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != testCase.exitCode {
+				t.Errorf("exit code: got %d, want %d", exitCode, testCase.exitCode)
+			}
+			if testCase.exp == nil {
+				if len(api.payloads) != 0 {
+					t.Fatalf("no payload should be sent: %+v", api.payloads[0])
+				}
+				return
+			}
+			if len(api.payloads) != 1 {
+				t.Fatalf("a payload should be sent: %d", len(api.payloads))
+			}
+			if diff := cmp.Diff(testCase.exp, api.payloads[0]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// SignatureHeader is the header of the HMAC-SHA256 signature of the request body.
+// The value is "sha256=" followed by the hex encoded signature
+const SignatureHeader = "X-Tfcmt-Signature-256"
+
+// API is the interface to send payloads
+type API interface {
+	Send(ctx context.Context, payload *Payload) error
+}
+
+// Payload is the request body of the webhook
+type Payload struct {
+	Program  string            `json:"program"`
+	Command  string            `json:"command"`
+	Target   string            `json:"target"`
+	ExitCode int               `json:"exit_code"`
+	Result   *Result           `json:"result"`
+	Body     string            `json:"body"`
+	CI       *CI               `json:"ci"`
+	Vars     map[string]string `json:"vars"`
+}
+
+// Result is the parsed result of terraform
+type Result struct {
+	Summary           string            `json:"summary"`
+	Succeeded         bool              `json:"succeeded"`
+	HasChanges        bool              `json:"has_changes"`
+	HasDestroy        bool              `json:"has_destroy"`
+	HasParseError     bool              `json:"has_parse_error"`
+	Error             string            `json:"error,omitempty"`
+	ErrorCategory     string            `json:"error_category,omitempty"`
+	CreatedResources  []string          `json:"created_resources"`
+	UpdatedResources  []string          `json:"updated_resources"`
+	DeletedResources  []string          `json:"deleted_resources"`
+	ReplacedResources []string          `json:"replaced_resources"`
+	DriftedResources  []string          `json:"drifted_resources"`
+	AppliedResources  []string          `json:"applied_resources"`
+	FailedResources   []string          `json:"failed_resources"`
+	Outputs           map[string]string `json:"outputs,omitempty"`
+}
+
+// CI is the metadata of the CI job
+type CI struct {
+	Name     string `json:"name"`
+	Owner    string `json:"owner"`
+	Repo     string `json:"repo"`
+	SHA      string `json:"sha"`
+	Branch   string `json:"branch"`
+	PRNumber int    `json:"pr_number"`
+	Link     string `json:"link"`
+}
+
+// Webhook sends payloads to the URL
+type Webhook struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+	secret  string
+}
+
+// Send posts the payload as JSON. If the secret is set, the request body is signed with HMAC-SHA256
+func (w *Webhook) Send(ctx context.Context, payload *Payload) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal the payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create a request: %w", err)
+	}
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		req.Header.Set(SignatureHeader, sign(w.secret, b))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send a request to the webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("the webhook returned the status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// sign returns the HMAC-SHA256 signature of the body
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeAPI struct {
+	payloads []*Payload
+}
+
+func (w *fakeAPI) Send(ctx context.Context, payload *Payload) error {
+	w.payloads = append(w.payloads, payload)
+	return nil
+}
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+	var (
+		got       *Payload
+		header    http.Header
+		signature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		b, _ := ioutil.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(b)
+		signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		got = &Payload{}
+		_ = json.Unmarshal(b, got)
+		if got.Command == "fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	wh := &Webhook{
+		client:  server.Client(),
+		url:     server.URL,
+		headers: map[string]string{"X-Team": "infra"},
+		secret:  "secret",
+	}
+	payload := &Payload{
+		Program: "tfcmt",
+		Command: "plan",
+		Result:  &Result{Summary: "Plan: 1 to add, 0 to change, 0 to destroy."},
+		CI:      &CI{Name: "github-actions"},
+	}
+	if err := wh.Send(context.Background(), payload); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(payload, got); diff != "" {
+		t.Error(diff)
+	}
+	if h := header.Get("X-Team"); h != "infra" {
+		t.Errorf("the custom header should be sent: %s", h)
+	}
+	if h := header.Get(SignatureHeader); h != signature {
+		t.Errorf("signature: got %s, want %s", h, signature)
+	}
+	if err := wh.Send(context.Background(), &Payload{Command: "fail"}); err == nil {
+		t.Fatal("error should be returned")
+	}
+}
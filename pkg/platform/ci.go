@@ -2,8 +2,10 @@ package platform
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/suzuki-shunsuke/go-ci-env/cienv"
 	"github.com/suzuki-shunsuke/tfcmt/pkg/config"
@@ -47,6 +49,18 @@ func getLink(ciname string) string {
 			os.Getenv("GITHUB_REPOSITORY"),
 			os.Getenv("GITHUB_RUN_ID"),
 		)
+	case "buildkite":
+		return os.Getenv("BUILDKITE_BUILD_URL")
+	case "gitlab-ci":
+		return os.Getenv("CI_JOB_URL")
+	case "bitbucket-pipelines":
+		if origin := os.Getenv("BITBUCKET_GIT_HTTP_ORIGIN"); origin != "" {
+			return origin + "/pipelines/results/" + os.Getenv("BITBUCKET_BUILD_NUMBER")
+		}
+	case "azure-pipelines":
+		if uri := os.Getenv("SYSTEM_COLLECTIONURI"); uri != "" {
+			return strings.TrimSuffix(uri, "/") + "/" + url.PathEscape(os.Getenv("SYSTEM_TEAMPROJECT")) + "/_build/results?buildId=" + os.Getenv("BUILD_BUILDID")
+		}
 	case "cloud-build", "cloudbuild":
 		return fmt.Sprintf(
 			"https://console.cloud.google.com/cloud-build/builds/%s?project=%s",
@@ -59,31 +73,44 @@ func getLink(ciname string) string {
 
 func complementWithCIEnv(ci *config.CI) error {
 	if pt := cienv.Get(); pt != nil {
-		ci.Name = pt.CI()
+		return complementWithPlatform(ci, pt)
+	}
+	// Fall back to the platforms which go-ci-env doesn't support
+	if pt := getExtraPlatform(os.Getenv); pt != nil {
+		return complementWithPlatform(ci, pt)
+	}
+	return nil
+}
 
-		if ci.Owner == "" {
-			ci.Owner = pt.RepoOwner()
-		}
+func complementWithPlatform(ci *config.CI, pt platform) error {
+	ci.Name = pt.CI()
 
-		if ci.Repo == "" {
-			ci.Repo = pt.RepoName()
-		}
+	if ci.Owner == "" {
+		ci.Owner = pt.RepoOwner()
+	}
 
-		if ci.SHA == "" {
-			ci.SHA = pt.SHA()
-		}
+	if ci.Repo == "" {
+		ci.Repo = pt.RepoName()
+	}
 
-		if ci.PRNumber <= 0 {
-			n, err := pt.PRNumber()
-			if err != nil {
-				return err
-			}
-			ci.PRNumber = n
-		}
+	if ci.SHA == "" {
+		ci.SHA = pt.SHA()
+	}
+
+	if ci.Branch == "" {
+		ci.Branch = pt.Branch()
+	}
 
-		if ci.Link == "" {
-			ci.Link = getLink(ci.Name)
+	if ci.PRNumber <= 0 {
+		n, err := pt.PRNumber()
+		if err != nil {
+			return err
 		}
+		ci.PRNumber = n
+	}
+
+	if ci.Link == "" {
+		ci.Link = getLink(ci.Name)
 	}
 	return nil
 }
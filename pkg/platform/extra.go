@@ -0,0 +1,260 @@
+package platform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// platform is the subset of cienv.Platform which tfcmt uses to complement the configuration
+type platform interface {
+	CI() string
+	RepoOwner() string
+	RepoName() string
+	SHA() string
+	Branch() string
+	PRNumber() (int, error)
+}
+
+// getExtraPlatform returns the CI platform which go-ci-env doesn't support.
+// If no platform matches, nil is returned.
+func getExtraPlatform(getenv func(string) string) platform {
+	if bk := (buildkite{getenv: getenv}); bk.Match() {
+		return bk
+	}
+	if hn := (harness{getenv: getenv}); hn.Match() {
+		return hn
+	}
+	if gl := (gitlabCI{getenv: getenv}); gl.Match() {
+		return gl
+	}
+	if bb := (bitbucketPipelines{getenv: getenv}); bb.Match() {
+		return bb
+	}
+	if az := (azurePipelines{getenv: getenv}); az.Match() {
+		return az
+	}
+	return nil
+}
+
+// buildkite gets the information from the environment variables of Buildkite.
+// https://buildkite.com/docs/pipelines/environment-variables
+type buildkite struct {
+	getenv func(string) string
+}
+
+func (bk buildkite) CI() string {
+	return "buildkite"
+}
+
+func (bk buildkite) Match() bool {
+	return bk.getenv("BUILDKITE") == "true"
+}
+
+func (bk buildkite) RepoOwner() string {
+	owner, _ := parseRepoURL(bk.getenv("BUILDKITE_REPO"))
+	return owner
+}
+
+func (bk buildkite) RepoName() string {
+	_, repo := parseRepoURL(bk.getenv("BUILDKITE_REPO"))
+	return repo
+}
+
+func (bk buildkite) SHA() string {
+	// BUILDKITE_COMMIT can be HEAD when the build is triggered without a commit
+	if sha := bk.getenv("BUILDKITE_COMMIT"); sha != "HEAD" {
+		return sha
+	}
+	return ""
+}
+
+func (bk buildkite) Branch() string {
+	return bk.getenv("BUILDKITE_BRANCH")
+}
+
+func (bk buildkite) PRNumber() (int, error) {
+	// BUILDKITE_PULL_REQUEST is "false" if the build isn't a pull request build
+	return parsePRNumber("BUILDKITE_PULL_REQUEST", bk.getenv("BUILDKITE_PULL_REQUEST"), "false")
+}
+
+// harness gets the information from the environment variables of Harness CI.
+// Harness CI sets the environment variables compatible with Drone, but DRONE isn't set.
+// https://developer.harness.io/docs/continuous-integration/troubleshoot-ci/ci-env-var
+type harness struct {
+	getenv func(string) string
+}
+
+func (hn harness) CI() string {
+	return "harness"
+}
+
+func (hn harness) Match() bool {
+	return hn.getenv("HARNESS_BUILD_ID") != ""
+}
+
+func (hn harness) RepoOwner() string {
+	return hn.getenv("DRONE_REPO_OWNER")
+}
+
+func (hn harness) RepoName() string {
+	return hn.getenv("DRONE_REPO_NAME")
+}
+
+func (hn harness) SHA() string {
+	return hn.getenv("DRONE_COMMIT_SHA")
+}
+
+func (hn harness) Branch() string {
+	return hn.getenv("DRONE_SOURCE_BRANCH")
+}
+
+func (hn harness) PRNumber() (int, error) {
+	return parsePRNumber("DRONE_PULL_REQUEST", hn.getenv("DRONE_PULL_REQUEST"), "")
+}
+
+// gitlabCI gets the information from the environment variables of GitLab CI.
+// The owner is the namespace of the project, which can include subgroups.
+// The pull request number is the internal id (iid) of the merge request, which is set only in merge request pipelines.
+// https://docs.gitlab.com/ee/ci/variables/predefined_variables.html
+type gitlabCI struct {
+	getenv func(string) string
+}
+
+func (gl gitlabCI) CI() string {
+	return "gitlab-ci"
+}
+
+func (gl gitlabCI) Match() bool {
+	return gl.getenv("GITLAB_CI") == "true"
+}
+
+func (gl gitlabCI) RepoOwner() string {
+	return gl.getenv("CI_PROJECT_NAMESPACE")
+}
+
+func (gl gitlabCI) RepoName() string {
+	return gl.getenv("CI_PROJECT_NAME")
+}
+
+func (gl gitlabCI) SHA() string {
+	return gl.getenv("CI_COMMIT_SHA")
+}
+
+func (gl gitlabCI) Branch() string {
+	if branch := gl.getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"); branch != "" {
+		return branch
+	}
+	return gl.getenv("CI_COMMIT_BRANCH")
+}
+
+func (gl gitlabCI) PRNumber() (int, error) {
+	return parsePRNumber("CI_MERGE_REQUEST_IID", gl.getenv("CI_MERGE_REQUEST_IID"), "")
+}
+
+// bitbucketPipelines gets the information from the environment variables of Bitbucket Pipelines.
+// The owner is the workspace and the name is the slug of the repository.
+// https://support.atlassian.com/bitbucket-cloud/docs/variables-and-secrets/
+type bitbucketPipelines struct {
+	getenv func(string) string
+}
+
+func (bb bitbucketPipelines) CI() string {
+	return "bitbucket-pipelines"
+}
+
+func (bb bitbucketPipelines) Match() bool {
+	return bb.getenv("BITBUCKET_BUILD_NUMBER") != ""
+}
+
+func (bb bitbucketPipelines) RepoOwner() string {
+	if workspace := bb.getenv("BITBUCKET_WORKSPACE"); workspace != "" {
+		return workspace
+	}
+	return bb.getenv("BITBUCKET_REPO_OWNER")
+}
+
+func (bb bitbucketPipelines) RepoName() string {
+	return bb.getenv("BITBUCKET_REPO_SLUG")
+}
+
+func (bb bitbucketPipelines) SHA() string {
+	return bb.getenv("BITBUCKET_COMMIT")
+}
+
+func (bb bitbucketPipelines) Branch() string {
+	return bb.getenv("BITBUCKET_BRANCH")
+}
+
+func (bb bitbucketPipelines) PRNumber() (int, error) {
+	return parsePRNumber("BITBUCKET_PR_ID", bb.getenv("BITBUCKET_PR_ID"), "")
+}
+
+// azurePipelines gets the information from the environment variables of Azure Pipelines.
+// The owner is the project of Azure DevOps.
+// https://learn.microsoft.com/en-us/azure/devops/pipelines/build/variables
+type azurePipelines struct {
+	getenv func(string) string
+}
+
+func (az azurePipelines) CI() string {
+	return "azure-pipelines"
+}
+
+func (az azurePipelines) Match() bool {
+	return az.getenv("TF_BUILD") == "True"
+}
+
+func (az azurePipelines) RepoOwner() string {
+	return az.getenv("SYSTEM_TEAMPROJECT")
+}
+
+func (az azurePipelines) RepoName() string {
+	return az.getenv("BUILD_REPOSITORY_NAME")
+}
+
+func (az azurePipelines) SHA() string {
+	// BUILD_SOURCEVERSION is the merge commit in pull request builds
+	if sha := az.getenv("SYSTEM_PULLREQUEST_SOURCECOMMITID"); sha != "" {
+		return sha
+	}
+	return az.getenv("BUILD_SOURCEVERSION")
+}
+
+func (az azurePipelines) Branch() string {
+	if branch := az.getenv("SYSTEM_PULLREQUEST_SOURCEBRANCH"); branch != "" {
+		return strings.TrimPrefix(branch, "refs/heads/")
+	}
+	return az.getenv("BUILD_SOURCEBRANCHNAME")
+}
+
+func (az azurePipelines) PRNumber() (int, error) {
+	return parsePRNumber("SYSTEM_PULLREQUEST_PULLREQUESTID", az.getenv("SYSTEM_PULLREQUEST_PULLREQUESTID"), "")
+}
+
+func parsePRNumber(name, value, none string) (int, error) {
+	if value == "" || value == none {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s %s: %w", name, value, err)
+	}
+	return n, nil
+}
+
+// parseRepoURL returns the owner and name of the repository from the clone URL.
+// Both SSH (git@github.com:owner/repo.git) and HTTPS (https://github.com/owner/repo.git) URLs are supported.
+func parseRepoURL(u string) (string, string) {
+	u = strings.TrimSuffix(u, ".git")
+	if idx := strings.Index(u, "://"); idx != -1 {
+		u = u[idx+len("://"):]
+	} else {
+		u = strings.Replace(u, ":", "/", 1)
+	}
+	paths := strings.Split(u, "/")
+	if len(paths) < 3 { //nolint:gomnd
+		return "", ""
+	}
+	return paths[len(paths)-2], paths[len(paths)-1]
+}
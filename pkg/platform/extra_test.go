@@ -0,0 +1,197 @@
+package platform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/suzuki-shunsuke/tfcmt/pkg/config"
+)
+
+func TestComplementWithExtraPlatform(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name  string
+		env   map[string]string
+		ci    config.CI
+		exp   config.CI
+		isNil bool
+		isErr bool
+	}{
+		{
+			name:  "unknown platform",
+			env:   map[string]string{},
+			isNil: true,
+		},
+		{
+			name: "buildkite",
+			env: map[string]string{
+				"BUILDKITE":              "true",
+				"BUILDKITE_REPO":         "git@github.com:suzuki-shunsuke/tfcmt.git",
+				"BUILDKITE_COMMIT":       "abcd",
+				"BUILDKITE_BRANCH":       "feature",
+				"BUILDKITE_PULL_REQUEST": "10",
+			},
+			exp: config.CI{
+				Name:     "buildkite",
+				Owner:    "suzuki-shunsuke",
+				Repo:     "tfcmt",
+				SHA:      "abcd",
+				Branch:   "feature",
+				PRNumber: 10,
+			},
+		},
+		{
+			name: "buildkite not pull request",
+			env: map[string]string{
+				"BUILDKITE":              "true",
+				"BUILDKITE_REPO":         "https://github.com/suzuki-shunsuke/tfcmt.git",
+				"BUILDKITE_COMMIT":       "HEAD",
+				"BUILDKITE_PULL_REQUEST": "false",
+			},
+			exp: config.CI{
+				Name:  "buildkite",
+				Owner: "suzuki-shunsuke",
+				Repo:  "tfcmt",
+			},
+		},
+		{
+			name: "buildkite invalid pull request number",
+			env: map[string]string{
+				"BUILDKITE":              "true",
+				"BUILDKITE_PULL_REQUEST": "foo",
+			},
+			isErr: true,
+		},
+		{
+			name: "harness",
+			env: map[string]string{
+				"HARNESS_BUILD_ID":    "5",
+				"DRONE_REPO_OWNER":    "suzuki-shunsuke",
+				"DRONE_REPO_NAME":     "tfcmt",
+				"DRONE_COMMIT_SHA":    "abcd",
+				"DRONE_SOURCE_BRANCH": "feature",
+				"DRONE_PULL_REQUEST":  "3",
+			},
+			ci: config.CI{
+				PRNumber: 1,
+			},
+			exp: config.CI{
+				Name:     "harness",
+				Owner:    "suzuki-shunsuke",
+				Repo:     "tfcmt",
+				SHA:      "abcd",
+				Branch:   "feature",
+				PRNumber: 1,
+			},
+		},
+		{
+			name: "gitlab merge request pipeline",
+			env: map[string]string{
+				"GITLAB_CI":                           "true",
+				"CI_PROJECT_NAMESPACE":                "suzuki-shunsuke/infra",
+				"CI_PROJECT_NAME":                     "tfcmt",
+				"CI_COMMIT_SHA":                       "abcd",
+				"CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature",
+				"CI_COMMIT_BRANCH":                    "main",
+				"CI_MERGE_REQUEST_IID":                "7",
+			},
+			exp: config.CI{
+				Name:     "gitlab-ci",
+				Owner:    "suzuki-shunsuke/infra",
+				Repo:     "tfcmt",
+				SHA:      "abcd",
+				Branch:   "feature",
+				PRNumber: 7,
+			},
+		},
+		{
+			name: "gitlab branch pipeline",
+			env: map[string]string{
+				"GITLAB_CI":            "true",
+				"CI_PROJECT_NAMESPACE": "suzuki-shunsuke",
+				"CI_PROJECT_NAME":      "tfcmt",
+				"CI_COMMIT_SHA":        "abcd",
+				"CI_COMMIT_BRANCH":     "main",
+			},
+			exp: config.CI{
+				Name:   "gitlab-ci",
+				Owner:  "suzuki-shunsuke",
+				Repo:   "tfcmt",
+				SHA:    "abcd",
+				Branch: "main",
+			},
+		},
+		{
+			name: "bitbucket pipelines",
+			env: map[string]string{
+				"BITBUCKET_BUILD_NUMBER": "10",
+				"BITBUCKET_WORKSPACE":    "suzuki-shunsuke",
+				"BITBUCKET_REPO_OWNER":   "foo",
+				"BITBUCKET_REPO_SLUG":    "tfcmt",
+				"BITBUCKET_COMMIT":       "abcd",
+				"BITBUCKET_BRANCH":       "feature",
+				"BITBUCKET_PR_ID":        "4",
+			},
+			exp: config.CI{
+				Name:     "bitbucket-pipelines",
+				Owner:    "suzuki-shunsuke",
+				Repo:     "tfcmt",
+				SHA:      "abcd",
+				Branch:   "feature",
+				PRNumber: 4,
+			},
+		},
+		{
+			name: "azure pipelines",
+			env: map[string]string{
+				"TF_BUILD":                          "True",
+				"SYSTEM_TEAMPROJECT":                "infra",
+				"BUILD_REPOSITORY_NAME":             "tfcmt",
+				"BUILD_SOURCEVERSION":               "merge",
+				"SYSTEM_PULLREQUEST_SOURCECOMMITID": "abcd",
+				"SYSTEM_PULLREQUEST_SOURCEBRANCH":   "refs/heads/feature",
+				"BUILD_SOURCEBRANCHNAME":            "merge",
+				"SYSTEM_PULLREQUEST_PULLREQUESTID":  "12",
+			},
+			exp: config.CI{
+				Name:     "azure-pipelines",
+				Owner:    "infra",
+				Repo:     "tfcmt",
+				SHA:      "abcd",
+				Branch:   "feature",
+				PRNumber: 12,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			pt := getExtraPlatform(func(k string) string {
+				return testCase.env[k]
+			})
+			if pt == nil {
+				if !testCase.isNil {
+					t.Fatal("platform should be found")
+				}
+				return
+			}
+			if testCase.isNil {
+				t.Fatalf("platform should not be found: %s", pt.CI())
+			}
+			ci := testCase.ci
+			if err := complementWithPlatform(&ci, pt); err != nil {
+				if !testCase.isErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			if testCase.isErr {
+				t.Fatal("error should be returned")
+			}
+			if diff := cmp.Diff(testCase.exp, ci); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package terraform
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CDKTFParser is a parser for the output of cdktf diff and cdktf deploy.
+// cdktf prefixes the output of terraform with the stack name and appends the construct id to resource addresses,
+// so the output is converted to the one of terraform and parsed by Plan or Apply
+type CDKTFParser struct {
+	// Command is either CommandPlan or CommandApply. This is set to ParseResult.DetectedCommand
+	Command string
+	Plan    *PlanParser
+	Apply   *ApplyParser
+	// StackPrefix matches lines prefixed with the stack name like "dev  Plan: 1 to add, 0 to change, 0 to destroy."
+	StackPrefix *regexp.Regexp
+	// ConstructID matches resource addresses with the construct id like "aws_s3_bucket.bucket (bucket) will be created"
+	ConstructID *regexp.Regexp
+}
+
+// NewCDKTFParser is CDKTFParser initializer
+func NewCDKTFParser(command string) *CDKTFParser {
+	return &CDKTFParser{
+		Command:     command,
+		Plan:        NewPlanParser(),
+		Apply:       NewApplyParser(),
+		StackPrefix: regexp.MustCompile(`^([a-zA-Z0-9_-]+)  (.*)$`),
+		ConstructID: regexp.MustCompile(`^( *(?:# )?[^ ()]+) \([^()]+\)((?: will be| must be| has|:) .*)$`),
+	}
+}
+
+// normalize removes the stack prefix and construct ids from the output.
+// cdktf indents the continued lines of the same stack by the width of the prefix
+func (p *CDKTFParser) normalize(body string) string {
+	lines := strings.Split(body, "\n")
+	indent := ""
+	for i, line := range lines {
+		if arr := p.StackPrefix.FindStringSubmatch(line); arr != nil {
+			indent = strings.Repeat(" ", len(arr[1])+2) //nolint:gomnd
+			line = arr[2]
+		} else if indent != "" && strings.HasPrefix(line, indent) {
+			line = strings.TrimPrefix(line, indent)
+		}
+		lines[i] = p.ConstructID.ReplaceAllString(line, "$1$2")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Parse parses the output of cdktf diff or cdktf deploy
+func (p *CDKTFParser) Parse(body string) ParseResult {
+	body = p.normalize(body)
+	var result ParseResult
+	if p.Command == CommandApply {
+		result = p.Apply.Parse(body)
+	} else {
+		result = p.Plan.Parse(body)
+	}
+	result.DetectedCommand = p.Command
+	return result
+}
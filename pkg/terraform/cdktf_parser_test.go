@@ -0,0 +1,109 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const cdktfDiffResult = `dev  Initializing the backend...
+dev  Terraform used the selected providers to generate the following execution
+     plan. Resource actions are indicated with the following symbols:
+       + create
+       - destroy
+
+     Terraform will perform the following actions:
+dev    # aws_s3_bucket.bucket (bucket) will be created
+       + resource "aws_s3_bucket" "bucket" {
+           + bucket = "foo"
+         }
+
+       # aws_s3_bucket.old (old) will be destroyed
+       - resource "aws_s3_bucket" "old" {}
+
+     Plan: 1 to add, 0 to change, 1 to destroy.
+`
+
+const cdktfDeployResult = `dev  aws_s3_bucket.bucket (bucket): Creating...
+dev  aws_s3_bucket.bucket (bucket): Creation complete after 1s [id=foo]
+dev
+     Apply complete! Resources: 1 added, 0 changed, 0 destroyed.
+`
+
+const cdktfDeployFailureResult = `dev  aws_s3_bucket.bucket (bucket): Creating...
+     aws_s3_bucket.bar (bar): Creating...
+dev  aws_s3_bucket.bucket (bucket): Creation complete after 1s [id=foo]
+dev  Error: creating S3 Bucket (bar): BucketAlreadyExists
+`
+
+func TestCDKTFParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		command  string
+		body     string
+		result   string
+		destroy  bool
+		created  []string
+		deleted  []string
+		applied  []string
+		exitCode int
+	}{
+		{
+			name:     "diff",
+			command:  CommandPlan,
+			body:     cdktfDiffResult,
+			result:   "Plan: 1 to add, 0 to change, 1 to destroy.",
+			destroy:  true,
+			created:  []string{"aws_s3_bucket.bucket"},
+			deleted:  []string{"aws_s3_bucket.old"},
+			exitCode: ExitPass,
+		},
+		{
+			name:     "deploy",
+			command:  CommandApply,
+			body:     cdktfDeployResult,
+			result:   "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+			exitCode: ExitPass,
+		},
+		{
+			name:     "deploy failed partially",
+			command:  CommandApply,
+			body:     cdktfDeployFailureResult,
+			result:   "Error: creating S3 Bucket (bar): BucketAlreadyExists",
+			applied:  []string{"aws_s3_bucket.bucket"},
+			exitCode: ExitFail,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewCDKTFParser(testCase.command).Parse(testCase.body)
+			if result.HasParseError {
+				t.Fatal(result.Error)
+			}
+			if result.Result != testCase.result {
+				t.Errorf("Result: got %q, wanted %q", result.Result, testCase.result)
+			}
+			if result.ExitCode != testCase.exitCode {
+				t.Errorf("ExitCode: got %d, wanted %d", result.ExitCode, testCase.exitCode)
+			}
+			if result.HasDestroy != testCase.destroy {
+				t.Errorf("HasDestroy: got %v, wanted %v", result.HasDestroy, testCase.destroy)
+			}
+			if result.DetectedCommand != testCase.command {
+				t.Errorf("DetectedCommand: got %q, wanted %q", result.DetectedCommand, testCase.command)
+			}
+			if diff := cmp.Diff(testCase.created, result.CreatedResources); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.deleted, result.DeletedResources); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.applied, result.AppliedResources); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
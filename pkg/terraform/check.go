@@ -0,0 +1,110 @@
+package terraform
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// CheckSeverityError means the condition failed and the plan or apply failed
+	CheckSeverityError = "error"
+	// CheckSeverityWarning means the check block assertion failed. The plan or apply doesn't fail
+	CheckSeverityWarning = "warning"
+)
+
+// Check is a failed assertion of a check block or a failed precondition or postcondition
+type Check struct {
+	// Address is the address of the object such as "check.health" and "aws_instance.foo[0]"
+	Address string
+	// Summary is the summary of the diagnostic such as "Check block assertion failed"
+	Summary string
+	// Message is the error message of the condition
+	Message string
+	// Severity is either CheckSeverityError or CheckSeverityWarning
+	Severity string
+}
+
+var (
+	// checkSummaryPattern matches the first line of diagnostics of checks. Diagnostics may be drawn in the box of "│ "
+	checkSummaryPattern = regexp.MustCompile(`^(?:│ ?)?(Error|Warning): (Check block assertion failed|Error in check block|Resource precondition failed|Resource postcondition failed|Module output value precondition failed|Output value precondition failed)$`)
+	// diagnosticPattern matches the first line of any diagnostics
+	diagnosticPattern = regexp.MustCompile(`^(?:│ ?)?(?:Error|Warning): `)
+	// checkLocationPattern matches the location like `on main.tf line 1, in check "health":`
+	checkLocationPattern = regexp.MustCompile(`^\s+on .+ line \d+, in (check|resource|data|output) "([^"]+)"(?: "([^"]+)")?:$`)
+	// checkWithPattern matches the object of the diagnostic like "with aws_instance.foo[0],"
+	checkWithPattern = regexp.MustCompile(`^\s+with (.+),$`)
+)
+
+// parseChecks returns failed checks in the output of terraform plan or terraform apply.
+// The message is lines which aren't indented after the source code of the condition
+func parseChecks(body string) []Check {
+	var checks []Check
+	var check *Check
+	var with string
+	// done is true if the message ends
+	var done bool
+	flush := func() {
+		if check == nil {
+			return
+		}
+		if with != "" {
+			check.Address = with
+		}
+		checks = append(checks, *check)
+		check = nil
+		with = ""
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if arr := checkSummaryPattern.FindStringSubmatch(line); arr != nil {
+			flush()
+			check = &Check{
+				Summary:  arr[2],
+				Severity: strings.ToLower(arr[1]),
+			}
+			done = false
+			continue
+		}
+		if check == nil {
+			continue
+		}
+		if diagnosticPattern.MatchString(line) || strings.HasPrefix(line, "╵") || strings.HasPrefix(line, "─") {
+			flush()
+			continue
+		}
+		line = strings.TrimPrefix(strings.TrimPrefix(line, "│"), " ")
+		if arr := checkLocationPattern.FindStringSubmatch(line); arr != nil {
+			check.Address = checkAddress(arr[1], arr[2], arr[3])
+			continue
+		}
+		if arr := checkWithPattern.FindStringSubmatch(line); arr != nil {
+			with = arr[1]
+			continue
+		}
+		if done || strings.HasPrefix(line, " ") {
+			continue
+		}
+		if line == "" {
+			done = check.Message != ""
+			continue
+		}
+		if check.Message == "" {
+			check.Message = line
+		} else {
+			check.Message += "\n" + line
+		}
+	}
+	flush()
+	return checks
+}
+
+// checkAddress returns the address of the object from the block type and labels of the location
+func checkAddress(blockType, label1, label2 string) string {
+	switch blockType {
+	case "resource":
+		return label1 + "." + label2
+	case "data":
+		return "data." + label1 + "." + label2
+	default:
+		return blockType + "." + label1
+	}
+}
@@ -0,0 +1,137 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const planCheckBlockAssertionFailed = `
+Terraform will perform the following actions:
+
+  # null_resource.foo will be created
+  + resource "null_resource" "foo" {}
+
+Plan: 1 to add, 0 to change, 0 to destroy.
+╷
+│ Warning: Check block assertion failed
+│
+│   on main.tf line 10, in check "health":
+│   10:     condition     = data.http.example.status_code == 200
+│     ├────────────────
+│     │ data.http.example.status_code is 404
+│
+│ The health check failed: status code 404
+╵
+
+─────────────────────────────────────────────────────────────────────────────
+
+Note: You didn't use the -out option to save this plan, so Terraform can't
+guarantee to take exactly these actions if you run "terraform apply" now.
+`
+
+const applyPostconditionFailed = `null_resource.foo[0]: Creating...
+null_resource.foo[0]: Creation complete after 0s [id=1]
+
+Error: Resource postcondition failed
+
+  on main.tf line 5, in resource "null_resource" "foo":
+   5:       condition     = self.id != ""
+    ├────────────────
+    │ self.id is ""
+
+The id must not be empty.
+
+Error: Module output value precondition failed
+
+  on main.tf line 20, in output "url":
+  20:     condition     = var.url != ""
+
+The url must not be empty.
+`
+
+func TestParseChecks(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		body   string
+		checks []Check
+	}{
+		{
+			name: "check block",
+			body: planCheckBlockAssertionFailed,
+			checks: []Check{
+				{
+					Address:  "check.health",
+					Summary:  "Check block assertion failed",
+					Message:  "The health check failed: status code 404",
+					Severity: CheckSeverityWarning,
+				},
+			},
+		},
+		{
+			name: "postcondition and precondition",
+			body: applyPostconditionFailed,
+			checks: []Check{
+				{
+					Address:  "null_resource.foo",
+					Summary:  "Resource postcondition failed",
+					Message:  "The id must not be empty.",
+					Severity: CheckSeverityError,
+				},
+				{
+					Address:  "output.url",
+					Summary:  "Module output value precondition failed",
+					Message:  "The url must not be empty.",
+					Severity: CheckSeverityError,
+				},
+			},
+		},
+		{
+			name: "the address in the with line",
+			body: `Error: Resource precondition failed
+
+  on main.tf line 3, in resource "null_resource" "foo":
+   3:       condition     = var.enabled
+    ├────────────────
+    │ var.enabled is false
+
+  with null_resource.foo["a"],
+
+The resource must be enabled.
+`,
+			checks: []Check{
+				{
+					Address:  `null_resource.foo["a"]`,
+					Summary:  "Resource precondition failed",
+					Message:  "The resource must be enabled.",
+					Severity: CheckSeverityError,
+				},
+			},
+		},
+		{
+			name: "no check",
+			body: "Error: Invalid reference\n\n  on main.tf line 1:\n\nA reference to a resource type must be followed by at least one attribute access.",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(testCase.checks, parseChecks(testCase.body)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestPlanParserParseChecks(t *testing.T) {
+	t.Parallel()
+	result := NewPlanParser().Parse(planCheckBlockAssertionFailed)
+	if result.ExitCode != ExitPass {
+		t.Fatalf("ExitCode: got %d, wanted %d", result.ExitCode, ExitPass)
+	}
+	if len(result.Checks) != 1 || result.Checks[0].Address != "check.health" {
+		t.Errorf("the failed check should be parsed: %+v", result.Checks)
+	}
+}
@@ -0,0 +1,132 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CheckovReport is the report of Checkov.
+// The format is compatible with the output of `checkov --output json`.
+// Checkov outputs an array of reports if multiple frameworks are scanned
+type CheckovReport struct {
+	Reports []CheckovJSONReport
+}
+
+// CheckovJSONReport is the report of a framework
+type CheckovJSONReport struct {
+	CheckType string `json:"check_type"`
+	Results   struct {
+		FailedChecks []CheckovJSONCheck `json:"failed_checks"`
+	} `json:"results"`
+}
+
+// CheckovJSONCheck is a failed check of Checkov
+type CheckovJSONCheck struct {
+	CheckID       string `json:"check_id"`
+	CheckName     string `json:"check_name"`
+	FilePath      string `json:"file_path"`
+	FileLineRange []int  `json:"file_line_range"`
+	Resource      string `json:"resource"`
+	Guideline     string `json:"guideline"`
+}
+
+// CheckovResource is passed to templates as an element of CheckovResources
+type CheckovResource struct {
+	Address string
+	// Action is the action of the resource in the plan such as "create" and "update". This is empty if the resource isn't changed
+	Action string
+	Checks []CheckovCheck
+}
+
+// CheckovCheck is a failed check of a resource
+type CheckovCheck struct {
+	ID       string
+	Name     string
+	Filename string
+	Line     int
+	// Guideline is the URL of the document of the check. This may be empty
+	Guideline string
+}
+
+// ParseCheckovReport parses the report of Checkov
+func ParseCheckovReport(b []byte) (*CheckovReport, error) {
+	report := &CheckovReport{}
+	if strings.HasPrefix(strings.TrimSpace(string(b)), "[") {
+		if err := json.Unmarshal(b, &report.Reports); err != nil {
+			return nil, fmt.Errorf("parse a report of Checkov as JSON: %w", err)
+		}
+		return report, nil
+	}
+	r := CheckovJSONReport{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("parse a report of Checkov as JSON: %w", err)
+	}
+	report.Reports = []CheckovJSONReport{r}
+	return report, nil
+}
+
+// FailedResources returns failed checks grouped by the resource address.
+// Resources changed in the plan come first in the order of the plan result, and the action of the resource is set
+func (report *CheckovReport) FailedResources(result ParseResult) []CheckovResource {
+	if report == nil {
+		return nil
+	}
+	actions := map[string]string{}
+	order := map[string]int{}
+	for _, a := range []struct {
+		action    string
+		resources []string
+	}{
+		{action: "create", resources: result.CreatedResources},
+		{action: "update", resources: result.UpdatedResources},
+		{action: "replace", resources: result.ReplacedResources},
+		{action: "delete", resources: result.DeletedResources},
+	} {
+		for _, address := range a.resources {
+			actions[address] = a.action
+			order[address] = len(order)
+		}
+	}
+	var changed, unchanged []CheckovResource
+	indexes := map[string]int{}
+	for _, r := range report.Reports {
+		for _, check := range r.Results.FailedChecks {
+			c := CheckovCheck{
+				ID:        check.CheckID,
+				Name:      check.CheckName,
+				Filename:  strings.TrimPrefix(check.FilePath, "/"),
+				Guideline: check.Guideline,
+			}
+			if len(check.FileLineRange) != 0 {
+				c.Line = check.FileLineRange[0]
+			}
+			if idx, ok := indexes[check.Resource]; ok {
+				if _, isChanged := actions[check.Resource]; isChanged {
+					changed[idx].Checks = append(changed[idx].Checks, c)
+				} else {
+					unchanged[idx].Checks = append(unchanged[idx].Checks, c)
+				}
+				continue
+			}
+			resource := CheckovResource{
+				Address: check.Resource,
+				Action:  actions[check.Resource],
+				Checks:  []CheckovCheck{c},
+			}
+			if resource.Action != "" {
+				indexes[check.Resource] = len(changed)
+				changed = append(changed, resource)
+				continue
+			}
+			indexes[check.Resource] = len(unchanged)
+			unchanged = append(unchanged, resource)
+		}
+	}
+	// the order of the plan result is kept
+	sort.SliceStable(changed, func(i, j int) bool {
+		return order[changed[i].Address] < order[changed[j].Address]
+	})
+	return append(changed, unchanged...)
+}
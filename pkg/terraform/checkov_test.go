@@ -0,0 +1,140 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const checkovReport = `{
+  "check_type": "terraform",
+  "results": {
+    "passed_checks": [],
+    "failed_checks": [
+      {
+        "check_id": "CKV_AWS_18",
+        "check_name": "Ensure the S3 bucket has access logging enabled",
+        "check_result": {"result": "FAILED"},
+        "file_path": "/main.tf",
+        "file_line_range": [10, 12],
+        "resource": "aws_s3_bucket.unchanged",
+        "guideline": "https://example.com/CKV_AWS_18"
+      },
+      {
+        "check_id": "CKV_AWS_8",
+        "check_name": "Ensure all data stored in the Launch configuration EBS is securely encrypted",
+        "check_result": {"result": "FAILED"},
+        "file_path": "/main.tf",
+        "file_line_range": [1, 5],
+        "resource": "aws_instance.web"
+      },
+      {
+        "check_id": "CKV_AWS_21",
+        "check_name": "Ensure all data stored in the S3 bucket have versioning enabled",
+        "check_result": {"result": "FAILED"},
+        "file_path": "/main.tf",
+        "file_line_range": [20, 22],
+        "resource": "aws_s3_bucket.created"
+      },
+      {
+        "check_id": "CKV_AWS_144",
+        "check_name": "Ensure that S3 bucket has cross-region replication enabled",
+        "check_result": {"result": "FAILED"},
+        "file_path": "/main.tf",
+        "file_line_range": [20, 22],
+        "resource": "aws_s3_bucket.created"
+      }
+    ]
+  },
+  "summary": {"passed": 0, "failed": 4}
+}`
+
+func TestParseCheckovReport(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		body   string
+		result ParseResult
+		exp    []CheckovResource
+	}{
+		{
+			name: "resources changed in the plan come first",
+			body: checkovReport,
+			result: ParseResult{
+				CreatedResources: []string{"aws_s3_bucket.created"},
+				UpdatedResources: []string{"aws_instance.web"},
+			},
+			exp: []CheckovResource{
+				{
+					Address: "aws_s3_bucket.created",
+					Action:  "create",
+					Checks: []CheckovCheck{
+						{
+							ID:       "CKV_AWS_21",
+							Name:     "Ensure all data stored in the S3 bucket have versioning enabled",
+							Filename: "main.tf",
+							Line:     20,
+						},
+						{
+							ID:       "CKV_AWS_144",
+							Name:     "Ensure that S3 bucket has cross-region replication enabled",
+							Filename: "main.tf",
+							Line:     20,
+						},
+					},
+				},
+				{
+					Address: "aws_instance.web",
+					Action:  "update",
+					Checks: []CheckovCheck{
+						{
+							ID:       "CKV_AWS_8",
+							Name:     "Ensure all data stored in the Launch configuration EBS is securely encrypted",
+							Filename: "main.tf",
+							Line:     1,
+						},
+					},
+				},
+				{
+					Address: "aws_s3_bucket.unchanged",
+					Checks: []CheckovCheck{
+						{
+							ID:        "CKV_AWS_18",
+							Name:      "Ensure the S3 bucket has access logging enabled",
+							Filename:  "main.tf",
+							Line:      10,
+							Guideline: "https://example.com/CKV_AWS_18",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "multiple frameworks",
+			body: `[{"check_type": "terraform", "results": {"failed_checks": [{"check_id": "CKV_AWS_18", "resource": "aws_s3_bucket.foo"}]}}, {"check_type": "secrets", "results": {"failed_checks": []}}]`,
+			exp: []CheckovResource{
+				{
+					Address: "aws_s3_bucket.foo",
+					Checks: []CheckovCheck{
+						{
+							ID: "CKV_AWS_18",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			report, err := ParseCheckovReport([]byte(testCase.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.exp, report.FailedResources(testCase.result)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// CostEstimate is a cost estimate of the plan.
+// The format is compatible with the output of `infracost breakdown --format json`.
+type CostEstimate struct {
+	Currency             string        `json:"currency"`
+	TotalMonthlyCost     string        `json:"totalMonthlyCost"`
+	PastTotalMonthlyCost string        `json:"pastTotalMonthlyCost"`
+	DiffTotalMonthlyCost string        `json:"diffTotalMonthlyCost"`
+	Projects             []CostProject `json:"projects"`
+}
+
+// CostProject is a cost estimate of a project
+type CostProject struct {
+	Name          string         `json:"name"`
+	Breakdown     *CostBreakdown `json:"breakdown"`
+	PastBreakdown *CostBreakdown `json:"pastBreakdown"`
+	Diff          *CostBreakdown `json:"diff"`
+}
+
+// CostBreakdown is a breakdown of a cost estimate
+type CostBreakdown struct {
+	TotalMonthlyCost string         `json:"totalMonthlyCost"`
+	Resources        []CostResource `json:"resources"`
+}
+
+// CostResource is a cost estimate of a resource
+type CostResource struct {
+	Name        string `json:"name"`
+	MonthlyCost string `json:"monthlyCost"`
+}
+
+// CostBreakdownEntry is passed to templates as an element of CostBreakdown
+type CostBreakdownEntry struct {
+	Name            string
+	MonthlyCost     string
+	PastMonthlyCost string
+	Delta           string
+}
+
+// CostResourceEntry is passed to templates as an element of CostResources
+type CostResourceEntry struct {
+	Project         string
+	Name            string
+	MonthlyCost     string
+	PastMonthlyCost string
+	Delta           string
+}
+
+// ParseCostEstimate parses a cost estimate
+func ParseCostEstimate(b []byte) (*CostEstimate, error) {
+	cost := &CostEstimate{}
+	if err := json.Unmarshal(b, cost); err != nil {
+		return nil, fmt.Errorf("parse a cost estimate as JSON: %w", err)
+	}
+	return cost, nil
+}
+
+// Delta returns the formatted difference of the monthly cost like "+$123.00"
+func (cost *CostEstimate) Delta() string {
+	if cost == nil {
+		return ""
+	}
+	return formatCostDelta(cost.Currency, cost.DiffTotalMonthlyCost)
+}
+
+// Breakdown returns the cost estimates per project
+func (cost *CostEstimate) Breakdown() []CostBreakdownEntry {
+	if cost == nil {
+		return nil
+	}
+	entries := make([]CostBreakdownEntry, len(cost.Projects))
+	for i, project := range cost.Projects {
+		entry := CostBreakdownEntry{
+			Name: project.Name,
+		}
+		if project.Breakdown != nil {
+			entry.MonthlyCost = formatCost(cost.Currency, project.Breakdown.TotalMonthlyCost)
+		}
+		if project.PastBreakdown != nil {
+			entry.PastMonthlyCost = formatCost(cost.Currency, project.PastBreakdown.TotalMonthlyCost)
+		}
+		if project.Diff != nil {
+			entry.Delta = formatCostDelta(cost.Currency, project.Diff.TotalMonthlyCost)
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// Resources returns the cost estimates of resources whose costs are changed.
+// The resources are listed in the diff of each project
+func (cost *CostEstimate) Resources() []CostResourceEntry {
+	if cost == nil {
+		return nil
+	}
+	var entries []CostResourceEntry
+	for _, project := range cost.Projects {
+		if project.Diff == nil {
+			continue
+		}
+		for _, resource := range project.Diff.Resources {
+			entries = append(entries, CostResourceEntry{
+				Project:         project.Name,
+				Name:            resource.Name,
+				MonthlyCost:     formatCost(cost.Currency, project.Breakdown.monthlyCostOf(resource.Name)),
+				PastMonthlyCost: formatCost(cost.Currency, project.PastBreakdown.monthlyCostOf(resource.Name)),
+				Delta:           formatCostDelta(cost.Currency, resource.MonthlyCost),
+			})
+		}
+	}
+	return entries
+}
+
+// monthlyCostOf returns the monthly cost of the resource. If the resource isn't found, "0" is returned
+func (breakdown *CostBreakdown) monthlyCostOf(name string) string {
+	if breakdown == nil {
+		return "0"
+	}
+	for _, resource := range breakdown.Resources {
+		if resource.Name == name {
+			if resource.MonthlyCost == "" {
+				return "0"
+			}
+			return resource.MonthlyCost
+		}
+	}
+	return "0"
+}
+
+func currencySymbol(currency string) string {
+	switch currency {
+	case "", "USD":
+		return "$"
+	case "EUR":
+		return "€"
+	case "GBP":
+		return "£"
+	case "JPY":
+		return "¥"
+	default:
+		return currency + " "
+	}
+}
+
+func formatCost(currency, value string) string {
+	if value == "" {
+		return ""
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return fmt.Sprintf("%s%.2f", currencySymbol(currency), f)
+}
+
+func formatCostDelta(currency, value string) string {
+	if value == "" {
+		return ""
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	sign := "+"
+	if f < 0 {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s%s%.2f", sign, currencySymbol(currency), math.Abs(f))
+}
@@ -0,0 +1,117 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const costEstimate = `{
+  "version": "0.2",
+  "currency": "USD",
+  "projects": [
+    {
+      "name": "foo",
+      "breakdown": {
+        "totalMonthlyCost": "223.4",
+        "resources": [
+          {"name": "aws_instance.web", "monthlyCost": "200"},
+          {"name": "aws_s3_bucket.foo", "monthlyCost": "23.4"},
+          {"name": "aws_iam_role.foo", "monthlyCost": null}
+        ]
+      },
+      "pastBreakdown": {
+        "totalMonthlyCost": "100",
+        "resources": [
+          {"name": "aws_instance.web", "monthlyCost": "100"}
+        ]
+      },
+      "diff": {
+        "totalMonthlyCost": "123.4",
+        "resources": [
+          {"name": "aws_instance.web", "monthlyCost": "100"},
+          {"name": "aws_s3_bucket.foo", "monthlyCost": "23.4"}
+        ]
+      }
+    }
+  ],
+  "totalMonthlyCost": "223.4",
+  "pastTotalMonthlyCost": "100",
+  "diffTotalMonthlyCost": "123.4"
+}`
+
+func TestParseCostEstimate(t *testing.T) {
+	t.Parallel()
+	cost, err := ParseCostEstimate([]byte(costEstimate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta := cost.Delta(); delta != "+$123.40" {
+		t.Errorf("got %q but want %q", delta, "+$123.40")
+	}
+	exp := []CostBreakdownEntry{
+		{
+			Name:            "foo",
+			MonthlyCost:     "$223.40",
+			PastMonthlyCost: "$100.00",
+			Delta:           "+$123.40",
+		},
+	}
+	if diff := cmp.Diff(cost.Breakdown(), exp); diff != "" {
+		t.Error(diff)
+	}
+	expResources := []CostResourceEntry{
+		{
+			Project:         "foo",
+			Name:            "aws_instance.web",
+			MonthlyCost:     "$200.00",
+			PastMonthlyCost: "$100.00",
+			Delta:           "+$100.00",
+		},
+		{
+			Project:         "foo",
+			Name:            "aws_s3_bucket.foo",
+			MonthlyCost:     "$23.40",
+			PastMonthlyCost: "$0.00",
+			Delta:           "+$23.40",
+		},
+	}
+	if diff := cmp.Diff(cost.Resources(), expResources); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFormatCostDelta(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		currency string
+		value    string
+		exp      string
+	}{
+		{
+			currency: "USD",
+			value:    "-10",
+			exp:      "-$10.00",
+		},
+		{
+			currency: "EUR",
+			value:    "0",
+			exp:      "+€0.00",
+		},
+		{
+			currency: "CAD",
+			value:    "1.234",
+			exp:      "+CAD 1.23",
+		},
+		{
+			currency: "USD",
+			value:    "",
+			exp:      "",
+		},
+	}
+	for _, testCase := range testCases {
+		if delta := formatCostDelta(testCase.currency, testCase.value); delta != testCase.exp {
+			t.Errorf("got %q but want %q", delta, testCase.exp)
+		}
+	}
+}
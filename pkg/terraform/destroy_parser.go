@@ -0,0 +1,73 @@
+package terraform
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CommandDestroy is the command terraform destroy. This is set to ParseResult.DetectedCommand by DestroyParser
+const CommandDestroy = "destroy"
+
+// DestroyParser is a parser for the output of terraform destroy.
+// Unlike ApplyParser, the result is "Destroy complete!" and destroyed resources are listed in DeletedResources
+type DestroyParser struct {
+	Pass *regexp.Regexp
+	Fail *regexp.Regexp
+	// Destroyed matches the line like "aws_instance.web: Destruction complete after 1s"
+	Destroyed      *regexp.Regexp
+	FailedResource *regexp.Regexp
+}
+
+// NewDestroyParser is DestroyParser initializer
+func NewDestroyParser() *DestroyParser {
+	return &DestroyParser{
+		Pass:           regexp.MustCompile(`(?m)^(Destroy complete!)`),
+		Fail:           regexp.MustCompile(`(?m)^(?:│ ?)?(Error: )`),
+		Destroyed:      regexp.MustCompile(`(?m)^(.+?): Destruction complete after`),
+		FailedResource: regexp.MustCompile(`(?m)^(?:│)?\s+with (.+),$`),
+	}
+}
+
+// Parse parses the output of terraform destroy.
+// If terraform destroy fails, resources destroyed before the error are listed in AppliedResources as well as ApplyParser
+func (p *DestroyParser) Parse(body string) ParseResult {
+	var exitCode int
+	switch {
+	case p.Pass.MatchString(body):
+		exitCode = ExitPass
+	case p.Fail.MatchString(body):
+		exitCode = ExitFail
+	default:
+		return ParseResult{
+			ExitCode:        ExitFail,
+			HasParseError:   true,
+			Error:           newParseError("destroy", body, `"Destroy complete!" or "Error: "`),
+			DetectedCommand: CommandDestroy,
+		}
+	}
+	destroyed := findAllResources(p.Destroyed, body)
+	if exitCode == ExitFail {
+		// the result starts with "Error: " without the box drawing character
+		loc := p.Fail.FindStringSubmatchIndex(body)
+		return ParseResult{
+			Result:           strings.TrimSpace(body[loc[2]:]),
+			ExitCode:         ExitFail,
+			HasApplyError:    true,
+			AppliedResources: destroyed,
+			FailedResources:  findAllResources(p.FailedResource, body),
+			DetectedCommand:  CommandDestroy,
+			Checks:           parseChecks(body),
+		}
+	}
+	loc := p.Pass.FindStringIndex(body)
+	result := body[loc[0]:]
+	if i := strings.Index(result, "\n"); i != -1 {
+		result = result[:i]
+	}
+	return ParseResult{
+		Result:           result,
+		ExitCode:         ExitPass,
+		DeletedResources: destroyed,
+		DetectedCommand:  CommandDestroy,
+	}
+}
@@ -0,0 +1,98 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDestroyParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		body   string
+		result ParseResult
+	}{
+		{
+			name: "success",
+			body: `aws_instance.web: Destroying... [id=i-0123456789]
+aws_instance.web: Destruction complete after 1s
+aws_s3_bucket.logs: Destroying... [id=logs]
+aws_s3_bucket.logs: Destruction complete after 0s
+
+Destroy complete! Resources: 2 destroyed.
+`,
+			result: ParseResult{
+				Result:           "Destroy complete! Resources: 2 destroyed.",
+				ExitCode:         ExitPass,
+				DeletedResources: []string{"aws_instance.web", "aws_s3_bucket.logs"},
+				DetectedCommand:  CommandDestroy,
+			},
+		},
+		{
+			name: "no objects",
+			body: `No changes. No objects need to be destroyed.
+
+Either you have not created any objects yet or the existing objects were
+already deleted outside of Terraform.
+
+Destroy complete! Resources: 0 destroyed.
+`,
+			result: ParseResult{
+				Result:          "Destroy complete! Resources: 0 destroyed.",
+				ExitCode:        ExitPass,
+				DetectedCommand: CommandDestroy,
+			},
+		},
+		{
+			name: "partially failed",
+			body: `aws_instance.web: Destroying... [id=i-0123456789]
+aws_instance.web: Destruction complete after 1s
+aws_s3_bucket.logs: Destroying... [id=logs]
+╷
+│ Error: deleting S3 Bucket (logs): BucketNotEmpty
+│
+│   with aws_s3_bucket.logs,
+│   on main.tf line 5, in resource "aws_s3_bucket" "logs":
+│    5: resource "aws_s3_bucket" "logs" {
+│
+╵
+`,
+			result: ParseResult{
+				Result: `Error: deleting S3 Bucket (logs): BucketNotEmpty
+│
+│   with aws_s3_bucket.logs,
+│   on main.tf line 5, in resource "aws_s3_bucket" "logs":
+│    5: resource "aws_s3_bucket" "logs" {
+│
+╵`,
+				ExitCode:         ExitFail,
+				HasApplyError:    true,
+				AppliedResources: []string{"aws_instance.web"},
+				FailedResources:  []string{"aws_s3_bucket.logs"},
+				DetectedCommand:  CommandDestroy,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewDestroyParser().Parse(testCase.body)
+			if diff := cmp.Diff(testCase.result, result); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDestroyParserParseError(t *testing.T) {
+	t.Parallel()
+	result := NewDestroyParser().Parse("Apply complete! Resources: 0 added, 0 changed, 0 destroyed.")
+	if !result.HasParseError {
+		t.Fatal("the output without the result of terraform destroy should be a parse error")
+	}
+	if result.ExitCode != ExitFail {
+		t.Errorf("ExitCode: got %d, wanted %d", result.ExitCode, ExitFail)
+	}
+}
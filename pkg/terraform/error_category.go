@@ -0,0 +1,61 @@
+package terraform
+
+import (
+	"regexp"
+)
+
+const (
+	// ErrorCategoryStateLock means the state is locked by another operation. Retrying later may resolve the error
+	ErrorCategoryStateLock = "state_lock"
+	// ErrorCategoryBackend means the backend or the credential is unavailable
+	ErrorCategoryBackend = "backend"
+	// ErrorCategoryProvider means providers can't be installed or configured
+	ErrorCategoryProvider = "provider"
+	// ErrorCategoryConfig means the configuration is invalid
+	ErrorCategoryConfig = "config"
+	// ErrorCategoryUnknown means the error doesn't match any category
+	ErrorCategoryUnknown = "unknown"
+)
+
+// errorCategories is a list of error categories and their patterns.
+// The first matching category is used, so more specific categories come first.
+// "Error refreshing state" isn't a backend error because old terraform outputs it when it fails to refresh resources
+var errorCategories = []struct {
+	category string
+	pattern  *regexp.Regexp
+}{
+	{
+		category: ErrorCategoryStateLock,
+		pattern:  regexp.MustCompile(`Error acquiring the state lock|Error locking state|Error releasing the state lock|state blob is already locked|ConditionalCheckFailedException`),
+	},
+	{
+		category: ErrorCategoryBackend,
+		pattern:  regexp.MustCompile(`Backend initialization required|Error loading state|Failed to load state|Failed to save state|Failed to get existing workspaces|Error configuring the backend|error configuring S3 Backend|No valid credential sources found|NoCredentialProviders|ExpiredToken|could not find default credentials`),
+	},
+	{
+		category: ErrorCategoryProvider,
+		pattern:  regexp.MustCompile(`Failed to query available provider packages|Failed to install provider|Inconsistent dependency lock file|Could not load plugin|Failed to load plugin schemas|Plugin did not respond|Failed to instantiate provider|Missing required provider|Invalid provider configuration`),
+	},
+	{
+		category: ErrorCategoryConfig,
+		pattern:  regexp.MustCompile(`Error: (Unsupported argument|Missing required argument|Unsupported block type|Unsupported attribute|Reference to undeclared|Invalid reference|Invalid expression|Invalid function argument|Call to unknown function|Argument or block definition required|Duplicate resource|No value for required variable|Invalid value for variable|Incorrect attribute value type|Module not installed)`),
+	},
+}
+
+// classifyError returns the category of the error output. If no category matches, an empty string is returned
+func classifyError(text string) string {
+	for _, c := range errorCategories {
+		if c.pattern.MatchString(text) {
+			return c.category
+		}
+	}
+	return ""
+}
+
+// classifyPlanError returns the category of the plan error. If no category matches, ErrorCategoryUnknown is returned
+func classifyPlanError(text string) string {
+	if category := classifyError(text); category != "" {
+		return category
+	}
+	return ErrorCategoryUnknown
+}
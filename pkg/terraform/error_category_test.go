@@ -0,0 +1,130 @@
+package terraform
+
+import (
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		text string
+		exp  string
+	}{
+		{
+			name: "state lock",
+			text: `Error: Error acquiring the state lock
+
+Error message: ConditionalCheckFailedException: The conditional request failed
+Lock Info:
+  ID:        9db590f1-b6fe-c5f2-2678-8804f089deba`,
+			exp: ErrorCategoryStateLock,
+		},
+		{
+			name: "backend",
+			text: `Error: Failed to get existing workspaces: AccessDenied: Access Denied`,
+			exp:  ErrorCategoryBackend,
+		},
+		{
+			name: "provider",
+			text: `╷
+│ Error: Failed to query available provider packages
+│
+│ Could not retrieve the list of available versions for provider hashicorp/aws`,
+			exp: ErrorCategoryProvider,
+		},
+		{
+			name: "config",
+			text: `╷
+│ Error: Unsupported argument
+│
+│   on main.tf line 3, in resource "null_resource" "foo":
+│    3:   foo = "bar"`,
+			exp: ErrorCategoryConfig,
+		},
+		{
+			name: "no category",
+			text: `Error: creating EC2 Instance: InvalidParameterValue`,
+			exp:  "",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if category := classifyError(testCase.text); category != testCase.exp {
+				t.Errorf("got %q but want %q", category, testCase.exp)
+			}
+		})
+	}
+}
+
+func TestPlanParserParseErrorCategory(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		body string
+		exp  string
+	}{
+		{
+			name: "state lock",
+			body: "Acquiring state lock. This may take a few moments...\n\nError: Error acquiring the state lock\n\nLock Info:\n",
+			exp:  ErrorCategoryStateLock,
+		},
+		{
+			name: "unknown",
+			body: "Error: creating EC2 Instance: InvalidParameterValue\n",
+			exp:  ErrorCategoryUnknown,
+		},
+		{
+			name: "no error",
+			body: "Plan: 1 to add, 0 to change, 0 to destroy.\n",
+			exp:  "",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewPlanParser().Parse(testCase.body)
+			if result.ErrorCategory != testCase.exp {
+				t.Errorf("got %q but want %q", result.ErrorCategory, testCase.exp)
+			}
+		})
+	}
+}
+
+func TestApplyParserParseErrorCategory(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		body string
+		exp  string
+	}{
+		{
+			name: "state lock",
+			body: "Acquiring state lock. This may take a few moments...\n\nError: Error acquiring the state lock\n\nLock Info:\n",
+			exp:  ErrorCategoryStateLock,
+		},
+		{
+			name: "no category",
+			body: "Error: creating EC2 Instance: InvalidParameterValue\n",
+			exp:  "",
+		},
+		{
+			name: "no error",
+			body: "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.\n",
+			exp:  "",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewApplyParser().Parse(testCase.body)
+			if result.ErrorCategory != testCase.exp {
+				t.Errorf("got %q but want %q", result.ErrorCategory, testCase.exp)
+			}
+		})
+	}
+}
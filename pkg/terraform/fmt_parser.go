@@ -0,0 +1,160 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CommandFmt is the command terraform fmt. This is set to ParseResult.DetectedCommand by FmtParser
+const CommandFmt = "fmt"
+
+// FmtHunk is a change of terraform fmt -diff. The lines [StartLine, EndLine] of File are replaced with Suggestion.
+// Context lines before the first change and after the last change are excluded
+type FmtHunk struct {
+	File       string
+	StartLine  int
+	EndLine    int
+	Suggestion string
+}
+
+// FmtParser is a parser for the output of terraform fmt -check -diff.
+// terraform fmt outputs the names of unformatted files, and the diff follows each file name if -diff is set
+type FmtParser struct {
+	HunkHeader *regexp.Regexp
+	Fail       *regexp.Regexp
+}
+
+// NewFmtParser is FmtParser initializer
+func NewFmtParser() *FmtParser {
+	return &FmtParser{
+		HunkHeader: regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+\d+(?:,\d+)? @@`),
+		Fail:       regexp.MustCompile(`(?m)^(?:│ ?)?(Error: )`),
+	}
+}
+
+// fmtLine is a line of a hunk. oldLine is the line number of the old file, which is 0 for added lines
+type fmtLine struct {
+	kind    byte
+	text    string
+	oldLine int
+}
+
+// Parse parses the output of terraform fmt -check -diff
+func (p *FmtParser) Parse(body string) ParseResult {
+	if loc := p.Fail.FindStringIndex(body); loc != nil {
+		return ParseResult{
+			Result:          strings.TrimSpace(body[loc[0]:]),
+			ExitCode:        ExitFail,
+			DetectedCommand: CommandFmt,
+		}
+	}
+	var files []string
+	var hunks []FmtHunk
+	var file string
+	var lines []fmtLine
+	inHunk := false
+	oldLine := 0
+	flush := func() {
+		if hunk, ok := newFmtHunk(file, lines); ok {
+			hunks = append(hunks, hunk)
+		}
+		lines = nil
+		inHunk = false
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if arr := p.HunkHeader.FindStringSubmatch(line); arr != nil {
+			flush()
+			oldLine, _ = strconv.Atoi(arr[1])
+			inHunk = true
+			continue
+		}
+		if strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+			continue
+		}
+		if inHunk && line != "" {
+			switch line[0] {
+			case ' ', '-':
+				lines = append(lines, fmtLine{kind: line[0], text: line[1:], oldLine: oldLine})
+				oldLine++
+				continue
+			case '+':
+				lines = append(lines, fmtLine{kind: line[0], text: line[1:]})
+				continue
+			case '\\':
+				// "\ No newline at end of file"
+				continue
+			}
+		}
+		flush()
+		if name := strings.TrimSpace(line); name != "" {
+			file = name
+			files = appendUnique(files, name)
+		}
+	}
+	flush()
+	if len(files) == 0 {
+		return ParseResult{
+			Result:          "All files are formatted.",
+			ExitCode:        ExitPass,
+			DetectedCommand: CommandFmt,
+		}
+	}
+	return ParseResult{
+		Result:           fmt.Sprintf("%d file(s) are not formatted.", len(files)),
+		ExitCode:         ExitFail,
+		DetectedCommand:  CommandFmt,
+		UnformattedFiles: files,
+		FmtHunks:         hunks,
+	}
+}
+
+// newFmtHunk returns the change of the lines of a hunk.
+// If the change only adds lines, the previous context line is included because a suggestion must replace existing lines
+func newFmtHunk(file string, lines []fmtLine) (FmtHunk, bool) {
+	first, last := -1, -1
+	for i, line := range lines {
+		if line.kind != ' ' {
+			if first == -1 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first == -1 {
+		return FmtHunk{}, false
+	}
+	hunk := FmtHunk{File: file}
+	suggestion := []string{}
+	for {
+		suggestion = suggestion[:0]
+		hunk.StartLine, hunk.EndLine = 0, 0
+		for _, line := range lines[first : last+1] {
+			if line.kind != '-' {
+				suggestion = append(suggestion, line.text)
+			}
+			if line.oldLine == 0 {
+				continue
+			}
+			if hunk.StartLine == 0 {
+				hunk.StartLine = line.oldLine
+			}
+			hunk.EndLine = line.oldLine
+		}
+		if hunk.StartLine != 0 {
+			break
+		}
+		switch {
+		case first > 0:
+			first--
+		case last < len(lines)-1:
+			last++
+		default:
+			// the file is empty
+			return FmtHunk{}, false
+		}
+	}
+	hunk.Suggestion = strings.Join(suggestion, "\n")
+	return hunk, true
+}
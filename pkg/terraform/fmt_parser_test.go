@@ -0,0 +1,98 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const fmtDiffResult = `main.tf
+--- old/main.tf
++++ new/main.tf
+@@ -1,5 +1,5 @@
+ resource "null_resource" "foo" {
+-  triggers = {a="b"}
++  triggers = { a = "b" }
+ }
+ 
+ resource "null_resource" "bar" {
+modules/foo/variables.tf
+--- old/modules/foo/variables.tf
++++ new/modules/foo/variables.tf
+@@ -1,3 +1,4 @@
+ variable "foo" {
++  type = string
+ }
+`
+
+func TestFmtParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		body   string
+		result ParseResult
+	}{
+		{
+			name: "diff",
+			body: fmtDiffResult,
+			result: ParseResult{
+				Result:           "2 file(s) are not formatted.",
+				ExitCode:         ExitFail,
+				DetectedCommand:  CommandFmt,
+				UnformattedFiles: []string{"main.tf", "modules/foo/variables.tf"},
+				FmtHunks: []FmtHunk{
+					{
+						File:       "main.tf",
+						StartLine:  2,
+						EndLine:    2,
+						Suggestion: `  triggers = { a = "b" }`,
+					},
+					{
+						File:       "modules/foo/variables.tf",
+						StartLine:  1,
+						EndLine:    1,
+						Suggestion: "variable \"foo\" {\n  type = string",
+					},
+				},
+			},
+		},
+		{
+			name: "without diff",
+			body: "main.tf\nvariables.tf\n",
+			result: ParseResult{
+				Result:           "2 file(s) are not formatted.",
+				ExitCode:         ExitFail,
+				DetectedCommand:  CommandFmt,
+				UnformattedFiles: []string{"main.tf", "variables.tf"},
+			},
+		},
+		{
+			name: "formatted",
+			body: "",
+			result: ParseResult{
+				Result:          "All files are formatted.",
+				ExitCode:        ExitPass,
+				DetectedCommand: CommandFmt,
+			},
+		},
+		{
+			name: "syntax error",
+			body: "╷\n│ Error: Invalid character\n│\n│   on main.tf line 1:\n╵\n",
+			result: ParseResult{
+				Result:          "│ Error: Invalid character\n│\n│   on main.tf line 1:\n╵",
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandFmt,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewFmtParser().Parse(testCase.body)
+			if diff := cmp.Diff(testCase.result, result); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JSONParser is a parser for the machine readable output of terraform plan -json and terraform apply -json.
+// The output is a stream of JSON objects, one event per line.
+// https://developer.hashicorp.com/terraform/internals/machine-readable-ui
+type JSONParser struct {
+	// Command is either CommandPlan or CommandApply. This is set to ParseResult.DetectedCommand
+	Command string
+	// IgnoredResources is a list of glob patterns of resource types or addresses which are excluded from the list of changed resources
+	IgnoredResources []string
+	// KeepResourceOrder keeps the order of resources in the output
+	KeepResourceOrder bool
+}
+
+// NewJSONParser is JSONParser initializer
+func NewJSONParser(command string) *JSONParser {
+	return &JSONParser{
+		Command: command,
+	}
+}
+
+type jsonEvent struct {
+	Message    string                `json:"@message"`
+	Type       string                `json:"type"`
+	Diagnostic *jsonDiagnostic       `json:"diagnostic"`
+	Change     *jsonChange           `json:"change"`
+	Changes    *jsonChangeSummary    `json:"changes"`
+	Hook       *jsonHook             `json:"hook"`
+	Outputs    map[string]jsonOutput `json:"outputs"`
+}
+
+type jsonDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail"`
+	Address  string `json:"address"`
+	// Range is the location of the diagnostic. This is nil if the diagnostic isn't related to a file
+	Range *jsonRange `json:"range"`
+}
+
+type jsonRange struct {
+	Filename string `json:"filename"`
+	Start    struct {
+		Line int `json:"line"`
+	} `json:"start"`
+}
+
+type jsonResource struct {
+	Addr string `json:"addr"`
+}
+
+type jsonChange struct {
+	Resource jsonResource `json:"resource"`
+	Action   string       `json:"action"`
+	// PreviousResource is set if the resource is moved
+	PreviousResource *jsonResource `json:"previous_resource"`
+	// Importing is set if the resource is imported
+	Importing *jsonImporting `json:"importing"`
+}
+
+type jsonImporting struct {
+	ID string `json:"id"`
+}
+
+type jsonChangeSummary struct {
+	Add       int    `json:"add"`
+	Change    int    `json:"change"`
+	Remove    int    `json:"remove"`
+	Operation string `json:"operation"`
+	Import    int    `json:"import"`
+}
+
+type jsonHook struct {
+	Resource jsonResource `json:"resource"`
+	Action   string       `json:"action"`
+}
+
+type jsonOutput struct {
+	Sensitive bool            `json:"sensitive"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// jsonStream is the aggregation of events
+type jsonStream struct {
+	events         int
+	summary        *jsonChangeSummary
+	summaryMessage string
+	errors         []string
+	warnings       []string
+	changes        []string
+	created        []string
+	updated        []string
+	deleted        []string
+	replaced       []string
+	drifted        []string
+	moved          []MovedResource
+	imported       []string
+	applied        []string
+	failed         []string
+	outputs        map[string]string
+}
+
+func (s *jsonStream) add(event *jsonEvent) {
+	s.events++
+	switch event.Type {
+	case "diagnostic":
+		if event.Diagnostic == nil {
+			return
+		}
+		msg := event.Diagnostic.Summary
+		if event.Diagnostic.Detail != "" {
+			msg += "\n\n" + event.Diagnostic.Detail
+		}
+		if event.Diagnostic.Severity == "error" {
+			s.errors = append(s.errors, "Error: "+msg)
+			if event.Diagnostic.Address != "" {
+				s.failed = appendUnique(s.failed, event.Diagnostic.Address)
+			}
+			return
+		}
+		s.warnings = append(s.warnings, "Warning: "+msg)
+	case "planned_change":
+		if event.Change == nil {
+			return
+		}
+		addr := event.Change.Resource.Addr
+		if prev := event.Change.PreviousResource; prev != nil && prev.Addr != "" {
+			s.moved = append(s.moved, MovedResource{Before: prev.Addr, After: addr})
+		}
+		if event.Change.Importing != nil || event.Change.Action == "import" {
+			s.imported = append(s.imported, addr)
+		}
+		switch event.Change.Action {
+		case "create":
+			s.created = append(s.created, addr)
+		case "update":
+			s.updated = append(s.updated, addr)
+		case "delete":
+			s.deleted = append(s.deleted, addr)
+		case "replace":
+			s.replaced = append(s.replaced, addr)
+		default:
+			// read, noop, and move don't change resources
+			return
+		}
+		s.changes = append(s.changes, event.Message)
+	case "resource_drift":
+		if event.Change != nil {
+			s.drifted = append(s.drifted, event.Change.Resource.Addr)
+		}
+	case "change_summary":
+		s.summary = event.Changes
+		s.summaryMessage = event.Message
+	case "apply_complete":
+		if event.Hook != nil {
+			s.applied = appendUnique(s.applied, event.Hook.Resource.Addr)
+		}
+	case "apply_errored":
+		if event.Hook != nil {
+			s.failed = appendUnique(s.failed, event.Hook.Resource.Addr)
+		}
+	case "outputs":
+		s.outputs = make(map[string]string, len(event.Outputs))
+		for name, output := range event.Outputs {
+			if output.Sensitive {
+				s.outputs[name] = sensitiveOutput
+				continue
+			}
+			s.outputs[name] = string(output.Value)
+		}
+	}
+}
+
+func appendUnique(arr []string, s string) []string {
+	for _, a := range arr {
+		if a == s {
+			return arr
+		}
+	}
+	return append(arr, s)
+}
+
+// jsonPlan is the JSON representation of a plan which is output by terraform show -json.
+// https://developer.hashicorp.com/terraform/internals/json-format
+type jsonPlan struct {
+	FormatVersion   string               `json:"format_version"`
+	Errored         bool                 `json:"errored"`
+	ResourceChanges []jsonResourceChange `json:"resource_changes"`
+	ResourceDrift   []jsonResourceChange `json:"resource_drift"`
+}
+
+type jsonResourceChange struct {
+	Address string `json:"address"`
+	Change  struct {
+		Actions   []string       `json:"actions"`
+		Importing *jsonImporting `json:"importing"`
+	} `json:"change"`
+	// PreviousAddress is set if the resource is moved
+	PreviousAddress string `json:"previous_address"`
+}
+
+// action converts the actions of the plan representation to the action of planned_change events
+func (c *jsonResourceChange) action() string {
+	switch strings.Join(c.Change.Actions, ",") {
+	case "create":
+		return "create"
+	case "update":
+		return "update"
+	case "delete":
+		return "delete"
+	case "delete,create", "create,delete":
+		return "replace"
+	default:
+		return ""
+	}
+}
+
+// parseJSONPlan parses the output of terraform show -json.
+// If the body isn't a JSON representation of a plan, false is returned
+func parseJSONPlan(body string) (*jsonPlan, bool) {
+	body = strings.TrimSpace(body)
+	if !strings.HasPrefix(body, "{") {
+		return nil, false
+	}
+	plan := &jsonPlan{}
+	// A stream of events can't be unmarshalled as a single object
+	if err := json.Unmarshal([]byte(body), plan); err != nil || plan.FormatVersion == "" {
+		return nil, false
+	}
+	return plan, true
+}
+
+// addPlan converts the plan representation to events.
+// The summary is computed in the same way as terraform, so a replaced resource is counted as both add and remove
+func (s *jsonStream) addPlan(plan *jsonPlan) {
+	s.events++
+	if plan.Errored {
+		s.errors = append(s.errors, "Error: the plan is errored")
+		return
+	}
+	summary := &jsonChangeSummary{Operation: "plan"}
+	for _, rc := range plan.ResourceChanges {
+		if rc.PreviousAddress != "" {
+			s.moved = append(s.moved, MovedResource{Before: rc.PreviousAddress, After: rc.Address})
+		}
+		if rc.Change.Importing != nil {
+			s.imported = append(s.imported, rc.Address)
+			summary.Import++
+		}
+		action := rc.action()
+		switch action {
+		case "create":
+			s.created = append(s.created, rc.Address)
+			summary.Add++
+		case "update":
+			s.updated = append(s.updated, rc.Address)
+			summary.Change++
+		case "delete":
+			s.deleted = append(s.deleted, rc.Address)
+			summary.Remove++
+		case "replace":
+			s.replaced = append(s.replaced, rc.Address)
+			summary.Add++
+			summary.Remove++
+		default:
+			continue
+		}
+		s.changes = append(s.changes, rc.Address+": Plan to "+action)
+	}
+	for _, rc := range plan.ResourceDrift {
+		if rc.action() != "" {
+			s.drifted = append(s.drifted, rc.Address)
+		}
+	}
+	s.summary = summary
+	switch {
+	case summary.Import != 0:
+		s.summaryMessage = fmt.Sprintf("Plan: %d to import, %d to add, %d to change, %d to destroy.", summary.Import, summary.Add, summary.Change, summary.Remove)
+	case summary.Add+summary.Change+summary.Remove == 0:
+		s.summaryMessage = "No changes. Your infrastructure matches the configuration."
+	default:
+		s.summaryMessage = fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", summary.Add, summary.Change, summary.Remove)
+	}
+}
+
+// Parse aggregates the stream of events into ParseResult.
+// The JSON representation of a plan which is output by terraform show -json is also supported.
+// Lines which aren't JSON objects are ignored
+func (p *JSONParser) Parse(body string) ParseResult {
+	stream := &jsonStream{}
+	if plan, ok := parseJSONPlan(body); ok && p.Command != CommandApply {
+		stream.addPlan(plan)
+		return p.parsePlan(stream)
+	}
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		event := &jsonEvent{}
+		if err := json.Unmarshal([]byte(line), event); err != nil {
+			continue
+		}
+		stream.add(event)
+	}
+	if stream.events == 0 {
+		return ParseResult{
+			HasParseError:   true,
+			ExitCode:        ExitFail,
+			Error:           errors.New("cannot parse the result: no JSON event is found. Please run terraform with -json"),
+			DetectedCommand: p.Command,
+		}
+	}
+	if p.Command == CommandApply {
+		return p.parseApply(stream)
+	}
+	return p.parsePlan(stream)
+}
+
+func (p *JSONParser) parsePlan(stream *jsonStream) ParseResult {
+	ret := ParseResult{
+		ChangedResult:     strings.Join(stream.changes, "\n"),
+		Warning:           strings.Join(stream.warnings, "\n\n"),
+		CreatedResources:  stream.created,
+		UpdatedResources:  stream.updated,
+		DeletedResources:  stream.deleted,
+		ReplacedResources: stream.replaced,
+		DriftedResources:  stream.drifted,
+		MovedResources:    stream.moved,
+		ImportedResources: stream.imported,
+		DetectedCommand:   p.Command,
+	}
+	switch {
+	case len(stream.errors) != 0:
+		ret.Result = strings.Join(stream.errors, "\n\n")
+		ret.HasPlanError = true
+		ret.ExitCode = ExitFail
+		ret.ErrorCategory = classifyPlanError(ret.Result)
+	case stream.summary != nil:
+		ret.Result = stream.summaryMessage
+		ret.ExitCode = ExitPass
+		ret.HasDestroy = stream.summary.Remove > 0
+		hasChanges := stream.summary.Add+stream.summary.Change+stream.summary.Remove != 0
+		ret.HasImportOnly = !hasChanges && stream.summary.Import != 0
+		ret.HasNoChanges = !hasChanges && !ret.HasImportOnly
+		ret.HasAddOrUpdateOnly = hasChanges && !ret.HasDestroy
+	default:
+		return ParseResult{
+			HasParseError:   true,
+			ExitCode:        ExitFail,
+			Error:           errors.New("cannot parse plan result: neither change_summary nor error diagnostic is found"),
+			DetectedCommand: p.Command,
+		}
+	}
+	(&PlanParser{IgnoredResources: p.IgnoredResources}).filterIgnoredResources(&ret)
+	if !p.KeepResourceOrder {
+		sortResources(&ret)
+	}
+	ret.ModuleChanges = groupByModule(ret)
+	return ret
+}
+
+func (p *JSONParser) parseApply(stream *jsonStream) ParseResult {
+	ret := ParseResult{
+		Warning:         strings.Join(stream.warnings, "\n\n"),
+		Outputs:         stream.outputs,
+		DetectedCommand: p.Command,
+	}
+	switch {
+	case len(stream.errors) != 0:
+		ret.Result = strings.Join(stream.errors, "\n\n")
+		ret.ExitCode = ExitFail
+		ret.HasApplyError = true
+		ret.AppliedResources = stream.applied
+		ret.FailedResources = stream.failed
+	case stream.summary != nil:
+		ret.Result = stream.summaryMessage
+		ret.ExitCode = ExitPass
+	default:
+		return ParseResult{
+			HasParseError:   true,
+			ExitCode:        ExitFail,
+			Error:           errors.New("cannot parse apply result: neither change_summary nor error diagnostic is found"),
+			DetectedCommand: p.Command,
+		}
+	}
+	return ret
+}
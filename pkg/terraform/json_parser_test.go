@@ -0,0 +1,206 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const jsonPlanSuccessResult = `{"@level":"info","@message":"Terraform 1.2.0","@module":"terraform.ui","type":"version","terraform":"1.2.0","ui":"1.0"}
+{"@level":"info","@message":"null_resource.drifted: Drift detected (update)","@module":"terraform.ui","type":"resource_drift","change":{"resource":{"addr":"null_resource.drifted","resource_type":"null_resource"},"action":"update"}}
+{"@level":"info","@message":"null_resource.foo: Plan to create","@module":"terraform.ui","type":"planned_change","change":{"resource":{"addr":"null_resource.foo","resource_type":"null_resource"},"action":"create"}}
+{"@level":"info","@message":"module.bar.null_resource.bar: Plan to replace","@module":"terraform.ui","type":"planned_change","change":{"resource":{"addr":"module.bar.null_resource.bar","module":"module.bar","resource_type":"null_resource"},"action":"replace"}}
+{"@level":"info","@message":"data.null_data_source.baz: Plan to read","@module":"terraform.ui","type":"planned_change","change":{"resource":{"addr":"data.null_data_source.baz","resource_type":"null_data_source"},"action":"read"}}
+{"@level":"warn","@message":"Warning: Argument is deprecated","@module":"terraform.ui","type":"diagnostic","diagnostic":{"severity":"warning","summary":"Argument is deprecated","detail":"Use foo instead"}}
+{"@level":"info","@message":"Plan: 2 to add, 0 to change, 1 to destroy.","@module":"terraform.ui","type":"change_summary","changes":{"add":2,"change":0,"remove":1,"operation":"plan"}}
+`
+
+const jsonApplyFailureResult = `{"@level":"info","@message":"Terraform 1.2.0","@module":"terraform.ui","type":"version","terraform":"1.2.0","ui":"1.0"}
+{"@level":"info","@message":"null_resource.foo: Creating...","@module":"terraform.ui","type":"apply_start","hook":{"resource":{"addr":"null_resource.foo"},"action":"create"}}
+{"@level":"info","@message":"null_resource.foo: Creation complete after 0s [id=1]","@module":"terraform.ui","type":"apply_complete","hook":{"resource":{"addr":"null_resource.foo"},"action":"create","id_key":"id","id_value":"1","elapsed_seconds":0}}
+{"@level":"info","@message":"null_resource.bar: Creating...","@module":"terraform.ui","type":"apply_start","hook":{"resource":{"addr":"null_resource.bar"},"action":"create"}}
+{"@level":"error","@message":"null_resource.bar: Creation errored after 0s","@module":"terraform.ui","type":"apply_errored","hook":{"resource":{"addr":"null_resource.bar"},"action":"create","elapsed_seconds":0}}
+{"@level":"error","@message":"Error: local-exec provisioner error","@module":"terraform.ui","type":"diagnostic","diagnostic":{"severity":"error","summary":"local-exec provisioner error","detail":"exit status 1","address":"null_resource.bar"}}
+`
+
+func TestJSONParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		command string
+		body    string
+		ignored []string
+		result  ParseResult
+	}{
+		{
+			name:    "plan",
+			command: CommandPlan,
+			body:    jsonPlanSuccessResult,
+			result: ParseResult{
+				Result:            "Plan: 2 to add, 0 to change, 1 to destroy.",
+				ChangedResult:     "null_resource.foo: Plan to create\nmodule.bar.null_resource.bar: Plan to replace",
+				Warning:           "Warning: Argument is deprecated\n\nUse foo instead",
+				HasDestroy:        true,
+				ExitCode:          ExitPass,
+				CreatedResources:  []string{"null_resource.foo"},
+				ReplacedResources: []string{"module.bar.null_resource.bar"},
+				DriftedResources:  []string{"null_resource.drifted"},
+				ModuleChanges: []ModuleChanges{
+					{
+						Module:           RootModule,
+						CreatedResources: []string{"null_resource.foo"},
+					},
+					{
+						Module:            "module.bar",
+						ReplacedResources: []string{"module.bar.null_resource.bar"},
+					},
+				},
+				DetectedCommand: CommandPlan,
+			},
+		},
+		{
+			name:    "ignored resources",
+			command: CommandPlan,
+			body:    jsonPlanSuccessResult,
+			ignored: []string{"null_resource"},
+			result: ParseResult{
+				Result:            "Plan: 2 to add, 0 to change, 1 to destroy.",
+				ChangedResult:     "null_resource.foo: Plan to create\nmodule.bar.null_resource.bar: Plan to replace",
+				Warning:           "Warning: Argument is deprecated\n\nUse foo instead",
+				HasNoChanges:      true,
+				ExitCode:          ExitPass,
+				CreatedResources:  []string{},
+				ReplacedResources: []string{},
+				DriftedResources:  []string{"null_resource.drifted"},
+				DetectedCommand:   CommandPlan,
+			},
+		},
+		{
+			name:    "plan error",
+			command: CommandPlan,
+			body: `{"@level":"info","@message":"Terraform 1.2.0","@module":"terraform.ui","type":"version","terraform":"1.2.0","ui":"1.0"}
+{"@level":"error","@message":"Error: Error acquiring the state lock","@module":"terraform.ui","type":"diagnostic","diagnostic":{"severity":"error","summary":"Error acquiring the state lock","detail":"ConditionalCheckFailedException"}}`,
+			result: ParseResult{
+				Result:          "Error: Error acquiring the state lock\n\nConditionalCheckFailedException",
+				HasPlanError:    true,
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandPlan,
+				ErrorCategory:   ErrorCategoryStateLock,
+			},
+		},
+		{
+			name:    "terraform show -json",
+			command: CommandPlan,
+			body: `{"format_version":"1.1","terraform_version":"1.2.0","resource_drift":[{"address":"null_resource.drifted","change":{"actions":["update"]}}],"resource_changes":[{"address":"null_resource.foo","change":{"actions":["create"]}},{"address":"module.bar.null_resource.bar","change":{"actions":["delete","create"]}},{"address":"null_resource.baz","change":{"actions":["no-op"]}},{"address":"data.null_data_source.baz","change":{"actions":["read"]}}]}
+`,
+			result: ParseResult{
+				Result:            "Plan: 2 to add, 0 to change, 1 to destroy.",
+				ChangedResult:     "null_resource.foo: Plan to create\nmodule.bar.null_resource.bar: Plan to replace",
+				HasDestroy:        true,
+				ExitCode:          ExitPass,
+				CreatedResources:  []string{"null_resource.foo"},
+				ReplacedResources: []string{"module.bar.null_resource.bar"},
+				DriftedResources:  []string{"null_resource.drifted"},
+				ModuleChanges: []ModuleChanges{
+					{
+						Module:           RootModule,
+						CreatedResources: []string{"null_resource.foo"},
+					},
+					{
+						Module:            "module.bar",
+						ReplacedResources: []string{"module.bar.null_resource.bar"},
+					},
+				},
+				DetectedCommand: CommandPlan,
+			},
+		},
+		{
+			name:    "terraform show -json without changes",
+			command: CommandPlan,
+			body:    `{"format_version":"1.1","terraform_version":"1.2.0","resource_changes":[{"address":"null_resource.foo","change":{"actions":["no-op"]}}]}`,
+			result: ParseResult{
+				Result:          "No changes. Your infrastructure matches the configuration.",
+				HasNoChanges:    true,
+				ExitCode:        ExitPass,
+				DetectedCommand: CommandPlan,
+			},
+		},
+		{
+			name:    "terraform show -json of the errored plan",
+			command: CommandPlan,
+			body:    `{"format_version":"1.2","terraform_version":"1.4.0","errored":true}`,
+			result: ParseResult{
+				Result:          "Error: the plan is errored",
+				HasPlanError:    true,
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandPlan,
+				ErrorCategory:   ErrorCategoryUnknown,
+			},
+		},
+		{
+			name:    "plan isn't json",
+			command: CommandPlan,
+			body:    "Plan: 1 to add, 0 to change, 0 to destroy.",
+			result: ParseResult{
+				HasParseError:   true,
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandPlan,
+			},
+		},
+		{
+			name:    "apply",
+			command: CommandApply,
+			body: `{"@level":"info","@message":"null_resource.foo: Creation complete after 0s [id=1]","@module":"terraform.ui","type":"apply_complete","hook":{"resource":{"addr":"null_resource.foo"},"action":"create"}}
+{"@level":"info","@message":"Apply complete! Resources: 1 added, 0 changed, 0 destroyed.","@module":"terraform.ui","type":"change_summary","changes":{"add":1,"change":0,"remove":0,"operation":"apply"}}
+{"@level":"info","@message":"Outputs: 2","@module":"terraform.ui","type":"outputs","outputs":{"id":{"sensitive":false,"type":"string","value":"1"},"password":{"sensitive":true,"type":"string"}}}`,
+			result: ParseResult{
+				Result:   "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+				ExitCode: ExitPass,
+				Outputs: map[string]string{
+					"id":       `"1"`,
+					"password": "(sensitive)",
+				},
+				DetectedCommand: CommandApply,
+			},
+		},
+		{
+			name:    "apply failed partially",
+			command: CommandApply,
+			body:    jsonApplyFailureResult,
+			result: ParseResult{
+				Result:           "Error: local-exec provisioner error\n\nexit status 1",
+				ExitCode:         ExitFail,
+				HasApplyError:    true,
+				AppliedResources: []string{"null_resource.foo"},
+				FailedResources:  []string{"null_resource.bar"},
+				DetectedCommand:  CommandApply,
+			},
+		},
+		{
+			name:    "apply without summary",
+			command: CommandApply,
+			body:    `{"@level":"info","@message":"Terraform 1.2.0","@module":"terraform.ui","type":"version","terraform":"1.2.0","ui":"1.0"}`,
+			result: ParseResult{
+				HasParseError:   true,
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandApply,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			parser := NewJSONParser(testCase.command)
+			parser.IgnoredResources = testCase.ignored
+			result := parser.Parse(testCase.body)
+			if diff := cmp.Diff(testCase.result, result, cmpopts.IgnoreFields(ParseResult{}, "Error")); diff != "" {
+				t.Error(diff)
+			}
+			if result.HasParseError && result.Error == nil {
+				t.Error("the parse error should be returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LintResult is the result of tflint.
+// The format is compatible with the output of `tflint --format json`.
+type LintResult struct {
+	Issues []LintJSONIssue `json:"issues"`
+	Errors []LintJSONError `json:"errors"`
+}
+
+// LintJSONIssue is an issue of tflint
+type LintJSONIssue struct {
+	Rule    LintJSONRule  `json:"rule"`
+	Message string        `json:"message"`
+	Range   LintJSONRange `json:"range"`
+}
+
+// LintJSONRule is a rule of tflint
+type LintJSONRule struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Link     string `json:"link"`
+}
+
+// LintJSONRange is the location of an issue
+type LintJSONRange struct {
+	Filename string `json:"filename"`
+	Start    struct {
+		Line int `json:"line"`
+	} `json:"start"`
+}
+
+// LintJSONError is an error which tflint fails to lint
+type LintJSONError struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+// LintIssue is passed to templates as an element of LintIssues
+type LintIssue struct {
+	Rule string
+	// Severity is "error", "warning", or "info"
+	Severity string
+	Message  string
+	Filename string
+	Line     int
+	// Link is the URL of the document of the rule. This may be empty
+	Link string
+}
+
+// ParseLintResult parses the result of tflint
+func ParseLintResult(b []byte) (*LintResult, error) {
+	result := &LintResult{}
+	if err := json.Unmarshal(b, result); err != nil {
+		return nil, fmt.Errorf("parse a tflint result as JSON: %w", err)
+	}
+	return result, nil
+}
+
+// Entries returns issues and errors of tflint. Errors are treated as issues whose severity is "error"
+func (result *LintResult) Entries() []LintIssue {
+	if result == nil {
+		return nil
+	}
+	issues := make([]LintIssue, 0, len(result.Issues)+len(result.Errors))
+	for _, issue := range result.Issues {
+		issues = append(issues, LintIssue{
+			Rule:     issue.Rule.Name,
+			Severity: issue.Rule.Severity,
+			Message:  issue.Message,
+			Filename: issue.Range.Filename,
+			Line:     issue.Range.Start.Line,
+			Link:     issue.Rule.Link,
+		})
+	}
+	for _, e := range result.Errors {
+		issues = append(issues, LintIssue{
+			Severity: "error",
+			Message:  e.Message,
+		})
+	}
+	return issues
+}
+
+// Summary returns the numbers of issues per severity like "1 error(s), 2 warning(s), 0 notice(s)".
+// If the result isn't given, an empty string is returned
+func (result *LintResult) Summary() string {
+	if result == nil {
+		return ""
+	}
+	var errs, warnings, notices int
+	for _, issue := range result.Entries() {
+		switch issue.Severity {
+		case "error":
+			errs++
+		case "warning":
+			warnings++
+		default:
+			notices++
+		}
+	}
+	if errs+warnings+notices == 0 {
+		return "No issues are found"
+	}
+	return fmt.Sprintf("%d error(s), %d warning(s), %d notice(s)", errs, warnings, notices)
+}
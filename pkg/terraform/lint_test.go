@@ -0,0 +1,103 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const lintResult = `{
+  "issues": [
+    {
+      "rule": {
+        "name": "terraform_unused_declarations",
+        "severity": "warning",
+        "link": "https://example.com/terraform_unused_declarations.md"
+      },
+      "message": "variable \"foo\" is declared but not used",
+      "range": {
+        "filename": "variables.tf",
+        "start": {"line": 1, "column": 1},
+        "end": {"line": 1, "column": 15}
+      },
+      "callers": []
+    }
+  ],
+  "errors": [
+    {
+      "message": "Failed to load configurations",
+      "severity": "error"
+    }
+  ]
+}`
+
+func TestParseLintResult(t *testing.T) {
+	t.Parallel()
+	result, err := ParseLintResult([]byte(lintResult))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary := result.Summary(); summary != "1 error(s), 1 warning(s), 0 notice(s)" {
+		t.Errorf("got %q but want %q", summary, "1 error(s), 1 warning(s), 0 notice(s)")
+	}
+	exp := []LintIssue{
+		{
+			Rule:     "terraform_unused_declarations",
+			Severity: "warning",
+			Message:  `variable "foo" is declared but not used`,
+			Filename: "variables.tf",
+			Line:     1,
+			Link:     "https://example.com/terraform_unused_declarations.md",
+		},
+		{
+			Severity: "error",
+			Message:  "Failed to load configurations",
+		},
+	}
+	if diff := cmp.Diff(result.Entries(), exp); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLintResultSummary(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		result *LintResult
+		exp    string
+	}{
+		{
+			name: "not given",
+		},
+		{
+			name:   "no issue",
+			result: &LintResult{},
+			exp:    "No issues are found",
+		},
+		{
+			name: "notice",
+			result: &LintResult{
+				Issues: []LintJSONIssue{
+					{Rule: LintJSONRule{Name: "foo", Severity: "info"}},
+				},
+			},
+			exp: "0 error(s), 0 warning(s), 1 notice(s)",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if summary := testCase.result.Summary(); summary != testCase.exp {
+				t.Errorf("got %q but want %q", summary, testCase.exp)
+			}
+		})
+	}
+}
+
+func TestParseLintResultInvalid(t *testing.T) {
+	t.Parallel()
+	if _, err := ParseLintResult([]byte("Failed to load configurations")); err == nil {
+		t.Error("an error should be returned")
+	}
+}
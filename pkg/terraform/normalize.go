@@ -0,0 +1,17 @@
+package terraform
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ansiEscapePattern matches ANSI escape sequences such as colors of terraform without -no-color
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// NormalizeOutput strips ANSI escape sequences and converts CRLF and CR line endings to LF.
+// Otherwise the output of terraform without -no-color or captured on Windows can't be parsed and escape sequences leak into comments
+func NormalizeOutput(output string) string {
+	output = ansiEscapePattern.ReplaceAllString(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	return strings.ReplaceAll(output, "\r", "\n")
+}
@@ -0,0 +1,39 @@
+package terraform
+
+import (
+	"testing"
+)
+
+func TestNormalizeOutput(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		output string
+		exp    string
+	}{
+		{
+			name:   "no change",
+			output: "Plan: 1 to add, 0 to change, 0 to destroy.\n",
+			exp:    "Plan: 1 to add, 0 to change, 0 to destroy.\n",
+		},
+		{
+			name:   "colors",
+			output: "\x1b[0m\x1b[1mPlan:\x1b[0m 1 to add, 0 to change, 0 to destroy.\x1b[0m",
+			exp:    "Plan: 1 to add, 0 to change, 0 to destroy.",
+		},
+		{
+			name:   "crlf",
+			output: "No changes.\r\nfoo\r",
+			exp:    "No changes.\nfoo\n",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if s := NormalizeOutput(testCase.output); s != testCase.exp {
+				t.Errorf("got %q but want %q", s, testCase.exp)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package terraform
 
 import (
 	"errors"
+	"fmt"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -28,8 +31,100 @@ type ParseResult struct {
 	UpdatedResources   []string
 	DeletedResources   []string
 	ReplacedResources  []string
+	ModuleChanges      []ModuleChanges
+	// DriftedResources is a list of resources which have changed outside of Terraform
+	DriftedResources []string
+	// HasApplyError is true if terraform apply failed.
+	// If AppliedResources isn't empty, the apply failed partially
+	HasApplyError    bool
+	AppliedResources []string
+	FailedResources  []string
+	// DetectedCommand is the command detected by AutoParser, either CommandPlan or CommandApply.
+	// This is empty if the other parsers are used or the command can't be detected
+	DetectedCommand string
+	// Outputs is a map of the output names and values shown after terraform apply.
+	// The values of sensitive outputs are "(sensitive)"
+	Outputs map[string]string
+	// ErrorCategory is the category of the error such as ErrorCategoryStateLock.
+	// This is set only if the plan or apply fails or the output can't be parsed.
+	// If the plan fails and no category matches, this is ErrorCategoryUnknown. Otherwise, if no category matches, this is empty
+	ErrorCategory string
+	// TerragruntModules is the result of each module. This is set only by TerragruntParser
+	TerragruntModules []TerragruntModule
+	// MovedResources is a list of resources whose addresses are changed by moved blocks.
+	// A resource which is moved and changed is also included in the list of the change
+	MovedResources []MovedResource
+	// ImportedResources is a list of resources imported by import blocks.
+	// A resource which is imported and changed is also included in the list of the change
+	ImportedResources []string
+	// HasImportOnly is true if the plan only imports resources. HasAddOrUpdateOnly is false in that case
+	HasImportOnly bool
+	// IsRefreshOnly is true if the plan is of terraform plan -refresh-only. The drift is in DriftedResources and OutsideTerraform
+	IsRefreshOnly bool
+	// Checks is a list of failed assertions of check blocks and failed preconditions and postconditions
+	Checks []Check
+	// TestPassed, TestFailed, and TestSkipped are the numbers of run blocks of terraform test. They are set only by TestParser.
+	// FailedTests is a list of failed run blocks like "tests/main.tftest.hcl: setup"
+	TestPassed  int
+	TestFailed  int
+	TestSkipped int
+	FailedTests []string
+	// Diagnostics is a list of errors and warnings of terraform validate. This is set only by ValidateParser
+	Diagnostics []Diagnostic
+	// UnformattedFiles is a list of files which aren't formatted and FmtHunks is the list of changes of terraform fmt -diff.
+	// They are set only by FmtParser
+	UnformattedFiles []string
+	FmtHunks         []FmtHunk
 }
 
+// HasDriftDetected returns true if the refresh-only plan detects changes outside of Terraform
+func (r *ParseResult) HasDriftDetected() bool {
+	return r.IsRefreshOnly && len(r.DriftedResources) != 0
+}
+
+// MovedResource is a resource whose address is changed by a moved block
+type MovedResource struct {
+	Before string
+	After  string
+}
+
+// HasChanges returns true if the plan would change any resources
+func (r *ParseResult) HasChanges() bool {
+	return r.HasAddOrUpdateOnly || r.HasDestroy || r.HasImportOnly || r.HasDriftDetected()
+}
+
+// Succeeded returns true if the command succeeded.
+// terraform plan -detailed-exitcode exits with 2 if the plan succeeded with changes,
+// so the exit code 2 is treated as success unless the output can't be parsed.
+// It doesn't depend on HasChanges because changes may be excluded by ignored_resources
+// and moving resources is a change for terraform even if it is treated as no changes by tfcmt
+func (r *ParseResult) Succeeded() bool {
+	if r.HasPlanError {
+		return false
+	}
+	switch r.ExitCode {
+	case ExitPass:
+		return true
+	case ExitChanges:
+		return !r.HasParseError
+	default:
+		return false
+	}
+}
+
+// ModuleChanges is a group of changed resources in the same module
+type ModuleChanges struct {
+	// Module is the module path like "module.network". The module path of root resources is "root"
+	Module            string
+	CreatedResources  []string
+	UpdatedResources  []string
+	DeletedResources  []string
+	ReplacedResources []string
+}
+
+// RootModule is the module path of resources in the root module
+const RootModule = "root"
+
 // DefaultParser is a parser for terraform commands
 type DefaultParser struct{}
 
@@ -43,14 +138,69 @@ type PlanParser struct {
 	Update       *regexp.Regexp
 	Delete       *regexp.Regexp
 	Replace      *regexp.Regexp
+	Drift        *regexp.Regexp
+	// Move matches resources moved without changes and MovedFrom matches the annotation of resources moved with changes
+	Move      *regexp.Regexp
+	MovedFrom *regexp.Regexp
+	// NoChangesExceptMoves matches the summary of the plan which only moves resources
+	NoChangesExceptMoves *regexp.Regexp
+	// Import matches resources imported without changes and ImportedFrom matches the annotation of resources imported with changes
+	Import       *regexp.Regexp
+	ImportedFrom *regexp.Regexp
+	// ImportOnly matches the summary of the plan which only imports resources
+	ImportOnly *regexp.Regexp
+	// RefreshOnly matches the output of terraform plan -refresh-only, which has no line "Plan: " even if the drift is detected
+	RefreshOnly *regexp.Regexp
+	// IgnoredResources is a list of glob patterns of resource types or addresses which are excluded from the list of changed resources
+	IgnoredResources []string
+	// KeepResourceOrder keeps the order of resources in the output.
+	// By default, the lists of resources are sorted by the address because terraform doesn't guarantee the order
+	KeepResourceOrder bool
 }
 
 // ApplyParser is a parser for terraform apply
 type ApplyParser struct {
-	Pass *regexp.Regexp
-	Fail *regexp.Regexp
+	Pass           *regexp.Regexp
+	Fail           *regexp.Regexp
+	Applied        *regexp.Regexp
+	FailedResource *regexp.Regexp
+	Output         *regexp.Regexp
+}
+
+// AutoParser detects whether the output is of terraform plan or terraform apply and delegates to PlanParser or ApplyParser
+type AutoParser struct {
+	Plan  *PlanParser
+	Apply *ApplyParser
+	// ApplyMarker and PlanMarker are patterns to detect the command.
+	// ApplyMarker is checked first because the output of terraform apply without -auto-approve includes the plan
+	ApplyMarker *regexp.Regexp
+	PlanMarker  *regexp.Regexp
+}
+
+const (
+	// ProductTerraform and ProductOpenTofu are the names of the products which output the result
+	ProductTerraform = "Terraform"
+	ProductOpenTofu  = "OpenTofu"
+)
+
+// openTofuMarker matches the lines which only OpenTofu outputs
+var openTofuMarker = regexp.MustCompile(`(?m)^(OpenTofu will perform the following actions:|Note: Objects have changed outside of OpenTofu|OpenTofu v\d|OpenTofu has been successfully initialized!|OpenTofu used the selected providers)`)
+
+// DetectProduct returns ProductOpenTofu if the output is of OpenTofu, otherwise ProductTerraform
+func DetectProduct(output string) string {
+	if openTofuMarker.MatchString(output) {
+		return ProductOpenTofu
+	}
+	return ProductTerraform
 }
 
+const (
+	// CommandPlan is the value of ParseResult.DetectedCommand for terraform plan
+	CommandPlan = "plan"
+	// CommandApply is the value of ParseResult.DetectedCommand for terraform apply
+	CommandApply = "apply"
+)
+
 // NewDefaultParser is DefaultParser initializer
 func NewDefaultParser() *DefaultParser {
 	return &DefaultParser{}
@@ -68,15 +218,85 @@ func NewPlanParser() *PlanParser {
 		Update:       regexp.MustCompile(`^ *# (.*) will be updated in-place$`),
 		Delete:       regexp.MustCompile(`^ *# (.*) will be destroyed$`),
 		Replace:      regexp.MustCompile(`^ *# (.*) must be replaced$`),
+		// Terraform v1.0 outputs "has been changed" and Terraform v1.2 or later outputs "has changed"
+		Drift: regexp.MustCompile(`^ *# (.*) has (?:been )?(?:changed|deleted)$`),
+		// Terraform v1.1 or later supports moved blocks
+		Move:                 regexp.MustCompile(`^ *# (.*) has moved to (.*)$`),
+		MovedFrom:            regexp.MustCompile(`^ *# \(moved from (.*)\)$`),
+		NoChangesExceptMoves: regexp.MustCompile(`^Plan: 0 to add, 0 to change, 0 to destroy\.`),
+		// Terraform v1.5 or later supports import blocks
+		Import:       regexp.MustCompile(`^ *# (.*) will be imported$`),
+		ImportedFrom: regexp.MustCompile(`^ *# \(imported from .*\)$`),
+		ImportOnly:   regexp.MustCompile(`^Plan: [1-9][0-9]* to import, 0 to add, 0 to change, 0 to destroy\.`),
+		RefreshOnly:  regexp.MustCompile(`(?m)^(This is a refresh-only plan|No changes\. Your infrastructure still matches the configuration\.)`),
 	}
 }
 
 // NewApplyParser is ApplyParser initialized with its Regexp
 func NewApplyParser() *ApplyParser {
 	return &ApplyParser{
-		Pass: regexp.MustCompile(`(?m)^(Apply complete!)`),
-		Fail: regexp.MustCompile(`(?m)^(Error: )`),
+		Pass:           regexp.MustCompile(`(?m)^(Apply complete!)`),
+		Fail:           regexp.MustCompile(`(?m)^(Error: )`),
+		Applied:        regexp.MustCompile(`(?m)^(.+?): (?:Creation|Modifications|Destruction) complete after`),
+		FailedResource: regexp.MustCompile(`(?m)^(?:│)?\s+with (.+),$`),
+		Output:         regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_-]*) = (.*)$`),
+	}
+}
+
+// NewAutoParser is AutoParser initialized with its Regexp
+func NewAutoParser() *AutoParser {
+	return &AutoParser{
+		Plan:        NewPlanParser(),
+		Apply:       NewApplyParser(),
+		ApplyMarker: regexp.MustCompile(`(?m)^(Apply complete!|.+: (Creating|Modifying|Destroying)\.\.\.)`),
+		PlanMarker:  regexp.MustCompile(`(?m)^(Plan: \d|No changes\.|(?:Terraform|OpenTofu) will perform the following actions:)`),
+	}
+}
+
+// Parse parses the combined output of terraform plan without any dependency on GitHub.
+// This is the entrypoint to use tfcmt's parser as a library.
+func Parse(combinedOutput string, exitCode int) (ParseResult, error) {
+	return parse(NewPlanParser(), combinedOutput, exitCode)
+}
+
+// ParseApply parses the combined output of terraform apply.
+func ParseApply(combinedOutput string, exitCode int) (ParseResult, error) {
+	return parse(NewApplyParser(), combinedOutput, exitCode)
+}
+
+// ParseAuto detects whether the combined output is of terraform plan or terraform apply and parses it.
+// The detected command is set to ParseResult.DetectedCommand.
+func ParseAuto(combinedOutput string, exitCode int) (ParseResult, error) {
+	return parse(NewAutoParser(), combinedOutput, exitCode)
+}
+
+func parse(parser Parser, combinedOutput string, exitCode int) (ParseResult, error) {
+	result := parser.Parse(NormalizeOutput(combinedOutput))
+	result.ExitCode = exitCode
+	return result, result.Error
+}
+
+// Parse detects the command and returns ParseResult of it.
+// If the command can't be detected, ParseResult.HasParseError is true
+func (p *AutoParser) Parse(body string) ParseResult {
+	var result ParseResult
+	switch {
+	case p.ApplyMarker.MatchString(body):
+		result = p.Apply.Parse(body)
+		result.DetectedCommand = CommandApply
+	case p.PlanMarker.MatchString(body):
+		result = p.Plan.Parse(body)
+		result.DetectedCommand = CommandPlan
+	default:
+		return ParseResult{
+			Result:        "",
+			ExitCode:      ExitFail,
+			HasParseError: true,
+			Error:         errors.New("cannot detect whether the output is of terraform plan or terraform apply"),
+			ErrorCategory: classifyError(body),
+		}
 	}
+	return result
 }
 
 // Parse returns ParseResult related with terraform commands
@@ -88,6 +308,14 @@ func (p *DefaultParser) Parse(body string) ParseResult {
 	}
 }
 
+// newParseError returns an error describing why the output of the command can't be parsed
+func newParseError(command, body, patterns string) error {
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("cannot parse %s result: the output is empty", command)
+	}
+	return fmt.Errorf("cannot parse %s result: no line starts with %s", command, patterns)
+}
+
 func extractResource(pattern *regexp.Regexp, line string) string {
 	if arr := pattern.FindStringSubmatch(line); len(arr) == 2 { //nolint:gomnd
 		return arr[1]
@@ -98,23 +326,30 @@ func extractResource(pattern *regexp.Regexp, line string) string {
 // Parse returns ParseResult related with terraform plan
 func (p *PlanParser) Parse(body string) ParseResult { //nolint:cyclop
 	var exitCode int
+	isRefreshOnly := p.RefreshOnly.MatchString(body)
 	switch {
 	case p.Pass.MatchString(body):
 		exitCode = ExitPass
 	case p.Fail.MatchString(body):
 		exitCode = ExitFail
+	case isRefreshOnly:
+		exitCode = ExitPass
 	default:
 		return ParseResult{
 			Result:        "",
 			HasParseError: true,
 			ExitCode:      ExitFail,
-			Error:         errors.New("cannot parse plan result"),
+			Error:         newParseError("plan", body, `"Plan: ", "No changes.", or "Error: "`),
+			ErrorCategory: classifyError(body),
 		}
 	}
 	lines := strings.Split(body, "\n")
 	firstMatchLineIndex := -1
-	var result, firstMatchLine string
-	var createdResources, updatedResources, deletedResources, replacedResources []string
+	var result, firstMatchLine, noteOutsideTerraform string
+	var createdResources, updatedResources, deletedResources, replacedResources, driftedResources, importedResources []string
+	var movedResources []MovedResource
+	// lastResource is the address of the last changed resource, which the annotation "(moved from ...)" follows
+	var lastResource string
 	startOutsideTerraform := -1
 	endOutsideTerraform := -1
 	startChangeOutput := -1
@@ -122,13 +357,23 @@ func (p *PlanParser) Parse(body string) ParseResult { //nolint:cyclop
 	startWarning := -1
 	endWarning := -1
 	for i, line := range lines {
-		if line == "Note: Objects have changed outside of Terraform" { // https://github.com/hashicorp/terraform/blob/332045a4e4b1d256c45f98aac74e31102ace7af7/internal/command/views/plan.go#L403
+		if line == "Note: Objects have changed outside of Terraform" || line == "Note: Objects have changed outside of OpenTofu" { // https://github.com/hashicorp/terraform/blob/332045a4e4b1d256c45f98aac74e31102ace7af7/internal/command/views/plan.go#L403
 			startOutsideTerraform = i + 1
+			noteOutsideTerraform = line
+		}
+		// a refresh-only plan ends the section with the different sentence
+		if startOutsideTerraform != -1 && endOutsideTerraform == -1 && strings.HasPrefix(line, "This is a refresh-only plan") {
+			endOutsideTerraform = i + 1
 		}
 		if startOutsideTerraform != -1 && endOutsideTerraform == -1 && strings.HasPrefix(line, "Unless you have made equivalent changes to your configuration") { // https://github.com/hashicorp/terraform/blob/332045a4e4b1d256c45f98aac74e31102ace7af7/internal/command/views/plan.go#L110
 			endOutsideTerraform = i + 1
 		}
-		if line == "Terraform will perform the following actions:" { // https://github.com/hashicorp/terraform/blob/332045a4e4b1d256c45f98aac74e31102ace7af7/internal/command/views/plan.go#L252
+		if startOutsideTerraform != -1 && endOutsideTerraform == -1 {
+			if rsc := extractResource(p.Drift, line); rsc != "" {
+				driftedResources = append(driftedResources, rsc)
+			}
+		}
+		if line == "Terraform will perform the following actions:" || line == "OpenTofu will perform the following actions:" { // https://github.com/hashicorp/terraform/blob/332045a4e4b1d256c45f98aac74e31102ace7af7/internal/command/views/plan.go#L252
 			startChangeOutput = i + 1
 		}
 		if startChangeOutput != -1 && endChangeOutput == -1 && strings.HasPrefix(line, "Plan: ") { // https://github.com/hashicorp/terraform/blob/dfc12a6a9e1cff323829026d51873c1b80200757/internal/command/views/plan.go#L306
@@ -148,12 +393,24 @@ func (p *PlanParser) Parse(body string) ParseResult { //nolint:cyclop
 		}
 		if rsc := extractResource(p.Create, line); rsc != "" {
 			createdResources = append(createdResources, rsc)
+			lastResource = rsc
 		} else if rsc := extractResource(p.Update, line); rsc != "" {
 			updatedResources = append(updatedResources, rsc)
+			lastResource = rsc
 		} else if rsc := extractResource(p.Delete, line); rsc != "" {
 			deletedResources = append(deletedResources, rsc)
+			lastResource = rsc
 		} else if rsc := extractResource(p.Replace, line); rsc != "" {
 			replacedResources = append(replacedResources, rsc)
+			lastResource = rsc
+		} else if arr := p.Move.FindStringSubmatch(line); arr != nil {
+			movedResources = append(movedResources, MovedResource{Before: arr[1], After: arr[2]})
+		} else if rsc := extractResource(p.MovedFrom, line); rsc != "" && lastResource != "" {
+			movedResources = append(movedResources, MovedResource{Before: rsc, After: lastResource})
+		} else if rsc := extractResource(p.Import, line); rsc != "" {
+			importedResources = append(importedResources, rsc)
+		} else if p.ImportedFrom.MatchString(line) && lastResource != "" {
+			importedResources = append(importedResources, lastResource)
 		}
 	}
 	var hasPlanError bool
@@ -163,11 +420,19 @@ func (p *PlanParser) Parse(body string) ParseResult { //nolint:cyclop
 	case p.Fail.MatchString(firstMatchLine):
 		hasPlanError = true
 		result = strings.Join(trimLastNewline(lines[firstMatchLineIndex:]), "\n")
+	case isRefreshOnly:
+		// e.g. "Objects have changed outside of Terraform"
+		result = strings.TrimPrefix(noteOutsideTerraform, "Note: ")
 	}
 
 	hasDestroy := p.HasDestroy.MatchString(firstMatchLine)
 	hasNoChanges := p.HasNoChanges.MatchString(firstMatchLine)
-	HasAddOrUpdateOnly := !hasNoChanges && !hasDestroy && !hasPlanError
+	// moving resources only changes the state, so it isn't treated as a change
+	if len(movedResources) != 0 && p.NoChangesExceptMoves.MatchString(firstMatchLine) {
+		hasNoChanges = true
+	}
+	hasImportOnly := p.ImportOnly.MatchString(firstMatchLine)
+	HasAddOrUpdateOnly := !hasNoChanges && !hasDestroy && !hasPlanError && !hasImportOnly && !isRefreshOnly
 
 	outsideTerraform := ""
 	if startOutsideTerraform != -1 {
@@ -188,7 +453,7 @@ func (p *PlanParser) Parse(body string) ParseResult { //nolint:cyclop
 		}
 	}
 
-	return ParseResult{
+	ret := ParseResult{
 		Result:             result,
 		ChangedResult:      changeResult,
 		OutsideTerraform:   outsideTerraform,
@@ -203,7 +468,216 @@ func (p *PlanParser) Parse(body string) ParseResult { //nolint:cyclop
 		UpdatedResources:   updatedResources,
 		DeletedResources:   deletedResources,
 		ReplacedResources:  replacedResources,
+		DriftedResources:   driftedResources,
+		MovedResources:     movedResources,
+		ImportedResources:  importedResources,
+		HasImportOnly:      hasImportOnly,
+		IsRefreshOnly:      isRefreshOnly,
+		Checks:             parseChecks(body),
+	}
+	if hasPlanError {
+		ret.ErrorCategory = classifyPlanError(result)
+	}
+	p.filterIgnoredResources(&ret)
+	if !p.KeepResourceOrder {
+		sortResources(&ret)
+	}
+	ret.ModuleChanges = groupByModule(ret)
+	return ret
+}
+
+// sortResources sorts the lists of resources by the address so that comments are stable across runs.
+// The raw output such as ChangedResult isn't changed.
+func sortResources(result *ParseResult) {
+	for _, resources := range [][]string{
+		result.CreatedResources,
+		result.UpdatedResources,
+		result.DeletedResources,
+		result.ReplacedResources,
+		result.DriftedResources,
+		result.ImportedResources,
+	} {
+		sort.Strings(resources)
+	}
+	sort.Slice(result.MovedResources, func(i, j int) bool {
+		return result.MovedResources[i].After < result.MovedResources[j].After
+	})
+}
+
+// getModulePath returns the module path of the resource address.
+// e.g. module.foo.module.bar[0].null_resource.foo => module.foo.module.bar[0]
+func getModulePath(address string) string {
+	modulePath := ""
+	for {
+		loc := modulePrefixPattern.FindStringIndex(address)
+		if loc == nil {
+			break
+		}
+		modulePath += address[:loc[1]]
+		address = address[loc[1]:]
+	}
+	if modulePath == "" {
+		return RootModule
+	}
+	return strings.TrimSuffix(modulePath, ".")
+}
+
+// groupByModule groups changed resources by the module path.
+// The root module comes first, and the other modules are sorted by the module path.
+func groupByModule(result ParseResult) []ModuleChanges {
+	groups := map[string]*ModuleChanges{}
+	get := func(address string) *ModuleChanges {
+		modulePath := getModulePath(address)
+		group, ok := groups[modulePath]
+		if !ok {
+			group = &ModuleChanges{
+				Module: modulePath,
+			}
+			groups[modulePath] = group
+		}
+		return group
+	}
+	for _, address := range result.CreatedResources {
+		group := get(address)
+		group.CreatedResources = append(group.CreatedResources, address)
+	}
+	for _, address := range result.UpdatedResources {
+		group := get(address)
+		group.UpdatedResources = append(group.UpdatedResources, address)
+	}
+	for _, address := range result.DeletedResources {
+		group := get(address)
+		group.DeletedResources = append(group.DeletedResources, address)
+	}
+	for _, address := range result.ReplacedResources {
+		group := get(address)
+		group.ReplacedResources = append(group.ReplacedResources, address)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	modulePaths := make([]string, 0, len(groups))
+	for modulePath := range groups {
+		modulePaths = append(modulePaths, modulePath)
+	}
+	sort.Slice(modulePaths, func(i, j int) bool {
+		if modulePaths[i] == RootModule {
+			return modulePaths[j] != RootModule
+		}
+		if modulePaths[j] == RootModule {
+			return false
+		}
+		return modulePaths[i] < modulePaths[j]
+	})
+	ret := make([]ModuleChanges, len(modulePaths))
+	for i, modulePath := range modulePaths {
+		ret[i] = *groups[modulePath]
+	}
+	return ret
+}
+
+var modulePrefixPattern = regexp.MustCompile(`^module\.[^.\[]+(\[[^\]]*\])?\.`)
+
+// getResourceType returns the resource type of the resource address.
+// e.g. module.foo.null_resource.bar[0] => null_resource
+func getResourceType(address string) string {
+	for {
+		loc := modulePrefixPattern.FindStringIndex(address)
+		if loc == nil {
+			break
+		}
+		address = address[loc[1]:]
+	}
+	address = strings.TrimPrefix(address, "data.")
+	if i := strings.Index(address, "."); i != -1 {
+		return address[:i]
+	}
+	return address
+}
+
+func (p *PlanParser) isIgnoredResource(address string) bool {
+	rType := getResourceType(address)
+	for _, pattern := range p.IgnoredResources {
+		if f, _ := path.Match(pattern, address); f {
+			return true
+		}
+		if f, _ := path.Match(pattern, rType); f {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PlanParser) filterResources(addresses []string) ([]string, bool) {
+	ret := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if !p.isIgnoredResource(address) {
+			ret = append(ret, address)
+		}
+	}
+	if len(ret) == len(addresses) {
+		return addresses, false
+	}
+	return ret, true
+}
+
+// filterIgnoredResources excludes ignored resources from the lists of changed resources.
+// If no resource remains, the result is treated as no changes.
+// The raw output such as Result and ChangedResult isn't filtered.
+func (p *PlanParser) filterIgnoredResources(result *ParseResult) {
+	if len(p.IgnoredResources) == 0 || result.HasPlanError {
+		return
+	}
+	var f1, f2, f3, f4 bool
+	result.CreatedResources, f1 = p.filterResources(result.CreatedResources)
+	result.UpdatedResources, f2 = p.filterResources(result.UpdatedResources)
+	result.DeletedResources, f3 = p.filterResources(result.DeletedResources)
+	result.ReplacedResources, f4 = p.filterResources(result.ReplacedResources)
+	if !f1 && !f2 && !f3 && !f4 {
+		return
+	}
+	result.HasDestroy = len(result.DeletedResources)+len(result.ReplacedResources) > 0
+	hasAddOrUpdate := len(result.CreatedResources)+len(result.UpdatedResources) > 0
+	hasImport := len(result.ImportedResources) > 0
+	result.HasAddOrUpdateOnly = !result.HasDestroy && hasAddOrUpdate
+	result.HasImportOnly = !result.HasDestroy && !hasAddOrUpdate && hasImport
+	result.HasNoChanges = !result.HasDestroy && !hasAddOrUpdate && !hasImport
+}
+
+// sensitiveOutput is the value of sensitive outputs in ParseResult.Outputs
+const sensitiveOutput = "(sensitive)"
+
+// parseOutputs parses the "Outputs:" section of terraform apply.
+// A value spanning multiple lines such as a list or a map is joined with newlines
+func (p *ApplyParser) parseOutputs(lines []string) map[string]string {
+	start := -1
+	for i, line := range lines {
+		if line == "Outputs:" {
+			start = i + 1
+		}
+	}
+	if start == -1 {
+		return nil
 	}
+	outputs := map[string]string{}
+	var name string
+	for _, line := range lines[start:] {
+		if arr := p.Output.FindStringSubmatch(line); len(arr) == 3 { //nolint:gomnd
+			name = arr[1]
+			outputs[name] = arr[2]
+			continue
+		}
+		if name == "" || line == "" {
+			continue
+		}
+		outputs[name] += "\n" + line
+	}
+	for k, v := range outputs {
+		if v == "<sensitive>" {
+			outputs[k] = sensitiveOutput
+		}
+	}
+	return outputs
 }
 
 // Parse returns ParseResult related with terraform apply
@@ -219,7 +693,8 @@ func (p *ApplyParser) Parse(body string) ParseResult {
 			Result:        "",
 			ExitCode:      ExitFail,
 			HasParseError: true,
-			Error:         errors.New("cannot parse apply result"),
+			Error:         newParseError("apply", body, `"Apply complete!" or "Error: "`),
+			ErrorCategory: classifyError(body),
 		}
 	}
 	lines := strings.Split(body, "\n")
@@ -236,11 +711,35 @@ func (p *ApplyParser) Parse(body string) ParseResult {
 	case p.Fail.MatchString(line):
 		result = strings.Join(trimLastNewline(lines[i:]), "\n")
 	}
-	return ParseResult{
+	ret := ParseResult{
 		Result:   result,
 		ExitCode: exitCode,
 		Error:    nil,
+		Checks:   parseChecks(body),
+	}
+	ret.Outputs = p.parseOutputs(lines)
+	if exitCode == ExitFail {
+		ret.HasApplyError = true
+		ret.ErrorCategory = classifyError(result)
+		ret.AppliedResources = findAllResources(p.Applied, body)
+		ret.FailedResources = findAllResources(p.FailedResource, body)
+	}
+	return ret
+}
+
+// findAllResources returns resource addresses matching the first capture group of the pattern without duplication.
+func findAllResources(pattern *regexp.Regexp, body string) []string {
+	var addrs []string
+	found := map[string]struct{}{}
+	for _, match := range pattern.FindAllStringSubmatch(body, -1) {
+		addr := match[1]
+		if _, ok := found[addr]; ok {
+			continue
+		}
+		found[addr] = struct{}{}
+		addrs = append(addrs, addr)
 	}
+	return addrs
 }
 
 func trimLastNewline(s []string) []string {
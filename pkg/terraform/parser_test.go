@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -297,6 +298,106 @@ Error: Batch "project/tfcmt-jp-tfcmt-prod/services:batchEnable" for request "Ena
 
 `
 
+const applyPartialFailureResult = `
+aws_instance.foo: Creating...
+module.db.aws_db_instance.main: Destroying... [id=db-1]
+aws_s3_bucket.bar: Creating...
+aws_instance.foo: Creation complete after 2s [id=i-1234]
+module.db.aws_db_instance.main: Destruction complete after 10s
+module.db.aws_db_instance.main: Creating...
+module.db.aws_db_instance.main: Creation complete after 5m0s [id=db-2]
+
+Error: creating S3 Bucket (bar): BucketAlreadyExists
+
+  with aws_s3_bucket.bar,
+  on main.tf line 5, in resource "aws_s3_bucket" "bar":
+   5: resource "aws_s3_bucket" "bar" {
+
+`
+
+const applyOutputsResult = `
+aws_instance.foo: Creating...
+aws_instance.foo: Creation complete after 2s [id=i-1234]
+
+Apply complete! Resources: 1 added, 0 changed, 0 destroyed.
+
+Outputs:
+
+instance_id = "i-1234"
+password = <sensitive>
+subnets = [
+  "subnet-a",
+  "subnet-b",
+]
+`
+
+func TestApplyParserParseOutputs(t *testing.T) {
+	t.Parallel()
+	result := NewApplyParser().Parse(applyOutputsResult)
+	exp := map[string]string{
+		"instance_id": `"i-1234"`,
+		"password":    "(sensitive)",
+		"subnets":     "[\n  \"subnet-a\",\n  \"subnet-b\",\n]",
+	}
+	if diff := cmp.Diff(exp, result.Outputs); diff != "" {
+		t.Error(diff)
+	}
+	if result.Result != "Apply complete! Resources: 1 added, 0 changed, 0 destroyed." {
+		t.Errorf("the result is wrong: %s", result.Result)
+	}
+}
+
+func TestAutoParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name          string
+		body          string
+		command       string
+		result        string
+		hasParseError bool
+	}{
+		{
+			name:    "plan",
+			body:    planHasAddAndDestroy,
+			command: CommandPlan,
+			result:  "Plan: 1 to add, 0 to change, 1 to destroy.",
+		},
+		{
+			name:    "apply",
+			body:    applySuccessResult,
+			command: CommandApply,
+			result:  "Apply complete! Resources: 0 added, 0 changed, 0 destroyed.",
+		},
+		{
+			name:    "apply with outputs",
+			body:    applyOutputsResult,
+			command: CommandApply,
+			result:  "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+		},
+		{
+			name:          "unknown",
+			body:          "Initializing the backend...",
+			hasParseError: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewAutoParser().Parse(testCase.body)
+			if result.DetectedCommand != testCase.command {
+				t.Errorf("command: got %q but want %q", result.DetectedCommand, testCase.command)
+			}
+			if result.HasParseError != testCase.hasParseError {
+				t.Errorf("HasParseError: got %v but want %v", result.HasParseError, testCase.hasParseError)
+			}
+			if result.Result != testCase.result {
+				t.Errorf("result: got %q but want %q", result.Result, testCase.result)
+			}
+		})
+	}
+}
+
 func TestDefaultParserParse(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -381,6 +482,7 @@ Plan: 1 to add, 0 to change, 0 to destroy.`,
 				HasPlanError:       true,
 				ExitCode:           1,
 				Error:              nil,
+				ErrorCategory:      ErrorCategoryUnknown,
 			},
 		},
 		{
@@ -513,8 +615,25 @@ func TestApplyParserParse(t *testing.T) {
    6: resource "google_project_service" "gcp_api_service" {
 
 `,
-				ExitCode: 1,
-				Error:    nil,
+				ExitCode:      1,
+				Error:         nil,
+				HasApplyError: true,
+			},
+		},
+		{
+			name: "apply partially failed",
+			body: applyPartialFailureResult,
+			result: ParseResult{
+				Result: `Error: creating S3 Bucket (bar): BucketAlreadyExists
+
+  with aws_s3_bucket.bar,
+  on main.tf line 5, in resource "aws_s3_bucket" "bar":
+   5: resource "aws_s3_bucket" "bar" {
+`,
+				ExitCode:         1,
+				HasApplyError:    true,
+				AppliedResources: []string{"aws_instance.foo", "module.db.aws_db_instance.main"},
+				FailedResources:  []string{"aws_s3_bucket.bar"},
 			},
 		},
 	}
@@ -530,6 +649,707 @@ func TestApplyParserParse(t *testing.T) {
 	}
 }
 
+func TestParserParseErrorMessage(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		parser Parser
+		body   string
+		exp    string
+	}{
+		{
+			name:   "plan with no stdin",
+			parser: NewPlanParser(),
+			body:   "",
+			exp:    "cannot parse plan result: the output is empty",
+		},
+		{
+			name:   "plan with unknown output",
+			parser: NewPlanParser(),
+			body:   "foo\nbar\n",
+			exp:    `cannot parse plan result: no line starts with "Plan: ", "No changes.", or "Error: "`,
+		},
+		{
+			name:   "apply with no stdin",
+			parser: NewApplyParser(),
+			body:   "",
+			exp:    "cannot parse apply result: the output is empty",
+		},
+		{
+			name:   "apply with unknown output",
+			parser: NewApplyParser(),
+			body:   "foo\nbar\n",
+			exp:    `cannot parse apply result: no line starts with "Apply complete!" or "Error: "`,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := testCase.parser.Parse(testCase.body)
+			if !result.HasParseError {
+				t.Fatal("HasParseError must be true")
+			}
+			if result.Error == nil {
+				t.Fatal("Error must not be nil")
+			}
+			if diff := cmp.Diff(testCase.exp, result.Error.Error()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+const planHasIgnoredResources = `
+Terraform will perform the following actions:
+
+  # null_resource.foo must be replaced
+-/+ resource "null_resource" "foo" {
+      ~ id       = "1" -> (known after apply)
+      ~ triggers = { # forces replacement
+          ~ "now" = "a" -> "b"
+        }
+    }
+
+  # module.foo["a.b"].random_id.bar will be created
+  + resource "random_id" "bar" {
+      + id = (known after apply)
+    }
+
+Plan: 2 to add, 0 to change, 1 to destroy.
+`
+
+func TestPlanParserParseIgnoredResources(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		ignored []string
+		result  ParseResult
+	}{
+		{
+			name:    "all resources are ignored",
+			ignored: []string{"null_resource", "random_*"},
+			result: ParseResult{
+				Result:            "Plan: 2 to add, 0 to change, 1 to destroy.",
+				HasNoChanges:      true,
+				CreatedResources:  []string{},
+				UpdatedResources:  nil,
+				DeletedResources:  nil,
+				ReplacedResources: []string{},
+			},
+		},
+		{
+			name:    "some resources are ignored",
+			ignored: []string{"module.foo*"},
+			result: ParseResult{
+				Result:            "Plan: 2 to add, 0 to change, 1 to destroy.",
+				HasDestroy:        true,
+				CreatedResources:  []string{},
+				ReplacedResources: []string{"null_resource.foo"},
+			},
+		},
+		{
+			name:    "no resource is ignored",
+			ignored: []string{"aws_*"},
+			result: ParseResult{
+				Result:            "Plan: 2 to add, 0 to change, 1 to destroy.",
+				HasDestroy:        true,
+				CreatedResources:  []string{`module.foo["a.b"].random_id.bar`},
+				ReplacedResources: []string{"null_resource.foo"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			parser := NewPlanParser()
+			parser.IgnoredResources = testCase.ignored
+			result := parser.Parse(planHasIgnoredResources)
+			if diff := cmp.Diff(result, testCase.result, cmpopts.IgnoreFields(ParseResult{}, "Error", "ChangedResult", "ModuleChanges")); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestPlanParserParseIgnoredResourcesSucceeded(t *testing.T) {
+	t.Parallel()
+	parser := NewPlanParser()
+	parser.IgnoredResources = []string{"null_resource", "random_*"}
+	result := parser.Parse(planHasIgnoredResources)
+	// terraform plan -detailed-exitcode exits with 2 even if all changes are ignored by tfcmt
+	result.ExitCode = ExitChanges
+	if result.HasChanges() {
+		t.Error("HasChanges must be false if all changes are ignored")
+	}
+	if !result.Succeeded() {
+		t.Error("Succeeded must be true if all changes are ignored")
+	}
+}
+
+const planHasDrift = `
+null_resource.foo: Refreshing state... [id=1]
+
+Note: Objects have changed outside of Terraform
+
+Terraform detected the following changes made outside of Terraform since the
+last "terraform apply":
+
+  # null_resource.foo has changed
+  ~ resource "null_resource" "foo" {
+        id       = "1"
+      ~ triggers = {
+          ~ "foo" = "bar" -> "baz"
+        }
+    }
+
+  # null_resource.bar has been deleted
+  - resource "null_resource" "bar" {
+      - id = "2" -> null
+    }
+
+Unless you have made equivalent changes to your configuration, or ignored the
+relevant attributes using ignore_changes, the following plan may include
+actions to undo or respond to these changes.
+
+─────────────────────────────────────────────────────────────────────────────
+
+Terraform used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  + create
+
+Terraform will perform the following actions:
+
+  # null_resource.bar will be created
+  + resource "null_resource" "bar" {
+      + id = (known after apply)
+    }
+
+Plan: 1 to add, 0 to change, 0 to destroy.
+`
+
+func TestPlanParserParseDriftedResources(t *testing.T) {
+	t.Parallel()
+	result := NewPlanParser().Parse(planHasDrift)
+	if diff := cmp.Diff(result.DriftedResources, []string{"null_resource.bar", "null_resource.foo"}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(result.CreatedResources, []string{"null_resource.bar"}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+const planHasMovedResources = `
+Terraform will perform the following actions:
+
+  # null_resource.old has moved to null_resource.new
+    resource "null_resource" "new" {
+        id = "1"
+    }
+
+  # null_resource.bar will be updated in-place
+  # (moved from null_resource.foo)
+  ~ resource "null_resource" "bar" {
+        id       = "2"
+      ~ triggers = {
+          ~ "foo" = "bar" -> "baz"
+        }
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`
+
+const planHasOnlyMovedResources = `
+Terraform will perform the following actions:
+
+  # null_resource.old has moved to null_resource.new
+    resource "null_resource" "new" {
+        id = "1"
+    }
+
+Plan: 0 to add, 0 to change, 0 to destroy.
+`
+
+func TestPlanParserParseMovedResources(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name         string
+		body         string
+		moved        []MovedResource
+		updated      []string
+		hasNoChanges bool
+	}{
+		{
+			name: "moved with changes",
+			body: planHasMovedResources,
+			moved: []MovedResource{
+				{Before: "null_resource.foo", After: "null_resource.bar"},
+				{Before: "null_resource.old", After: "null_resource.new"},
+			},
+			updated: []string{"null_resource.bar"},
+		},
+		{
+			name: "only moved",
+			body: planHasOnlyMovedResources,
+			moved: []MovedResource{
+				{Before: "null_resource.old", After: "null_resource.new"},
+			},
+			hasNoChanges: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewPlanParser().Parse(testCase.body)
+			if diff := cmp.Diff(testCase.moved, result.MovedResources); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.updated, result.UpdatedResources); diff != "" {
+				t.Error(diff)
+			}
+			if result.HasNoChanges != testCase.hasNoChanges {
+				t.Errorf("HasNoChanges: got %v, wanted %v", result.HasNoChanges, testCase.hasNoChanges)
+			}
+			if result.HasAddOrUpdateOnly == testCase.hasNoChanges {
+				t.Errorf("HasAddOrUpdateOnly: got %v, wanted %v", result.HasAddOrUpdateOnly, !testCase.hasNoChanges)
+			}
+		})
+	}
+}
+
+const planHasImportedResources = `
+Terraform will perform the following actions:
+
+  # null_resource.foo will be imported
+    resource "null_resource" "foo" {
+        id = "1"
+    }
+
+  # null_resource.bar will be updated in-place
+  # (imported from "2")
+  ~ resource "null_resource" "bar" {
+        id       = "2"
+      ~ triggers = {
+          ~ "foo" = "bar" -> "baz"
+        }
+    }
+
+Plan: 2 to import, 0 to add, 1 to change, 0 to destroy.
+`
+
+const planHasOnlyImportedResources = `
+Terraform will perform the following actions:
+
+  # null_resource.foo will be imported
+    resource "null_resource" "foo" {
+        id = "1"
+    }
+
+Plan: 1 to import, 0 to add, 0 to change, 0 to destroy.
+`
+
+func TestPlanParserParseImportedResources(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name               string
+		body               string
+		imported           []string
+		hasImportOnly      bool
+		hasAddOrUpdateOnly bool
+	}{
+		{
+			name:               "imported with changes",
+			body:               planHasImportedResources,
+			imported:           []string{"null_resource.bar", "null_resource.foo"},
+			hasAddOrUpdateOnly: true,
+		},
+		{
+			name:          "only imported",
+			body:          planHasOnlyImportedResources,
+			imported:      []string{"null_resource.foo"},
+			hasImportOnly: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewPlanParser().Parse(testCase.body)
+			if diff := cmp.Diff(testCase.imported, result.ImportedResources); diff != "" {
+				t.Error(diff)
+			}
+			if result.HasImportOnly != testCase.hasImportOnly {
+				t.Errorf("HasImportOnly: got %v, wanted %v", result.HasImportOnly, testCase.hasImportOnly)
+			}
+			if result.HasAddOrUpdateOnly != testCase.hasAddOrUpdateOnly {
+				t.Errorf("HasAddOrUpdateOnly: got %v, wanted %v", result.HasAddOrUpdateOnly, testCase.hasAddOrUpdateOnly)
+			}
+			if result.HasNoChanges {
+				t.Error("HasNoChanges should be false")
+			}
+			if !result.HasChanges() {
+				t.Error("HasChanges should be true")
+			}
+		})
+	}
+}
+
+const planRefreshOnlyHasDrift = `
+null_resource.foo: Refreshing state... [id=1]
+
+Note: Objects have changed outside of Terraform
+
+Terraform detected the following changes made outside of Terraform since the
+last "terraform apply" which may have affected this plan:
+
+  # null_resource.foo has changed
+  ~ resource "null_resource" "foo" {
+        id       = "1"
+      ~ triggers = {
+          ~ "foo" = "bar" -> "baz"
+        }
+    }
+
+This is a refresh-only plan, so Terraform will not take any actions to undo
+these. If you were expecting these changes then you can apply this plan to
+record the updated values in the Terraform state without changing any remote
+objects.
+`
+
+func TestPlanParserParseRefreshOnly(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		body       string
+		res        string
+		drifted    []string
+		noChanges  bool
+		hasChanges bool
+	}{
+		{
+			name:       "drift is detected",
+			body:       planRefreshOnlyHasDrift,
+			res:        "Objects have changed outside of Terraform",
+			drifted:    []string{"null_resource.foo"},
+			hasChanges: true,
+		},
+		{
+			name:      "no drift",
+			body:      "No changes. Your infrastructure still matches the configuration.",
+			res:       "No changes. Your infrastructure still matches the configuration.",
+			noChanges: true,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewPlanParser().Parse(testCase.body)
+			if result.HasParseError {
+				t.Fatal(result.Error)
+			}
+			if !result.IsRefreshOnly {
+				t.Error("IsRefreshOnly should be true")
+			}
+			if result.Result != testCase.res {
+				t.Errorf("Result: got %q, wanted %q", result.Result, testCase.res)
+			}
+			if diff := cmp.Diff(testCase.drifted, result.DriftedResources); diff != "" {
+				t.Error(diff)
+			}
+			if result.HasNoChanges != testCase.noChanges {
+				t.Errorf("HasNoChanges: got %v, wanted %v", result.HasNoChanges, testCase.noChanges)
+			}
+			if result.HasAddOrUpdateOnly {
+				t.Error("HasAddOrUpdateOnly should be false")
+			}
+			if result.HasChanges() != testCase.hasChanges {
+				t.Errorf("HasChanges: got %v, wanted %v", result.HasChanges(), testCase.hasChanges)
+			}
+			if testCase.drifted != nil && !strings.Contains(result.OutsideTerraform, "null_resource.foo has changed") {
+				t.Errorf("the changes outside of Terraform should be extracted: %q", result.OutsideTerraform)
+			}
+		})
+	}
+}
+
+func TestPlanParserParseOpenTofu(t *testing.T) {
+	t.Parallel()
+	body := strings.ReplaceAll(strings.ReplaceAll(planHasDrift, "Terraform", "OpenTofu"), `"terraform apply"`, `"tofu apply"`)
+	result := NewPlanParser().Parse(body)
+	if diff := cmp.Diff(result.DriftedResources, []string{"null_resource.bar", "null_resource.foo"}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(result.CreatedResources, []string{"null_resource.bar"}); diff != "" {
+		t.Error(diff)
+	}
+	if !strings.Contains(result.OutsideTerraform, "null_resource.foo has changed") {
+		t.Errorf("the changes outside of OpenTofu should be extracted: %q", result.OutsideTerraform)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(result.ChangedResult), "# null_resource.bar will be created") {
+		t.Errorf("the changed result should be extracted: %q", result.ChangedResult)
+	}
+}
+
+func TestDetectProduct(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		output string
+		exp    string
+	}{
+		{
+			name:   "terraform",
+			output: planHasDrift,
+			exp:    ProductTerraform,
+		},
+		{
+			name:   "opentofu",
+			output: "OpenTofu will perform the following actions:\n\nPlan: 1 to add, 0 to change, 0 to destroy.",
+			exp:    ProductOpenTofu,
+		},
+		{
+			name:   "the output doesn't tell the product",
+			output: "No changes.",
+			exp:    ProductTerraform,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if product := DetectProduct(testCase.output); product != testCase.exp {
+				t.Errorf("got %q, wanted %q", product, testCase.exp)
+			}
+		})
+	}
+}
+
+const planUnsortedResources = `
+Terraform will perform the following actions:
+
+  # null_resource.c will be created
+  + resource "null_resource" "c" {
+      + id = (known after apply)
+    }
+
+  # module.foo.null_resource.a will be created
+  + resource "null_resource" "a" {
+      + id = (known after apply)
+    }
+
+  # null_resource.b will be created
+  + resource "null_resource" "b" {
+      + id = (known after apply)
+    }
+
+Plan: 3 to add, 0 to change, 0 to destroy.
+`
+
+func TestPlanParserParseSortResources(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name      string
+		keepOrder bool
+		exp       []string
+	}{
+		{
+			name: "sorted",
+			exp:  []string{"module.foo.null_resource.a", "null_resource.b", "null_resource.c"},
+		},
+		{
+			name:      "keep the order",
+			keepOrder: true,
+			exp:       []string{"null_resource.c", "module.foo.null_resource.a", "null_resource.b"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			parser := NewPlanParser()
+			parser.KeepResourceOrder = testCase.keepOrder
+			result := parser.Parse(planUnsortedResources)
+			if diff := cmp.Diff(testCase.exp, result.CreatedResources); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestPlanParserParseModuleChanges(t *testing.T) {
+	t.Parallel()
+	result := NewPlanParser().Parse(planHasIgnoredResources)
+	exp := []ModuleChanges{
+		{
+			Module:            "root",
+			ReplacedResources: []string{"null_resource.foo"},
+		},
+		{
+			Module:           `module.foo["a.b"]`,
+			CreatedResources: []string{`module.foo["a.b"].random_id.bar`},
+		},
+	}
+	if diff := cmp.Diff(result.ModuleChanges, exp); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestGetModulePath(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		address string
+		exp     string
+	}{
+		{
+			address: "null_resource.foo",
+			exp:     "root",
+		},
+		{
+			address: "module.network.aws_vpc.main",
+			exp:     "module.network",
+		},
+		{
+			address: "module.foo.module.bar[0].null_resource.foo[\"a\"]",
+			exp:     "module.foo.module.bar[0]",
+		},
+	}
+	for _, testCase := range testCases {
+		if modulePath := getModulePath(testCase.address); modulePath != testCase.exp {
+			t.Errorf("got %q but want %q", modulePath, testCase.exp)
+		}
+	}
+}
+
+func TestGetResourceType(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		address string
+		exp     string
+	}{
+		{
+			address: "null_resource.foo",
+			exp:     "null_resource",
+		},
+		{
+			address: "module.foo.module.bar[0].null_resource.foo[\"a\"]",
+			exp:     "null_resource",
+		},
+		{
+			address: "module.foo[\"a.b\"].data.aws_caller_identity.current",
+			exp:     "aws_caller_identity",
+		},
+	}
+	for _, testCase := range testCases {
+		if rType := getResourceType(testCase.address); rType != testCase.exp {
+			t.Errorf("got %q but want %q", rType, testCase.exp)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		body     string
+		exitCode int
+		result   string
+		ok       bool
+	}{
+		{
+			name:     "plan ok pattern",
+			body:     planSuccessResult,
+			exitCode: 2,
+			result:   "Plan: 1 to add, 0 to change, 0 to destroy.",
+			ok:       true,
+		},
+		{
+			name:     "no stdin",
+			body:     "",
+			exitCode: 1,
+			ok:       false,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := Parse(testCase.body, testCase.exitCode)
+			if (err == nil) != testCase.ok {
+				t.Errorf("got error %v", err)
+			}
+			if result.Result != testCase.result {
+				t.Errorf("got %q but want %q", result.Result, testCase.result)
+			}
+			if result.ExitCode != testCase.exitCode {
+				t.Errorf("got %d but want %d", result.ExitCode, testCase.exitCode)
+			}
+		})
+	}
+}
+
+func TestParseResultSucceeded(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		result     ParseResult
+		hasChanges bool
+		succeeded  bool
+	}{
+		{
+			name:      "no changes",
+			result:    ParseResult{HasNoChanges: true, ExitCode: 0},
+			succeeded: true,
+		},
+		{
+			name:       "changes without -detailed-exitcode",
+			result:     ParseResult{HasAddOrUpdateOnly: true, ExitCode: 0},
+			hasChanges: true,
+			succeeded:  true,
+		},
+		{
+			name:       "changes with -detailed-exitcode",
+			result:     ParseResult{HasDestroy: true, ExitCode: 2},
+			hasChanges: true,
+			succeeded:  true,
+		},
+		{
+			name:      "exit code 2 without changes",
+			result:    ParseResult{HasNoChanges: true, ExitCode: 2},
+			succeeded: true,
+		},
+		{
+			name:      "exit code 2 with a parse error",
+			result:    ParseResult{HasParseError: true, ExitCode: 2},
+			succeeded: false,
+		},
+		{
+			name:      "exit code 2 with only moved resources",
+			result:    ParseResult{HasNoChanges: true, ExitCode: 2, MovedResources: []MovedResource{{Before: "null_resource.foo", After: "null_resource.bar"}}},
+			succeeded: true,
+		},
+		{
+			name:      "plan error",
+			result:    ParseResult{HasPlanError: true, ExitCode: 1},
+			succeeded: false,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if hasChanges := testCase.result.HasChanges(); hasChanges != testCase.hasChanges {
+				t.Errorf("HasChanges: got %v but want %v", hasChanges, testCase.hasChanges)
+			}
+			if succeeded := testCase.result.Succeeded(); succeeded != testCase.succeeded {
+				t.Errorf("Succeeded: got %v but want %v", succeeded, testCase.succeeded)
+			}
+		})
+	}
+}
+
 func TestTrimLastNewline(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
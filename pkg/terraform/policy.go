@@ -0,0 +1,97 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Severities of policy violations
+const (
+	PolicySeverityFailure = "failure"
+	PolicySeverityWarning = "warning"
+)
+
+// PolicyResult is the result of policies evaluated against the plan JSON.
+// The format is compatible with the output of `conftest test --output json`.
+type PolicyResult []PolicyJSONResult
+
+// PolicyJSONResult is the result of a namespace of policies
+type PolicyJSONResult struct {
+	Filename  string              `json:"filename"`
+	Namespace string              `json:"namespace"`
+	Successes int                 `json:"successes"`
+	Failures  []PolicyJSONMessage `json:"failures"`
+	Warnings  []PolicyJSONMessage `json:"warnings"`
+}
+
+// PolicyJSONMessage is a message of a failed rule
+type PolicyJSONMessage struct {
+	Msg string `json:"msg"`
+}
+
+// PolicyViolation is passed to templates as an element of PolicyViolations
+type PolicyViolation struct {
+	Namespace string
+	// Severity is either "failure" or "warning"
+	Severity string
+	Message  string
+}
+
+// ParsePolicyResult parses the result of conftest
+func ParsePolicyResult(b []byte) (PolicyResult, error) {
+	result := PolicyResult{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("parse a result of conftest as JSON: %w", err)
+	}
+	return result, nil
+}
+
+// Violations returns failures and warnings. Failures come first
+func (result PolicyResult) Violations() []PolicyViolation {
+	var violations []PolicyViolation
+	for _, r := range result {
+		for _, failure := range r.Failures {
+			violations = append(violations, PolicyViolation{
+				Namespace: r.Namespace,
+				Severity:  PolicySeverityFailure,
+				Message:   failure.Msg,
+			})
+		}
+	}
+	for _, r := range result {
+		for _, warning := range r.Warnings {
+			violations = append(violations, PolicyViolation{
+				Namespace: r.Namespace,
+				Severity:  PolicySeverityWarning,
+				Message:   warning.Msg,
+			})
+		}
+	}
+	return violations
+}
+
+// CountFailures returns the number of failures
+func (result PolicyResult) CountFailures() int {
+	cnt := 0
+	for _, r := range result {
+		cnt += len(r.Failures)
+	}
+	return cnt
+}
+
+// Summary returns the numbers of violations like "1 failure(s), 2 warning(s)".
+// If policies aren't evaluated, an empty string is returned
+func (result PolicyResult) Summary() string {
+	if result == nil {
+		return ""
+	}
+	warnings := 0
+	for _, r := range result {
+		warnings += len(r.Warnings)
+	}
+	failures := result.CountFailures()
+	if failures+warnings == 0 {
+		return "All policies are passed"
+	}
+	return fmt.Sprintf("%d failure(s), %d warning(s)", failures, warnings)
+}
@@ -0,0 +1,88 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const conftestResult = `[
+  {
+    "filename": "-",
+    "namespace": "main",
+    "successes": 1,
+    "failures": [
+      {"msg": "aws_s3_bucket.foo must have tags", "metadata": {"query": "data.main.deny"}}
+    ],
+    "warnings": [
+      {"msg": "aws_instance.web should use a larger instance type"}
+    ]
+  },
+  {
+    "filename": "-",
+    "namespace": "tags",
+    "successes": 2
+  }
+]`
+
+func TestParsePolicyResult(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name       string
+		body       string
+		violations []PolicyViolation
+		summary    string
+		failures   int
+	}{
+		{
+			name: "violations",
+			body: conftestResult,
+			violations: []PolicyViolation{
+				{
+					Namespace: "main",
+					Severity:  PolicySeverityFailure,
+					Message:   "aws_s3_bucket.foo must have tags",
+				},
+				{
+					Namespace: "main",
+					Severity:  PolicySeverityWarning,
+					Message:   "aws_instance.web should use a larger instance type",
+				},
+			},
+			summary:  "1 failure(s), 1 warning(s)",
+			failures: 1,
+		},
+		{
+			name:    "passed",
+			body:    `[{"filename": "-", "namespace": "main", "successes": 3}]`,
+			summary: "All policies are passed",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ParsePolicyResult([]byte(testCase.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.violations, result.Violations()); diff != "" {
+				t.Error(diff)
+			}
+			if summary := result.Summary(); summary != testCase.summary {
+				t.Errorf("got %q but want %q", summary, testCase.summary)
+			}
+			if cnt := result.CountFailures(); cnt != testCase.failures {
+				t.Errorf("got %d but want %d", cnt, testCase.failures)
+			}
+		})
+	}
+}
+
+func TestPolicyResultNotEvaluated(t *testing.T) {
+	t.Parallel()
+	var result PolicyResult
+	if summary := result.Summary(); summary != "" {
+		t.Errorf("got %q but want an empty string", summary)
+	}
+}
@@ -0,0 +1,321 @@
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ProductPulumi is the name of the product for the output of pulumi
+const ProductPulumi = "Pulumi"
+
+// pulumiStackType is the type of the stack resource, which is excluded from the list of changed resources
+const pulumiStackType = "pulumi:pulumi:Stack"
+
+// PulumiParser is a parser for the output of pulumi preview and pulumi up.
+// Both the human readable output and the output of --json are supported.
+// pulumi preview --json outputs a JSON object and pulumi up --json outputs a stream of engine events
+type PulumiParser struct {
+	// Command is either CommandPlan or CommandApply. CommandPlan means pulumi preview and CommandApply means pulumi up
+	Command string
+	// Row matches rows of the table of resources like " +   ├─ aws:s3:Bucket  my-bucket  create"
+	Row    *regexp.Regexp
+	Error  *regexp.Regexp
+	Output *regexp.Regexp
+}
+
+// NewPulumiParser is PulumiParser initializer
+func NewPulumiParser(command string) *PulumiParser {
+	return &PulumiParser{
+		Command: command,
+		Row:     regexp.MustCompile(`^ *(?:\+-|-\+|\+\+|--|\+|~|-|>|=)? +(?:[│├└─]+ )?([a-zA-Z0-9_-]+:\S+) +(\S+) +\**([a-z-]+(?: failed)?)`),
+		Error:   regexp.MustCompile(`^ *(error: .*)$`),
+		Output:  regexp.MustCompile(`^    ([a-zA-Z_][a-zA-Z0-9_-]*) *: (.*)$`),
+	}
+}
+
+// pulumiResult is the aggregation of the output
+type pulumiResult struct {
+	summary  string
+	errors   []string
+	created  []string
+	updated  []string
+	deleted  []string
+	replaced []string
+	failed   []string
+	outputs  map[string]string
+}
+
+// addResource adds the resource to the list of the operation. Operations which don't change resources such as "same" are ignored
+func (r *pulumiResult) addResource(op, addr string) {
+	switch op {
+	case "create", "created":
+		r.created = appendUnique(r.created, addr)
+	case "update", "updated":
+		r.updated = appendUnique(r.updated, addr)
+	case "delete", "deleted":
+		r.deleted = appendUnique(r.deleted, addr)
+	case "replace", "replaced", "create-replacement", "created-replacement", "delete-replaced", "deleted-replaced":
+		r.replaced = appendUnique(r.replaced, addr)
+	default:
+		if strings.HasSuffix(op, " failed") {
+			r.failed = appendUnique(r.failed, addr)
+		}
+	}
+}
+
+func (r *pulumiResult) parseResult(command string) ParseResult {
+	ret := ParseResult{
+		ExitCode:        ExitPass,
+		Outputs:         r.outputs,
+		DetectedCommand: command,
+	}
+	if len(r.errors) != 0 {
+		ret.Result = strings.Join(r.errors, "\n")
+		ret.ExitCode = ExitFail
+		if command == CommandApply {
+			ret.HasApplyError = true
+			for _, resources := range [][]string{r.created, r.updated, r.deleted, r.replaced} {
+				ret.AppliedResources = append(ret.AppliedResources, resources...)
+			}
+			ret.FailedResources = r.failed
+			return ret
+		}
+		ret.HasPlanError = true
+		return ret
+	}
+	ret.Result = r.summary
+	if command == CommandApply {
+		return ret
+	}
+	ret.CreatedResources = r.created
+	ret.UpdatedResources = r.updated
+	ret.DeletedResources = r.deleted
+	ret.ReplacedResources = r.replaced
+	ret.HasDestroy = len(r.deleted)+len(r.replaced) > 0
+	hasAddOrUpdate := len(r.created)+len(r.updated) > 0
+	ret.HasAddOrUpdateOnly = !ret.HasDestroy && hasAddOrUpdate
+	ret.HasNoChanges = !ret.HasDestroy && !hasAddOrUpdate
+	ret.ModuleChanges = groupByModule(ret)
+	return ret
+}
+
+// Parse parses the output of pulumi preview or pulumi up
+func (p *PulumiParser) Parse(body string) ParseResult {
+	var (
+		result *pulumiResult
+		err    error
+	)
+	if strings.HasPrefix(strings.TrimSpace(body), "{") {
+		result, err = p.parseJSON(body)
+	} else {
+		result, err = p.parseText(body)
+	}
+	if err != nil {
+		return ParseResult{
+			HasParseError:   true,
+			ExitCode:        ExitFail,
+			Error:           err,
+			DetectedCommand: p.Command,
+		}
+	}
+	return result.parseResult(p.Command)
+}
+
+func (p *PulumiParser) commandName() string {
+	if p.Command == CommandApply {
+		return "pulumi up"
+	}
+	return "pulumi preview"
+}
+
+// parseText parses the human readable output.
+// The result is the "Resources:" section, and resources are got from the table of resources
+func (p *PulumiParser) parseText(body string) (*pulumiResult, error) {
+	result := &pulumiResult{}
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		switch line {
+		case "Resources:":
+			summary := []string{}
+			for _, l := range lines[i+1:] {
+				if strings.TrimSpace(l) == "" {
+					break
+				}
+				summary = append(summary, strings.TrimSpace(l))
+			}
+			result.summary = "Resources: " + strings.Join(summary, ", ")
+			continue
+		case "Outputs:":
+			result.outputs = p.parseOutputs(lines[i+1:])
+			continue
+		}
+		if arr := p.Error.FindStringSubmatch(line); arr != nil {
+			result.errors = append(result.errors, arr[1])
+			continue
+		}
+		if arr := p.Row.FindStringSubmatch(line); arr != nil && arr[1] != pulumiStackType {
+			result.addResource(arr[3], arr[1]+"::"+arr[2])
+		}
+	}
+	if result.summary == "" && len(result.errors) == 0 {
+		return nil, newParseError(p.commandName(), body, `"Resources:" or "error: "`)
+	}
+	return result, nil
+}
+
+// parseOutputs parses the "Outputs:" section. A value spanning multiple lines such as an object is joined with newlines
+func (p *PulumiParser) parseOutputs(lines []string) map[string]string {
+	outputs := map[string]string{}
+	var name string
+	for _, line := range lines {
+		if line == "" {
+			break
+		}
+		if arr := p.Output.FindStringSubmatch(line); arr != nil {
+			name = arr[1]
+			outputs[name] = arr[2]
+			continue
+		}
+		if name != "" {
+			outputs[name] += "\n" + strings.TrimPrefix(line, "    ")
+		}
+	}
+	for k, v := range outputs {
+		if v == "[secret]" {
+			outputs[k] = sensitiveOutput
+		}
+	}
+	return outputs
+}
+
+type pulumiStep struct {
+	Op  string `json:"op"`
+	URN string `json:"urn"`
+}
+
+type pulumiDiagnostic struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+type pulumiMetadataEvent struct {
+	Metadata pulumiStep `json:"metadata"`
+}
+
+type pulumiSummaryEvent struct {
+	ResourceChanges map[string]int `json:"resourceChanges"`
+}
+
+// pulumiJSON is either the JSON object of pulumi preview --json or an engine event of pulumi up --json
+type pulumiJSON struct {
+	Steps            []pulumiStep         `json:"steps"`
+	Diagnostics      []pulumiDiagnostic   `json:"diagnostics"`
+	ChangeSummary    map[string]int       `json:"changeSummary"`
+	ResOutputsEvent  *pulumiMetadataEvent `json:"resOutputsEvent"`
+	ResOpFailedEvent *pulumiMetadataEvent `json:"resOpFailedEvent"`
+	DiagnosticEvent  *pulumiDiagnostic    `json:"diagnosticEvent"`
+	SummaryEvent     *pulumiSummaryEvent  `json:"summaryEvent"`
+}
+
+// parseJSON parses the output of --json
+func (p *PulumiParser) parseJSON(body string) (*pulumiResult, error) {
+	result := &pulumiResult{}
+	var summary map[string]int
+	decoder := json.NewDecoder(strings.NewReader(body))
+	for {
+		var obj pulumiJSON
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("cannot parse %s result: %w", p.commandName(), err)
+		}
+		for _, step := range obj.Steps {
+			if addr := pulumiAddress(step.URN); addr != "" {
+				result.addResource(step.Op, addr)
+			}
+		}
+		for _, diag := range obj.Diagnostics {
+			result.addDiagnostic(diag)
+		}
+		if obj.ChangeSummary != nil {
+			summary = obj.ChangeSummary
+		}
+		if e := obj.ResOutputsEvent; e != nil {
+			if addr := pulumiAddress(e.Metadata.URN); addr != "" {
+				result.addResource(e.Metadata.Op, addr)
+			}
+		}
+		if e := obj.ResOpFailedEvent; e != nil {
+			if addr := pulumiAddress(e.Metadata.URN); addr != "" {
+				result.failed = appendUnique(result.failed, addr)
+			}
+		}
+		if obj.DiagnosticEvent != nil {
+			result.addDiagnostic(*obj.DiagnosticEvent)
+		}
+		if obj.SummaryEvent != nil {
+			summary = obj.SummaryEvent.ResourceChanges
+		}
+	}
+	if summary == nil && len(result.errors) == 0 {
+		return nil, fmt.Errorf("cannot parse %s result: neither the change summary nor errors are found", p.commandName())
+	}
+	result.summary = pulumiSummary(summary, p.Command)
+	return result, nil
+}
+
+func (r *pulumiResult) addDiagnostic(diag pulumiDiagnostic) {
+	if diag.Severity == "error" {
+		r.errors = append(r.errors, "error: "+strings.TrimSpace(diag.Message))
+	}
+}
+
+// pulumiSummary returns the result like "Resources: 2 to create, 1 unchanged" from the counts of operations
+func pulumiSummary(summary map[string]int, command string) string {
+	words := [][3]string{
+		{"create", "to create", "created"},
+		{"update", "to update", "updated"},
+		{"delete", "to delete", "deleted"},
+		{"replace", "to replace", "replaced"},
+		{"same", "unchanged", "unchanged"},
+	}
+	parts := []string{}
+	for _, w := range words {
+		n, ok := summary[w[0]]
+		if !ok || n == 0 {
+			continue
+		}
+		word := w[1]
+		if command == CommandApply {
+			word = w[2]
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", n, word))
+	}
+	if len(parts) == 0 {
+		return "Resources: no changes"
+	}
+	return "Resources: " + strings.Join(parts, ", ")
+}
+
+// pulumiAddress returns the resource address like "aws:s3/bucket:Bucket::my-bucket" from the URN.
+// The URN is "urn:pulumi:<stack>::<project>::<parent type>$<type>::<name>".
+// The stack resource is excluded and an empty string is returned
+func pulumiAddress(urn string) string {
+	parts := strings.Split(urn, "::")
+	if len(parts) < 4 { //nolint:gomnd
+		return ""
+	}
+	typ := parts[2]
+	if i := strings.LastIndex(typ, "$"); i != -1 {
+		typ = typ[i+1:]
+	}
+	if typ == pulumiStackType {
+		return ""
+	}
+	return typ + "::" + strings.Join(parts[3:], "::")
+}
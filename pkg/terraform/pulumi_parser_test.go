@@ -0,0 +1,149 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+const pulumiPreviewResult = `Previewing update (dev)
+
+View in Browser (Ctrl+O): https://app.pulumi.com/octocat/proj/dev/previews/1
+
+     Type                 Name       Plan       Info
+     pulumi:pulumi:Stack  proj-dev
+ +   ├─ aws:s3:Bucket     foo        create
+ ~   ├─ aws:s3:Bucket     bar        update     [diff: ~tags]
+ -   ├─ aws:s3:Bucket     baz        delete
+ +-  └─ aws:s3:Bucket     qux        replace    [diff: ~bucket]
+
+Resources:
+    + 1 to create
+    ~ 1 to update
+    - 1 to delete
+    +-1 to replace
+    4 changes. 1 unchanged
+`
+
+const pulumiUpFailureResult = `Updating (dev)
+
+     Type                 Name       Status                  Info
+     pulumi:pulumi:Stack  proj-dev   **failed**              1 error
+ +   ├─ aws:s3:Bucket     foo        created (2s)
+ +   └─ aws:s3:Bucket     bar        **creating failed**     1 error
+
+Diagnostics:
+  aws:s3:Bucket (bar):
+    error: creating S3 Bucket (bar): BucketAlreadyExists
+
+Outputs:
+    bucketName: "foo-1234"
+
+Resources:
+    + 1 created
+    1 unchanged
+
+Duration: 5s
+`
+
+const pulumiPreviewJSONResult = `{
+    "steps": [
+        {"op": "same", "urn": "urn:pulumi:dev::proj::pulumi:pulumi:Stack::proj-dev"},
+        {"op": "create", "urn": "urn:pulumi:dev::proj::aws:s3/bucket:Bucket::foo"},
+        {"op": "update", "urn": "urn:pulumi:dev::proj::my:component:Component$aws:s3/bucket:Bucket::bar"}
+    ],
+    "diagnostics": [],
+    "duration": 1234,
+    "changeSummary": {"create": 1, "update": 1, "same": 1}
+}`
+
+const pulumiUpJSONResult = `{"sequence":0,"timestamp":1,"preludeEvent":{"config":{}}}
+{"sequence":1,"timestamp":2,"resOutputsEvent":{"metadata":{"op":"create","urn":"urn:pulumi:dev::proj::aws:s3/bucket:Bucket::foo"}}}
+{"sequence":2,"timestamp":3,"summaryEvent":{"maybeCorrupt":false,"durationSeconds":5,"resourceChanges":{"create":1,"same":1}}}
+`
+
+func TestPulumiParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		command string
+		body    string
+		result  ParseResult
+	}{
+		{
+			name:    "preview",
+			command: CommandPlan,
+			body:    pulumiPreviewResult,
+			result: ParseResult{
+				Result:            "Resources: + 1 to create, ~ 1 to update, - 1 to delete, +-1 to replace, 4 changes. 1 unchanged",
+				HasDestroy:        true,
+				ExitCode:          ExitPass,
+				CreatedResources:  []string{"aws:s3:Bucket::foo"},
+				UpdatedResources:  []string{"aws:s3:Bucket::bar"},
+				DeletedResources:  []string{"aws:s3:Bucket::baz"},
+				ReplacedResources: []string{"aws:s3:Bucket::qux"},
+				DetectedCommand:   CommandPlan,
+			},
+		},
+		{
+			name:    "up failed partially",
+			command: CommandApply,
+			body:    pulumiUpFailureResult,
+			result: ParseResult{
+				Result:           "error: creating S3 Bucket (bar): BucketAlreadyExists",
+				ExitCode:         ExitFail,
+				HasApplyError:    true,
+				AppliedResources: []string{"aws:s3:Bucket::foo"},
+				FailedResources:  []string{"aws:s3:Bucket::bar"},
+				Outputs: map[string]string{
+					"bucketName": `"foo-1234"`,
+				},
+				DetectedCommand: CommandApply,
+			},
+		},
+		{
+			name:    "preview --json",
+			command: CommandPlan,
+			body:    pulumiPreviewJSONResult,
+			result: ParseResult{
+				Result:             "Resources: 1 to create, 1 to update, 1 unchanged",
+				HasAddOrUpdateOnly: true,
+				ExitCode:           ExitPass,
+				CreatedResources:   []string{"aws:s3/bucket:Bucket::foo"},
+				UpdatedResources:   []string{"aws:s3/bucket:Bucket::bar"},
+				DetectedCommand:    CommandPlan,
+			},
+		},
+		{
+			name:    "up --json",
+			command: CommandApply,
+			body:    pulumiUpJSONResult,
+			result: ParseResult{
+				Result:          "Resources: 1 created, 1 unchanged",
+				ExitCode:        ExitPass,
+				DetectedCommand: CommandApply,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewPulumiParser(testCase.command).Parse(testCase.body)
+			if diff := cmp.Diff(testCase.result, result, cmpopts.IgnoreFields(ParseResult{}, "ModuleChanges")); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestPulumiParserParseError(t *testing.T) {
+	t.Parallel()
+	for _, body := range []string{"Previewing update (dev)", `{"steps": [`} {
+		result := NewPulumiParser(CommandPlan).Parse(body)
+		if !result.HasParseError {
+			t.Errorf("the output should be a parse error: %s", body)
+		}
+	}
+}
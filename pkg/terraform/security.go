@@ -0,0 +1,173 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SecuritySeverityCritical is the severity of the most serious misconfigurations
+const SecuritySeverityCritical = "CRITICAL"
+
+// SecurityFinding is a misconfiguration found by a security scanner
+type SecurityFinding struct {
+	ID    string
+	Title string
+	// Severity is one of "CRITICAL", "HIGH", "MEDIUM", "LOW", and "UNKNOWN"
+	Severity string
+	Message  string
+	Resource string
+	Filename string
+	Line     int
+	// Link is the URL of the document of the check. This may be empty
+	Link string
+}
+
+// SecurityScan is the result of a security scanner.
+// The output of `trivy config --format json` and `tfsec --format json` is supported
+type SecurityScan struct {
+	Findings []SecurityFinding
+}
+
+type trivyReport struct {
+	Results []struct {
+		Target            string `json:"Target"`
+		Misconfigurations []struct {
+			ID            string `json:"ID"`
+			Title         string `json:"Title"`
+			Message       string `json:"Message"`
+			Severity      string `json:"Severity"`
+			PrimaryURL    string `json:"PrimaryURL"`
+			Status        string `json:"Status"`
+			CauseMetadata struct {
+				Resource  string `json:"Resource"`
+				StartLine int    `json:"StartLine"`
+			} `json:"CauseMetadata"`
+		} `json:"Misconfigurations"`
+	} `json:"Results"`
+}
+
+type tfsecReport struct {
+	Results []struct {
+		RuleID      string   `json:"rule_id"`
+		Description string   `json:"description"`
+		Severity    string   `json:"severity"`
+		Resource    string   `json:"resource"`
+		Links       []string `json:"links"`
+		Location    struct {
+			Filename  string `json:"filename"`
+			StartLine int    `json:"start_line"`
+		} `json:"location"`
+	} `json:"results"`
+}
+
+// ParseSecurityScan parses the output of trivy or tfsec.
+// The format is detected by the top level key, which is "Results" in trivy and "results" in tfsec
+func ParseSecurityScan(b []byte) (*SecurityScan, error) {
+	keys := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return nil, fmt.Errorf("parse a security scan result as JSON: %w", err)
+	}
+	if results, ok := keys["Results"]; ok {
+		return parseTrivyReport(results)
+	}
+	if results, ok := keys["results"]; ok {
+		return parseTFSecReport(results)
+	}
+	return &SecurityScan{}, nil
+}
+
+func parseTrivyReport(results json.RawMessage) (*SecurityScan, error) {
+	report := trivyReport{}
+	if err := json.Unmarshal(results, &report.Results); err != nil {
+		return nil, fmt.Errorf("parse a result of trivy: %w", err)
+	}
+	scan := &SecurityScan{}
+	for _, result := range report.Results {
+		for _, misconf := range result.Misconfigurations {
+			if misconf.Status != "" && misconf.Status != "FAIL" {
+				// trivy outputs passed checks with --include-non-failures
+				continue
+			}
+			scan.Findings = append(scan.Findings, SecurityFinding{
+				ID:       misconf.ID,
+				Title:    misconf.Title,
+				Severity: strings.ToUpper(misconf.Severity),
+				Message:  misconf.Message,
+				Resource: misconf.CauseMetadata.Resource,
+				Filename: result.Target,
+				Line:     misconf.CauseMetadata.StartLine,
+				Link:     misconf.PrimaryURL,
+			})
+		}
+	}
+	return scan, nil
+}
+
+func parseTFSecReport(results json.RawMessage) (*SecurityScan, error) {
+	report := tfsecReport{}
+	if err := json.Unmarshal(results, &report.Results); err != nil {
+		return nil, fmt.Errorf("parse a result of tfsec: %w", err)
+	}
+	scan := &SecurityScan{}
+	for _, result := range report.Results {
+		finding := SecurityFinding{
+			ID:       result.RuleID,
+			Severity: strings.ToUpper(result.Severity),
+			Message:  result.Description,
+			Resource: result.Resource,
+			Filename: result.Location.Filename,
+			Line:     result.Location.StartLine,
+		}
+		if len(result.Links) != 0 {
+			finding.Link = result.Links[0]
+		}
+		scan.Findings = append(scan.Findings, finding)
+	}
+	return scan, nil
+}
+
+// Entries returns findings. If the result isn't given, nil is returned
+func (scan *SecurityScan) Entries() []SecurityFinding {
+	if scan == nil {
+		return nil
+	}
+	return scan.Findings
+}
+
+// Summary returns the numbers of findings per severity like "CRITICAL: 1, HIGH: 2, MEDIUM: 0, LOW: 0".
+// If the result isn't given, an empty string is returned
+func (scan *SecurityScan) Summary() string {
+	if scan == nil {
+		return ""
+	}
+	if len(scan.Findings) == 0 {
+		return "No misconfigurations are found"
+	}
+	severities := []string{SecuritySeverityCritical, "HIGH", "MEDIUM", "LOW"}
+	counts := make(map[string]int, len(severities))
+	for _, finding := range scan.Findings {
+		counts[finding.Severity]++
+	}
+	arr := make([]string, 0, len(severities)+1)
+	known := 0
+	for _, severity := range severities {
+		arr = append(arr, fmt.Sprintf("%s: %d", severity, counts[severity]))
+		known += counts[severity]
+	}
+	if unknown := len(scan.Findings) - known; unknown != 0 {
+		arr = append(arr, fmt.Sprintf("UNKNOWN: %d", unknown))
+	}
+	return strings.Join(arr, ", ")
+}
+
+// CountCritical returns the number of critical findings
+func (scan *SecurityScan) CountCritical() int {
+	cnt := 0
+	for _, finding := range scan.Entries() {
+		if finding.Severity == SecuritySeverityCritical {
+			cnt++
+		}
+	}
+	return cnt
+}
@@ -0,0 +1,133 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const trivyResult = `{
+  "SchemaVersion": 2,
+  "ArtifactName": ".",
+  "ArtifactType": "filesystem",
+  "Results": [
+    {
+      "Target": "main.tf",
+      "Class": "config",
+      "Type": "terraform",
+      "MisconfSummary": {"Successes": 1, "Failures": 1},
+      "Misconfigurations": [
+        {
+          "Type": "Terraform Security Check",
+          "ID": "AVD-AWS-0086",
+          "Title": "S3 Access block should block public ACL",
+          "Message": "No public access block so not blocking public acls",
+          "Severity": "HIGH",
+          "PrimaryURL": "https://avd.aquasec.com/misconfig/avd-aws-0086",
+          "Status": "FAIL",
+          "CauseMetadata": {"Resource": "aws_s3_bucket.foo", "Provider": "AWS", "StartLine": 1, "EndLine": 3}
+        },
+        {
+          "ID": "AVD-AWS-0088",
+          "Severity": "HIGH",
+          "Status": "PASS"
+        }
+      ]
+    }
+  ]
+}`
+
+const tfsecResult = `{
+  "results": [
+    {
+      "rule_id": "AVD-AWS-0057",
+      "long_id": "aws-iam-no-policy-wildcards",
+      "description": "IAM policy document uses wildcarded action 's3:*'",
+      "severity": "CRITICAL",
+      "resource": "aws_iam_policy.foo",
+      "links": ["https://aquasecurity.github.io/tfsec/latest/checks/aws/iam/no-policy-wildcards/"],
+      "location": {"filename": "/work/iam.tf", "start_line": 5, "end_line": 10}
+    }
+  ]
+}`
+
+func TestParseSecurityScan(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		body     string
+		findings []SecurityFinding
+		summary  string
+		critical int
+	}{
+		{
+			name: "trivy",
+			body: trivyResult,
+			findings: []SecurityFinding{
+				{
+					ID:       "AVD-AWS-0086",
+					Title:    "S3 Access block should block public ACL",
+					Severity: "HIGH",
+					Message:  "No public access block so not blocking public acls",
+					Resource: "aws_s3_bucket.foo",
+					Filename: "main.tf",
+					Line:     1,
+					Link:     "https://avd.aquasec.com/misconfig/avd-aws-0086",
+				},
+			},
+			summary: "CRITICAL: 0, HIGH: 1, MEDIUM: 0, LOW: 0",
+		},
+		{
+			name: "tfsec",
+			body: tfsecResult,
+			findings: []SecurityFinding{
+				{
+					ID:       "AVD-AWS-0057",
+					Severity: "CRITICAL",
+					Message:  "IAM policy document uses wildcarded action 's3:*'",
+					Resource: "aws_iam_policy.foo",
+					Filename: "/work/iam.tf",
+					Line:     5,
+					Link:     "https://aquasecurity.github.io/tfsec/latest/checks/aws/iam/no-policy-wildcards/",
+				},
+			},
+			summary:  "CRITICAL: 1, HIGH: 0, MEDIUM: 0, LOW: 0",
+			critical: 1,
+		},
+		{
+			name:    "no finding",
+			body:    `{"SchemaVersion": 2, "ArtifactName": "."}`,
+			summary: "No misconfigurations are found",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			scan, err := ParseSecurityScan([]byte(testCase.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(testCase.findings, scan.Entries()); diff != "" {
+				t.Error(diff)
+			}
+			if summary := scan.Summary(); summary != testCase.summary {
+				t.Errorf("got %q but want %q", summary, testCase.summary)
+			}
+			if cnt := scan.CountCritical(); cnt != testCase.critical {
+				t.Errorf("got %d but want %d", cnt, testCase.critical)
+			}
+		})
+	}
+}
+
+func TestSecurityScanNotGiven(t *testing.T) {
+	t.Parallel()
+	var scan *SecurityScan
+	if summary := scan.Summary(); summary != "" {
+		t.Errorf("got %q but want an empty string", summary)
+	}
+	if cnt := scan.CountCritical(); cnt != 0 {
+		t.Errorf("got %d but want 0", cnt)
+	}
+}
@@ -0,0 +1,98 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SourceLocation is the location of the configuration block of a resource.
+// File is the path from the root directory of the repository
+type SourceLocation struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// SourceMap maps resource addresses without instance keys like `module.foo.aws_instance.bar` to their source locations
+type SourceMap map[string]SourceLocation
+
+// ParseSourceMap parses a source map.
+// The format is a JSON object whose keys are resource addresses, e.g. `{"aws_instance.foo": {"file": "main.tf", "line": 10}}`
+func ParseSourceMap(b []byte) (SourceMap, error) {
+	m := SourceMap{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("parse a source map as JSON: %w", err)
+	}
+	return m, nil
+}
+
+// Find returns the source location of the resource.
+// Instance keys like `[0]` and `["foo"]` of the address are ignored
+func (m SourceMap) Find(address string) (SourceLocation, bool) {
+	if loc, ok := m[address]; ok {
+		return loc, true
+	}
+	loc, ok := m[trimInstanceKeys(address)]
+	return loc, ok
+}
+
+// BlobURLs returns URLs of the source locations of resources.
+// repoURL is the URL of the repository like https://github.com/suzuki-shunsuke/tfcmt
+func (m SourceMap) BlobURLs(repoURL, revision string, addresses ...[]string) map[string]string {
+	if len(m) == 0 || revision == "" {
+		return nil
+	}
+	urls := map[string]string{}
+	for _, arr := range addresses {
+		for _, address := range arr {
+			loc, ok := m.Find(address)
+			if !ok || loc.File == "" {
+				continue
+			}
+			u := repoURL + "/blob/" + revision + "/" + strings.TrimPrefix(loc.File, "/")
+			if loc.Line > 0 {
+				u += "#L" + strconv.Itoa(loc.Line)
+			}
+			urls[address] = u
+		}
+	}
+	return urls
+}
+
+// trimInstanceKeys removes instance keys from the address.
+// e.g. `module.foo["a"].aws_instance.bar[0]` => `module.foo.aws_instance.bar`
+func trimInstanceKeys(address string) string {
+	var b strings.Builder
+	depth := 0
+	inString := false
+	for i := 0; i < len(address); i++ {
+		c := address[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"' && depth > 0:
+			inString = true
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// linkResource renders the resource address as a Markdown link to the source location.
+// If the URL of the resource isn't found, the address is rendered as plain text
+func linkResource(urls map[string]string, address string) string {
+	if u, ok := urls[address]; ok {
+		return "[" + escapeMarkdown(address) + "](" + u + ")"
+	}
+	return escapeMarkdown(address)
+}
@@ -0,0 +1,81 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTrimInstanceKeys(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		address string
+		exp     string
+	}{
+		{
+			name:    "no instance key",
+			address: "module.foo.aws_instance.bar",
+			exp:     "module.foo.aws_instance.bar",
+		},
+		{
+			name:    "count",
+			address: "aws_instance.bar[0]",
+			exp:     "aws_instance.bar",
+		},
+		{
+			name:    "for_each",
+			address: `module.foo["a.b]"].aws_instance.bar["c\"d"]`,
+			exp:     "module.foo.aws_instance.bar",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if a := trimInstanceKeys(testCase.address); a != testCase.exp {
+				t.Errorf("got %q, wanted %q", a, testCase.exp)
+			}
+		})
+	}
+}
+
+func TestSourceMapBlobURLs(t *testing.T) {
+	t.Parallel()
+	m, err := ParseSourceMap([]byte(`{
+  "aws_instance.foo": {"file": "main.tf", "line": 10},
+  "module.bar.aws_instance.baz": {"file": "/modules/bar/main.tf"}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name     string
+		revision string
+		exp      map[string]string
+	}{
+		{
+			name:     "normal",
+			revision: "abcd",
+			exp: map[string]string{
+				"aws_instance.foo[0]":         "https://github.com/suzuki-shunsuke/tfcmt/blob/abcd/main.tf#L10",
+				"module.bar.aws_instance.baz": "https://github.com/suzuki-shunsuke/tfcmt/blob/abcd/modules/bar/main.tf",
+			},
+		},
+		{
+			name: "no revision",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			urls := m.BlobURLs("https://github.com/suzuki-shunsuke/tfcmt", testCase.revision,
+				[]string{"aws_instance.foo[0]", "null_resource.unknown"},
+				[]string{"module.bar.aws_instance.baz"})
+			if diff := cmp.Diff(testCase.exp, urls); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -16,9 +16,12 @@ const (
 
 {{if .Link}}[CI link]({{.Link}}){{end}}
 
-{{if .HasDestroy}}{{template "deletion_warning" .}}{{end}}
-{{template "result" .}}
-{{template "updated_resources" .}}
+{{if .HasDestroy}}{{template "deletion_warning" .}}{{end}}{{if .ReplacedResources}}
+
+{{template "replacement_warning" .}}
+{{end}}
+{{template "result" .}}{{template "error_category" .}}
+{{template "updated_resources" .}}{{template "moved_resources" .}}{{template "change_outside_terraform" .}}{{template "failed_checks" .}}{{template "lint" .}}{{template "security_findings" .}}{{template "policy_violations" .}}{{template "checkov" .}}{{template "artifacts" .}}
 <details><summary>Details (Click me)</summary>
 {{wrapCode .CombinedOutput}}
 </details>
@@ -34,7 +37,7 @@ const (
 
 {{if .Link}}[CI link]({{.Link}}){{end}}
 
-{{template "result" .}}
+{{template "result" .}}{{template "error_category" .}}{{template "partial_apply" .}}{{template "failed_checks" .}}{{template "artifacts" .}}
 
 <details><summary>Details (Click me)</summary>
 {{wrapCode .CombinedOutput}}
@@ -52,7 +55,14 @@ const (
 {{if .Link}}[CI link]({{.Link}}){{end}}
 
 It failed to parse the result.
-
+{{if .ParseErrorMessage}}
+:warning: {{.ParseErrorMessage}}
+{{end}}{{if .ErrorCategory}}
+Error category: {{.ErrorCategory}}
+{{end}}{{if .CombinedOutputTail}}
+The last lines of the output:
+{{wrapCode .CombinedOutputTail}}
+{{end}}
 <details><summary>Details (Click me)</summary>
 {{wrapCode .CombinedOutput}}
 </details>
@@ -65,11 +75,263 @@ It failed to parse the result.
 {{if .Link}}[CI link]({{.Link}}){{end}}
 
 It failed to parse the result.
+{{if .ParseErrorMessage}}
+:warning: {{.ParseErrorMessage}}
+{{end}}{{if .ErrorCategory}}
+Error category: {{.ErrorCategory}}
+{{end}}{{if .CombinedOutputTail}}
+The last lines of the output:
+{{wrapCode .CombinedOutputTail}}
+{{end}}
+<details><summary>Details (Click me)</summary>
+{{wrapCode .CombinedOutput}}
+</details>
+`
+
+	// DefaultTerragruntPlanTemplate is a default template for terragrunt run-all plan. The result of each module is rendered as a section
+	DefaultTerragruntPlanTemplate = `
+{{template "plan_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{if .HasDestroy}}{{template "deletion_warning" .}}{{end}}{{if .ReplacedResources}}
+
+{{template "replacement_warning" .}}
+{{end}}
+{{template "result" .}}
+{{template "terragrunt_modules" .}}
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultMultiDirPlanTemplate is a default template for terraform plan run in multiple directories.
+	// The results are rendered as a summary table and a collapsible section per directory
+	DefaultMultiDirPlanTemplate = `
+{{template "plan_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{if .HasDestroy}}{{template "deletion_warning" .}}{{end}}{{if .ReplacedResources}}
 
+{{template "replacement_warning" .}}
+{{end}}
+{{template "result" .}}
+{{template "targets_table" .}}
+{{template "target_sections" .}}
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultPulumiPreviewTemplate is a default template for pulumi preview
+	DefaultPulumiPreviewTemplate = `
+## {{if eq .ExitCode 1}}:x: {{end}}Preview Result{{if .Vars.target}} ({{.Vars.target}}){{end}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{if .HasDestroy}}{{template "deletion_warning" .}}{{end}}{{if .ReplacedResources}}
+
+{{template "replacement_warning" .}}
+{{end}}
+{{template "result" .}}
+{{template "updated_resources" .}}
+<details><summary>Details (Click me)</summary>
+
+<pre><code>{{.CombinedOutput}}</code></pre>
+</details>
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultPulumiUpTemplate is a default template for pulumi up
+	DefaultPulumiUpTemplate = `
+## :{{if eq .ExitCode 0}}white_check_mark{{else}}x{{end}}: Update Result{{if .Vars.target}} ({{.Vars.target}}){{end}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{template "result" .}}{{template "partial_apply" .}}
+
+<details><summary>Details (Click me)</summary>
+
+<pre><code>{{.CombinedOutput}}</code></pre>
+</details>
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultRefreshOnlyPlanTemplate is a default template for terraform plan -refresh-only
+	DefaultRefreshOnlyPlanTemplate = `
+## {{if eq .ExitCode 1}}:x: {{end}}Refresh-only Plan Result{{if .Vars.target}} ({{.Vars.target}}){{end}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{template "result" .}}
+{{template "drift_summary" .}}
+<details><summary>Details (Click me)</summary>
+{{wrapCode .CombinedOutput}}
+</details>
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultTestTemplate is a default template for terraform test
+	DefaultTestTemplate = `
+{{template "test_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{template "result" .}}{{template "failed_tests" .}}{{template "failed_checks" .}}
+
+<details><summary>Details (Click me)</summary>
+{{wrapCode .CombinedOutput}}
+</details>
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultTestParseErrorTemplate is a default template for terraform test parse error
+	DefaultTestParseErrorTemplate = `
+{{template "test_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+It failed to parse the result.
+{{if .ParseErrorMessage}}
+:warning: {{.ParseErrorMessage}}
+{{end}}{{if .CombinedOutputTail}}
+The last lines of the output:
+{{wrapCode .CombinedOutputTail}}
+{{end}}
+<details><summary>Details (Click me)</summary>
+{{wrapCode .CombinedOutput}}
+</details>
+`
+
+	// DefaultDestroyTemplate is a default template for terraform destroy
+	DefaultDestroyTemplate = `
+{{template "destroy_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{template "result" .}}{{template "destroyed_resources" .}}{{template "partial_destroy" .}}{{template "failed_checks" .}}{{template "artifacts" .}}
+
+<details><summary>Details (Click me)</summary>
+{{wrapCode .CombinedOutput}}
+</details>
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultDestroyParseErrorTemplate is a default template for terraform destroy parse error
+	DefaultDestroyParseErrorTemplate = `
+{{template "destroy_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+It failed to parse the result.
+{{if .ParseErrorMessage}}
+:warning: {{.ParseErrorMessage}}
+{{end}}{{if .CombinedOutputTail}}
+The last lines of the output:
+{{wrapCode .CombinedOutputTail}}
+{{end}}
+<details><summary>Details (Click me)</summary>
+{{wrapCode .CombinedOutput}}
+</details>
+`
+
+	// DefaultValidateTemplate is a default template for terraform validate
+	DefaultValidateTemplate = `
+{{template "validate_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{template "result" .}}{{template "diagnostics" .}}
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultValidateParseErrorTemplate is a default template for terraform validate parse error
+	DefaultValidateParseErrorTemplate = `
+{{template "validate_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+It failed to parse the result.
+{{if .ParseErrorMessage}}
+:warning: {{.ParseErrorMessage}}
+{{end}}{{if .CombinedOutputTail}}
+The last lines of the output:
+{{wrapCode .CombinedOutputTail}}
+{{end}}
 <details><summary>Details (Click me)</summary>
 {{wrapCode .CombinedOutput}}
 </details>
 `
+
+	// DefaultFmtTemplate is a default template for terraform fmt -check -diff
+	DefaultFmtTemplate = `
+{{template "fmt_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{template "result" .}}{{template "unformatted_files" .}}
+{{if .CombinedOutput}}
+<details><summary>Details (Click me)</summary>
+
+<pre><code>{{.CombinedOutput}}</code></pre>
+</details>
+{{end}}{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultPlanGistTemplate is a compact template for terraform plan whose result is uploaded to a Gist because it is too large
+	DefaultPlanGistTemplate = `
+{{template "plan_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{template "gist_summary" .}}{{if .ReplacedResources}}
+
+{{template "replacement_warning" .}}
+{{end}}
+{{template "result" .}}{{template "error_category" .}}
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
+
+	// DefaultApplyGistTemplate is a compact template for terraform apply whose result is uploaded to a Gist because it is too large
+	DefaultApplyGistTemplate = `
+{{template "apply_title" .}}
+
+{{if .Link}}[CI link]({{.Link}}){{end}}
+
+{{template "result" .}}{{template "error_category" .}}
+{{template "gist_summary" .}}
+{{if .ErrorMessages}}
+## :warning: Errors
+{{range .ErrorMessages}}
+* {{. -}}
+{{- end}}{{end}}`
 )
 
 // CommonTemplate represents template entities
@@ -80,18 +342,85 @@ type CommonTemplate struct {
 	Warning                string
 	Link                   string
 	UseRawOutput           bool
+	MaxResources           int
 	HasDestroy             bool
+	HasChanges             bool
+	Succeeded              bool
 	Vars                   map[string]string
 	Templates              map[string]string
 	Stdout                 string
 	Stderr                 string
 	CombinedOutput         string
-	ExitCode               int
-	ErrorMessages          []string
-	CreatedResources       []string
-	UpdatedResources       []string
-	DeletedResources       []string
-	ReplacedResources      []string
+	// ParseErrorMessage, CombinedOutputHead, and CombinedOutputTail are set only if tfcmt fails to parse the output
+	ParseErrorMessage  string
+	CombinedOutputHead string
+	CombinedOutputTail string
+	ExitCode           int
+	ErrorMessages      []string
+	CreatedResources   []string
+	UpdatedResources   []string
+	DeletedResources   []string
+	ReplacedResources  []string
+	ModuleChanges      []ModuleChanges
+	DriftedResources   []string
+	CostDelta          string
+	CostBreakdown      []CostBreakdownEntry
+	CostResources      []CostResourceEntry
+	HasApplyError      bool
+	AppliedResources   []string
+	FailedResources    []string
+	Outputs            map[string]string
+	Env                map[string]string
+	// ResourceURLs maps resource addresses to URLs of their source locations
+	ResourceURLs map[string]string
+	// GistURL is the URL of the Gist where the whole result is uploaded. This is set only in the compact template
+	GistURL string
+	// RunURL, JobURL, RunAttempt, and Actor are the context of the CI run. They are empty if the CI platform doesn't provide them
+	RunURL     string
+	JobURL     string
+	RunAttempt string
+	Actor      string
+	// ResultLabel is the label of the plan result added to the pull request. This is empty if labels are disabled
+	ResultLabel string
+	// ErrorCategory is the category of the error of plan or apply such as "state_lock"
+	ErrorCategory string
+	// Product is the product which outputs the result, either ProductTerraform, ProductOpenTofu, or ProductPulumi. The default value is ProductTerraform
+	Product string
+	// TerragruntModules is the result of each module of terragrunt run-all plan. This is empty if terragrunt_run_all is disabled
+	TerragruntModules []TerragruntModule
+	// MovedResources is a list of resources moved by moved blocks. Each element has Before and After
+	MovedResources []MovedResource
+	// ImportedResources is a list of resources imported by import blocks
+	ImportedResources []string
+	// IsRefreshOnly is true if the plan is of terraform plan -refresh-only
+	IsRefreshOnly bool
+	// Checks is a list of failed assertions of check blocks and failed preconditions and postconditions
+	Checks []Check
+	// TestPassed, TestFailed, TestSkipped, and FailedTests are the result of terraform test
+	TestPassed  int
+	TestFailed  int
+	TestSkipped int
+	FailedTests []string
+	// Diagnostics is a list of errors and warnings of terraform validate
+	Diagnostics []Diagnostic
+	// UnformattedFiles is a list of files which aren't formatted by terraform fmt
+	UnformattedFiles []string
+	// LintSummary and LintIssues are the result of tflint. They are empty if the result of tflint isn't given
+	LintSummary string
+	LintIssues  []LintIssue
+	// SecuritySummary and SecurityFindings are the result of a security scanner such as trivy.
+	// They are empty if the result isn't given
+	SecuritySummary  string
+	SecurityFindings []SecurityFinding
+	// PolicySummary and PolicyViolations are the result of policies evaluated by conftest. They are empty if policies aren't evaluated
+	PolicySummary    string
+	PolicyViolations []PolicyViolation
+	// CheckovResources are failed checks of Checkov grouped by the resource address. This is empty if the report isn't given
+	CheckovResources []CheckovResource
+	// ArtifactURL and PlanJSONURL are the URLs of the output and the plan JSON uploaded to S3 or GCS.
+	// They are empty if the storage isn't configured
+	ArtifactURL string
+	PlanJSONURL string
 }
 
 // Template is a default template for terraform commands
@@ -120,6 +449,76 @@ func NewApplyTemplate(template string) *Template {
 	}
 }
 
+// NewTestTemplate is the initializer of the template for terraform test
+func NewTestTemplate(template string) *Template {
+	if template == "" {
+		template = DefaultTestTemplate
+	}
+	return &Template{
+		Template: template,
+	}
+}
+
+// NewTestParseErrorTemplate is the initializer of the template for terraform test parse error
+func NewTestParseErrorTemplate(template string) *Template {
+	if template == "" {
+		template = DefaultTestParseErrorTemplate
+	}
+	return &Template{
+		Template: template,
+	}
+}
+
+// NewDestroyTemplate is the initializer of the template for terraform destroy
+func NewDestroyTemplate(template string) *Template {
+	if template == "" {
+		template = DefaultDestroyTemplate
+	}
+	return &Template{
+		Template: template,
+	}
+}
+
+// NewDestroyParseErrorTemplate is the initializer of the template for terraform destroy parse error
+func NewDestroyParseErrorTemplate(template string) *Template {
+	if template == "" {
+		template = DefaultDestroyParseErrorTemplate
+	}
+	return &Template{
+		Template: template,
+	}
+}
+
+// NewValidateTemplate is the initializer of the template for terraform validate
+func NewValidateTemplate(template string) *Template {
+	if template == "" {
+		template = DefaultValidateTemplate
+	}
+	return &Template{
+		Template: template,
+	}
+}
+
+// NewValidateParseErrorTemplate is the initializer of the template for terraform validate parse error
+func NewValidateParseErrorTemplate(template string) *Template {
+	if template == "" {
+		template = DefaultValidateParseErrorTemplate
+	}
+	return &Template{
+		Template: template,
+	}
+}
+
+// NewFmtTemplate is the initializer of the template for terraform fmt
+func NewFmtTemplate(template string) *Template {
+	if template == "" {
+		template = DefaultFmtTemplate
+	}
+	return &Template{
+		Template: template,
+	}
+}
+
 func NewPlanParseErrorTemplate(template string) *Template {
 	if template == "" {
 		template = DefaultPlanParseErrorTemplate
@@ -138,6 +537,26 @@ func NewApplyParseErrorTemplate(template string) *Template {
 	}
 }
 
+// NewSummaryTemplate returns the template of the summary which is rendered apart from the main body
+func NewSummaryTemplate() *Template {
+	return &Template{
+		Template: `{{template "summary" .}}`,
+	}
+}
+
+// NewGistTemplate returns the compact template for the result uploaded to a Gist
+func NewGistTemplate(template string, isPlan bool) *Template {
+	if template == "" {
+		template = DefaultApplyGistTemplate
+		if isPlan {
+			template = DefaultPlanGistTemplate
+		}
+	}
+	return &Template{
+		Template: template,
+	}
+}
+
 func avoidHTMLEscape(text string) htmltemplate.HTML {
 	return htmltemplate.HTML(text) //nolint:gosec
 }
@@ -149,13 +568,83 @@ func wrapCode(text string) interface{} {
 	return htmltemplate.HTML("\n```hcl\n" + text + "\n```\n") //nolint:gosec
 }
 
-func generateOutput(kind, template string, data map[string]interface{}, useRawOutput bool) (string, error) {
+// escapeMarkdown escapes characters which break Markdown formatting in resource addresses like `module.x["a_b*c"]`.
+// An underscore between alphanumeric characters like `aws_instance` isn't escaped because it never becomes emphasis on GitHub.
+func escapeMarkdown(text string) string {
+	var b strings.Builder
+	for i, c := range text {
+		switch c {
+		case '\\', '`', '*', '[', ']', '~', '|':
+			b.WriteRune('\\')
+		case '_':
+			if !isAlnum(text, i-1) || !isAlnum(text, i+1) {
+				b.WriteRune('\\')
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func isAlnum(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return false
+	}
+	c := text[i]
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// diffResources renders changed resources in a diff code block, which GitHub renders with colors.
+// Created resources are prefixed with "+", updated ones with "~", deleted ones with "-", and replaced ones with "-/+"
+func diffResources(created, updated, deleted, replaced []string) interface{} {
+	if len(created)+len(updated)+len(deleted)+len(replaced) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(created)+len(updated)+len(deleted)+len(replaced))
+	for _, group := range []struct {
+		prefix    string
+		resources []string
+	}{
+		{"+ ", created},
+		{"~ ", updated},
+		{"- ", deleted},
+		{"-/+ ", replaced},
+	} {
+		for _, rsc := range group.resources {
+			lines = append(lines, group.prefix+rsc)
+		}
+	}
+	return htmltemplate.HTML("\n```diff\n" + strings.Join(lines, "\n") + "\n```\n") //nolint:gosec
+}
+
+// limitResources returns the first max resources. If max isn't positive, all resources are returned
+func limitResources(resources []string, max int) []string {
+	if max <= 0 || len(resources) <= max {
+		return resources
+	}
+	return resources[:max]
+}
+
+// moreResources returns the number of resources which are omitted by limitResources
+func moreResources(resources []string, max int) int {
+	if max <= 0 || len(resources) <= max {
+		return 0
+	}
+	return len(resources) - max
+}
+
+func generateOutput(kind, template string, data interface{}, useRawOutput bool) (string, error) {
 	var b bytes.Buffer
 
 	if useRawOutput {
 		tpl, err := texttemplate.New(kind).Funcs(texttemplate.FuncMap{
 			"avoidHTMLEscape": avoidHTMLEscape,
 			"wrapCode":        wrapCode,
+			"escapeMarkdown":  escapeMarkdown,
+			"diffResources":   diffResources,
+			"limitResources":  limitResources,
+			"moreResources":   moreResources,
+			"linkResource":    linkResource,
 		}).Funcs(sprig.TxtFuncMap()).Parse(template)
 		if err != nil {
 			return "", err
@@ -167,6 +656,11 @@ func generateOutput(kind, template string, data map[string]interface{}, useRawOu
 		tpl, err := htmltemplate.New(kind).Funcs(htmltemplate.FuncMap{
 			"avoidHTMLEscape": avoidHTMLEscape,
 			"wrapCode":        wrapCode,
+			"escapeMarkdown":  escapeMarkdown,
+			"diffResources":   diffResources,
+			"limitResources":  limitResources,
+			"moreResources":   moreResources,
+			"linkResource":    linkResource,
 		}).Funcs(sprig.FuncMap()).Parse(template)
 		if err != nil {
 			return "", err
@@ -181,16 +675,20 @@ func generateOutput(kind, template string, data map[string]interface{}, useRawOu
 
 // Execute binds the execution result of terraform command into template
 func (t *Template) Execute() (string, error) {
-	data := map[string]interface{}{
+	return t.execute(map[string]interface{}{
 		"Result":                 t.Result,
 		"ChangedResult":          t.ChangedResult,
 		"ChangeOutsideTerraform": t.ChangeOutsideTerraform,
 		"Warning":                t.Warning,
 		"Link":                   t.Link,
+		"MaxResources":           t.MaxResources,
 		"Vars":                   t.Vars,
 		"Stdout":                 t.Stdout,
 		"Stderr":                 t.Stderr,
 		"CombinedOutput":         t.CombinedOutput,
+		"ParseErrorMessage":      t.ParseErrorMessage,
+		"CombinedOutputHead":     t.CombinedOutputHead,
+		"CombinedOutputTail":     t.CombinedOutputTail,
 		"ExitCode":               t.ExitCode,
 		"ErrorMessages":          t.ErrorMessages,
 		"CreatedResources":       t.CreatedResources,
@@ -198,29 +696,326 @@ func (t *Template) Execute() (string, error) {
 		"DeletedResources":       t.DeletedResources,
 		"ReplacedResources":      t.ReplacedResources,
 		"HasDestroy":             t.HasDestroy,
+		"HasChanges":             t.HasChanges,
+		"Succeeded":              t.Succeeded,
+		"ModuleChanges":          t.ModuleChanges,
+		"DriftedResources":       t.DriftedResources,
+		"CostDelta":              t.CostDelta,
+		"CostBreakdown":          t.CostBreakdown,
+		"CostResources":          t.CostResources,
+		"HasApplyError":          t.HasApplyError,
+		"AppliedResources":       t.AppliedResources,
+		"FailedResources":        t.FailedResources,
+		"Outputs":                t.Outputs,
+		"ResourceURLs":           t.ResourceURLs,
+		"GistURL":                t.GistURL,
+		"RunURL":                 t.RunURL,
+		"JobURL":                 t.JobURL,
+		"RunAttempt":             t.RunAttempt,
+		"Actor":                  t.Actor,
+		"ResultLabel":            t.ResultLabel,
+		"ErrorCategory":          t.ErrorCategory,
+		"Env":                    t.Env,
+		"Product":                t.product(),
+		"TerragruntModules":      t.TerragruntModules,
+		"MovedResources":         t.MovedResources,
+		"ImportedResources":      t.ImportedResources,
+		"IsRefreshOnly":          t.IsRefreshOnly,
+		"Checks":                 t.Checks,
+		"TestPassed":             t.TestPassed,
+		"TestFailed":             t.TestFailed,
+		"TestSkipped":            t.TestSkipped,
+		"FailedTests":            t.FailedTests,
+		"Diagnostics":            t.Diagnostics,
+		"UnformattedFiles":       t.UnformattedFiles,
+		"LintSummary":            t.LintSummary,
+		"LintIssues":             t.LintIssues,
+		"SecuritySummary":        t.SecuritySummary,
+		"SecurityFindings":       t.SecurityFindings,
+		"PolicySummary":          t.PolicySummary,
+		"PolicyViolations":       t.PolicyViolations,
+		"CheckovResources":       t.CheckovResources,
+		"ArtifactURL":            t.ArtifactURL,
+		"PlanJSONURL":            t.PlanJSONURL,
+	})
+}
+
+func (t *Template) product() string {
+	if t.Product == "" {
+		return ProductTerraform
 	}
+	return t.Product
+}
 
+// execute renders the template with data. data is either the map of template entities or CommonTemplate
+func (t *Template) execute(data interface{}) (string, error) {
 	templates := map[string]string{
 		"plan_title":  "## {{if eq .ExitCode 1}}:x: {{end}}Plan Result{{if .Vars.target}} ({{.Vars.target}}){{end}}",
 		"apply_title": "## :{{if eq .ExitCode 0}}white_check_mark{{else}}x{{end}}: Apply Result{{if .Vars.target}} ({{.Vars.target}}){{end}}",
+		"test_title":  "## :{{if eq .ExitCode 0}}white_check_mark{{else}}x{{end}}: Test Result{{if .Vars.target}} ({{.Vars.target}}){{end}}",
 		"result":      "{{if .Result}}<pre><code>{{ .Result }}</code></pre>{{end}}",
+		"error_category": `{{if .ErrorCategory}}
+
+Error category: {{.ErrorCategory}}{{end}}`,
 		"updated_resources": `{{if .CreatedResources}}
 * Create
+{{- range limitResources .CreatedResources .MaxResources}}
+  * {{linkResource $.ResourceURLs .}}
+{{- end}}{{with moreResources .CreatedResources .MaxResources}}
+  * ... and {{.}} more{{end}}{{end}}{{if .UpdatedResources}}
+* Update
+{{- range limitResources .UpdatedResources .MaxResources}}
+  * {{linkResource $.ResourceURLs .}}
+{{- end}}{{with moreResources .UpdatedResources .MaxResources}}
+  * ... and {{.}} more{{end}}{{end}}{{if .DeletedResources}}
+* Delete
+{{- range limitResources .DeletedResources .MaxResources}}
+  * {{linkResource $.ResourceURLs .}}
+{{- end}}{{with moreResources .DeletedResources .MaxResources}}
+  * ... and {{.}} more{{end}}{{end}}{{if .ReplacedResources}}
+* Replace
+{{- range limitResources .ReplacedResources .MaxResources}}
+  * {{linkResource $.ResourceURLs .}}
+{{- end}}{{with moreResources .ReplacedResources .MaxResources}}
+  * ... and {{.}} more{{end}}{{end}}{{if .ImportedResources}}
+* Import
+{{- range limitResources .ImportedResources .MaxResources}}
+  * {{linkResource $.ResourceURLs .}}
+{{- end}}{{with moreResources .ImportedResources .MaxResources}}
+  * ... and {{.}} more{{end}}{{end}}`,
+		"moved_resources": `{{if .MovedResources}}
+* Move
+{{- range .MovedResources}}
+  * {{escapeMarkdown .Before}} -> {{escapeMarkdown .After}}
+{{- end}}{{end}}`,
+		"updated_resources_diff": `{{diffResources .CreatedResources .UpdatedResources .DeletedResources .ReplacedResources}}`,
+		"module_changes": `{{range .ModuleChanges}}
+<details><summary>{{escapeMarkdown .Module}} ({{len .CreatedResources}} to add, {{len .UpdatedResources}} to change, {{len .DeletedResources}} to destroy, {{len .ReplacedResources}} to replace)</summary>
+{{range .CreatedResources}}
+* Create {{escapeMarkdown .}}
+{{- end}}{{range .UpdatedResources}}
+* Update {{escapeMarkdown .}}
+{{- end}}{{range .DeletedResources}}
+* Delete {{escapeMarkdown .}}
+{{- end}}{{range .ReplacedResources}}
+* Replace {{escapeMarkdown .}}
+{{- end}}
+
+</details>
+{{end}}`,
+		"terragrunt_modules": `{{range .TerragruntModules}}
+### {{if .HasPlanError}}:x: {{end}}{{escapeMarkdown .Path}}
+
+{{if .Result}}<pre><code>{{ .Result }}</code></pre>{{end}}
+{{- if .CreatedResources}}
+* Create
+{{- range .CreatedResources}}
+  * {{escapeMarkdown .}}
+{{- end}}{{end}}{{if .UpdatedResources}}
+* Update
+{{- range .UpdatedResources}}
+  * {{escapeMarkdown .}}
+{{- end}}{{end}}{{if .DeletedResources}}
+* Delete
+{{- range .DeletedResources}}
+  * {{escapeMarkdown .}}
+{{- end}}{{end}}{{if .ReplacedResources}}
+* Replace
+{{- range .ReplacedResources}}
+  * {{escapeMarkdown .}}
+{{- end}}{{end}}{{if .ChangedResult}}
+
+<details><summary>Details (Click me)</summary>
+{{wrapCode .ChangedResult}}
+</details>{{end}}
+{{end}}`,
+		"targets_table": `{{if .TerragruntModules}}
+| Target | Add | Change | Destroy | Replace |
+|--------|-----|--------|---------|---------|
+{{- range .TerragruntModules}}
+| {{if .HasPlanError}}:x: {{else if .HasDestroy}}:warning: {{end}}{{escapeMarkdown .Path}} | {{if .HasPlanError}}-{{else}}{{len .CreatedResources}}{{end}} | {{if .HasPlanError}}-{{else}}{{len .UpdatedResources}}{{end}} | {{if .HasPlanError}}-{{else}}{{len .DeletedResources}}{{end}} | {{if .HasPlanError}}-{{else}}{{len .ReplacedResources}}{{end}} |
+{{- end}}
+{{end}}`,
+		"target_sections": `{{range .TerragruntModules}}
+<details><summary>{{if .HasPlanError}}:x: {{end}}{{escapeMarkdown .Path}}{{if .HasNoChanges}} (No changes){{end}}</summary>
+
+{{if .Result}}<pre><code>{{ .Result }}</code></pre>{{end}}
+{{- if .CreatedResources}}
+* Create
 {{- range .CreatedResources}}
-  * {{.}}
+  * {{escapeMarkdown .}}
 {{- end}}{{end}}{{if .UpdatedResources}}
 * Update
 {{- range .UpdatedResources}}
-  * {{.}}
+  * {{escapeMarkdown .}}
 {{- end}}{{end}}{{if .DeletedResources}}
 * Delete
 {{- range .DeletedResources}}
-  * {{.}}
+  * {{escapeMarkdown .}}
 {{- end}}{{end}}{{if .ReplacedResources}}
 * Replace
 {{- range .ReplacedResources}}
-  * {{.}}
+  * {{escapeMarkdown .}}
+{{- end}}{{end}}{{if .ChangedResult}}
+{{wrapCode .ChangedResult}}{{end}}
+
+</details>
+{{end}}`,
+		"change_outside_terraform": `{{if .ChangeOutsideTerraform}}
+<details><summary>:warning: {{if .DriftedResources}}{{len .DriftedResources}} {{if eq (len .DriftedResources) 1}}resource{{else}}resources{{end}} drifted{{else}}Objects have changed outside of {{.Product}}{{end}} (Click me)</summary>
+{{range .DriftedResources}}
+* {{escapeMarkdown .}}
+{{- end}}
+{{wrapCode .ChangeOutsideTerraform}}
+</details>{{end}}`,
+		"drift_summary": `{{if .DriftedResources}}### :warning: {{len .DriftedResources}} {{if eq (len .DriftedResources) 1}}resource has{{else}}resources have{{end}} drifted :warning:
+{{range .DriftedResources}}
+* {{escapeMarkdown .}}
+{{- end}}{{if .ChangeOutsideTerraform}}
+
+<details><summary>Changes outside of {{.Product}} (Click me)</summary>
+{{wrapCode .ChangeOutsideTerraform}}
+</details>{{end}}{{else if .Succeeded}}:white_check_mark: No drift is detected.{{end}}`,
+		"cost_estimate": `{{if .CostDelta}}:moneybag: Monthly cost change: {{.CostDelta}}/mo
+{{- range .CostBreakdown}}
+* {{.Name}}: {{.Delta}}/mo ({{.PastMonthlyCost}} -> {{.MonthlyCost}})
+{{- end}}{{if .CostResources}}
+
+<details><summary>Cost changes per resource</summary>
+
+| Resource | Monthly cost | Change |
+|----------|--------------|--------|
+{{- range .CostResources}}
+| <code>{{.Name}}</code> | {{.PastMonthlyCost}} -> {{.MonthlyCost}} | {{.Delta}}/mo |
+{{- end}}
+
+</details>{{end}}{{end}}`,
+		"lint": `{{if .LintSummary}}
+
+### Lint
+{{.LintSummary}}{{if .LintIssues}}
+
+| Severity | Rule | Location | Message |
+|----------|------|----------|---------|
+{{- range .LintIssues}}
+| {{.Severity}} | {{if .Link}}[{{.Rule}}]({{.Link}}){{else}}{{.Rule}}{{end}} | {{if .Filename}}<code>{{.Filename}}{{if .Line}}:{{.Line}}{{end}}</code>{{end}} | {{replace "\n" " " .Message}} |
+{{- end}}{{end}}{{end}}`,
+		"security_findings": `{{if .SecuritySummary}}
+
+### Security findings
+{{.SecuritySummary}}{{if .SecurityFindings}}
+
+| Severity | ID | Resource | Location | Message |
+|----------|----|----------|----------|---------|
+{{- range .SecurityFindings}}
+| {{if eq .Severity "CRITICAL"}}:rotating_light: {{end}}{{.Severity}} | {{if .Link}}[{{.ID}}]({{.Link}}){{else}}{{.ID}}{{end}} | {{if .Resource}}<code>{{.Resource}}</code>{{end}} | {{if .Filename}}<code>{{.Filename}}{{if .Line}}:{{.Line}}{{end}}</code>{{end}} | {{replace "\n" " " .Message}} |
+{{- end}}{{end}}{{end}}`,
+		"policy_violations": `{{if .PolicySummary}}
+
+### Policy
+{{.PolicySummary}}
+{{- range .PolicyViolations}}
+* {{if eq .Severity "failure"}}:x:{{else}}:warning:{{end}} {{if .Namespace}}<code>{{.Namespace}}</code>: {{end}}{{.Message}}
 {{- end}}{{end}}`,
+		"checkov": `{{if .CheckovResources}}
+
+<details><summary>:shield: Checkov: {{len .CheckovResources}} resource(s) failed checks</summary>
+{{range .CheckovResources}}
+* <code>{{.Address}}</code>{{if .Action}} ({{.Action}}){{end}}
+{{- range .Checks}}
+  * {{if .Guideline}}[{{.ID}}]({{.Guideline}}){{else}}{{.ID}}{{end}}: {{.Name}}{{if .Filename}} (<code>{{.Filename}}{{if .Line}}:{{.Line}}{{end}}</code>){{end}}
+{{- end}}
+{{- end}}
+
+</details>{{end}}`,
+		"artifacts": `{{if .ArtifactURL}}
+:package: [The whole output]({{.ArtifactURL}}){{if .PlanJSONURL}} / [The plan JSON]({{.PlanJSONURL}}){{end}}
+{{end}}`,
+		"partial_apply": `{{if and .HasApplyError .AppliedResources}}
+
+### :warning: Apply failed partially :warning:
+Some resources were applied before the error. Please check the state urgently!
+
+* Applied
+{{- range .AppliedResources}}
+  * {{escapeMarkdown .}}
+{{- end}}{{if .FailedResources}}
+* Failed
+{{- range .FailedResources}}
+  * {{escapeMarkdown .}}
+{{- end}}{{end}}{{end}}`,
+		"destroy_title": "## :{{if eq .ExitCode 0}}white_check_mark{{else}}x{{end}}: Destroy Result{{if .Vars.target}} ({{.Vars.target}}){{end}}",
+		"destroyed_resources": `{{if .DeletedResources}}
+
+<details><summary>Destroyed resources ({{len .DeletedResources}})</summary>
+{{range .DeletedResources}}
+* {{escapeMarkdown .}}
+{{- end}}
+
+</details>{{end}}`,
+		"partial_destroy": `{{if and .HasApplyError .AppliedResources}}
+
+### :warning: Destroy failed partially :warning:
+Some resources were destroyed before the error. Please check the state urgently!
+
+* Destroyed
+{{- range .AppliedResources}}
+  * {{escapeMarkdown .}}
+{{- end}}{{if .FailedResources}}
+* Failed
+{{- range .FailedResources}}
+  * {{escapeMarkdown .}}
+{{- end}}{{end}}{{end}}`,
+		"failed_tests": `{{if .FailedTests}}
+
+### :x: Failed tests
+{{range .FailedTests}}
+* {{escapeMarkdown .}}
+{{- end}}{{end}}`,
+		"diagnostics": `{{if .Diagnostics}}
+
+| Severity | Location | Summary |
+|----------|----------|---------|
+{{- range .Diagnostics}}
+| {{if eq .Severity "error"}}:x:{{else}}:warning:{{end}} {{.Severity}} | {{if .Filename}}<code>{{.Filename}}{{if .Line}}:{{.Line}}{{end}}</code>{{end}} | {{.Summary}}{{if .Detail}}<br>{{replace "\n" " " .Detail}}{{end}} |
+{{- end}}{{end}}`,
+		"validate_title": "## :{{if eq .ExitCode 0}}white_check_mark{{else}}x{{end}}: Validate Result{{if .Vars.target}} ({{.Vars.target}}){{end}}",
+		"unformatted_files": `{{if .UnformattedFiles}}
+
+The following files aren't formatted. Please run terraform fmt.
+{{range .UnformattedFiles}}
+* {{escapeMarkdown .}}
+{{- end}}{{end}}`,
+		"fmt_title": "## :{{if eq .ExitCode 0}}white_check_mark{{else}}x{{end}}: Fmt Result{{if .Vars.target}} ({{.Vars.target}}){{end}}",
+		"failed_checks": `{{if .Checks}}
+
+### :warning: Failed checks :warning:
+{{range .Checks}}
+* {{if .Address}}{{escapeMarkdown .Address}}: {{end}}{{.Summary}}{{if .Message}}
+  {{replace "\n" "\n  " .Message}}{{end}}
+{{- end}}{{end}}`,
+		"outputs": `{{if .Outputs}}
+| Output | Value |
+|--------|-------|
+{{- range $name, $value := .Outputs}}
+| {{$name}} | <code>{{replace "\n" " " $value}}</code> |
+{{- end}}{{end}}`,
+		"replacement_warning": `{{if .ReplacedResources}}### :warning: Resource Replacement will happen :warning:
+The following resources will be destroyed and then created again. Please check the plan result very carefully!
+{{range .ReplacedResources}}
+* {{linkResource $.ResourceURLs .}}
+{{- end}}{{end}}`,
+		"gist_summary": `{{if .HasDestroy}}{{template "deletion_warning" .}}
+
+{{end}}The result is too large to post as a comment, so the whole result is uploaded to [the Gist]({{.GistURL}}).
+{{if or .CreatedResources .UpdatedResources .DeletedResources .ReplacedResources}}
+* Create: {{len .CreatedResources}}
+* Update: {{len .UpdatedResources}}
+* Delete: {{len .DeletedResources}}
+* Replace: {{len .ReplacedResources}}
+{{end}}`,
+		"summary": `**Summary:** {{if .Succeeded}}{{len .CreatedResources}} to add, {{len .UpdatedResources}} to change, {{len .DeletedResources}} to destroy, {{len .ReplacedResources}} to replace{{else}}:x: Failed{{end}}
+{{- with .ResultLabel}} (label: ` + "`{{.}}`" + `){{end}}`,
 		"deletion_warning": `### :warning: Resource Deletion will happen :warning:
 This plan contains resource delete operation. Please check the plan result very carefully!`,
 	}
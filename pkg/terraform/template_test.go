@@ -165,6 +165,573 @@ body
 			},
 			resp: `c-d`,
 		},
+		{
+			name:     "cost estimate",
+			template: `{{template "cost_estimate" .}}`,
+			value: CommonTemplate{
+				CostDelta: "+$123.40",
+				CostBreakdown: []CostBreakdownEntry{
+					{
+						Name:            "foo",
+						MonthlyCost:     "$223.40",
+						PastMonthlyCost: "$100.00",
+						Delta:           "+$123.40",
+					},
+				},
+				UseRawOutput: true,
+			},
+			resp: `:moneybag: Monthly cost change: +$123.40/mo
+* foo: +$123.40/mo ($100.00 -> $223.40)`,
+		},
+		{
+			name:     "cost estimate per resource",
+			template: `{{template "cost_estimate" .}}`,
+			value: CommonTemplate{
+				CostDelta: "+$100.00",
+				CostResources: []CostResourceEntry{
+					{
+						Project:         "foo",
+						Name:            "aws_instance.web",
+						MonthlyCost:     "$200.00",
+						PastMonthlyCost: "$100.00",
+						Delta:           "+$100.00",
+					},
+				},
+				UseRawOutput: true,
+			},
+			resp: `:moneybag: Monthly cost change: +$100.00/mo
+
+<details><summary>Cost changes per resource</summary>
+
+| Resource | Monthly cost | Change |
+|----------|--------------|--------|
+| <code>aws_instance.web</code> | $100.00 -> $200.00 | +$100.00/mo |
+
+</details>`,
+		},
+		{
+			name:     "lint",
+			template: `{{template "lint" .}}`,
+			value: CommonTemplate{
+				LintSummary: "0 error(s), 1 warning(s), 0 notice(s)",
+				LintIssues: []LintIssue{
+					{
+						Rule:     "terraform_unused_declarations",
+						Severity: "warning",
+						Message:  "variable is declared\nbut not used",
+						Filename: "variables.tf",
+						Line:     1,
+						Link:     "https://example.com",
+					},
+					{
+						Severity: "error",
+						Message:  "Failed to load configurations",
+					},
+				},
+				UseRawOutput: true,
+			},
+			resp: `
+
+### Lint
+0 error(s), 1 warning(s), 0 notice(s)
+
+| Severity | Rule | Location | Message |
+|----------|------|----------|---------|
+| warning | [terraform_unused_declarations](https://example.com) | <code>variables.tf:1</code> | variable is declared but not used |
+| error |  |  | Failed to load configurations |`,
+		},
+		{
+			name:     "security findings",
+			template: `{{template "security_findings" .}}`,
+			value: CommonTemplate{
+				SecuritySummary: "CRITICAL: 1, HIGH: 0, MEDIUM: 0, LOW: 0",
+				SecurityFindings: []SecurityFinding{
+					{
+						ID:       "AVD-AWS-0057",
+						Severity: "CRITICAL",
+						Message:  "IAM policy document uses wildcarded action",
+						Resource: "aws_iam_policy.foo",
+						Filename: "iam.tf",
+						Line:     5,
+						Link:     "https://example.com",
+					},
+				},
+				UseRawOutput: true,
+			},
+			resp: `
+
+### Security findings
+CRITICAL: 1, HIGH: 0, MEDIUM: 0, LOW: 0
+
+| Severity | ID | Resource | Location | Message |
+|----------|----|----------|----------|---------|
+| :rotating_light: CRITICAL | [AVD-AWS-0057](https://example.com) | <code>aws_iam_policy.foo</code> | <code>iam.tf:5</code> | IAM policy document uses wildcarded action |`,
+		},
+		{
+			name:     "policy violations",
+			template: `{{template "policy_violations" .}}`,
+			value: CommonTemplate{
+				PolicySummary: "1 failure(s), 1 warning(s)",
+				PolicyViolations: []PolicyViolation{
+					{
+						Namespace: "main",
+						Severity:  PolicySeverityFailure,
+						Message:   "aws_s3_bucket.foo must have tags",
+					},
+					{
+						Severity: PolicySeverityWarning,
+						Message:  "aws_instance.web should use a larger instance type",
+					},
+				},
+				UseRawOutput: true,
+			},
+			resp: `
+
+### Policy
+1 failure(s), 1 warning(s)
+* :x: <code>main</code>: aws_s3_bucket.foo must have tags
+* :warning: aws_instance.web should use a larger instance type`,
+		},
+		{
+			name:     "checkov",
+			template: `{{template "checkov" .}}`,
+			value: CommonTemplate{
+				CheckovResources: []CheckovResource{
+					{
+						Address: "aws_s3_bucket.foo",
+						Action:  "create",
+						Checks: []CheckovCheck{
+							{
+								ID:        "CKV_AWS_18",
+								Name:      "Ensure the S3 bucket has access logging enabled",
+								Filename:  "main.tf",
+								Line:      1,
+								Guideline: "https://example.com",
+							},
+						},
+					},
+				},
+				UseRawOutput: true,
+			},
+			resp: `
+
+<details><summary>:shield: Checkov: 1 resource(s) failed checks</summary>
+
+* <code>aws_s3_bucket.foo</code> (create)
+  * [CKV_AWS_18](https://example.com): Ensure the S3 bucket has access logging enabled (<code>main.tf:1</code>)
+
+</details>`,
+		},
+		{
+			name:     "destroyed resources",
+			template: `{{template "destroyed_resources" .}}{{template "partial_destroy" .}}`,
+			value: CommonTemplate{
+				DeletedResources: []string{"aws_instance.web", "aws_s3_bucket.logs"},
+				UseRawOutput:     true,
+			},
+			resp: `
+
+<details><summary>Destroyed resources (2)</summary>
+
+* aws_instance.web
+* aws_s3_bucket.logs
+
+</details>`,
+		},
+		{
+			name:     "partial destroy",
+			template: `{{template "destroyed_resources" .}}{{template "partial_destroy" .}}`,
+			value: CommonTemplate{
+				HasApplyError:    true,
+				AppliedResources: []string{"aws_instance.web"},
+				FailedResources:  []string{"aws_s3_bucket.logs"},
+				UseRawOutput:     true,
+			},
+			resp: `
+
+### :warning: Destroy failed partially :warning:
+Some resources were destroyed before the error. Please check the state urgently!
+
+* Destroyed
+  * aws_instance.web
+* Failed
+  * aws_s3_bucket.logs`,
+		},
+		{
+			name:     "artifacts",
+			template: `{{template "artifacts" .}}`,
+			value: CommonTemplate{
+				ArtifactURL:  "https://example.com/output.txt",
+				PlanJSONURL:  "https://example.com/plan.json",
+				UseRawOutput: true,
+			},
+			resp: `
+:package: [The whole output](https://example.com/output.txt) / [The plan JSON](https://example.com/plan.json)
+`,
+		},
+		{
+			name:     "artifacts aren't uploaded",
+			template: `{{template "artifacts" .}}`,
+			value:    CommonTemplate{},
+			resp:     ``,
+		},
+		{
+			name:     "lint isn't given",
+			template: `{{template "lint" .}}`,
+			value:    CommonTemplate{},
+			resp:     ``,
+		},
+		{
+			name:     "module changes",
+			template: `{{template "module_changes" .}}`,
+			value: CommonTemplate{
+				ModuleChanges: []ModuleChanges{
+					{
+						Module:           "module.network",
+						CreatedResources: []string{"module.network.aws_vpc.main"},
+					},
+				},
+				UseRawOutput: true,
+			},
+			resp: `
+<details><summary>module.network (1 to add, 0 to change, 0 to destroy, 0 to replace)</summary>
+
+* Create module.network.aws_vpc.main
+
+</details>
+`,
+		},
+		{
+			name:     "succeeded",
+			template: `{{if .Succeeded}}ok{{end}}{{if .HasChanges}} changes{{end}}`,
+			value: CommonTemplate{
+				Succeeded:  true,
+				HasChanges: true,
+			},
+			resp: `ok changes`,
+		},
+		{
+			name:     "replacement warning",
+			template: `{{template "replacement_warning" .}}`,
+			value: CommonTemplate{
+				ReplacedResources: []string{"aws_instance.foo"},
+			},
+			resp: `### :warning: Resource Replacement will happen :warning:
+The following resources will be destroyed and then created again. Please check the plan result very carefully!
+
+* aws_instance.foo`,
+		},
+		{
+			name:     "default plan template with replaced resources",
+			template: DefaultPlanTemplate,
+			value: CommonTemplate{
+				Result:            "Plan: 1 to add, 0 to change, 1 to destroy.",
+				HasDestroy:        true,
+				ReplacedResources: []string{"aws_instance.foo"},
+				UseRawOutput:      true,
+			},
+			resp: `
+## Plan Result
+
+
+
+### :warning: Resource Deletion will happen :warning:
+This plan contains resource delete operation. Please check the plan result very carefully!
+
+### :warning: Resource Replacement will happen :warning:
+The following resources will be destroyed and then created again. Please check the plan result very carefully!
+
+* aws_instance.foo
+
+<pre><code>Plan: 1 to add, 0 to change, 1 to destroy.</code></pre>
+
+* Replace
+  * aws_instance.foo
+<details><summary>Details (Click me)</summary>
+
+` + "```hcl" + `
+
+` + "```" + `
+
+</details>
+`,
+		},
+		{
+			name:     "escape resource addresses",
+			template: `{{template "updated_resources" .}}`,
+			value: CommonTemplate{
+				CreatedResources: []string{`null_resource.foo["a*b"]`},
+				UseRawOutput:     true,
+			},
+			resp: `
+* Create
+  * null_resource.foo\["a\*b"\]`,
+		},
+		{
+			name:     "change outside terraform",
+			template: `{{template "change_outside_terraform" .}}`,
+			value: CommonTemplate{
+				ChangeOutsideTerraform: "  # null_resource.foo has changed",
+				DriftedResources:       []string{"null_resource.foo", "null_resource.bar"},
+			},
+			resp: "\n<details><summary>:warning: 2 resources drifted (Click me)</summary>\n\n* null_resource.foo\n* null_resource.bar\n\n```hcl\n  # null_resource.foo has changed\n```\n\n</details>",
+		},
+		{
+			name:     "no change outside terraform",
+			template: `{{template "change_outside_terraform" .}}`,
+			value:    CommonTemplate{},
+			resp:     ``,
+		},
+		{
+			name:     "updated resources diff",
+			template: `{{template "updated_resources_diff" .}}`,
+			value: CommonTemplate{
+				CreatedResources:  []string{`null_resource.foo["a"]`},
+				UpdatedResources:  []string{"null_resource.bar"},
+				DeletedResources:  []string{"null_resource.baz"},
+				ReplacedResources: []string{"null_resource.qux"},
+			},
+			resp: "\n```diff\n+ null_resource.foo[\"a\"]\n~ null_resource.bar\n- null_resource.baz\n-/+ null_resource.qux\n```\n",
+		},
+		{
+			name:     "no updated resources diff",
+			template: `{{template "updated_resources_diff" .}}`,
+			value:    CommonTemplate{},
+			resp:     ``,
+		},
+		{
+			name:     "max resources",
+			template: `{{template "updated_resources" .}}`,
+			value: CommonTemplate{
+				CreatedResources: []string{"null_resource.a", "null_resource.b", "null_resource.c"},
+				DeletedResources: []string{"null_resource.d"},
+				MaxResources:     2,
+			},
+			resp: `
+* Create
+  * null_resource.a
+  * null_resource.b
+  * ... and 1 more
+* Delete
+  * null_resource.d`,
+		},
+		{
+			name:     "link resources",
+			template: `{{template "updated_resources" .}}`,
+			value: CommonTemplate{
+				CreatedResources: []string{"null_resource.a[0]", "null_resource.b"},
+				ResourceURLs: map[string]string{
+					"null_resource.a[0]": "https://github.com/suzuki-shunsuke/tfcmt/blob/abcd/main.tf#L3",
+				},
+			},
+			resp: `
+* Create
+  * [null_resource.a\[0\]](https://github.com/suzuki-shunsuke/tfcmt/blob/abcd/main.tf#L3)
+  * null_resource.b`,
+		},
+		{
+			name:     "moved resources",
+			template: `{{template "moved_resources" .}}`,
+			value: CommonTemplate{
+				MovedResources: []MovedResource{
+					{Before: "null_resource.foo", After: `module.bar.null_resource.foo["a"]`},
+				},
+			},
+			resp: `
+* Move
+  * null_resource.foo -> module.bar.null_resource.foo\[&#34;a&#34;\]`,
+		},
+		{
+			name:     "drift summary",
+			template: `{{template "drift_summary" .}}`,
+			value: CommonTemplate{
+				IsRefreshOnly:    true,
+				DriftedResources: []string{"null_resource.foo"},
+			},
+			resp: `### :warning: 1 resource has drifted :warning:
+
+* null_resource.foo`,
+		},
+		{
+			name:     "failed checks",
+			template: `{{template "failed_checks" .}}`,
+			value: CommonTemplate{
+				Checks: []Check{
+					{
+						Address:  "check.health",
+						Summary:  "Check block assertion failed",
+						Message:  "The health check failed\nstatus code 404",
+						Severity: CheckSeverityWarning,
+					},
+				},
+			},
+			resp: `
+
+### :warning: Failed checks :warning:
+
+* check.health: Check block assertion failed
+  The health check failed
+  status code 404`,
+		},
+		{
+			name:     "failed tests",
+			template: `{{template "failed_tests" .}}`,
+			value: CommonTemplate{
+				FailedTests: []string{"tests/main.tftest.hcl: check_bucket"},
+			},
+			resp: `
+
+### :x: Failed tests
+
+* tests/main.tftest.hcl: check_bucket`,
+		},
+		{
+			name:     "diagnostics",
+			template: `{{template "diagnostics" .}}`,
+			value: CommonTemplate{
+				Diagnostics: []Diagnostic{
+					{
+						Severity: "error",
+						Summary:  "Unsupported argument",
+						Detail:   "An argument is not expected here.",
+						Filename: "main.tf",
+						Line:     3,
+					},
+					{
+						Severity: "warning",
+						Summary:  "Deprecated attribute",
+					},
+				},
+			},
+			resp: `
+
+| Severity | Location | Summary |
+|----------|----------|---------|
+| :x: error | <code>main.tf:3</code> | Unsupported argument<br>An argument is not expected here. |
+| :warning: warning |  | Deprecated attribute |`,
+		},
+		{
+			name:     "no drift",
+			template: `{{template "drift_summary" .}}`,
+			value: CommonTemplate{
+				IsRefreshOnly: true,
+				Succeeded:     true,
+			},
+			resp: `:white_check_mark: No drift is detected.`,
+		},
+		{
+			name:     "terragrunt modules",
+			template: `{{template "terragrunt_modules" .}}`,
+			value: CommonTemplate{
+				TerragruntModules: []TerragruntModule{
+					{
+						Path: "app",
+						ParseResult: ParseResult{
+							Result:       "Error: Invalid reference",
+							HasPlanError: true,
+						},
+					},
+					{
+						Path: "vpc",
+						ParseResult: ParseResult{
+							Result:           "Plan: 1 to add, 0 to change, 0 to destroy.",
+							ChangedResult:    "  + resource \"aws_vpc\" \"main\" {}",
+							CreatedResources: []string{"aws_vpc.main"},
+						},
+					},
+				},
+			},
+			resp: `
+### :x: app
+
+<pre><code>Error: Invalid reference</code></pre>
+
+### vpc
+
+<pre><code>Plan: 1 to add, 0 to change, 0 to destroy.</code></pre>
+* Create
+  * aws_vpc.main
+
+<details><summary>Details (Click me)</summary>
+
+` + "```hcl" + `
+  + resource "aws_vpc" "main" {}
+` + "```" + `
+
+</details>
+`,
+		},
+		{
+			name:     "targets",
+			template: `{{template "targets_table" .}}{{template "target_sections" .}}`,
+			value: CommonTemplate{
+				TerragruntModules: []TerragruntModule{
+					{
+						Path: "envs/dev",
+						ParseResult: ParseResult{
+							Result:           "Plan: 1 to add, 0 to change, 0 to destroy.",
+							ChangedResult:    "  + resource \"aws_vpc\" \"main\" {}",
+							CreatedResources: []string{"aws_vpc.main"},
+						},
+					},
+					{
+						Path: "envs/prd",
+						ParseResult: ParseResult{
+							Result:       "Error: Invalid reference",
+							HasPlanError: true,
+						},
+					},
+				},
+			},
+			resp: `
+| Target | Add | Change | Destroy | Replace |
+|--------|-----|--------|---------|---------|
+| envs/dev | 1 | 0 | 0 | 0 |
+| :x: envs/prd | - | - | - | - |
+
+<details><summary>envs/dev</summary>
+
+<pre><code>Plan: 1 to add, 0 to change, 0 to destroy.</code></pre>
+* Create
+  * aws_vpc.main
+
+` + "```hcl" + `
+  + resource "aws_vpc" "main" {}
+` + "```" + `
+
+
+</details>
+
+<details><summary>:x: envs/prd</summary>
+
+<pre><code>Error: Invalid reference</code></pre>
+
+</details>
+`,
+		},
+		{
+			name:     "targets aren't given",
+			template: `{{template "targets_table" .}}{{template "target_sections" .}}`,
+			value:    CommonTemplate{},
+			resp:     ``,
+		},
+		{
+			name:     "no cost estimate",
+			template: `{{template "cost_estimate" .}}`,
+			value:    CommonTemplate{},
+			resp:     ``,
+		},
+		{
+			name:     "plan error with the error category",
+			template: `{{template "result" .}}{{template "error_category" .}}`,
+			value: CommonTemplate{
+				Result:        "Error: Error acquiring the state lock",
+				ErrorCategory: "state_lock",
+			},
+			resp: `<pre><code>Error: Error acquiring the state lock</code></pre>
+
+Error category: state_lock`,
+		},
 	}
 	for i, testCase := range testCases {
 		testCase := testCase
@@ -339,6 +906,77 @@ This is a "body".
 			},
 			resp: `c-d`,
 		},
+		{
+			name:     "partial apply",
+			template: `{{template "partial_apply" .}}`,
+			value: CommonTemplate{
+				HasApplyError:    true,
+				AppliedResources: []string{"aws_instance.foo"},
+				FailedResources:  []string{"aws_s3_bucket.bar"},
+			},
+			resp: `
+
+### :warning: Apply failed partially :warning:
+Some resources were applied before the error. Please check the state urgently!
+
+* Applied
+  * aws_instance.foo
+* Failed
+  * aws_s3_bucket.bar`,
+		},
+		{
+			name:     "outputs",
+			template: `{{template "outputs" .}}`,
+			value: CommonTemplate{
+				Outputs: map[string]string{
+					"instance_id": `"i-1234"`,
+					"password":    "(sensitive)",
+					"subnets":     "[\n  \"subnet-a\",\n]",
+				},
+				UseRawOutput: true,
+			},
+			resp: `
+| Output | Value |
+|--------|-------|
+| instance_id | <code>"i-1234"</code> |
+| password | <code>(sensitive)</code> |
+| subnets | <code>[   "subnet-a", ]</code> |`,
+		},
+		{
+			name:     "apply failed without applied resources",
+			template: `{{template "partial_apply" .}}`,
+			value: CommonTemplate{
+				HasApplyError: true,
+			},
+			resp: ``,
+		},
+		{
+			name:     "apply failed with the error category",
+			template: DefaultApplyTemplate,
+			value: CommonTemplate{
+				Result:         "Error: Error acquiring the state lock",
+				CombinedOutput: "body",
+				ExitCode:       1,
+				ErrorCategory:  "state_lock",
+			},
+			resp: `
+## :x: Apply Result
+
+
+
+<pre><code>Error: Error acquiring the state lock</code></pre>
+
+Error category: state_lock
+
+<details><summary>Details (Click me)</summary>
+
+` + "```hcl" + `
+body
+` + "```" + `
+
+</details>
+`,
+		},
 	}
 	for i, testCase := range testCases {
 		testCase := testCase
@@ -359,3 +997,37 @@ This is a "body".
 		})
 	}
 }
+
+func TestEscapeMarkdown(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		text string
+		exp  string
+	}{
+		{
+			name: "no special character",
+			text: "module.foo.aws_instance.bar",
+			exp:  "module.foo.aws_instance.bar",
+		},
+		{
+			name: "for_each key",
+			text: `module.x["a_b*c"]`,
+			exp:  `module.x\["a_b\*c"\]`,
+		},
+		{
+			name: "underscore at the boundary",
+			text: `null_resource.foo["_a_"]`,
+			exp:  `null_resource.foo\["\_a\_"\]`,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(testCase.exp, escapeMarkdown(testCase.text)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package terraform
+
+import (
+	"fmt"
+)
+
+// SampleCommonTemplate returns template entities with all fields populated.
+// This is used to validate templates without running terraform
+func SampleCommonTemplate() CommonTemplate {
+	return CommonTemplate{
+		Result:                 "Plan: 1 to add, 1 to change, 1 to destroy.",
+		ChangedResult:          "Terraform will perform the following actions:",
+		ChangeOutsideTerraform: "Terraform detected the following changes made outside of Terraform since the last \"terraform apply\":",
+		Warning:                "Warning: Argument is deprecated",
+		Link:                   "https://ci.example.com/builds/1",
+		MaxResources:           10,
+		HasDestroy:             true,
+		HasChanges:             true,
+		Succeeded:              true,
+		Vars: map[string]string{
+			"target": "foo",
+		},
+		Stdout:             "stdout",
+		Stderr:             "stderr",
+		CombinedOutput:     "combined output",
+		ParseErrorMessage:  "the output can't be parsed",
+		CombinedOutputHead: "the head of the combined output",
+		CombinedOutputTail: "the tail of the combined output",
+		ExitCode:           2,
+		ErrorMessages:      []string{"add a label destroy: internal server error"},
+		CreatedResources:   []string{"null_resource.foo"},
+		UpdatedResources:   []string{"module.bar.null_resource.bar"},
+		DeletedResources:   []string{`null_resource.baz["a"]`},
+		ReplacedResources:  []string{"null_resource.qux"},
+		ModuleChanges: []ModuleChanges{
+			{
+				Module:            "root",
+				CreatedResources:  []string{"null_resource.foo"},
+				DeletedResources:  []string{`null_resource.baz["a"]`},
+				ReplacedResources: []string{"null_resource.qux"},
+			},
+			{
+				Module:           "module.bar",
+				UpdatedResources: []string{"module.bar.null_resource.bar"},
+			},
+		},
+		DriftedResources: []string{"null_resource.drifted"},
+		CostDelta:        "+$10",
+		CostBreakdown: []CostBreakdownEntry{
+			{
+				Name:            "foo",
+				MonthlyCost:     "$20",
+				PastMonthlyCost: "$10",
+				Delta:           "+$10",
+			},
+		},
+		CostResources: []CostResourceEntry{
+			{
+				Project:         "foo",
+				Name:            "aws_instance.web",
+				MonthlyCost:     "$20",
+				PastMonthlyCost: "$10",
+				Delta:           "+$10",
+			},
+		},
+		HasApplyError:    true,
+		AppliedResources: []string{"null_resource.foo"},
+		FailedResources:  []string{"null_resource.qux"},
+		Outputs: map[string]string{
+			"id": "foo",
+		},
+		Env: map[string]string{
+			"TFCMT_JOB_NAME": "plan",
+		},
+		ResourceURLs: map[string]string{
+			"null_resource.foo": "https://github.com/suzuki-shunsuke/tfcmt/blob/main/main.tf#L1",
+		},
+		GistURL:    "https://gist.github.com/suzuki-shunsuke/1",
+		RunURL:     "https://github.com/suzuki-shunsuke/tfcmt/actions/runs/1/attempts/2",
+		JobURL:     "https://github.com/suzuki-shunsuke/tfcmt/actions/runs/1/job/1",
+		RunAttempt: "2",
+		Actor:      "octocat",
+		// the label is a template, so the sample is a rendered one
+		ResultLabel:   "terraform/destroy",
+		ErrorCategory: ErrorCategoryStateLock,
+		Product:       ProductTerraform,
+		TerragruntModules: []TerragruntModule{
+			{
+				Path: "vpc",
+				ParseResult: ParseResult{
+					Result:           "Plan: 1 to add, 0 to change, 0 to destroy.",
+					ChangedResult:    "changed result",
+					CreatedResources: []string{"aws_vpc.main"},
+				},
+			},
+		},
+		MovedResources: []MovedResource{
+			{
+				Before: "null_resource.old",
+				After:  "null_resource.new",
+			},
+		},
+		ImportedResources: []string{"null_resource.imported"},
+		IsRefreshOnly:     true,
+		Checks: []Check{
+			{
+				Address:  "check.health",
+				Summary:  "Check block assertion failed",
+				Message:  "the health check failed",
+				Severity: CheckSeverityWarning,
+			},
+		},
+		TestPassed:  1,
+		TestFailed:  1,
+		TestSkipped: 1,
+		FailedTests: []string{"tests/main.tftest.hcl: setup"},
+		Diagnostics: []Diagnostic{
+			{
+				Severity: "error",
+				Summary:  "Unsupported argument",
+				Detail:   `An argument named "foo" is not expected here.`,
+				Filename: "main.tf",
+				Line:     3,
+			},
+		},
+		UnformattedFiles: []string{"main.tf"},
+		LintSummary:      "0 error(s), 1 warning(s), 0 notice(s)",
+		LintIssues: []LintIssue{
+			{
+				Rule:     "terraform_unused_declarations",
+				Severity: "warning",
+				Message:  `variable "foo" is declared but not used`,
+				Filename: "variables.tf",
+				Line:     1,
+				Link:     "https://github.com/terraform-linters/tflint-ruleset-terraform/blob/main/docs/rules/terraform_unused_declarations.md",
+			},
+		},
+		SecuritySummary: "CRITICAL: 0, HIGH: 1, MEDIUM: 0, LOW: 0",
+		SecurityFindings: []SecurityFinding{
+			{
+				ID:       "AVD-AWS-0086",
+				Title:    "S3 Access block should block public ACL",
+				Severity: "HIGH",
+				Message:  "No public access block so not blocking public acls",
+				Resource: "aws_s3_bucket.foo",
+				Filename: "main.tf",
+				Line:     1,
+				Link:     "https://avd.aquasec.com/misconfig/avd-aws-0086",
+			},
+		},
+		PolicySummary: "1 failure(s), 0 warning(s)",
+		PolicyViolations: []PolicyViolation{
+			{
+				Namespace: "main",
+				Severity:  "failure",
+				Message:   "aws_s3_bucket.foo must have tags",
+			},
+		},
+		CheckovResources: []CheckovResource{
+			{
+				Address: "aws_s3_bucket.foo",
+				Action:  "create",
+				Checks: []CheckovCheck{
+					{
+						ID:        "CKV_AWS_18",
+						Name:      "Ensure the S3 bucket has access logging enabled",
+						Filename:  "main.tf",
+						Line:      1,
+						Guideline: "https://docs.prismacloud.io/en/enterprise-edition/policy-reference/aws-policies/s3-policies/s3-13-enable-logging",
+					},
+				},
+			},
+		},
+		ArtifactURL: "https://tfcmt-artifacts.s3.amazonaws.com/tfcmt/20240101T000000Z/output.txt",
+		PlanJSONURL: "https://tfcmt-artifacts.s3.amazonaws.com/tfcmt/20240101T000000Z/plan.json",
+	}
+}
+
+// Validate renders the template with sample template entities and returns an error if the template is invalid.
+// Unlike Execute, referring to an undefined field is an error.
+// The template is rendered twice, with all fields populated and with all fields empty, so that both branches of conditions are checked.
+// The error message includes the line number in the template
+func (t *Template) Validate() error {
+	for _, ct := range []CommonTemplate{SampleCommonTemplate(), {}} {
+		ct.UseRawOutput = t.UseRawOutput
+		ct.Templates = t.Templates
+		tpl := &Template{
+			Template:       t.Template,
+			CommonTemplate: ct,
+		}
+		// render the struct instead of the map so that undefined fields are errors
+		if _, err := tpl.execute(ct); err != nil {
+			return fmt.Errorf("render the template with sample data: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplateValidate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name         string
+		template     *Template
+		templates    map[string]string
+		useRawOutput bool
+		err          string
+	}{
+		{
+			name:     "default plan template",
+			template: NewPlanTemplate(""),
+		},
+		{
+			name:     "default plan parse error template",
+			template: NewPlanParseErrorTemplate(""),
+		},
+		{
+			name:         "default apply template with raw output",
+			template:     NewApplyTemplate(""),
+			useRawOutput: true,
+		},
+		{
+			name:     "default apply parse error template",
+			template: NewApplyParseErrorTemplate(""),
+		},
+		{
+			name:     "built-in templates",
+			template: NewPlanTemplate(`{{template "updated_resources_diff" .}}{{template "module_changes" .}}{{template "cost_estimate" .}}{{template "outputs" .}}{{template "replacement_warning" .}}{{template "gist_summary" .}}{{template "summary" .}}{{template "failed_checks" .}}`),
+		},
+		{
+			name:     "default terragrunt plan template",
+			template: NewPlanTemplate(DefaultTerragruntPlanTemplate),
+		},
+		{
+			name:     "default refresh-only plan template",
+			template: NewPlanTemplate(DefaultRefreshOnlyPlanTemplate),
+		},
+		{
+			name:     "default test templates",
+			template: NewTestTemplate(DefaultTestTemplate + DefaultTestParseErrorTemplate),
+		},
+		{
+			name:     "default destroy templates",
+			template: NewDestroyTemplate(DefaultDestroyTemplate + DefaultDestroyParseErrorTemplate),
+		},
+		{
+			name:     "default multi-dir plan template",
+			template: NewPlanTemplate(DefaultMultiDirPlanTemplate),
+		},
+		{
+			name:     "default validate templates",
+			template: NewValidateTemplate(DefaultValidateTemplate + DefaultValidateParseErrorTemplate),
+		},
+		{
+			name:     "default fmt template",
+			template: NewFmtTemplate(""),
+		},
+		{
+			name:     "default pulumi templates",
+			template: NewPlanTemplate(DefaultPulumiPreviewTemplate + DefaultPulumiUpTemplate),
+		},
+		{
+			name:     "undefined field",
+			template: NewPlanTemplate("{{.Result}}\n{{if .HasDestroy}}{{.Foo}}{{end}}"),
+			err:      `render the template with sample data: template: default:2:20: executing "default" at <.Foo>: can't evaluate field Foo in type terraform.CommonTemplate`,
+		},
+		{
+			name:     "undefined field in the empty branch",
+			template: NewPlanTemplate("{{if .HasDestroy}}{{else}}{{.Foo}}{{end}}"),
+			err:      `render the template with sample data: template: default:1:28: executing "default" at <.Foo>: can't evaluate field Foo in type terraform.CommonTemplate`,
+		},
+		{
+			name:     "undefined function",
+			template: NewPlanTemplate("{{.Result}}\n{{foo .Result}}"),
+			err:      `render the template with sample data: template: default:2: function "foo" not defined`,
+		},
+		{
+			name:      "invalid custom template",
+			template:  NewPlanTemplate(`{{template "my_title" .}}`),
+			templates: map[string]string{"my_title": "{{.Title}}"},
+			// the line number of the custom template depends on the order of templates
+			err: `executing "my_title" at <.Title>: can't evaluate field Title in type terraform.CommonTemplate`,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			testCase.template.Templates = testCase.templates
+			testCase.template.UseRawOutput = testCase.useRawOutput
+			err := testCase.template.Validate()
+			if testCase.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("error should be returned")
+			}
+			if !strings.Contains(err.Error(), testCase.err) {
+				t.Errorf("got %q but want %q", err.Error(), testCase.err)
+			}
+		})
+	}
+}
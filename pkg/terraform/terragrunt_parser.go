@@ -0,0 +1,155 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TerragruntModule is the result of terraform plan of a module run by terragrunt run-all
+type TerragruntModule struct {
+	// Path is the path of the module which terragrunt prefixes to the output
+	Path string
+	ParseResult
+}
+
+// TerragruntParser is a parser for the output of terragrunt run-all plan.
+// The output is split per module by the prefix of lines, and each module is parsed by Plan
+type TerragruntParser struct {
+	Plan *PlanParser
+	// Prefixes are patterns of lines of modules. The first submatch is the path of the module and the second one is the line
+	Prefixes []*regexp.Regexp
+}
+
+// NewTerragruntParser is TerragruntParser initializer
+func NewTerragruntParser() *TerragruntParser {
+	return &TerragruntParser{
+		Plan: NewPlanParser(),
+		Prefixes: []*regexp.Regexp{
+			// terragrunt v0.67.0 or later: "10:00:00.000 STDOUT [vpc] terraform: Plan: 1 to add, 0 to change, 0 to destroy."
+			regexp.MustCompile(`^\d{2}:\d{2}:\d{2}\.\d{3} STD(?:OUT|ERR) +\[([^\]]+)\] (?:terraform|tofu):(?: (.*))?$`),
+			// --terragrunt-include-module-prefix: "[vpc] Plan: 1 to add, 0 to change, 0 to destroy."
+			regexp.MustCompile(`^\[([^\]]+)\](?: (.*))?$`),
+		},
+	}
+}
+
+var planSummaryPattern = regexp.MustCompile(`(?:(\d+) to import, )?(\d+) to add, (\d+) to change, (\d+) to destroy`)
+
+// splitModules returns the output per module. Lines without the prefix such as logs of terragrunt are ignored
+func (p *TerragruntParser) splitModules(body string) map[string][]string {
+	modules := map[string][]string{}
+	for _, line := range strings.Split(body, "\n") {
+		for _, prefix := range p.Prefixes {
+			arr := prefix.FindStringSubmatch(line)
+			if arr == nil {
+				continue
+			}
+			// logs of old terragrunt are prefixed with "[terragrunt]"
+			if arr[1] != "terragrunt" {
+				modules[arr[1]] = append(modules[arr[1]], arr[2])
+			}
+			break
+		}
+	}
+	return modules
+}
+
+// Parse parses the output of each module and aggregates results.
+// The resources are prefixed with the module path like "[vpc] aws_vpc.main" because addresses can conflict between modules
+func (p *TerragruntParser) Parse(body string) ParseResult {
+	outputs := p.splitModules(body)
+	if len(outputs) == 0 {
+		return ParseResult{
+			HasParseError:   true,
+			ExitCode:        ExitFail,
+			Error:           errors.New("cannot parse the output of terragrunt run-all plan: no line is prefixed with the module path. Please run terragrunt with --terragrunt-include-module-prefix"),
+			ErrorCategory:   classifyError(body),
+			DetectedCommand: CommandPlan,
+		}
+	}
+	paths := make([]string, 0, len(outputs))
+	for path := range outputs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	ret := ParseResult{
+		ExitCode:        ExitPass,
+		HasNoChanges:    true,
+		DetectedCommand: CommandPlan,
+	}
+	var failed []string
+	var imported, add, change, destroy int
+	for _, path := range paths {
+		result := p.Plan.Parse(strings.Join(outputs[path], "\n"))
+		if result.HasParseError {
+			result.Result = "Error: " + result.Error.Error()
+			result.HasPlanError = true
+		}
+		result.Error = nil
+		ret.TerragruntModules = append(ret.TerragruntModules, TerragruntModule{
+			Path:        path,
+			ParseResult: result,
+		})
+		if result.HasPlanError {
+			failed = append(failed, path)
+			if ret.ErrorCategory == "" {
+				ret.ErrorCategory = result.ErrorCategory
+			}
+			continue
+		}
+		if arr := planSummaryPattern.FindStringSubmatch(result.Result); arr != nil {
+			i, _ := strconv.Atoi(arr[1])
+			a, _ := strconv.Atoi(arr[2])
+			c, _ := strconv.Atoi(arr[3])
+			d, _ := strconv.Atoi(arr[4])
+			imported += i
+			add += a
+			change += c
+			destroy += d
+		}
+		ret.HasDestroy = ret.HasDestroy || result.HasDestroy
+		ret.HasNoChanges = ret.HasNoChanges && result.HasNoChanges
+		ret.CreatedResources = append(ret.CreatedResources, prefixResources(path, result.CreatedResources)...)
+		ret.UpdatedResources = append(ret.UpdatedResources, prefixResources(path, result.UpdatedResources)...)
+		ret.DeletedResources = append(ret.DeletedResources, prefixResources(path, result.DeletedResources)...)
+		ret.ReplacedResources = append(ret.ReplacedResources, prefixResources(path, result.ReplacedResources)...)
+		ret.DriftedResources = append(ret.DriftedResources, prefixResources(path, result.DriftedResources)...)
+		ret.ImportedResources = append(ret.ImportedResources, prefixResources(path, result.ImportedResources)...)
+		for _, moved := range result.MovedResources {
+			ret.MovedResources = append(ret.MovedResources, MovedResource{
+				Before: "[" + path + "] " + moved.Before,
+				After:  "[" + path + "] " + moved.After,
+			})
+		}
+	}
+	switch {
+	case len(failed) != 0:
+		ret.Result = fmt.Sprintf("Error: the plan failed in %d of %d modules: %s", len(failed), len(paths), strings.Join(failed, ", "))
+		ret.HasPlanError = true
+		ret.HasNoChanges = false
+		ret.ExitCode = ExitFail
+	case ret.HasNoChanges:
+		ret.Result = "No changes."
+	case imported != 0:
+		ret.Result = fmt.Sprintf("Plan: %d to import, %d to add, %d to change, %d to destroy.", imported, add, change, destroy)
+		ret.HasImportOnly = add+change+destroy == 0
+		ret.HasAddOrUpdateOnly = !ret.HasImportOnly && !ret.HasDestroy
+	default:
+		ret.Result = fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", add, change, destroy)
+		ret.HasAddOrUpdateOnly = !ret.HasDestroy
+	}
+	return ret
+}
+
+func prefixResources(path string, resources []string) []string {
+	ret := make([]string, len(resources))
+	for i, rsc := range resources {
+		ret[i] = "[" + path + "] " + rsc
+	}
+	return ret
+}
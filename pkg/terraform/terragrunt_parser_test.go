@@ -0,0 +1,133 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const terragruntPlanResult = `10:00:00.000 INFO   The stack at . will be processed in the following order for command plan:
+Group 1
+- Module ./vpc
+- Module ./app
+
+10:00:01.000 STDOUT [vpc] terraform: Terraform will perform the following actions:
+10:00:01.000 STDOUT [vpc] terraform:
+10:00:01.000 STDOUT [vpc] terraform:   # aws_vpc.main will be created
+10:00:01.000 STDOUT [vpc] terraform:   + resource "aws_vpc" "main" {}
+10:00:01.000 STDOUT [vpc] terraform:
+10:00:01.000 STDOUT [vpc] terraform: Plan: 1 to add, 0 to change, 0 to destroy.
+10:00:02.000 STDOUT [app] terraform: Terraform will perform the following actions:
+10:00:02.000 STDOUT [app] terraform:
+10:00:02.000 STDOUT [app] terraform:   # null_resource.foo will be destroyed
+10:00:02.000 STDOUT [app] terraform:   - resource "null_resource" "foo" {}
+10:00:02.000 STDOUT [app] terraform:
+10:00:02.000 STDOUT [app] terraform:   # null_resource.bar will be updated in-place
+10:00:02.000 STDOUT [app] terraform:   ~ resource "null_resource" "bar" {}
+10:00:02.000 STDOUT [app] terraform:
+10:00:02.000 STDOUT [app] terraform: Plan: 0 to add, 1 to change, 1 to destroy.
+`
+
+const terragruntPrefixedPlanResult = `[terragrunt] 2021/01/01 10:00:00 Running command: terraform plan
+[vpc] No changes. Your infrastructure matches the configuration.
+[app] Error: Invalid reference
+[app]
+[app]   on main.tf line 1:
+`
+
+func TestTerragruntParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		body     string
+		result   string
+		exitCode int
+		destroy  bool
+		noChange bool
+		failed   bool
+		created  []string
+		updated  []string
+		deleted  []string
+		modules  []string
+	}{
+		{
+			name:     "plan",
+			body:     terragruntPlanResult,
+			result:   "Plan: 1 to add, 1 to change, 1 to destroy.",
+			exitCode: ExitPass,
+			destroy:  true,
+			created:  []string{"[vpc] aws_vpc.main"},
+			updated:  []string{"[app] null_resource.bar"},
+			deleted:  []string{"[app] null_resource.foo"},
+			modules:  []string{"app", "vpc"},
+		},
+		{
+			name:     "plan error in a module",
+			body:     terragruntPrefixedPlanResult,
+			result:   "Error: the plan failed in 1 of 2 modules: app",
+			exitCode: ExitFail,
+			failed:   true,
+			modules:  []string{"app", "vpc"},
+		},
+		{
+			name:     "no changes",
+			body:     "[vpc] No changes. Your infrastructure matches the configuration.\n[app] No changes. Your infrastructure matches the configuration.",
+			result:   "No changes.",
+			exitCode: ExitPass,
+			noChange: true,
+			modules:  []string{"app", "vpc"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewTerragruntParser().Parse(testCase.body)
+			if result.HasParseError {
+				t.Fatal(result.Error)
+			}
+			if result.Result != testCase.result {
+				t.Errorf("Result: got %q, wanted %q", result.Result, testCase.result)
+			}
+			if result.ExitCode != testCase.exitCode {
+				t.Errorf("ExitCode: got %d, wanted %d", result.ExitCode, testCase.exitCode)
+			}
+			if result.HasDestroy != testCase.destroy {
+				t.Errorf("HasDestroy: got %v, wanted %v", result.HasDestroy, testCase.destroy)
+			}
+			if result.HasNoChanges != testCase.noChange {
+				t.Errorf("HasNoChanges: got %v, wanted %v", result.HasNoChanges, testCase.noChange)
+			}
+			if result.HasPlanError != testCase.failed {
+				t.Errorf("HasPlanError: got %v, wanted %v", result.HasPlanError, testCase.failed)
+			}
+			if diff := cmp.Diff(testCase.created, result.CreatedResources); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.updated, result.UpdatedResources); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(testCase.deleted, result.DeletedResources); diff != "" {
+				t.Error(diff)
+			}
+			modules := make([]string, len(result.TerragruntModules))
+			for i, module := range result.TerragruntModules {
+				modules[i] = module.Path
+			}
+			if diff := cmp.Diff(testCase.modules, modules); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTerragruntParserParseError(t *testing.T) {
+	t.Parallel()
+	result := NewTerragruntParser().Parse("Plan: 1 to add, 0 to change, 0 to destroy.")
+	if !result.HasParseError {
+		t.Fatal("the output without the module prefix should be a parse error")
+	}
+	if result.ExitCode != ExitFail {
+		t.Errorf("ExitCode: got %d, wanted %d", result.ExitCode, ExitFail)
+	}
+}
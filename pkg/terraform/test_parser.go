@@ -0,0 +1,88 @@
+package terraform
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CommandTest is the command terraform test. This is set to ParseResult.DetectedCommand by TestParser
+const CommandTest = "test"
+
+// TestParser is a parser for the output of terraform test which is supported by Terraform v1.6 or later
+type TestParser struct {
+	// Summary matches the last line like "Failure! 1 passed, 1 failed, 1 skipped."
+	Summary *regexp.Regexp
+	// File matches the status of the test file like "tests/main.tftest.hcl... fail"
+	File *regexp.Regexp
+	// Run matches the status of the run block like `  run "setup"... pass`
+	Run  *regexp.Regexp
+	Fail *regexp.Regexp
+}
+
+// NewTestParser is TestParser initializer
+func NewTestParser() *TestParser {
+	return &TestParser{
+		Summary: regexp.MustCompile(`^(?:Success|Failure)! (\d+) passed, (\d+) failed(?:, (\d+) skipped)?\.`),
+		File:    regexp.MustCompile(`^(\S+)\.\.\. (?:in progress|tearing down|pass|fail|skip)$`),
+		Run:     regexp.MustCompile(`^ +run "([^"]+)"\.\.\. (pass|fail|skip|error)$`),
+		Fail:    regexp.MustCompile(`(?m)^(?:│ ?)?(Error: )`),
+	}
+}
+
+// Parse parses the output of terraform test.
+// The result is the summary line, and failed run blocks are listed in FailedTests as "<file>: <run>"
+func (p *TestParser) Parse(body string) ParseResult {
+	lines := strings.Split(body, "\n")
+	var file, summary string
+	var failedTests []string
+	var passed, failed, skipped int
+	for _, line := range lines {
+		if arr := p.Summary.FindStringSubmatch(line); arr != nil {
+			summary = line
+			passed, _ = strconv.Atoi(arr[1])
+			failed, _ = strconv.Atoi(arr[2])
+			if arr[3] != "" {
+				skipped, _ = strconv.Atoi(arr[3])
+			}
+			continue
+		}
+		if arr := p.File.FindStringSubmatch(line); arr != nil {
+			file = arr[1]
+			continue
+		}
+		if arr := p.Run.FindStringSubmatch(line); arr != nil && (arr[2] == "fail" || arr[2] == "error") {
+			failedTests = appendUnique(failedTests, file+": "+arr[1])
+		}
+	}
+	if summary == "" {
+		// terraform test fails before running tests if the configuration is invalid
+		if loc := p.Fail.FindStringIndex(body); loc != nil {
+			return ParseResult{
+				Result:          strings.TrimSpace(body[loc[0]:]),
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandTest,
+			}
+		}
+		return ParseResult{
+			HasParseError:   true,
+			ExitCode:        ExitFail,
+			Error:           newParseError("test", body, `"Success!", "Failure!", or "Error: "`),
+			DetectedCommand: CommandTest,
+		}
+	}
+	exitCode := ExitPass
+	if failed != 0 || len(failedTests) != 0 {
+		exitCode = ExitFail
+	}
+	return ParseResult{
+		Result:          summary,
+		ExitCode:        exitCode,
+		DetectedCommand: CommandTest,
+		TestPassed:      passed,
+		TestFailed:      failed,
+		TestSkipped:     skipped,
+		FailedTests:     failedTests,
+		Checks:          parseChecks(body),
+	}
+}
@@ -0,0 +1,92 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testFailureResult = `tests/main.tftest.hcl... in progress
+  run "setup"... pass
+  run "check_bucket"... fail
+╷
+│ Error: Test assertion failed
+│
+│   on tests/main.tftest.hcl line 12, in run "check_bucket":
+│   12:     condition     = aws_s3_bucket.bucket.bucket == "foo"
+│
+│ The bucket name is wrong
+╵
+  run "check_tags"... skip
+tests/main.tftest.hcl... tearing down
+tests/main.tftest.hcl... fail
+tests/vpc.tftest.hcl... in progress
+  run "check_vpc"... pass
+tests/vpc.tftest.hcl... tearing down
+tests/vpc.tftest.hcl... pass
+
+Failure! 2 passed, 1 failed, 1 skipped.
+`
+
+func TestTestParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		body   string
+		result ParseResult
+	}{
+		{
+			name: "failure",
+			body: testFailureResult,
+			result: ParseResult{
+				Result:          "Failure! 2 passed, 1 failed, 1 skipped.",
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandTest,
+				TestPassed:      2,
+				TestFailed:      1,
+				TestSkipped:     1,
+				FailedTests:     []string{"tests/main.tftest.hcl: check_bucket"},
+			},
+		},
+		{
+			name: "success",
+			body: "tests/main.tftest.hcl... in progress\n  run \"setup\"... pass\ntests/main.tftest.hcl... tearing down\ntests/main.tftest.hcl... pass\n\nSuccess! 1 passed, 0 failed.\n",
+			result: ParseResult{
+				Result:          "Success! 1 passed, 0 failed.",
+				ExitCode:        ExitPass,
+				DetectedCommand: CommandTest,
+				TestPassed:      1,
+			},
+		},
+		{
+			name: "invalid configuration",
+			body: "\nError: Invalid reference\n\n  on main.tf line 1:\n",
+			result: ParseResult{
+				Result:          "Error: Invalid reference\n\n  on main.tf line 1:",
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandTest,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewTestParser().Parse(testCase.body)
+			if diff := cmp.Diff(testCase.result, result); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTestParserParseError(t *testing.T) {
+	t.Parallel()
+	result := NewTestParser().Parse("tests/main.tftest.hcl... in progress")
+	if !result.HasParseError {
+		t.Fatal("the output without the summary should be a parse error")
+	}
+	if result.ExitCode != ExitFail {
+		t.Errorf("ExitCode: got %d, wanted %d", result.ExitCode, ExitFail)
+	}
+}
@@ -0,0 +1,86 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CommandValidate is the command terraform validate. This is set to ParseResult.DetectedCommand by ValidateParser
+const CommandValidate = "validate"
+
+// Diagnostic is an error or a warning of terraform validate
+type Diagnostic struct {
+	// Severity is either "error" or "warning"
+	Severity string
+	Summary  string
+	Detail   string
+	// Filename and Line are the location of the diagnostic. They are empty if the diagnostic isn't related to a file
+	Filename string
+	Line     int
+}
+
+// ValidateParser is a parser for the output of terraform validate -json
+type ValidateParser struct{}
+
+// NewValidateParser is ValidateParser initializer
+func NewValidateParser() *ValidateParser {
+	return &ValidateParser{}
+}
+
+type jsonValidate struct {
+	Valid        bool             `json:"valid"`
+	ErrorCount   int              `json:"error_count"`
+	WarningCount int              `json:"warning_count"`
+	Diagnostics  []jsonDiagnostic `json:"diagnostics"`
+}
+
+// Parse parses the output of terraform validate -json.
+// The output before the JSON object such as the log of terraform init is ignored
+func (p *ValidateParser) Parse(body string) ParseResult {
+	idx := strings.Index(body, "{")
+	if idx == -1 {
+		return ParseResult{
+			HasParseError:   true,
+			ExitCode:        ExitFail,
+			Error:           fmt.Errorf("cannot parse validate result: the output isn't JSON. Please run terraform validate with -json"),
+			DetectedCommand: CommandValidate,
+		}
+	}
+	var out jsonValidate
+	if err := json.NewDecoder(strings.NewReader(body[idx:])).Decode(&out); err != nil {
+		return ParseResult{
+			HasParseError:   true,
+			ExitCode:        ExitFail,
+			Error:           fmt.Errorf("cannot parse validate result: %w", err),
+			DetectedCommand: CommandValidate,
+		}
+	}
+	diagnostics := make([]Diagnostic, len(out.Diagnostics))
+	for i, diag := range out.Diagnostics {
+		diagnostics[i] = Diagnostic{
+			Severity: diag.Severity,
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+		}
+		if diag.Range != nil {
+			diagnostics[i].Filename = diag.Range.Filename
+			diagnostics[i].Line = diag.Range.Start.Line
+		}
+	}
+	ret := ParseResult{
+		ExitCode:        ExitPass,
+		DetectedCommand: CommandValidate,
+		Diagnostics:     diagnostics,
+	}
+	switch {
+	case !out.Valid:
+		ret.ExitCode = ExitFail
+		ret.Result = fmt.Sprintf("Error: The configuration is invalid. %d error(s), %d warning(s)", out.ErrorCount, out.WarningCount)
+	case out.WarningCount != 0:
+		ret.Result = fmt.Sprintf("Success! The configuration is valid, but there were %d warning(s).", out.WarningCount)
+	default:
+		ret.Result = "Success! The configuration is valid."
+	}
+	return ret
+}
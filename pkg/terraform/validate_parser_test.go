@@ -0,0 +1,108 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const validateInvalidResult = `{
+  "format_version": "1.0",
+  "valid": false,
+  "error_count": 1,
+  "warning_count": 1,
+  "diagnostics": [
+    {
+      "severity": "error",
+      "summary": "Unsupported argument",
+      "detail": "An argument named \"foo\" is not expected here.",
+      "range": {
+        "filename": "main.tf",
+        "start": {"line": 3, "column": 3, "byte": 40},
+        "end": {"line": 3, "column": 6, "byte": 43}
+      }
+    },
+    {
+      "severity": "warning",
+      "summary": "Provider configuration not present",
+      "detail": ""
+    }
+  ]
+}`
+
+func TestValidateParserParse(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name   string
+		body   string
+		result ParseResult
+	}{
+		{
+			name: "invalid",
+			body: validateInvalidResult,
+			result: ParseResult{
+				Result:          "Error: The configuration is invalid. 1 error(s), 1 warning(s)",
+				ExitCode:        ExitFail,
+				DetectedCommand: CommandValidate,
+				Diagnostics: []Diagnostic{
+					{
+						Severity: "error",
+						Summary:  "Unsupported argument",
+						Detail:   `An argument named "foo" is not expected here.`,
+						Filename: "main.tf",
+						Line:     3,
+					},
+					{
+						Severity: "warning",
+						Summary:  "Provider configuration not present",
+					},
+				},
+			},
+		},
+		{
+			name: "valid after terraform init",
+			body: "Initializing the backend...\n" + `{"format_version": "1.0", "valid": true, "error_count": 0, "warning_count": 0, "diagnostics": []}`,
+			result: ParseResult{
+				Result:          "Success! The configuration is valid.",
+				ExitCode:        ExitPass,
+				DetectedCommand: CommandValidate,
+				Diagnostics:     []Diagnostic{},
+			},
+		},
+		{
+			name: "valid with warnings",
+			body: `{"valid": true, "error_count": 0, "warning_count": 1, "diagnostics": [{"severity": "warning", "summary": "Deprecated attribute"}]}`,
+			result: ParseResult{
+				Result:          "Success! The configuration is valid, but there were 1 warning(s).",
+				ExitCode:        ExitPass,
+				DetectedCommand: CommandValidate,
+				Diagnostics: []Diagnostic{
+					{
+						Severity: "warning",
+						Summary:  "Deprecated attribute",
+					},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := NewValidateParser().Parse(testCase.body)
+			if diff := cmp.Diff(testCase.result, result); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestValidateParserParseError(t *testing.T) {
+	t.Parallel()
+	for _, body := range []string{"Success! The configuration is valid.", `{"valid": `} {
+		result := NewValidateParser().Parse(body)
+		if !result.HasParseError {
+			t.Errorf("the output should be a parse error: %s", body)
+		}
+	}
+}